@@ -0,0 +1,172 @@
+// migrate-storage 将本地上传目录中的文件迁移到 S3 兼容对象存储
+//
+// 用法:
+//
+//	go run ./cmd/migrate-storage -config config/config.yaml [-dry-run]
+//
+// 读取 upload.storage_path 下的全部文件上传到 storage.s3 配置的存储桶(key 保持不变),
+// 然后将数据库中指向 /uploads/ 的头像URL改写为对象存储的公开地址。
+// 照片附件只保存相对路径,访问时按当前存储生成签名URL,无需改写。
+// 迁移完成后再将 storage.driver 切换为 s3。
+package main
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/logger"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/persistence"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
+)
+
+// avatarColumns 保存头像URL的列(含记录表中冗余的创建者头像)
+var avatarColumns = []struct {
+	table  string
+	column string
+}{
+	{"users", "avatar_url"},
+	{"babies", "avatar_url"},
+	{"feeding_records", "created_by_avatar"},
+	{"sleep_records", "created_by_avatar"},
+	{"diaper_records", "created_by_avatar"},
+	{"growth_records", "created_by_avatar"},
+	{"baby_vaccine_schedules", "completed_by_avatar"},
+}
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "Configuration file path")
+	dryRun := flag.Bool("dry-run", false, "Only print what would be migrated")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := logger.Init(cfg.Log); err != nil {
+		log.Fatalf("Failed to init logger: %v", err)
+	}
+	defer logger.Sync()
+
+	target, err := storage.NewS3Storage(cfg.Storage.S3)
+	if err != nil {
+		log.Fatalf("Failed to init s3 storage: %v", err)
+	}
+	source := storage.NewLocalStorage(cfg.Upload.StoragePath, cfg.Server.BaseURL, storage.SigningSecret(cfg))
+
+	ctx := context.Background()
+
+	copied, err := copyFiles(ctx, source, target, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to copy files: %v", err)
+	}
+	log.Printf("Copied %d files from %s to bucket %s", copied, source.Root(), cfg.Storage.S3.Bucket)
+
+	db, err := persistence.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+
+	if err := rewriteAvatarURLs(db, cfg, target, *dryRun); err != nil {
+		log.Fatalf("Failed to rewrite avatar urls: %v", err)
+	}
+
+	if *dryRun {
+		log.Println("Dry run finished, nothing was changed")
+		return
+	}
+	log.Println("Migration finished, set storage.driver to s3 and restart the server")
+}
+
+// copyFiles 将本地存储目录下的文件逐个上传到对象存储
+func copyFiles(ctx context.Context, source *storage.LocalStorage, target storage.Storage, dryRun bool) (int, error) {
+	root := source.Root()
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	count := 0
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if dryRun {
+			log.Printf("[dry-run] upload %s", key)
+			count++
+			return nil
+		}
+
+		data, err := source.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := target.Put(ctx, key, data, mime.TypeByExtension(path.Ext(key))); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// rewriteAvatarURLs 将指向本地 /uploads/ 的头像URL替换为对象存储的公开地址
+func rewriteAvatarURLs(db *gorm.DB, cfg *config.Config, target *storage.S3Storage, dryRun bool) error {
+	newPrefix := target.PublicURLPrefix()
+	oldPrefixes := []string{"/uploads/"}
+	if cfg.Server.BaseURL != "" {
+		oldPrefixes = append([]string{strings.TrimSuffix(cfg.Server.BaseURL, "/") + "/uploads/"}, oldPrefixes...)
+	}
+
+	for _, col := range avatarColumns {
+		if !db.Migrator().HasColumn(col.table, col.column) {
+			continue
+		}
+		for _, oldPrefix := range oldPrefixes {
+			pattern := escapeLike(oldPrefix) + "%"
+
+			if dryRun {
+				var count int64
+				if err := db.Table(col.table).Where(col.column+" LIKE ?", pattern).Count(&count).Error; err != nil {
+					return err
+				}
+				log.Printf("[dry-run] %s.%s: %d rows with prefix %s", col.table, col.column, count, oldPrefix)
+				continue
+			}
+
+			// 只替换开头的前缀: 新前缀 + 去掉旧前缀后的剩余部分
+			result := db.Exec(
+				"UPDATE "+col.table+" SET "+col.column+" = ? || substr("+col.column+", ?) WHERE "+col.column+" LIKE ?",
+				newPrefix, len(oldPrefix)+1, pattern,
+			)
+			if result.Error != nil {
+				return result.Error
+			}
+			log.Printf("%s.%s: rewrote %d rows with prefix %s", col.table, col.column, result.RowsAffected, oldPrefix)
+		}
+	}
+	return nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,108 @@
+// vaccine-plans 导入导出疫苗计划(YAML)
+//
+// 用法:
+//
+//	go run ./cmd/vaccine-plans -config config/config.yaml import plan.yaml
+//	go run ./cmd/vaccine-plans -config config/config.yaml export -code cn-nip [-version 2] [-out plan.yaml]
+//
+// 每次导入生成该计划编码的一个新版本(YAML 中未指定 version 时自动递增),已选择旧版本的宝宝不受影响,
+// 需要在小程序中手动切换到新版本。文件格式见 internal/application/service/vaccine_plan_yaml.go。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/logger"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/persistence"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "Configuration file path")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: vaccine-plans [-config file] import <plan.yaml>")
+		fmt.Fprintln(os.Stderr, "       vaccine-plans [-config file] export -code <code> [-version n] [-out file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	zapLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		log.Fatalf("Failed to init logger: %v", err)
+	}
+	defer logger.Sync()
+
+	db, err := persistence.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+
+	planRepo := persistence.NewVaccinePlanRepository(db)
+	templateRepo := persistence.NewVaccinePlanTemplateRepository(db)
+	planService := service.NewVaccinePlanService(
+		planRepo,
+		templateRepo,
+		persistence.NewBabyVaccineScheduleRepository(db, planRepo, templateRepo),
+		persistence.NewBabyRepository(db),
+		persistence.NewBabyCollaboratorRepository(db),
+		persistence.NewUserRepository(db),
+		nil, // 命令行只导入/导出计划,不修改宝宝日程,无需记录修订
+		zapLogger,
+	)
+
+	ctx := context.Background()
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "import":
+		if len(args) != 1 {
+			log.Fatal("import requires exactly one YAML file")
+		}
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", args[0], err)
+		}
+		plan, doses, err := planService.ImportPlanYAML(ctx, data)
+		if err != nil {
+			log.Fatalf("Failed to import plan: %v", err)
+		}
+		log.Printf("Imported plan %s version %d (id %d) with %d doses", plan.Code, plan.Version, plan.ID, doses)
+
+	case "export":
+		fs := flag.NewFlagSet("export", flag.ExitOnError)
+		code := fs.String("code", "", "Plan code")
+		version := fs.Int("version", 0, "Plan version, latest when 0")
+		out := fs.String("out", "", "Output file, stdout when empty")
+		_ = fs.Parse(args)
+		if *code == "" {
+			log.Fatal("export requires -code")
+		}
+		data, err := planService.ExportPlanYAML(ctx, *code, *version)
+		if err != nil {
+			log.Fatalf("Failed to export plan: %v", err)
+		}
+		if *out == "" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *out, err)
+		}
+		log.Printf("Exported plan %s to %s", *code, *out)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,39 @@
+package dto
+
+// AttachmentDTO 照片附件DTO
+type AttachmentDTO struct {
+	AttachmentID string `json:"attachmentId"`
+	BabyID       string `json:"babyId"`
+	RecordType   string `json:"recordType"` // feeding, sleep, diaper, growth, milestone, vaccine
+	RecordID     string `json:"recordId"`
+	URL          string `json:"url"`          // 原图
+	DisplayURL   string `json:"displayUrl"`   // 展示图
+	ThumbnailURL string `json:"thumbnailUrl"` // 缩略图
+	Filename     string `json:"filename"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	TakenAt      int64  `json:"takenAt"` // 归档时间(毫秒时间戳)
+	UploadedBy   string `json:"uploadedBy"`
+	CreateTime   int64  `json:"createTime"`
+}
+
+// AttachmentRecordParams 附件关联记录路径参数
+type AttachmentRecordParams struct {
+	RecordType string `uri:"recordType" binding:"required,oneof=feeding sleep diaper growth milestone vaccine"`
+	RecordID   string `uri:"recordId" binding:"required"`
+}
+
+// GalleryQuery 宝宝相册查询参数
+type GalleryQuery struct {
+	Month string `form:"month"` // 月份 YYYY-MM,默认当前月
+}
+
+// GalleryResponse 宝宝相册(按月分页)
+type GalleryResponse struct {
+	Month     string          `json:"month"`               // 当前月份 YYYY-MM
+	Items     []AttachmentDTO `json:"items"`               // 当月照片(按时间倒序)
+	Total     int             `json:"total"`               // 当月照片数量
+	PrevMonth *string         `json:"prevMonth,omitempty"` // 更早一个有照片的月份,无则为空
+	NextMonth *string         `json:"nextMonth,omitempty"` // 更晚一个有照片的月份,无则为空
+}
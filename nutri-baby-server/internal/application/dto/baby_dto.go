@@ -21,6 +21,13 @@ type UpdateBabyRequest struct {
 	Weight    int    `json:"weight"` // g
 }
 
+// UpdateBabyResultDTO 更新宝宝结果
+type UpdateBabyResultDTO struct {
+	BirthDateChanged bool                     `json:"birthDateChanged"`
+	VaccineSchedules *VaccinePlanReconcileDTO `json:"vaccineSchedules,omitempty"` // 出生日期变化后疫苗日程的调整
+	DailyTipsCleared bool                     `json:"dailyTipsCleared"`           // 已清除按旧月龄生成的每日建议
+}
+
 // BabyDTO 宝宝DTO (去家庭化架构)
 type BabyDTO struct {
 	BabyID     string `json:"babyId"`
@@ -29,7 +36,7 @@ type BabyDTO struct {
 	Gender     string `json:"gender"`
 	BirthDate  string `json:"birthDate"`
 	AvatarURL  string `json:"avatarUrl"`
-	CreatorID  string `json:"creatorId"` // 创建者 openid
+	CreatorID  string `json:"creatorId"` // 所有者用户ID(初始为创建者)
 	Height     int    `json:"height"`
 	Weight     int    `json:"weight"`
 	CreateTime int64  `json:"createTime"`
@@ -41,7 +48,7 @@ type CollaboratorDTO struct {
 	OpenID       string `json:"openid"`
 	NickName     string `json:"nickName"`
 	AvatarURL    string `json:"avatarUrl"`
-	Role         string `json:"role"`         // admin, editor, viewer
+	Role         string `json:"role"`         // admin, editor, caregiver, viewer
 	Relationship string `json:"relationship"` // 与宝宝的关系: 爸爸, 妈妈, 爷爷, 奶奶, 外公, 外婆等
 	AccessType   string `json:"accessType"`   // permanent, temporary
 	ExpiresAt    *int64 `json:"expiresAt"`    // 临时权限过期时间
@@ -51,10 +58,14 @@ type CollaboratorDTO struct {
 // InviteFamilyMemberRequest 邀请亲友团成员请求 (微信分享/二维码)
 type InviteCollaboratorRequest struct {
 	InviteType   string `json:"inviteType" binding:"required,oneof=share qrcode"` // share=微信分享, qrcode=二维码
-	Role         string `json:"role" binding:"required,oneof=admin editor viewer"`
+	Role         string `json:"role" binding:"required,oneof=admin editor caregiver viewer"`
 	Relationship string `json:"relationship"` // 与宝宝的关系: 爸爸, 妈妈, 爷爷, 奶奶, 外公, 外婆等
 	AccessType   string `json:"accessType" binding:"required,oneof=permanent temporary"`
 	ExpiresAt    *int64 `json:"expiresAt"` // 仅当 accessType=temporary 时需要
+
+	LinkExpiresAt   *int64 `json:"linkExpiresAt"`                     // 邀请链接过期时间(毫秒),与协作权限过期时间无关,为空表示长期有效
+	MaxUses         int    `json:"maxUses" binding:"omitempty,min=0"` // 最大使用次数,0表示不限
+	RequireApproval bool   `json:"requireApproval"`                   // 是否需要管理员审批后才能加入
 }
 
 // BabyInvitationDTO 宝宝邀请信息DTO
@@ -66,6 +77,12 @@ type BabyInvitationDTO struct {
 	ShortCode    string        `json:"shortCode"`    // 6位短码(用于小程序码scene参数)
 	QRCodeParams *QRCodeParams `json:"qrcodeParams"` // 二维码参数
 	ExpiresAt    *int64        `json:"expiresAt"`    // 协作者权限过期时间(临时权限)
+
+	InvitationID    string `json:"invitationId"`    // 邀请ID(用于撤销)
+	LinkExpiresAt   *int64 `json:"linkExpiresAt"`   // 邀请链接过期时间
+	MaxUses         int    `json:"maxUses"`         // 最大使用次数,0表示不限
+	UsedCount       int    `json:"usedCount"`       // 已使用次数
+	RequireApproval bool   `json:"requireApproval"` // 是否需要管理员审批
 }
 
 // ShareParams 微信小程序分享参数
@@ -90,8 +107,13 @@ type JoinBabyRequest struct {
 
 // UpdateFamilyMemberRequest 更新亲友团成员请求
 type UpdateFamilyMemberRequest struct {
-	Role         string `json:"role" binding:"omitempty,oneof=admin editor viewer"` // 角色
-	Relationship string `json:"relationship"`                                       // 与宝宝的关系
+	Role         string `json:"role" binding:"omitempty,oneof=admin editor caregiver viewer"` // 角色
+	Relationship string `json:"relationship"`                                                 // 与宝宝的关系
+}
+
+// ExtendCollaboratorAccessRequest 延长临时成员访问期限请求
+type ExtendCollaboratorAccessRequest struct {
+	ExpiresAt int64 `json:"expiresAt" binding:"required"` // 新的过期时间(毫秒时间戳)
 }
 
 // InvitationDetailDTO 邀请详情DTO (用于通过短码查询)
@@ -104,4 +126,7 @@ type InvitationDetailDTO struct {
 	AccessType  string `json:"accessType"`  // 访问类型
 	ExpiresAt   *int64 `json:"expiresAt"`   // 权限过期时间(临时权限)
 	Token       string `json:"token"`       // Token(用于加入)
+
+	LinkExpiresAt   *int64 `json:"linkExpiresAt"`   // 邀请链接过期时间
+	RequireApproval bool   `json:"requireApproval"` // 加入后需等待管理员审批
 }
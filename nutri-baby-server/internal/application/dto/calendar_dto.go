@@ -0,0 +1,15 @@
+package dto
+
+// CalendarFeedDTO 日历订阅信息
+type CalendarFeedDTO struct {
+	URL            string `json:"url"`                      // ICS 订阅地址(https)
+	WebcalURL      string `json:"webcalUrl"`                // webcal:// 地址,部分日历应用点击即可订阅
+	CreateTime     int64  `json:"createTime"`               // 首次创建时间
+	TokenUpdatedAt int64  `json:"tokenUpdatedAt"`           // 订阅地址生成时间,重新生成后旧地址失效
+	LastAccessedAt *int64 `json:"lastAccessedAt,omitempty"` // 最近一次被日历应用拉取的时间
+}
+
+// CalendarFeedQuery ICS 订阅拉取参数
+type CalendarFeedQuery struct {
+	Timezone string `form:"tz"` // IANA 时区名,如 Asia/Shanghai,为空时使用服务器时区
+}
@@ -0,0 +1,59 @@
+package dto
+
+// CommentRecordParams 评论关联记录路径参数
+type CommentRecordParams struct {
+	RecordType string `uri:"recordType" binding:"required,oneof=feeding sleep diaper growth milestone vaccine"`
+	RecordID   string `uri:"recordId" binding:"required"`
+}
+
+// CreateCommentRequest 发表评论请求
+type CreateCommentRequest struct {
+	Content        string   `json:"content" binding:"required"`
+	ParentID       string   `json:"parentId"`       // 回复的评论ID,为空表示顶层评论;回复的回复归入同一顶层评论
+	MentionOpenIDs []string `json:"mentionOpenIds"` // @提及的亲友团成员
+}
+
+// ReactionRequest 表情回应请求
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// CommentUserDTO 评论中展示的成员
+type CommentUserDTO struct {
+	OpenID       string `json:"openid"`
+	NickName     string `json:"nickName"`
+	AvatarURL    string `json:"avatarUrl"`
+	Relationship string `json:"relationship"` // 与宝宝的关系,已离开的成员为空
+}
+
+// CommentDTO 评论DTO
+type CommentDTO struct {
+	CommentID  string           `json:"commentId"`
+	RecordType string           `json:"recordType"`
+	RecordID   string           `json:"recordId"`
+	ParentID   string           `json:"parentId,omitempty"`
+	Author     CommentUserDTO   `json:"author"`
+	ReplyTo    *CommentUserDTO  `json:"replyTo,omitempty"` // 被回复的成员
+	Content    string           `json:"content"`
+	Mentions   []CommentUserDTO `json:"mentions,omitempty"`
+	CanDelete  bool             `json:"canDelete"` // 当前用户能否删除(本人或管理员)
+	CreateTime int64            `json:"createTime"`
+	Replies    []CommentDTO     `json:"replies,omitempty"` // 回复(按时间正序),只有顶层评论有
+}
+
+// ReactionSummaryDTO 同一表情的回应汇总
+type ReactionSummaryDTO struct {
+	Emoji     string   `json:"emoji"`
+	Count     int      `json:"count"`
+	Reacted   bool     `json:"reacted"`   // 当前用户是否已回应
+	NickNames []string `json:"nickNames"` // 回应者昵称(按回应时间正序)
+}
+
+// RecordCommentsResponse 记录的评论与表情回应
+type RecordCommentsResponse struct {
+	RecordType   string               `json:"recordType"`
+	RecordID     string               `json:"recordId"`
+	Comments     []CommentDTO         `json:"comments"`     // 顶层评论(按时间正序),回复嵌套在 replies 中
+	CommentCount int                  `json:"commentCount"` // 评论总数(含回复)
+	Reactions    []ReactionSummaryDTO `json:"reactions"`
+}
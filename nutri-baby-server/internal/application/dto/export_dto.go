@@ -0,0 +1,136 @@
+package dto
+
+// ExportFormatName 导出 JSON 文档的格式标识,导入时据此识别本系统的导出文件
+const ExportFormatName = "nutri-baby-export"
+
+// ExportFormatVersion 导出 JSON 文档的格式版本
+const ExportFormatVersion = 1
+
+// CreateExportRequest 创建导出任务请求
+type CreateExportRequest struct {
+	StartTime   int64 `json:"startTime"`   // 开始时间(毫秒时间戳),0表示不限
+	EndTime     int64 `json:"endTime"`     // 结束时间(毫秒时间戳),0表示到当前
+	IncludeXLSX bool  `json:"includeXlsx"` // 是否额外生成 XLSX 工作簿
+}
+
+// ExportJobDTO 导出任务DTO
+type ExportJobDTO struct {
+	ExportID     string  `json:"exportId"`
+	BabyID       string  `json:"babyId"`
+	StartTime    int64   `json:"startTime"`
+	EndTime      int64   `json:"endTime"`
+	IncludeXLSX  bool    `json:"includeXlsx"`
+	Status       string  `json:"status"` // pending, processing, completed, failed, expired
+	RecordCount  int     `json:"recordCount"`
+	FileSize     int64   `json:"fileSize"`
+	DownloadURL  *string `json:"downloadUrl,omitempty"` // 仅 completed 时返回,为限时签名URL
+	ExpiresAt    *int64  `json:"expiresAt,omitempty"`   // 下载链接过期时间(毫秒时间戳)
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+	CompletedAt  *int64  `json:"completedAt,omitempty"`
+	CreateTime   int64   `json:"createTime"`
+}
+
+// ExportDocument 规范化的导出 JSON 文档(export.json)
+// 时间字段均为毫秒时间戳,ID 均为字符串
+type ExportDocument struct {
+	Format     string            `json:"format"`  // 固定为 nutri-baby-export
+	Version    int               `json:"version"` // 格式版本
+	ExportedAt int64             `json:"exportedAt"`
+	StartTime  int64             `json:"startTime"`
+	EndTime    int64             `json:"endTime"`
+	Baby       ExportBaby        `json:"baby"`
+	Feedings   []ExportFeeding   `json:"feedings"`
+	Sleeps     []ExportSleep     `json:"sleeps"`
+	Diapers    []ExportDiaper    `json:"diapers"`
+	Growths    []ExportGrowth    `json:"growths"`
+	Vaccines   []ExportVaccine   `json:"vaccines"`
+	Milestones []ExportMilestone `json:"milestones"`
+}
+
+// ExportBaby 导出的宝宝信息
+type ExportBaby struct {
+	BabyID    string `json:"babyId"`
+	Name      string `json:"name"`
+	Nickname  string `json:"nickname"`
+	Gender    string `json:"gender"`
+	BirthDate string `json:"birthDate"`
+}
+
+// ExportFeeding 导出的喂养记录
+type ExportFeeding struct {
+	RecordID      string         `json:"recordId"`
+	Time          int64          `json:"time"`
+	FeedingType   string         `json:"feedingType"` // breast, bottle, food
+	AmountMl      int64          `json:"amountMl"`
+	Duration      int            `json:"duration"` // 秒
+	Detail        map[string]any `json:"detail,omitempty"`
+	CreatedBy     string         `json:"createdBy"`
+	CreatedByName string         `json:"createdByName"`
+	CreateTime    int64          `json:"createTime"`
+}
+
+// ExportSleep 导出的睡眠记录
+type ExportSleep struct {
+	RecordID      string `json:"recordId"`
+	StartTime     int64  `json:"startTime"`
+	EndTime       *int64 `json:"endTime,omitempty"`
+	Duration      *int   `json:"duration,omitempty"` // 秒
+	Type          string `json:"type"`               // nap, night
+	CreatedBy     string `json:"createdBy"`
+	CreatedByName string `json:"createdByName"`
+	CreateTime    int64  `json:"createTime"`
+}
+
+// ExportDiaper 导出的换尿布记录
+type ExportDiaper struct {
+	RecordID      string  `json:"recordId"`
+	Time          int64   `json:"time"`
+	Type          string  `json:"type"` // pee, poop, both
+	PoopColor     *string `json:"poopColor,omitempty"`
+	PoopTexture   *string `json:"poopTexture,omitempty"`
+	Note          *string `json:"note,omitempty"`
+	CreatedBy     string  `json:"createdBy"`
+	CreatedByName string  `json:"createdByName"`
+	CreateTime    int64   `json:"createTime"`
+}
+
+// ExportGrowth 导出的成长记录
+type ExportGrowth struct {
+	RecordID          string   `json:"recordId"`
+	Time              int64    `json:"time"`
+	Height            *float64 `json:"height,omitempty"`            // cm
+	Weight            *float64 `json:"weight,omitempty"`            // kg
+	HeadCircumference *float64 `json:"headCircumference,omitempty"` // cm
+	Note              *string  `json:"note,omitempty"`
+	CreatedBy         string   `json:"createdBy"`
+	CreatedByName     string   `json:"createdByName"`
+	CreateTime        int64    `json:"createTime"`
+}
+
+// ExportVaccine 导出的疫苗接种日程
+type ExportVaccine struct {
+	ScheduleID        string  `json:"scheduleId"`
+	VaccineType       string  `json:"vaccineType"`
+	VaccineName       string  `json:"vaccineName"`
+	DoseNumber        int     `json:"doseNumber"`
+	AgeInMonths       int     `json:"ageInMonths"`
+	ScheduledDate     int64   `json:"scheduledDate"`
+	VaccinationStatus string  `json:"vaccinationStatus"` // pending, completed, skipped
+	VaccineDate       *int64  `json:"vaccineDate,omitempty"`
+	Hospital          *string `json:"hospital,omitempty"`
+	BatchNumber       *string `json:"batchNumber,omitempty"`
+	Doctor            *string `json:"doctor,omitempty"`
+	Reaction          *string `json:"reaction,omitempty"`
+	Note              *string `json:"note,omitempty"`
+}
+
+// ExportMilestone 导出的发育里程碑
+type ExportMilestone struct {
+	MilestoneID  string  `json:"milestoneId"`
+	Category     string  `json:"category"`
+	Title        string  `json:"title"`
+	AgeInMonths  int     `json:"ageInMonths"`
+	Status       string  `json:"status"`
+	AchievedDate *int64  `json:"achievedDate,omitempty"`
+	Note         *string `json:"note,omitempty"`
+}
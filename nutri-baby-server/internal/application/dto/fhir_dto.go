@@ -0,0 +1,42 @@
+package dto
+
+// FHIRRequest FHIR 导出/导入的公共参数
+type FHIRRequest struct {
+	Timezone string `form:"timezone"` // 输出/解析日期使用的时区(IANA 名称,如 Asia/Shanghai),默认服务器时区
+}
+
+// FHIRImportRequest FHIR 接种记录导入请求(请求体为 Bundle JSON)
+type FHIRImportRequest struct {
+	FHIRRequest
+	DryRun bool `form:"dryRun"` // 仅预览匹配结果,不写入
+}
+
+// FHIRImportItemDTO 单条接种记录的导入结果
+type FHIRImportItemDTO struct {
+	Entry       int      `json:"entry"` // Bundle 条目序号(从1开始)
+	VaccineType string   `json:"vaccineType,omitempty"`
+	VaccineName string   `json:"vaccineName"`
+	DoseNumber  int      `json:"doseNumber"`
+	VaccineDate int64    `json:"vaccineDate"`
+	Action      string   `json:"action"` // matched(匹配到待接种日程), created(新建自定义日程), duplicate(已有接种记录)
+	ScheduleID  string   `json:"scheduleId,omitempty"`
+	Warnings    []string `json:"warnings"` // 接种时间早于最小日龄/最小间隔等提示(不阻止导入)
+}
+
+// FHIRImportErrorDTO 无法导入的条目
+type FHIRImportErrorDTO struct {
+	Entry   int    `json:"entry"`
+	Message string `json:"message"`
+}
+
+// FHIRImportResultDTO FHIR 接种记录导入结果
+type FHIRImportResultDTO struct {
+	DryRun      bool                   `json:"dryRun"`
+	Matched     int                    `json:"matched"`
+	Created     int                    `json:"created"`
+	Duplicates  int                    `json:"duplicates"`
+	Skipped     int                    `json:"skipped"` // 未完成的接种(not-done、entered-in-error)
+	Items       []FHIRImportItemDTO    `json:"items"`
+	Errors      []FHIRImportErrorDTO   `json:"errors"`
+	Rescheduled []VaccinePlanChangeDTO `json:"rescheduled"` // 因补种规则顺延的后续剂次
+}
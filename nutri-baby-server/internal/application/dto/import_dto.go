@@ -0,0 +1,54 @@
+package dto
+
+// ImportRequest 导入请求(multipart 表单字段,文件字段名为 file)
+type ImportRequest struct {
+	Parser     string `form:"parser"`     // 解析器标识,为空时自动识别
+	DryRun     bool   `form:"dryRun"`     // 仅预览,不写入数据
+	SkipErrors bool   `form:"skipErrors"` // 存在错误行时仍导入其余有效行,默认有错误即不导入
+	Timezone   string `form:"timezone"`   // 源文件中时间的时区(IANA 名称,如 Asia/Shanghai),默认服务器时区
+}
+
+// ImportParserDTO 可用的导入解析器
+type ImportParserDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ImportCountsDTO 按记录类型统计的条数
+type ImportCountsDTO struct {
+	Feeding int `json:"feeding"`
+	Sleep   int `json:"sleep"`
+	Diaper  int `json:"diaper"`
+	Growth  int `json:"growth"`
+	Total   int `json:"total"`
+}
+
+// ImportRowErrorDTO 单行错误
+type ImportRowErrorDTO struct {
+	Source  string `json:"source"` // 来源分区(CSV 文件名或 JSON 数组名)
+	Row     int    `json:"row"`    // 行号
+	Message string `json:"message"`
+}
+
+// ImportPreviewItemDTO 预览条目
+type ImportPreviewItemDTO struct {
+	Source     string `json:"source"`
+	Row        int    `json:"row"`
+	RecordType string `json:"recordType"` // feeding, sleep, diaper, growth
+	Time       int64  `json:"time"`       // 记录时间(毫秒时间戳)
+	Summary    string `json:"summary"`
+	Duplicate  bool   `json:"duplicate"` // 与已有记录或文件中前面的记录重复,导入时跳过
+}
+
+// ImportResultDTO 导入结果(预览与正式导入共用)
+type ImportResultDTO struct {
+	Parser     string                 `json:"parser"`
+	DryRun     bool                   `json:"dryRun"`
+	Committed  bool                   `json:"committed"`  // 是否已写入;存在错误且未选择跳过时为 false
+	Importable ImportCountsDTO        `json:"importable"` // 可导入的记录数(不含重复)
+	Imported   ImportCountsDTO        `json:"imported"`   // 实际写入的记录数
+	Duplicates int                    `json:"duplicates"` // 重复而跳过的记录数
+	Skipped    int                    `json:"skipped"`    // 不支持的记录类型被忽略的行数
+	Errors     []ImportRowErrorDTO    `json:"errors"`
+	Items      []ImportPreviewItemDTO `json:"items"` // 预览条目(最多返回前 200 条)
+}
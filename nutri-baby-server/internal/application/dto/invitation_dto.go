@@ -0,0 +1,37 @@
+package dto
+
+// InvitationDTO 邀请管理列表项
+type InvitationDTO struct {
+	InvitationID    string `json:"invitationId"`
+	InviterOpenID   string `json:"inviterOpenid"`
+	InviterName     string `json:"inviterName"`
+	InviteType      string `json:"inviteType"` // share, qrcode
+	ShortCode       string `json:"shortCode"`
+	Role            string `json:"role"`
+	Relationship    string `json:"relationship"`
+	AccessType      string `json:"accessType"`    // permanent, temporary
+	ExpiresAt       *int64 `json:"expiresAt"`     // 协作权限过期时间
+	LinkExpiresAt   *int64 `json:"linkExpiresAt"` // 邀请链接过期时间
+	MaxUses         int    `json:"maxUses"`       // 0表示不限
+	UsedCount       int    `json:"usedCount"`
+	RequireApproval bool   `json:"requireApproval"`
+	Status          string `json:"status"` // active, revoked, expired, used_up
+	CreatedAt       int64  `json:"createdAt"`
+}
+
+// JoinRequestDTO 加入申请
+type JoinRequestDTO struct {
+	RequestID    string `json:"requestId"`
+	BabyID       string `json:"babyId"`
+	InvitationID string `json:"invitationId"`
+	OpenID       string `json:"openid"`
+	NickName     string `json:"nickName"`
+	AvatarURL    string `json:"avatarUrl"`
+	Role         string `json:"role"`
+	Relationship string `json:"relationship"`
+	AccessType   string `json:"accessType"`
+	ExpiresAt    *int64 `json:"expiresAt"`
+	Status       string `json:"status"` // pending, approved, rejected, cancelled
+	CreatedAt    int64  `json:"createdAt"`
+	ReviewedAt   *int64 `json:"reviewedAt,omitempty"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// MilestoneDTO 发育里程碑DTO
+type MilestoneDTO struct {
+	MilestoneID    string   `json:"milestoneId"`
+	BabyID         string   `json:"babyId"`
+	TemplateID     *string  `json:"templateId,omitempty"`
+	Category       string   `json:"category"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	AgeInMonths    int      `json:"ageInMonths"`
+	MaxAgeInMonths int      `json:"maxAgeInMonths"`
+	IsCustom       bool     `json:"isCustom"`
+	Status         string   `json:"status"`    // upcoming, achieved, discuss_with_doctor
+	IsOverdue      bool     `json:"isOverdue"` // 已超过最晚达成月龄仍未达成
+	AchievedDate   *int64   `json:"achievedDate,omitempty"`
+	Photos         []string `json:"photos"`
+	Note           *string  `json:"note,omitempty"`
+	CreateBy       string   `json:"createBy"`
+	CreateTime     int64    `json:"createTime"`
+	UpdateTime     int64    `json:"updateTime"`
+}
+
+// MilestoneListQuery 里程碑列表查询参数
+type MilestoneListQuery struct {
+	Status   string `form:"status"`   // 可选: upcoming, achieved, discuss_with_doctor
+	Category string `form:"category"` // 可选: motor, language, social, cognitive
+}
+
+// CreateMilestoneRequest 创建自定义里程碑请求
+type CreateMilestoneRequest struct {
+	Category       string   `json:"category" binding:"required,oneof=motor language social cognitive"`
+	Title          string   `json:"title" binding:"required,max=64"`
+	Description    string   `json:"description"`
+	AgeInMonths    int      `json:"ageInMonths" binding:"min=0"`
+	MaxAgeInMonths int      `json:"maxAgeInMonths" binding:"min=0"`
+	AchievedDate   *int64   `json:"achievedDate"` // 可选: 创建时即已达成
+	Photos         []string `json:"photos"`
+	Note           *string  `json:"note"`
+}
+
+// UpdateMilestoneStatusRequest 标记里程碑状态请求
+type UpdateMilestoneStatusRequest struct {
+	Status       string   `json:"status" binding:"required,oneof=upcoming achieved discuss_with_doctor"`
+	AchievedDate *int64   `json:"achievedDate"` // 达成日期(status=achieved时可选,默认当前时间)
+	Photos       []string `json:"photos"`       // 照片URL列表(为空表示不修改)
+	Note         *string  `json:"note"`         // 备注
+}
@@ -0,0 +1,20 @@
+package dto
+
+// NominateOwnerRequest 提名新所有者请求
+type NominateOwnerRequest struct {
+	TargetOpenID string `json:"targetOpenid" binding:"required"` // 被提名成员的 openid
+}
+
+// OwnershipTransferDTO 所有权转让
+type OwnershipTransferDTO struct {
+	TransferID  string `json:"transferId"`
+	BabyID      string `json:"babyId"`
+	BabyName    string `json:"babyName"`
+	FromOpenID  string `json:"fromOpenid"`
+	FromName    string `json:"fromName"`
+	ToOpenID    string `json:"toOpenid"`
+	ToName      string `json:"toName"`
+	Status      string `json:"status"` // pending, accepted, declined, cancelled
+	CreatedAt   int64  `json:"createdAt"`
+	RespondedAt *int64 `json:"respondedAt,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// CollaboratorPermissionsDTO 协作者权限矩阵
+type CollaboratorPermissionsDTO struct {
+	BabyID      string                     `json:"babyId"`
+	OpenID      string                     `json:"openid"`
+	Role        string                     `json:"role"`                // admin, editor, caregiver, viewer
+	Permissions map[string][]string        `json:"permissions"`         // 实际权限: 记录类型 -> 允许的操作
+	Overrides   map[string]map[string]bool `json:"overrides,omitempty"` // 在角色预设之上的覆盖
+}
+
+// UpdateCollaboratorPermissionsRequest 更新协作者权限覆盖请求
+// 记录类型: feeding, sleep, diaper, growth, vaccine, milestone
+// 操作: read, create, update_own, update_any, delete
+type UpdateCollaboratorPermissionsRequest struct {
+	Overrides map[string]map[string]bool `json:"overrides"` // 为空时清除全部覆盖,恢复角色预设
+}
+
+// RolePermissionPresetDTO 角色预设权限
+type RolePermissionPresetDTO struct {
+	Role        string              `json:"role"`
+	Name        string              `json:"name"`
+	Permissions map[string][]string `json:"permissions"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ReportRequest 就诊报告请求
+type ReportRequest struct {
+	StartTime int64 `form:"startTime"` // 开始时间(毫秒时间戳),默认结束时间前30天
+	EndTime   int64 `form:"endTime"`   // 结束时间(毫秒时间戳),默认当前时间
+}
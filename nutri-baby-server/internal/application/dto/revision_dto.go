@@ -0,0 +1,42 @@
+package dto
+
+// RevisionRecordParams 修订历史关联记录路径参数
+type RevisionRecordParams struct {
+	RecordType string `uri:"recordType" binding:"required,oneof=feeding sleep diaper growth milestone vaccine"`
+	RecordID   string `uri:"recordId" binding:"required"`
+}
+
+// RestoreRevisionRequest 恢复到历史版本请求
+type RestoreRevisionRequest struct {
+	RevisionID        string `json:"revisionId" binding:"required"`
+	ExpectedUpdatedAt int64  `json:"expectedUpdatedAt"` // 可选: 客户端看到的记录更新时间,记录已被他人修改时拒绝恢复
+}
+
+// RevisionFieldChangeDTO 字段变更
+type RevisionFieldChangeDTO struct {
+	Field string `json:"field"` // 字段名,嵌套字段用点号连接(如 detail.amount)
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// RecordRevisionDTO 记录修订DTO
+type RecordRevisionDTO struct {
+	RevisionID      string                   `json:"revisionId"`
+	RecordType      string                   `json:"recordType"`
+	RecordID        string                   `json:"recordId"`
+	Action          string                   `json:"action"` // create, update, delete, restore
+	ActorID         string                   `json:"actorId"`
+	ActorName       string                   `json:"actorName"`
+	Changes         []RevisionFieldChangeDTO `json:"changes"`
+	Snapshot        map[string]any           `json:"snapshot"` // 操作后的记录快照,删除时为删除前的快照
+	RecordUpdatedAt int64                    `json:"recordUpdatedAt"`
+	RestoredFrom    string                   `json:"restoredFrom,omitempty"` // 恢复来源的修订ID
+	CreateTime      int64                    `json:"createTime"`
+}
+
+// RecordHistoryResponse 记录修订历史(按时间倒序)
+type RecordHistoryResponse struct {
+	RecordType string              `json:"recordType"`
+	RecordID   string              `json:"recordId"`
+	Revisions  []RecordRevisionDTO `json:"revisions"`
+}
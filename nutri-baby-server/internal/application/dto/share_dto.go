@@ -0,0 +1,75 @@
+package dto
+
+// CreateShareLinkRequest 创建只读分享链接请求
+// 时间范围二选一: recentDays 表示最近 N 天(截至访问时);否则使用 startTime/endTime
+type CreateShareLinkRequest struct {
+	Label          string   `json:"label" binding:"max=64"`                                                              // 备注名称,如 "儿科王医生"
+	RecordTypes    []string `json:"recordTypes" binding:"required,min=1,dive,oneof=feeding sleep diaper growth vaccine"` // 授权的记录类型
+	RecentDays     int      `json:"recentDays" binding:"omitempty,min=1,max=90"`                                         // 最近 N 天
+	StartTime      int64    `json:"startTime"`                                                                           // 开始时间(毫秒时间戳)
+	EndTime        int64    `json:"endTime"`                                                                             // 结束时间(毫秒时间戳),0表示截至访问时
+	ExpiresInHours int      `json:"expiresInHours" binding:"omitempty,min=1,max=720"`                                    // 链接有效期(小时),默认72小时
+}
+
+// ShareLinkDTO 分享链接DTO
+type ShareLinkDTO struct {
+	ShareID        string   `json:"shareId"`
+	BabyID         string   `json:"babyId"`
+	Label          string   `json:"label"`
+	RecordTypes    []string `json:"recordTypes"`
+	StartTime      int64    `json:"startTime"`
+	EndTime        int64    `json:"endTime"` // 0 表示截至访问时
+	ExpiresAt      int64    `json:"expiresAt"`
+	RevokedAt      *int64   `json:"revokedAt,omitempty"`
+	Status         string   `json:"status"`          // active, expired, revoked
+	Token          string   `json:"token,omitempty"` // 仅有效链接返回
+	AccessCount    int      `json:"accessCount"`
+	LastAccessedAt *int64   `json:"lastAccessedAt,omitempty"`
+	CreateBy       string   `json:"createBy"`
+	CreateTime     int64    `json:"createTime"`
+}
+
+// ShareAccessLogDTO 分享链接访问日志DTO
+type ShareAccessLogDTO struct {
+	Resource   string `json:"resource"` // overview 或记录类型
+	Result     string `json:"result"`   // allowed, expired, revoked
+	IP         string `json:"ip"`
+	UserAgent  string `json:"userAgent"`
+	AccessedAt int64  `json:"accessedAt"`
+}
+
+// ShareAccessLogListResponse 分享链接访问日志列表
+type ShareAccessLogListResponse struct {
+	Items    []ShareAccessLogDTO `json:"items"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
+// ShareAccessMeta 分享链接访问者信息(用于审计)
+type ShareAccessMeta struct {
+	IP        string
+	UserAgent string
+}
+
+// SharedBabyDTO 通过分享链接查看的宝宝概况
+type SharedBabyDTO struct {
+	Name        string   `json:"name"`
+	Nickname    string   `json:"nickname"`
+	Gender      string   `json:"gender"`
+	BirthDate   string   `json:"birthDate"`
+	AvatarURL   string   `json:"avatarUrl"`
+	Label       string   `json:"label"`
+	RecordTypes []string `json:"recordTypes"`
+	StartTime   int64    `json:"startTime"`
+	EndTime     int64    `json:"endTime"` // 可查看的截止时间(毫秒时间戳)
+	ExpiresAt   int64    `json:"expiresAt"`
+}
+
+// SharedRecordsQuery 通过分享链接查询记录的参数,时间范围会被限制在分享范围内
+type SharedRecordsQuery struct {
+	RecordType string `form:"recordType"` // 可选,空表示全部已授权类型
+	StartTime  int64  `form:"startTime"`
+	EndTime    int64  `form:"endTime"`
+	PaginationRequest
+}
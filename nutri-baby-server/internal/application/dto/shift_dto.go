@@ -0,0 +1,85 @@
+package dto
+
+// StartShiftRequest 开始值班请求
+type StartShiftRequest struct {
+	RouteReminders bool `json:"routeReminders"` // 值班期间喂养等提醒只发给我
+}
+
+// EndShiftRequest 结束值班(交班)请求
+type EndShiftRequest struct {
+	HandoffNote     string `json:"handoffNote" binding:"max=500"` // 交班备注,需要下一位照护人留意的情况
+	HandoffToOpenID string `json:"handoffToOpenid"`               // 可选: 接班照护人的 openid,填写后向其推送交接摘要
+}
+
+// CaregiverShiftDTO 照护人值班
+type CaregiverShiftDTO struct {
+	ShiftID        string `json:"shiftId"`
+	BabyID         string `json:"babyId"`
+	OpenID         string `json:"openid"`
+	NickName       string `json:"nickName"`
+	AvatarURL      string `json:"avatarUrl"`
+	Relationship   string `json:"relationship"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        *int64 `json:"endTime,omitempty"`
+	OnDuty         bool   `json:"onDuty"`
+	RouteReminders bool   `json:"routeReminders"`
+	HandoffNote    string `json:"handoffNote,omitempty"`
+}
+
+// StartShiftResponse 开始值班响应
+type StartShiftResponse struct {
+	Shift   *CaregiverShiftDTO `json:"shift"`
+	Handoff *HandoffSummaryDTO `json:"handoff"` // 上一位照护人值班以来的交接摘要
+}
+
+// EndShiftResponse 结束值班响应
+type EndShiftResponse struct {
+	Shift   *CaregiverShiftDTO `json:"shift"`
+	Handoff *HandoffSummaryDTO `json:"handoff,omitempty"` // 指定接班照护人时返回推送的交接摘要
+	Pushed  bool               `json:"pushed"`            // 是否已向接班照护人推送(对方未授权订阅消息时为 false)
+}
+
+// HandoffSummaryDTO 交接摘要: 上一位照护人开始值班以来的记录和待办提醒
+// 只包含接班照护人有查看权限的记录类型
+type HandoffSummaryDTO struct {
+	BabyID           string               `json:"babyId"`
+	Since            int64                `json:"since"` // 汇总起点(毫秒时间戳)
+	GeneratedAt      int64                `json:"generatedAt"`
+	PreviousShift    *CaregiverShiftDTO   `json:"previousShift,omitempty"` // 上一位照护人的值班,没有值班记录时为空
+	LastFeeding      *HandoffRecordDTO    `json:"lastFeeding,omitempty"`   // 最近一次喂养(不限于汇总区间)
+	CurrentSleep     *HandoffRecordDTO    `json:"currentSleep,omitempty"`  // 进行中的睡眠
+	Counts           HandoffCountsDTO     `json:"counts"`
+	Records          []HandoffRecordDTO   `json:"records"` // 汇总区间内的记录(按时间倒序)
+	PendingReminders []HandoffReminderDTO `json:"pendingReminders"`
+	Notices          []string             `json:"notices"` // 需要留意的情况
+}
+
+// HandoffCountsDTO 交接区间内的记录统计
+type HandoffCountsDTO struct {
+	Feedings     int   `json:"feedings"`
+	BottleAmount int64 `json:"bottleAmount"` // 奶瓶总量(ml)
+	Sleeps       int   `json:"sleeps"`
+	SleepMinutes int   `json:"sleepMinutes"` // 已结束睡眠的总时长(分钟)
+	Diapers      int   `json:"diapers"`
+	WetDiapers   int   `json:"wetDiapers"`   // 含小便
+	DirtyDiapers int   `json:"dirtyDiapers"` // 含大便
+}
+
+// HandoffRecordDTO 交接摘要中的一条记录
+type HandoffRecordDTO struct {
+	RecordType    string `json:"recordType"` // "feeding" | "sleep" | "diaper" | "growth" | "milestone"
+	RecordID      string `json:"recordId"`
+	EventTime     int64  `json:"eventTime"`
+	EndTime       *int64 `json:"endTime,omitempty"` // 睡眠结束时间
+	Summary       string `json:"summary"`           // 简要描述,如 "奶瓶 120ml"
+	Note          string `json:"note,omitempty"`
+	CreatedByName string `json:"createdByName,omitempty"`
+}
+
+// HandoffReminderDTO 交接时仍待处理的提醒
+type HandoffReminderDTO struct {
+	ReminderType string `json:"reminderType"` // "feeding" | "vaccine" | "vaccine_followup"
+	Title        string `json:"title"`
+	DueAt        int64  `json:"dueAt"`
+	Overdue      bool   `json:"overdue"`
+}
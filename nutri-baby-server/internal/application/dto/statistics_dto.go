@@ -69,12 +69,66 @@ type WeeklyStatistics struct {
 	Feeding WeeklyFeedingStats `json:"feeding"` // 喂养统计
 	Sleep   WeeklySleepStats   `json:"sleep"`   // 睡眠统计
 	Growth  WeeklyGrowthStats  `json:"growth"`  // 成长统计
+
+	Caregivers *CaregiverStatsResponse `json:"caregivers,omitempty"` // 照护人分工(includeCaregivers=true 时返回)
 }
 
 // ============ 完整统计响应 ============
 
 // BabyStatisticsResponse 宝宝统计响应
 type BabyStatisticsResponse struct {
-	Today  TodayStatistics  `json:"today"`  // 今日统计
-	Weekly WeeklyStatistics `json:"weekly"` // 本周统计
+	Today   TodayStatistics      `json:"today"`             // 今日统计
+	Weekly  WeeklyStatistics     `json:"weekly"`            // 本周统计
+	Targets *TodayTargetProgress `json:"targets,omitempty"` // 今日目标进度
+}
+
+// StatisticsQuery 宝宝统计查询参数
+type StatisticsQuery struct {
+	IncludeCaregivers bool `form:"includeCaregivers"` // 本周统计中附带照护人分工
+}
+
+// ============ 照护人分工统计 ============
+
+// CaregiverStatsQuery 照护人分工统计查询参数
+type CaregiverStatsQuery struct {
+	StartTime int64  `form:"startTime"` // 开始时间(毫秒时间戳),默认最近7天
+	EndTime   int64  `form:"endTime"`   // 结束时间(毫秒时间戳),默认当前时间
+	Timezone  string `form:"timezone"`  // 划分夜间时段和小时分布使用的时区(IANA 名称,如 Asia/Shanghai),默认服务器时区
+}
+
+// CaregiverStatsResponse 照护人分工统计: 按记录创建者汇总喂养、换尿布、哄睡次数及时段分布
+// 无权查看的记录类型不参与统计
+type CaregiverStatsResponse struct {
+	StartTime      int64                   `json:"startTime"`
+	EndTime        int64                   `json:"endTime"`
+	NightStartHour int                     `json:"nightStartHour"` // 夜间时段开始(时)
+	NightEndHour   int                     `json:"nightEndHour"`   // 夜间时段结束(时)
+	Timezone       string                  `json:"timezone"`       // 统计使用的时区
+	Caregivers     []CaregiverContribution `json:"caregivers"`     // 按总次数倒序
+	Total          CaregiverContribution   `json:"total"`          // 全家合计
+}
+
+// CaregiverContribution 单个照护人的分工统计
+type CaregiverContribution struct {
+	OpenID            string                `json:"openid,omitempty"` // 已离开或导入的记录可能为空
+	NickName          string                `json:"nickName"`
+	AvatarURL         string                `json:"avatarUrl,omitempty"`
+	Relationship      string                `json:"relationship,omitempty"`
+	Feedings          int                   `json:"feedings"`          // 喂养次数
+	NightFeedings     int                   `json:"nightFeedings"`     // 夜间喂养次数
+	Diapers           int                   `json:"diapers"`           // 换尿布次数
+	NightDiapers      int                   `json:"nightDiapers"`      // 夜间换尿布次数
+	SleepSettles      int                   `json:"sleepSettles"`      // 哄睡次数(记录睡眠开始)
+	NightSleepSettles int                   `json:"nightSleepSettles"` // 夜间哄睡次数
+	Share             float64               `json:"share"`             // 占全家次数的百分比
+	NightShare        float64               `json:"nightShare"`        // 占全家夜间次数的百分比
+	Hourly            []CaregiverHourlyStat `json:"hourly"`            // 24 小时分布
+}
+
+// CaregiverHourlyStat 某个小时内的次数
+type CaregiverHourlyStat struct {
+	Hour         int `json:"hour"` // 0-23
+	Feedings     int `json:"feedings"`
+	Diapers      int `json:"diapers"`
+	SleepSettles int `json:"sleepSettles"`
 }
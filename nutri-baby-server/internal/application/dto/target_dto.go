@@ -0,0 +1,61 @@
+package dto
+
+// 目标来源
+const (
+	TargetSourceRecommended = "recommended" // 系统推荐(根据体重/月龄)
+	TargetSourceCustom      = "custom"      // 看护人自定义
+)
+
+// 目标达成状态
+const (
+	TargetStatusBelow  = "below"  // 低于目标
+	TargetStatusWithin = "within" // 在目标范围内
+	TargetStatusAbove  = "above"  // 高于目标
+)
+
+// TargetRange 目标区间
+type TargetRange struct {
+	Min    float64 `json:"min"`    // 下限
+	Max    float64 `json:"max"`    // 上限
+	Unit   string  `json:"unit"`   // 单位: ml, 次, min
+	Source string  `json:"source"` // 来源: recommended/custom
+}
+
+// BabyTargetsResponse 宝宝每日目标响应
+type BabyTargetsResponse struct {
+	BabyID      string      `json:"babyId"`
+	AgeInDays   int         `json:"ageInDays"`           // 月龄计算依据:出生天数
+	WeightKg    *float64    `json:"weightKg,omitempty"`  // 计算依据:最新体重(kg)
+	MilkMl      TargetRange `json:"milkMl"`              // 每日奶量(ml)
+	FeedCount   TargetRange `json:"feedCount"`           // 每日喂养次数
+	SleepMinute TargetRange `json:"sleepMinute"`         // 每日睡眠时长(分钟)
+	HasOverride bool        `json:"hasOverride"`         // 是否存在自定义覆盖
+	UpdatedAt   int64       `json:"updatedAt,omitempty"` // 覆盖配置更新时间(毫秒时间戳)
+}
+
+// UpdateBabyTargetsRequest 更新宝宝每日目标请求
+// 字段为空表示该项使用系统推荐值
+type UpdateBabyTargetsRequest struct {
+	MilkMinMl       *int64 `json:"milkMinMl" binding:"omitempty,min=0,max=3000"`
+	MilkMaxMl       *int64 `json:"milkMaxMl" binding:"omitempty,min=0,max=3000"`
+	FeedCountMin    *int   `json:"feedCountMin" binding:"omitempty,min=0,max=30"`
+	FeedCountMax    *int   `json:"feedCountMax" binding:"omitempty,min=0,max=30"`
+	SleepMinMinutes *int   `json:"sleepMinMinutes" binding:"omitempty,min=0,max=1440"`
+	SleepMaxMinutes *int   `json:"sleepMaxMinutes" binding:"omitempty,min=0,max=1440"`
+}
+
+// TargetProgress 单项目标进度
+type TargetProgress struct {
+	Current float64     `json:"current"` // 当前值
+	Target  TargetRange `json:"target"`  // 目标区间
+	Percent float64     `json:"percent"` // 相对目标下限的完成百分比
+	Status  string      `json:"status"`  // below/within/above
+}
+
+// TodayTargetProgress 今日目标进度
+type TodayTargetProgress struct {
+	MilkMl           TargetProgress `json:"milkMl"`           // 奶量进度(奶瓶实际奶量 + 亲喂估算奶量)
+	BreastMlEstimate float64        `json:"breastMlEstimate"` // 奶量进度中亲喂的估算奶量(ml)
+	FeedCount        TargetProgress `json:"feedCount"`        // 喂养次数进度
+	SleepMinute      TargetProgress `json:"sleepMinute"`      // 睡眠时长进度
+}
@@ -5,13 +5,13 @@ type TimelineQuery struct {
 	BabyID     string `form:"babyId" binding:"required"`
 	StartTime  int64  `form:"startTime"`
 	EndTime    int64  `form:"endTime"`
-	RecordType string `form:"recordType"` // 可选: "feeding" | "sleep" | "diaper" | "growth" | "" (空表示全部)
+	RecordType string `form:"recordType"` // 可选: "feeding" | "sleep" | "diaper" | "growth" | "milestone" | "" (空表示全部)
 	PaginationRequest
 }
 
 // TimelineItem 时间线记录项
 type TimelineItem struct {
-	RecordType   string `json:"recordType"` // "feeding" | "sleep" | "diaper" | "growth"
+	RecordType   string `json:"recordType"` // "feeding" | "sleep" | "diaper" | "growth" | "milestone"
 	RecordID     string `json:"recordId"`
 	BabyID       string `json:"babyId"`
 	EventTime    int64  `json:"eventTime"` // 统一时间戳
@@ -20,6 +20,10 @@ type TimelineItem struct {
 	CreateTime   int64  `json:"createTime"`
 	CreateName   string `json:"createName"`   // 创建者昵称
 	Relationship string `json:"relationship"` // 创建者与宝宝的关系
+
+	CommentCount   int                  `json:"commentCount"`             // 评论总数(含回复)
+	RecentComments []CommentDTO         `json:"recentComments,omitempty"` // 最近的顶层评论(最多3条,按时间正序)
+	Reactions      []ReactionSummaryDTO `json:"reactions,omitempty"`      // 表情回应
 }
 
 // TimelineResponse 时间线响应
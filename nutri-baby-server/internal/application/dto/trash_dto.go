@@ -0,0 +1,45 @@
+package dto
+
+// TrashQuery 回收站查询参数
+type TrashQuery struct {
+	RecordType string `form:"recordType" binding:"omitempty,oneof=feeding sleep diaper growth milestone vaccine"` // 可选,空表示全部
+	PaginationRequest
+}
+
+// TrashRecordParams 回收站记录路径参数
+type TrashRecordParams struct {
+	BabyID     string `uri:"babyId" binding:"required"`
+	RecordType string `uri:"recordType" binding:"required,oneof=feeding sleep diaper growth milestone vaccine"`
+	RecordID   string `uri:"recordId" binding:"required"`
+}
+
+// TrashItemDTO 回收站中的已删除记录
+type TrashItemDTO struct {
+	RecordType    string         `json:"recordType"`
+	RecordID      string         `json:"recordId"`
+	EventTime     int64          `json:"eventTime"` // 记录时间
+	Detail        map[string]any `json:"detail"`    // 删除前的记录内容
+	CreateBy      string         `json:"createBy"`
+	CreateName    string         `json:"createName"`
+	DeletedAt     int64          `json:"deletedAt"`
+	DeletedBy     string         `json:"deletedBy,omitempty"` // 删除人(来自修订历史,功能上线前删除的记录为空)
+	DeletedByName string         `json:"deletedByName,omitempty"`
+	PurgeAt       int64          `json:"purgeAt"` // 到期彻底删除的时间
+}
+
+// TrashResponse 回收站列表(按删除时间倒序)
+type TrashResponse struct {
+	Items         []TrashItemDTO `json:"items"`
+	Total         int64          `json:"total"`
+	Page          int            `json:"page"`
+	PageSize      int            `json:"pageSize"`
+	RetentionDays int            `json:"retentionDays"` // 保留天数
+}
+
+// RestoredRecordDTO 从回收站恢复的记录
+type RestoredRecordDTO struct {
+	RecordType string         `json:"recordType"`
+	RecordID   string         `json:"recordId"`
+	EventTime  int64          `json:"eventTime"`
+	Detail     map[string]any `json:"detail"`
+}
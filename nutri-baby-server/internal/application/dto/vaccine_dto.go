@@ -107,6 +107,8 @@ type VaccineScheduleDTO struct {
 	DoseNumber        int     `json:"doseNumber"`
 	IsRequired        bool    `json:"isRequired"`
 	ReminderDays      int     `json:"reminderDays"`
+	MinAgeDays        int     `json:"minAgeDays,omitempty"`      // 最小接种日龄(天)
+	MinIntervalDays   int     `json:"minIntervalDays,omitempty"` // 与上一剂最小间隔(天)
 	IsCustom          bool    `json:"isCustom"`
 	VaccinationStatus string  `json:"vaccinationStatus"` // pending, completed, skipped
 	// 接种记录信息(仅在 status='completed' 时有值)
@@ -120,6 +122,10 @@ type VaccineScheduleDTO struct {
 	CompletedByName   *string `json:"completedByName,omitempty"`
 	CompletedByAvatar *string `json:"completedByAvatar,omitempty"`
 	CompletedTime     *int64  `json:"completedTime,omitempty"`
+	// 接种后反应随访汇总
+	ReactionSeverity  string  `json:"reactionSeverity,omitempty"` // none, mild, moderate, severe
+	ReactionSummary   *string `json:"reactionSummary,omitempty"`
+	ReactionAlert     string  `json:"reactionAlert,omitempty"` // 前一剂出现重度反应,接种前需告知医生
 	CreateBy          string  `json:"createBy"`
 	CreateTime        int64   `json:"createTime"`
 }
@@ -147,6 +153,12 @@ type UpdateVaccineScheduleRequest struct {
 	VaccinationStatus string  `json:"vaccinationStatus"` // pending, completed, skipped
 }
 
+// UpdateVaccineScheduleResultDTO 记录接种结果
+type UpdateVaccineScheduleResultDTO struct {
+	Warnings    []string               `json:"warnings"`    // 接种时间早于最小日龄/最小间隔等提示(不阻止记录)
+	Rescheduled []VaccinePlanChangeDTO `json:"rescheduled"` // 因补种规则顺延的后续剂次
+}
+
 // UpdateScheduleInfoRequest 更新疫苗接种日程基本信息请求(仅限未完成的日程)
 type UpdateScheduleInfoRequest struct {
 	VaccineType  *string `json:"vaccineType"`  // 疫苗类型
@@ -1,59 +1,66 @@
 package dto
 
-// VaccinePlanDTO 疫苗计划响应DTO
+// VaccinePlanDTO 疫苗计划DTO
 type VaccinePlanDTO struct {
-	PlanID        string `json:"planId"`
-	VaccineType   string `json:"vaccineType"`
-	VaccineName   string `json:"vaccineName"`
-	Description   string `json:"description"`
-	AgeInMonths   int    `json:"ageInMonths"`
-	DoseNumber    int    `json:"doseNumber"`
-	IsRequired    bool   `json:"isRequired"`
-	ReminderDays  int    `json:"reminderDays"`
-	IsCustom      bool   `json:"isCustom"`                // 是否用户自定义
-	TemplateID    string `json:"templateId"`              // 来源模板ID (可选)
-	ScheduledDate int64  `json:"scheduledDate,omitempty"` // 预定日期(根据宝宝出生日期计算,可选)
-	Status        string `json:"status,omitempty"`        // 状态: pending/completed/overdue (可选)
+	PlanID      string `json:"planId"`
+	Code        string `json:"code"`
+	Version     int    `json:"version"`
+	Name        string `json:"name"`
+	Region      string `json:"region"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"isDefault"`
+	CreateTime  int64  `json:"createTime"`
 }
 
-// CreateBabyVaccinePlanRequest 创建宝宝疫苗计划请求
-type CreateBabyVaccinePlanRequest struct {
-	VaccineType  string `json:"vaccineType" binding:"required"`
-	VaccineName  string `json:"vaccineName" binding:"required"`
-	Description  string `json:"description"`
-	AgeInMonths  int    `json:"ageInMonths" binding:"required,min=0"`
-	DoseNumber   int    `json:"doseNumber" binding:"required,min=1"`
-	IsRequired   bool   `json:"isRequired"`
-	ReminderDays int    `json:"reminderDays" binding:"min=0,max=30"`
+// VaccinePlanItemDTO 疫苗计划中的一剂疫苗
+type VaccinePlanItemDTO struct {
+	TemplateID      string `json:"templateId"`
+	VaccineType     string `json:"vaccineType"`
+	VaccineName     string `json:"vaccineName"`
+	Description     string `json:"description"`
+	AgeInMonths     int    `json:"ageInMonths"`
+	DoseNumber      int    `json:"doseNumber"`
+	IsRequired      bool   `json:"isRequired"`
+	ReminderDays    int    `json:"reminderDays"`
+	MinAgeDays      int    `json:"minAgeDays"`      // 最小接种日龄(天)
+	MinIntervalDays int    `json:"minIntervalDays"` // 与上一剂最小间隔(天)
 }
 
-// UpdateBabyVaccinePlanRequest 更新宝宝疫苗计划请求
-type UpdateBabyVaccinePlanRequest struct {
-	VaccineName  string `json:"vaccineName"`
-	Description  string `json:"description"`
-	AgeInMonths  int    `json:"ageInMonths" binding:"min=0"`
-	DoseNumber   int    `json:"doseNumber" binding:"min=1"`
-	IsRequired   bool   `json:"isRequired"`
-	ReminderDays int    `json:"reminderDays" binding:"min=0,max=30"`
+// VaccinePlanDetailDTO 疫苗计划详情
+type VaccinePlanDetailDTO struct {
+	VaccinePlanDTO
+	Items []VaccinePlanItemDTO `json:"items"`
 }
 
-// InitializeVaccinePlansRequest 初始化疫苗计划请求
-type InitializeVaccinePlansRequest struct {
-	// 可选:是否强制重新初始化(删除已有计划)
-	Force bool `json:"force"`
+// ListVaccinePlansRequest 疫苗计划列表请求
+type ListVaccinePlansRequest struct {
+	Region string `form:"region"` // 地区,为空时返回全部
 }
 
-// InitializeVaccinePlansResponse 初始化疫苗计划响应
-type InitializeVaccinePlansResponse struct {
-	TotalPlans int              `json:"totalPlans"` // 初始化的计划总数
-	Plans      []VaccinePlanDTO `json:"plans"`      // 计划列表
-	Message    string           `json:"message"`    // 提示信息
+// SwitchVaccinePlanRequest 切换宝宝疫苗计划请求
+type SwitchVaccinePlanRequest struct {
+	PlanID string `json:"planId" binding:"required"`
+	DryRun bool   `json:"dryRun"` // 只返回调整预览,不保存
 }
 
-// GetVaccinePlansResponse 获取疫苗计划列表响应
-type GetVaccinePlansResponse struct {
-	Plans      []VaccinePlanDTO `json:"plans"`
-	Total      int              `json:"total"`
-	Completed  int              `json:"completed"`
-	Percentage int              `json:"percentage"`
+// VaccinePlanChangeDTO 日程调整明细
+type VaccinePlanChangeDTO struct {
+	ScheduleID        string `json:"scheduleId,omitempty"` // 新增的日程在保存前没有ID
+	VaccineType       string `json:"vaccineType"`
+	VaccineName       string `json:"vaccineName"`
+	DoseNumber        int    `json:"doseNumber"`
+	VaccinationStatus string `json:"vaccinationStatus"`
+	ScheduledDate     int64  `json:"scheduledDate"`
+}
+
+// VaccinePlanReconcileDTO 日程调整结果
+// added: 新增的待接种日程; updated: 按新计划调整的待接种日程; removed: 新计划中没有的待接种日程;
+// kept: 已接种、已跳过或自定义的日程,保持不变
+type VaccinePlanReconcileDTO struct {
+	Plan    *VaccinePlanDTO        `json:"plan"`
+	DryRun  bool                   `json:"dryRun"`
+	Added   []VaccinePlanChangeDTO `json:"added"`
+	Updated []VaccinePlanChangeDTO `json:"updated"`
+	Removed []VaccinePlanChangeDTO `json:"removed"`
+	Kept    []VaccinePlanChangeDTO `json:"kept"`
 }
@@ -0,0 +1,32 @@
+package dto
+
+// VaccineReactionFollowUpDTO 接种后反应随访DTO
+type VaccineReactionFollowUpDTO struct {
+	FollowUpID      string   `json:"followUpId"`
+	ScheduleID      string   `json:"scheduleId"`
+	CheckpointHours int      `json:"checkpointHours"` // 接种后第几小时
+	DueAt           int64    `json:"dueAt"`
+	PromptSentAt    *int64   `json:"promptSentAt,omitempty"`
+	TemperatureC    *float64 `json:"temperatureC,omitempty"`
+	Swelling        string   `json:"swelling,omitempty"`
+	Fussiness       string   `json:"fussiness,omitempty"`
+	Note            *string  `json:"note,omitempty"`
+	RecordedBy      *string  `json:"recordedBy,omitempty"`
+	RecordedAt      *int64   `json:"recordedAt,omitempty"`
+}
+
+// VaccineReactionFollowUpListDTO 日程的全部随访及汇总
+type VaccineReactionFollowUpListDTO struct {
+	ScheduleID       string                       `json:"scheduleId"`
+	ReactionSeverity string                       `json:"reactionSeverity,omitempty"`
+	ReactionSummary  *string                      `json:"reactionSummary,omitempty"`
+	FollowUps        []VaccineReactionFollowUpDTO `json:"followUps"`
+}
+
+// RecordVaccineReactionRequest 记录接种反应随访请求
+type RecordVaccineReactionRequest struct {
+	TemperatureC *float64 `json:"temperatureC"`                 // 体温(℃),可选
+	Swelling     string   `json:"swelling" binding:"required"`  // 接种部位红肿: none/mild/moderate/severe
+	Fussiness    string   `json:"fussiness" binding:"required"` // 哭闹烦躁: none/mild/moderate/severe
+	Note         *string  `json:"note"`                         // 备注(可选)
+}
@@ -0,0 +1,52 @@
+package fhir
+
+import "strings"
+
+// vaccineCode 本系统疫苗类型对应的 CVX 编码
+type vaccineCode struct {
+	CVX     string
+	Display string
+}
+
+// vaccineCodes 疫苗类型 → CVX(有多种剂型的疫苗取"未指定剂型"的通用编码)
+var vaccineCodes = map[string]vaccineCode{
+	"BCG":          {"19", "BCG"},
+	"HepB":         {"45", "Hep B, unspecified formulation"},
+	"OPV":          {"182", "OPV, Unspecified"},
+	"IPV":          {"10", "IPV"},
+	"DTaP":         {"107", "DTaP, unspecified formulation"},
+	"MR":           {"04", "M/R"},
+	"MMR":          {"03", "MMR"},
+	"JE":           {"129", "Japanese Encephalitis vaccine, unspecified formulation"},
+	"MeningAC":     {"108", "meningococcal, unknown serogroups"},
+	"MeningB":      {"164", "meningococcal B, unspecified"},
+	"HepA":         {"85", "Hep A, unspecified formulation"},
+	"Varicella":    {"21", "varicella"},
+	"Pneumococcal": {"109", "pneumococcal, unspecified formulation"},
+	"Rotavirus":    {"122", "rotavirus, unspecified formulation"},
+	"Hib":          {"17", "Hib, unspecified formulation"},
+	"Influenza":    {"88", "influenza, unspecified formulation"},
+}
+
+// vaccineTypeByCVX CVX(去除前导零) → 疫苗类型
+var vaccineTypeByCVX = func() map[string]string {
+	m := make(map[string]string, len(vaccineCodes))
+	for vaccineType, code := range vaccineCodes {
+		m[strings.TrimLeft(code.CVX, "0")] = vaccineType
+	}
+	return m
+}()
+
+// growthMeasure 成长测量项
+type growthMeasure struct {
+	Key     string // 资源ID后缀
+	LOINC   string
+	Display string
+	Unit    string // UCUM 单位
+}
+
+var (
+	measureWeight = growthMeasure{"weight", LOINCBodyWeight, "Body weight", "kg"}
+	measureHeight = growthMeasure{"height", LOINCBodyHeight, "Body height", "cm"}
+	measureHead   = growthMeasure{"head", LOINCHeadCircumference, "Head Occipital-frontal circumference", "cm"}
+)
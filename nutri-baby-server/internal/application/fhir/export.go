@@ -0,0 +1,204 @@
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// reactionNotePrefix 接种反应写入 Immunization.note 时的前缀,导入时据此还原
+const reactionNotePrefix = "接种反应: "
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	// Location 时间输出使用的时区,为空时使用服务器本地时区
+	Location *time.Location
+	// Now Bundle 生成时间,为零值时使用 time.Now
+	Now time.Time
+}
+
+// NewBundle 将宝宝、疫苗接种日程和成长记录转换为 FHIR R4 collection Bundle
+// 仅导出已完成且有接种日期的疫苗日程;成长记录中每个非空测量值各生成一个 Observation
+func NewBundle(
+	baby *entity.Baby,
+	schedules []*entity.BabyVaccineSchedule,
+	growths []*entity.GrowthRecord,
+	opts ExportOptions,
+) (*Bundle, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	babyKey := strconv.FormatInt(baby.ID, 10)
+	b := &bundleBuilder{
+		bundle: &Bundle{
+			ResourceType: "Bundle",
+			ID:           "nutri-baby-" + babyKey,
+			Type:         "collection",
+			Timestamp:    now.In(loc).Format(time.RFC3339),
+		},
+		locations: make(map[string]string),
+	}
+
+	patientURL := uuidURN("patient", babyKey)
+	if err := b.add(patientURL, newPatient(baby)); err != nil {
+		return nil, err
+	}
+	patientRef := Reference{Reference: patientURL, Display: baby.Name}
+
+	for _, s := range schedules {
+		if !s.IsCompleted() || s.VaccineDate == nil {
+			continue
+		}
+		immunization := newImmunization(s, patientRef, loc)
+		if s.Hospital != nil && strings.TrimSpace(*s.Hospital) != "" {
+			ref, err := b.location(strings.TrimSpace(*s.Hospital))
+			if err != nil {
+				return nil, err
+			}
+			immunization.Location = ref
+		}
+		if err := b.add(uuidURN("immunization", strconv.FormatInt(s.ID, 10)), immunization); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, g := range growths {
+		for _, m := range []struct {
+			measure growthMeasure
+			value   *float64
+		}{
+			{measureWeight, g.Weight},
+			{measureHeight, g.Height},
+			{measureHead, g.HeadCircumference},
+		} {
+			if m.value == nil || *m.value <= 0 {
+				continue
+			}
+			key := fmt.Sprintf("%d-%s", g.ID, m.measure.Key)
+			observation := newObservation(key, g, m.measure, *m.value, patientRef, loc)
+			if err := b.add(uuidURN("observation", key), observation); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b.bundle, nil
+}
+
+// bundleBuilder 逐个追加 Bundle 条目,同名医院只生成一个 Location
+type bundleBuilder struct {
+	bundle    *Bundle
+	locations map[string]string // 医院名称 → fullUrl
+}
+
+func (b *bundleBuilder) add(fullURL string, resource any) error {
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	b.bundle.Entry = append(b.bundle.Entry, BundleEntry{FullURL: fullURL, Resource: raw})
+	return nil
+}
+
+func (b *bundleBuilder) location(name string) (*Reference, error) {
+	fullURL, ok := b.locations[name]
+	if !ok {
+		fullURL = uuidURN("location", name)
+		b.locations[name] = fullURL
+		location := &Location{
+			ResourceType: "Location",
+			ID:           fmt.Sprintf("location-%d", len(b.locations)),
+			Name:         name,
+		}
+		if err := b.add(fullURL, location); err != nil {
+			return nil, err
+		}
+	}
+	return &Reference{Reference: fullURL, Display: name}, nil
+}
+
+func newPatient(baby *entity.Baby) *Patient {
+	patient := &Patient{
+		ResourceType: "Patient",
+		ID:           fmt.Sprintf("baby-%d", baby.ID),
+		Identifier:   []Identifier{{System: SystemBabyID, Value: strconv.FormatInt(baby.ID, 10)}},
+		Gender:       "unknown",
+		BirthDate:    baby.BirthDate,
+	}
+	if baby.Name != "" {
+		patient.Name = append(patient.Name, HumanName{Use: "official", Text: baby.Name})
+	}
+	if baby.Nickname != "" && baby.Nickname != baby.Name {
+		patient.Name = append(patient.Name, HumanName{Use: "nickname", Text: baby.Nickname})
+	}
+	if baby.Gender == "male" || baby.Gender == "female" {
+		patient.Gender = baby.Gender
+	}
+	return patient
+}
+
+func newImmunization(s *entity.BabyVaccineSchedule, patient Reference, loc *time.Location) *Immunization {
+	primarySource := false
+	immunization := &Immunization{
+		ResourceType:       "Immunization",
+		ID:                 fmt.Sprintf("immunization-%d", s.ID),
+		Status:             "completed",
+		VaccineCode:        CodeableConcept{Text: s.VaccineName},
+		Patient:            patient,
+		OccurrenceDateTime: time.UnixMilli(*s.VaccineDate).In(loc).Format("2006-01-02"),
+		PrimarySource:      &primarySource, // 由家长记录,非接种机构的一手记录
+	}
+	if code, ok := vaccineCodes[s.VaccineType]; ok {
+		immunization.VaccineCode.Coding = append(immunization.VaccineCode.Coding,
+			Coding{System: SystemCVX, Code: code.CVX, Display: code.Display})
+	}
+	if s.VaccineType != "" {
+		immunization.VaccineCode.Coding = append(immunization.VaccineCode.Coding,
+			Coding{System: SystemVaccineType, Code: s.VaccineType, Display: s.VaccineName})
+	}
+	if s.BatchNumber != nil {
+		immunization.LotNumber = strings.TrimSpace(*s.BatchNumber)
+	}
+	if s.DoseNumber > 0 {
+		immunization.ProtocolApplied = []ProtocolApplied{{DoseNumberPositiveInt: s.DoseNumber}}
+	}
+	if s.Note != nil && strings.TrimSpace(*s.Note) != "" {
+		immunization.Note = append(immunization.Note, Annotation{Text: strings.TrimSpace(*s.Note)})
+	}
+	if s.Reaction != nil && strings.TrimSpace(*s.Reaction) != "" {
+		immunization.Note = append(immunization.Note, Annotation{Text: reactionNotePrefix + strings.TrimSpace(*s.Reaction)})
+	}
+	return immunization
+}
+
+func newObservation(key string, g *entity.GrowthRecord, m growthMeasure, value float64, patient Reference, loc *time.Location) *Observation {
+	observation := &Observation{
+		ResourceType: "Observation",
+		ID:           "observation-" + key,
+		Status:       "final",
+		Category: []CodeableConcept{{
+			Coding: []Coding{{System: SystemObsCategory, Code: "vital-signs", Display: "Vital Signs"}},
+		}},
+		Code: CodeableConcept{
+			Coding: []Coding{{System: SystemLOINC, Code: m.LOINC, Display: m.Display}},
+			Text:   m.Display,
+		},
+		Subject:           patient,
+		EffectiveDateTime: time.UnixMilli(g.Time).In(loc).Format(time.RFC3339),
+		ValueQuantity:     &Quantity{Value: value, Unit: m.Unit, System: SystemUCUM, Code: m.Unit},
+	}
+	if g.Note != nil && strings.TrimSpace(*g.Note) != "" {
+		observation.Note = []Annotation{{Text: strings.TrimSpace(*g.Note)}}
+	}
+	return observation
+}
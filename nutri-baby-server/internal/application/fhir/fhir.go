@@ -0,0 +1,167 @@
+// Package fhir 宝宝健康数据与 HL7 FHIR R4 的互相转换
+//
+// 导出: 宝宝 → Patient,已完成的疫苗接种 → Immunization(接种医院为 Location),
+// 成长记录的每项测量 → LOINC 编码的 Observation,整体输出为 collection 类型的 Bundle。
+// 导入: 从 Bundle(或单个 Immunization 资源)中解析已完成的接种记录,用于预填疫苗接种史。
+// 这里只实现所需的资源子集,不依赖第三方 FHIR 库。
+package fhir
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+)
+
+// 编码系统
+const (
+	SystemLOINC       = "http://loinc.org"
+	SystemUCUM        = "http://unitsofmeasure.org"
+	SystemCVX         = "http://hl7.org/fhir/sid/cvx"
+	SystemObsCategory = "http://terminology.hl7.org/CodeSystem/observation-category"
+
+	// SystemBabyID 本系统宝宝ID标识符
+	SystemBabyID = "urn:nutri-baby:baby-id"
+	// SystemVaccineType 本系统疫苗类型编码(BCG、HepB 等)
+	SystemVaccineType = "urn:nutri-baby:vaccine-type"
+)
+
+// 成长测量的 LOINC 编码
+const (
+	LOINCBodyWeight        = "29463-7" // Body weight
+	LOINCBodyHeight        = "8302-2"  // Body height(婴儿身长同样使用该编码)
+	LOINCHeadCircumference = "9843-4"  // Head Occipital-frontal circumference
+)
+
+// Bundle FHIR Bundle 资源
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	ID           string        `json:"id,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// BundleEntry Bundle 条目,资源以原始 JSON 保存以便按 resourceType 分别解析
+type BundleEntry struct {
+	FullURL  string          `json:"fullUrl,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// Meta 资源元数据
+type Meta struct {
+	LastUpdated string   `json:"lastUpdated,omitempty"`
+	Profile     []string `json:"profile,omitempty"`
+}
+
+// Identifier 标识符
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// HumanName 姓名
+type HumanName struct {
+	Use  string `json:"use,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// Coding 编码
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// CodeableConcept 可编码概念
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Reference 资源引用
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+// Quantity 带单位的数值
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+// Annotation 备注
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+// Patient 患者
+type Patient struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         []HumanName  `json:"name,omitempty"`
+	Gender       string       `json:"gender,omitempty"` // male, female, other, unknown
+	BirthDate    string       `json:"birthDate,omitempty"`
+}
+
+// Location 地点(接种医院)
+type Location struct {
+	ResourceType string `json:"resourceType"`
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// ProtocolApplied 接种方案中的剂次
+type ProtocolApplied struct {
+	Series                string `json:"series,omitempty"`
+	DoseNumberPositiveInt int    `json:"doseNumberPositiveInt,omitempty"`
+	DoseNumberString      string `json:"doseNumberString,omitempty"`
+}
+
+// Immunization 免疫接种
+type Immunization struct {
+	ResourceType       string            `json:"resourceType"`
+	ID                 string            `json:"id,omitempty"`
+	Status             string            `json:"status"` // completed, entered-in-error, not-done
+	VaccineCode        CodeableConcept   `json:"vaccineCode"`
+	Patient            Reference         `json:"patient"`
+	OccurrenceDateTime string            `json:"occurrenceDateTime,omitempty"`
+	OccurrenceString   string            `json:"occurrenceString,omitempty"`
+	PrimarySource      *bool             `json:"primarySource,omitempty"`
+	Location           *Reference        `json:"location,omitempty"`
+	LotNumber          string            `json:"lotNumber,omitempty"`
+	Note               []Annotation      `json:"note,omitempty"`
+	ProtocolApplied    []ProtocolApplied `json:"protocolApplied,omitempty"`
+}
+
+// Observation 观察(成长测量)
+type Observation struct {
+	ResourceType      string            `json:"resourceType"`
+	ID                string            `json:"id,omitempty"`
+	Status            string            `json:"status"` // final 等
+	Category          []CodeableConcept `json:"category,omitempty"`
+	Code              CodeableConcept   `json:"code"`
+	Subject           Reference         `json:"subject"`
+	EffectiveDateTime string            `json:"effectiveDateTime,omitempty"`
+	ValueQuantity     *Quantity         `json:"valueQuantity,omitempty"`
+	Note              []Annotation      `json:"note,omitempty"`
+}
+
+// resourceHeader 用于识别资源类型
+type resourceHeader struct {
+	ResourceType string `json:"resourceType"`
+	ID           string `json:"id"`
+}
+
+// uuidURN 根据资源类型和本地标识生成稳定的 urn:uuid(基于 SHA-1 的第5版 UUID),
+// 同一数据多次导出得到相同的 fullUrl
+func uuidURN(kind, key string) string {
+	sum := sha1.Sum([]byte("nutri-baby/" + kind + "/" + key))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
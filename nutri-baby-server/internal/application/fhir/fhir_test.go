@@ -0,0 +1,198 @@
+package fhir
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+var cst = time.FixedZone("CST", 8*3600)
+
+func ptr[T any](v T) *T { return &v }
+
+func testData() (*entity.Baby, []*entity.BabyVaccineSchedule, []*entity.GrowthRecord) {
+	baby := &entity.Baby{ID: 101, Name: "小明", Nickname: "明明", Gender: "male", BirthDate: "2024-01-01"}
+	day := func(d string) *int64 {
+		t, _ := time.ParseInLocation("2006-01-02", d, cst)
+		return ptr(t.UnixMilli())
+	}
+	schedules := []*entity.BabyVaccineSchedule{
+		{ID: 1, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 1, VaccinationStatus: entity.VaccinationStatusCompleted,
+			VaccineDate: day("2024-01-01"), Hospital: ptr("市妇幼保健院"), BatchNumber: ptr("B2024001")},
+		{ID: 2, VaccineType: "BCG", VaccineName: "卡介苗", DoseNumber: 1, VaccinationStatus: entity.VaccinationStatusCompleted,
+			VaccineDate: day("2024-01-02"), Hospital: ptr("市妇幼保健院"), Reaction: ptr("局部红肿"), Note: ptr("左上臂")},
+		{ID: 3, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2, VaccinationStatus: entity.VaccinationStatusPending},
+	}
+	growths := []*entity.GrowthRecord{
+		{ID: 11, Time: time.Date(2024, 2, 1, 10, 0, 0, 0, cst).UnixMilli(), Weight: ptr(4.5), Height: ptr(55.0)},
+		{ID: 12, Time: time.Date(2024, 3, 1, 10, 0, 0, 0, cst).UnixMilli(), HeadCircumference: ptr(39.2)},
+	}
+	return baby, schedules, growths
+}
+
+func TestNewBundle(t *testing.T) {
+	baby, schedules, growths := testData()
+	bundle, err := NewBundle(baby, schedules, growths, ExportOptions{Location: cst, Now: time.Date(2024, 3, 2, 8, 0, 0, 0, cst)})
+	require.NoError(t, err)
+	require.NoError(t, Validate(bundle))
+
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	// 按通用 JSON 结构检查,避免只验证本包自己的类型
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "Bundle", doc["resourceType"])
+	assert.Equal(t, "collection", doc["type"])
+	assert.Equal(t, "2024-03-02T08:00:00+08:00", doc["timestamp"])
+
+	entries := doc["entry"].([]any)
+	// Patient + Location(同一医院只有一个) + 2 Immunization + 3 Observation
+	require.Len(t, entries, 7)
+
+	uuidPattern := regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	fullURLs := make(map[string]string)
+	counts := make(map[string]int)
+	for _, e := range entries {
+		entry := e.(map[string]any)
+		fullURL := entry["fullUrl"].(string)
+		assert.Regexp(t, uuidPattern, fullURL)
+		resource := entry["resource"].(map[string]any)
+		resourceType := resource["resourceType"].(string)
+		fullURLs[fullURL] = resourceType
+		counts[resourceType]++
+	}
+	assert.Equal(t, map[string]int{"Patient": 1, "Location": 1, "Immunization": 2, "Observation": 3}, counts)
+
+	resolve := func(ref any) string {
+		return fullURLs[ref.(map[string]any)["reference"].(string)]
+	}
+
+	var loincCodes []string
+	for _, e := range entries {
+		resource := e.(map[string]any)["resource"].(map[string]any)
+		switch resource["resourceType"] {
+		case "Patient":
+			assert.Equal(t, "male", resource["gender"])
+			assert.Equal(t, "2024-01-01", resource["birthDate"])
+		case "Immunization":
+			assert.Equal(t, "completed", resource["status"])
+			assert.Equal(t, "Patient", resolve(resource["patient"]))
+			assert.Equal(t, "Location", resolve(resource["location"]))
+			coding := resource["vaccineCode"].(map[string]any)["coding"].([]any)
+			assert.Equal(t, SystemCVX, coding[0].(map[string]any)["system"])
+		case "Observation":
+			assert.Equal(t, "final", resource["status"])
+			assert.Equal(t, "Patient", resolve(resource["subject"]))
+			coding := resource["code"].(map[string]any)["coding"].([]any)[0].(map[string]any)
+			assert.Equal(t, SystemLOINC, coding["system"])
+			loincCodes = append(loincCodes, coding["code"].(string))
+			quantity := resource["valueQuantity"].(map[string]any)
+			assert.Equal(t, SystemUCUM, quantity["system"])
+			assert.NotEmpty(t, quantity["code"])
+		}
+	}
+	assert.ElementsMatch(t, []string{LOINCBodyWeight, LOINCBodyHeight, LOINCHeadCircumference}, loincCodes)
+
+	// 多次导出 fullUrl 稳定
+	again, err := NewBundle(baby, schedules, growths, ExportOptions{Location: cst})
+	require.NoError(t, err)
+	assert.Equal(t, bundle.Entry[0].FullURL, again.Entry[0].FullURL)
+}
+
+func TestValidateRejectsInvalidBundle(t *testing.T) {
+	raw := `{
+		"resourceType": "Bundle",
+		"type": "unknown",
+		"entry": [
+			{"fullUrl": "urn:uuid:a", "resource": {"resourceType": "Patient", "id": "p 1", "gender": "boy", "birthDate": "2024/01/01"}},
+			{"fullUrl": "urn:uuid:a", "resource": {"resourceType": "Immunization", "status": "done", "vaccineCode": {},
+				"patient": {"reference": "urn:uuid:missing"}, "protocolApplied": [{}]}},
+			{"resource": {"resourceType": "Observation", "status": "final", "code": {"coding": [{"code": "29463-7"}]},
+				"subject": {"reference": "Location/1"}, "effectiveDateTime": "yesterday",
+				"valueQuantity": {"value": 1, "system": "http://unitsofmeasure.org"}}}
+		]
+	}`
+	var bundle Bundle
+	require.NoError(t, json.Unmarshal([]byte(raw), &bundle))
+
+	err := Validate(&bundle)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	problems := strings.Join(verr.Problems, "\n")
+	for _, want := range []string{
+		"Bundle.type", "resource.id", "duplicated", "gender", "birthDate",
+		"immunization status", "vaccineCode must have coding or text", "does not resolve",
+		"occurrence[x] is required", "doseNumber[x]", "system is required", "must reference a Patient",
+		"effectiveDateTime", "valueQuantity.code",
+	} {
+		assert.Contains(t, problems, want)
+	}
+}
+
+func TestParseImmunizationsRoundTrip(t *testing.T) {
+	baby, schedules, growths := testData()
+	bundle, err := NewBundle(baby, schedules, growths, ExportOptions{Location: cst})
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	result, err := ParseImmunizations(data, cst)
+	require.NoError(t, err)
+	require.Len(t, result.Records, 2)
+	assert.Empty(t, result.Errors)
+
+	hepB := result.Records[0]
+	assert.Equal(t, "HepB", hepB.VaccineType)
+	assert.Equal(t, "乙肝疫苗", hepB.VaccineName)
+	assert.Equal(t, 1, hepB.DoseNumber)
+	assert.Equal(t, "B2024001", hepB.LotNumber)
+	assert.Equal(t, "市妇幼保健院", hepB.Hospital)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, cst), hepB.Date)
+
+	bcg := result.Records[1]
+	assert.Equal(t, "局部红肿", bcg.Reaction)
+	assert.Equal(t, "左上臂", bcg.Note)
+}
+
+func TestParseImmunizationsFromClinic(t *testing.T) {
+	raw := `{
+		"resourceType": "Bundle",
+		"type": "searchset",
+		"entry": [
+			{"resource": {"resourceType": "Immunization", "id": "im1", "status": "completed",
+				"vaccineCode": {"coding": [{"system": "http://hl7.org/fhir/sid/cvx", "code": "3", "display": "MMR"}]},
+				"patient": {"reference": "Patient/123"}, "occurrenceDateTime": "2025-01-02T09:30:00Z",
+				"location": {"display": "社区卫生服务中心"}, "protocolApplied": [{"doseNumberString": "1"}]}},
+			{"resource": {"resourceType": "Immunization", "id": "im2", "status": "not-done",
+				"vaccineCode": {"text": "水痘疫苗"}, "patient": {"reference": "Patient/123"}, "occurrenceDateTime": "2025-01-02"}},
+			{"resource": {"resourceType": "Immunization", "id": "im3", "status": "completed",
+				"vaccineCode": {"text": "流感疫苗"}, "patient": {"reference": "Patient/123"}, "occurrenceDateTime": "2025-01"}}
+		]
+	}`
+
+	result, err := ParseImmunizations([]byte(raw), cst)
+	require.NoError(t, err)
+	require.Len(t, result.Records, 1)
+	assert.Equal(t, 1, result.Skipped)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Entry)
+
+	mmr := result.Records[0]
+	assert.Equal(t, "MMR", mmr.VaccineType)
+	assert.Equal(t, "MMR", mmr.VaccineName)
+	assert.Equal(t, 1, mmr.DoseNumber)
+	assert.Equal(t, "社区卫生服务中心", mmr.Hospital)
+	assert.Equal(t, time.Date(2025, 1, 2, 17, 30, 0, 0, cst), mmr.Date)
+
+	_, err = ParseImmunizations([]byte(`{"resourceType": "Patient"}`), cst)
+	assert.Error(t, err)
+}
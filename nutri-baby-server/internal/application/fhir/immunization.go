@@ -0,0 +1,188 @@
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImmunizationRecord 从 FHIR 解析出的一条已完成接种记录
+type ImmunizationRecord struct {
+	Entry       int       // Bundle 条目序号(从1开始)
+	ResourceID  string    // Immunization.id
+	VaccineType string    // 本系统疫苗类型,无法识别时为空
+	VaccineName string    // 疫苗名称
+	DoseNumber  int       // 剂次,0 表示未知
+	Date        time.Time // 接种日期
+	LotNumber   string    // 批次号
+	Hospital    string    // 接种地点
+	Note        string    // 备注
+	Reaction    string    // 接种反应
+}
+
+// ImmunizationError 单个 Immunization 的解析错误
+type ImmunizationError struct {
+	Entry   int
+	Message string
+}
+
+// ImmunizationResult 解析结果
+type ImmunizationResult struct {
+	Records []ImmunizationRecord
+	Errors  []ImmunizationError
+	Skipped int // 状态不是 completed 的接种(not-done、entered-in-error)
+}
+
+// ParseImmunizations 解析 Immunization Bundle(或单个 Immunization 资源)
+// Bundle 先经过结构校验,校验失败返回 *ValidationError;无法解析日期或疫苗的条目记入 Errors。
+// 仅含日期的 occurrenceDateTime 按 loc 时区的零点解析
+func ParseImmunizations(data []byte, loc *time.Location) (*ImmunizationResult, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var header resourceHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid FHIR JSON: %w", err)
+	}
+
+	var bundle Bundle
+	switch header.ResourceType {
+	case "Bundle":
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("invalid FHIR bundle: %w", err)
+		}
+	case "Immunization":
+		bundle = Bundle{ResourceType: "Bundle", Type: "collection", Entry: []BundleEntry{{Resource: data}}}
+	default:
+		return nil, fmt.Errorf("unsupported FHIR resource type %q, expected Bundle or Immunization", header.ResourceType)
+	}
+	if err := Validate(&bundle); err != nil {
+		return nil, err
+	}
+
+	// 接种地点可能以引用指向 Bundle 内的 Location
+	locations := make(map[string]string)
+	for _, entry := range bundle.Entry {
+		var l Location
+		if json.Unmarshal(entry.Resource, &l) != nil || l.ResourceType != "Location" || l.Name == "" {
+			continue
+		}
+		if entry.FullURL != "" {
+			locations[entry.FullURL] = l.Name
+		}
+		if l.ID != "" {
+			locations["Location/"+l.ID] = l.Name
+		}
+	}
+
+	result := &ImmunizationResult{}
+	for i, entry := range bundle.Entry {
+		var im Immunization
+		if json.Unmarshal(entry.Resource, &im) != nil || im.ResourceType != "Immunization" {
+			continue
+		}
+		if im.Status != "completed" {
+			result.Skipped++
+			continue
+		}
+		record, err := immunizationRecord(&im, locations, loc)
+		if err != nil {
+			result.Errors = append(result.Errors, ImmunizationError{Entry: i + 1, Message: err.Error()})
+			continue
+		}
+		record.Entry = i + 1
+		result.Records = append(result.Records, *record)
+	}
+	return result, nil
+}
+
+func immunizationRecord(im *Immunization, locations map[string]string, loc *time.Location) (*ImmunizationRecord, error) {
+	record := &ImmunizationRecord{
+		ResourceID: im.ID,
+		LotNumber:  strings.TrimSpace(im.LotNumber),
+	}
+
+	if im.OccurrenceDateTime == "" {
+		return nil, fmt.Errorf("接种日期不明确: %q", im.OccurrenceString)
+	}
+	date, err := parseDateTime(im.OccurrenceDateTime, loc)
+	if err != nil {
+		return nil, err
+	}
+	record.Date = date
+
+	for _, coding := range im.VaccineCode.Coding {
+		if coding.System == SystemVaccineType && coding.Code != "" {
+			record.VaccineType = coding.Code
+			break
+		}
+	}
+	if record.VaccineType == "" {
+		for _, coding := range im.VaccineCode.Coding {
+			if coding.System == SystemCVX {
+				record.VaccineType = vaccineTypeByCVX[strings.TrimLeft(strings.TrimSpace(coding.Code), "0")]
+			}
+			if record.VaccineType != "" {
+				break
+			}
+		}
+	}
+
+	record.VaccineName = strings.TrimSpace(im.VaccineCode.Text)
+	for _, coding := range im.VaccineCode.Coding {
+		if record.VaccineName != "" {
+			break
+		}
+		record.VaccineName = strings.TrimSpace(coding.Display)
+	}
+	if record.VaccineName == "" {
+		record.VaccineName = record.VaccineType
+	}
+	if record.VaccineName == "" {
+		return nil, fmt.Errorf("无法识别疫苗")
+	}
+
+	if len(im.ProtocolApplied) > 0 {
+		pa := im.ProtocolApplied[0]
+		record.DoseNumber = pa.DoseNumberPositiveInt
+		if record.DoseNumber == 0 {
+			record.DoseNumber, _ = strconv.Atoi(strings.TrimSpace(pa.DoseNumberString))
+		}
+	}
+
+	if im.Location != nil {
+		record.Hospital = locations[im.Location.Reference]
+		if record.Hospital == "" {
+			record.Hospital = strings.TrimSpace(im.Location.Display)
+		}
+	}
+
+	var notes []string
+	for _, note := range im.Note {
+		text := strings.TrimSpace(note.Text)
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, reactionNotePrefix):
+			record.Reaction = strings.TrimSpace(strings.TrimPrefix(text, reactionNotePrefix))
+		default:
+			notes = append(notes, text)
+		}
+	}
+	record.Note = strings.Join(notes, "\n")
+
+	return record, nil
+}
+
+// parseDateTime 解析 FHIR dateTime,只精确到年或月的日期无法对应到具体接种日
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.In(loc), nil
+	}
+	return time.Time{}, fmt.Errorf("接种日期 %q 需精确到日", value)
+}
@@ -0,0 +1,231 @@
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	resourceIDPattern  = regexp.MustCompile(`^[A-Za-z0-9\-.]{1,64}$`)
+	datePattern        = regexp.MustCompile(`^\d{4}(-(0[1-9]|1[0-2])(-(0[1-9]|[12]\d|3[01]))?)?$`)
+	dateTimePattern    = regexp.MustCompile(`^\d{4}(-(0[1-9]|1[0-2])(-(0[1-9]|[12]\d|3[01])(T([01]\d|2[0-3]):[0-5]\d:([0-5]\d|60)(\.\d+)?(Z|[+-]((0\d|1[0-3]):[0-5]\d|14:00)))?)?)?$`)
+	instantPattern     = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])T([01]\d|2[0-3]):[0-5]\d:([0-5]\d|60)(\.\d+)?(Z|[+-]((0\d|1[0-3]):[0-5]\d|14:00))$`)
+	relativeRefPattern = regexp.MustCompile(`^[A-Z][A-Za-z]+/[A-Za-z0-9\-.]{1,64}$`)
+)
+
+var (
+	bundleTypes = []string{"document", "message", "transaction", "transaction-response",
+		"batch", "batch-response", "history", "searchset", "collection"}
+	genders             = []string{"male", "female", "other", "unknown"}
+	immunizationStatus  = []string{"completed", "entered-in-error", "not-done"}
+	observationStatuses = []string{"registered", "preliminary", "final", "amended", "corrected",
+		"cancelled", "entered-in-error", "unknown"}
+)
+
+// ValidationError Bundle 结构校验错误,包含全部问题
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid FHIR bundle: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate 对 Bundle 做结构校验
+// 覆盖 Bundle 及本包使用的 Patient、Location、Immunization、Observation 的必填字段、取值集合、
+// 日期格式和 Bundle 内引用(urn:uuid 引用必须能在 Bundle 中解析到类型匹配的资源)
+func Validate(bundle *Bundle) error {
+	v := &validator{byFullURL: make(map[string]string)}
+
+	if bundle.ResourceType != "Bundle" {
+		v.fail("resourceType must be Bundle, got %q", bundle.ResourceType)
+	}
+	if !contains(bundleTypes, bundle.Type) {
+		v.fail("Bundle.type %q is not a valid bundle type", bundle.Type)
+	}
+	if bundle.Timestamp != "" && !instantPattern.MatchString(bundle.Timestamp) {
+		v.fail("Bundle.timestamp %q is not a valid instant", bundle.Timestamp)
+	}
+
+	// 先收集条目,引用可以指向后面的条目
+	headers := make([]resourceHeader, len(bundle.Entry))
+	for i, entry := range bundle.Entry {
+		path := fmt.Sprintf("Bundle.entry[%d]", i)
+		if len(entry.Resource) == 0 {
+			v.fail("%s.resource is required", path)
+			continue
+		}
+		if err := json.Unmarshal(entry.Resource, &headers[i]); err != nil {
+			v.fail("%s.resource is not a JSON object: %v", path, err)
+			continue
+		}
+		h := headers[i]
+		if h.ResourceType == "" {
+			v.fail("%s.resource.resourceType is required", path)
+			continue
+		}
+		if h.ID != "" {
+			if !resourceIDPattern.MatchString(h.ID) {
+				v.fail("%s.resource.id %q is not a valid id", path, h.ID)
+			}
+		}
+		if entry.FullURL != "" {
+			if _, dup := v.byFullURL[entry.FullURL]; dup {
+				v.fail("%s.fullUrl %q is duplicated", path, entry.FullURL)
+			}
+			v.byFullURL[entry.FullURL] = h.ResourceType
+		}
+	}
+
+	for i, entry := range bundle.Entry {
+		path := fmt.Sprintf("Bundle.entry[%d].resource", i)
+		switch headers[i].ResourceType {
+		case "Patient":
+			var p Patient
+			if v.decode(path, entry.Resource, &p) {
+				v.patient(path, &p)
+			}
+		case "Location":
+			var l Location
+			if v.decode(path, entry.Resource, &l) && l.Name == "" {
+				v.fail("%s.name is required", path)
+			}
+		case "Immunization":
+			var im Immunization
+			if v.decode(path, entry.Resource, &im) {
+				v.immunization(path, &im)
+			}
+		case "Observation":
+			var o Observation
+			if v.decode(path, entry.Resource, &o) {
+				v.observation(path, &o)
+			}
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	problems  []string
+	byFullURL map[string]string // fullUrl → resourceType
+}
+
+func (v *validator) fail(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) decode(path string, raw json.RawMessage, out any) bool {
+	if err := json.Unmarshal(raw, out); err != nil {
+		v.fail("%s: %v", path, err)
+		return false
+	}
+	return true
+}
+
+func (v *validator) patient(path string, p *Patient) {
+	if p.Gender != "" && !contains(genders, p.Gender) {
+		v.fail("%s.gender %q is not a valid administrative gender", path, p.Gender)
+	}
+	if p.BirthDate != "" && !datePattern.MatchString(p.BirthDate) {
+		v.fail("%s.birthDate %q is not a valid date", path, p.BirthDate)
+	}
+	for j, id := range p.Identifier {
+		if id.Value == "" {
+			v.fail("%s.identifier[%d].value is required", path, j)
+		}
+	}
+}
+
+func (v *validator) immunization(path string, im *Immunization) {
+	if !contains(immunizationStatus, im.Status) {
+		v.fail("%s.status %q is not a valid immunization status", path, im.Status)
+	}
+	v.concept(path+".vaccineCode", &im.VaccineCode)
+	v.reference(path+".patient", &im.Patient, "Patient", true)
+	if im.Location != nil {
+		v.reference(path+".location", im.Location, "Location", false)
+	}
+	switch {
+	case im.OccurrenceDateTime != "" && im.OccurrenceString != "":
+		v.fail("%s.occurrence[x] must have only one value", path)
+	case im.OccurrenceDateTime != "":
+		if !dateTimePattern.MatchString(im.OccurrenceDateTime) {
+			v.fail("%s.occurrenceDateTime %q is not a valid dateTime", path, im.OccurrenceDateTime)
+		}
+	case im.OccurrenceString == "":
+		v.fail("%s.occurrence[x] is required", path)
+	}
+	for j, pa := range im.ProtocolApplied {
+		if pa.DoseNumberPositiveInt <= 0 && pa.DoseNumberString == "" {
+			v.fail("%s.protocolApplied[%d].doseNumber[x] is required", path, j)
+		}
+	}
+}
+
+func (v *validator) observation(path string, o *Observation) {
+	if !contains(observationStatuses, o.Status) {
+		v.fail("%s.status %q is not a valid observation status", path, o.Status)
+	}
+	v.concept(path+".code", &o.Code)
+	for j := range o.Category {
+		v.concept(fmt.Sprintf("%s.category[%d]", path, j), &o.Category[j])
+	}
+	v.reference(path+".subject", &o.Subject, "Patient", false)
+	if o.EffectiveDateTime != "" && !dateTimePattern.MatchString(o.EffectiveDateTime) {
+		v.fail("%s.effectiveDateTime %q is not a valid dateTime", path, o.EffectiveDateTime)
+	}
+	if q := o.ValueQuantity; q != nil && q.System == SystemUCUM && q.Code == "" {
+		v.fail("%s.valueQuantity.code is required for UCUM quantities", path)
+	}
+}
+
+func (v *validator) concept(path string, c *CodeableConcept) {
+	if len(c.Coding) == 0 && c.Text == "" {
+		v.fail("%s must have coding or text", path)
+	}
+	for j, coding := range c.Coding {
+		if coding.Code != "" && coding.System == "" {
+			v.fail("%s.coding[%d].system is required when code is present", path, j)
+		}
+	}
+}
+
+// reference 校验引用格式;urn:uuid 引用必须指向 Bundle 内类型匹配的条目,相对引用校验资源类型
+func (v *validator) reference(path string, ref *Reference, targetType string, required bool) {
+	switch {
+	case ref.Reference == "":
+		if required {
+			v.fail("%s.reference is required", path)
+		}
+	case strings.HasPrefix(ref.Reference, "urn:uuid:") || strings.HasPrefix(ref.Reference, "urn:oid:"):
+		resourceType, ok := v.byFullURL[ref.Reference]
+		if !ok {
+			v.fail("%s.reference %q does not resolve within the bundle", path, ref.Reference)
+		} else if resourceType != targetType {
+			v.fail("%s.reference %q points to %s, expected %s", path, ref.Reference, resourceType, targetType)
+		}
+	case relativeRefPattern.MatchString(ref.Reference):
+		if resourceType, _, _ := strings.Cut(ref.Reference, "/"); resourceType != targetType {
+			v.fail("%s.reference %q must reference a %s", path, ref.Reference, targetType)
+		}
+	case strings.HasPrefix(ref.Reference, "http://") || strings.HasPrefix(ref.Reference, "https://"):
+		// 绝对引用指向外部服务器,不做解析
+	default:
+		v.fail("%s.reference %q is not a valid reference", path, ref.Reference)
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
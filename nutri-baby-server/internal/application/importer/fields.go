@@ -0,0 +1,374 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// 单位换算
+const (
+	mlPerOunce = 29.5735
+	kgPerPound = 0.45359237
+	cmPerInch  = 2.54
+)
+
+// 取值范围,超出视为数据错误
+const (
+	maxFeedingAmountMl = 1000
+	maxDurationSeconds = 24 * 60 * 60
+	minWeightKg        = 0.3
+	maxWeightKg        = 50
+	minHeightCm        = 20
+	maxHeightCm        = 200
+	minHeadCm          = 20
+	maxHeadCm          = 70
+)
+
+// utf8BOM Excel 等工具导出 CSV 时添加的字节序标记
+var utf8BOM = []byte("\ufeff")
+
+// 允许的时钟误差,记录时间不得晚于当前时间加此值
+const futureTolerance = 24 * time.Hour
+
+// timeLayouts 支持的时间格式,斜杠日期按美式 月/日/年 解析
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/1/2 15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"1/2/2006 15:04",
+	"1/2/2006 3:04 PM",
+	"1/2/2006 3:04:05 PM",
+	"1/2/06 15:04",
+	"1/2/06 3:04 PM",
+	"Jan 2, 2006 3:04 PM",
+	"Jan 2, 2006 15:04",
+	"2006-01-02",
+}
+
+// parseTime 解析时间为毫秒时间戳
+func parseTime(value string, loc *time.Location) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("时间为空")
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// 纯数字视为时间戳,按位数区分秒/毫秒
+		if ms < 1e11 {
+			ms *= 1000
+		}
+		return ms, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("无法识别的时间格式: %s", value)
+}
+
+var (
+	clockPattern    = regexp.MustCompile(`^(\d+):(\d{1,2})(?::(\d{1,2}))?$`)
+	durationPattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(h|hr|hrs|hour|hours|小时|m|min|mins|minute|minutes|分钟|分|s|sec|secs|second|seconds|秒)`)
+)
+
+// parseDuration 解析时长为秒
+// 支持 "1:05:00"/"05:00"(时:分[:秒],两段时按 时:分 解析)、"1h 5m"、"15 min" 以及纯数字(分钟)
+func parseDuration(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if m := clockPattern.FindStringSubmatch(value); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mi, _ := strconv.Atoi(m[2])
+		sec := 0
+		if m[3] != "" {
+			sec, _ = strconv.Atoi(m[3])
+		}
+		return h*3600 + mi*60 + sec, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return int(f * 60), nil
+	}
+	matches := durationPattern.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("无法识别的时长: %s", value)
+	}
+	total := 0.0
+	for _, m := range matches {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		switch strings.ToLower(m[2]) {
+		case "h", "hr", "hrs", "hour", "hours", "小时":
+			total += n * 3600
+		case "s", "sec", "secs", "second", "seconds", "秒":
+			total += n
+		default:
+			total += n * 60
+		}
+	}
+	return int(total), nil
+}
+
+var quantityPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*([a-z\p{Han}]*)`)
+
+// parseQuantity 解析带单位的数值,如 "120ml"、"4 oz"、"5.2kg";无单位时使用 defaultUnit
+func parseQuantity(value, defaultUnit string) (float64, string, bool) {
+	m := quantityPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, "", false
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+	if err != nil {
+		return 0, "", false
+	}
+	unit := strings.ToLower(m[2])
+	if unit == "" {
+		unit = defaultUnit
+	}
+	return n, unit, true
+}
+
+// parseAmountMl 解析奶量为毫升
+func parseAmountMl(value, defaultUnit string) (int64, error) {
+	n, unit, ok := parseQuantity(value, defaultUnit)
+	if !ok {
+		return 0, fmt.Errorf("无法识别的奶量: %s", value)
+	}
+	switch unit {
+	case "ml", "毫升", "cc":
+		return int64(n + 0.5), nil
+	case "oz", "floz", "盎司":
+		return int64(n*mlPerOunce + 0.5), nil
+	}
+	return 0, fmt.Errorf("不支持的奶量单位: %s", value)
+}
+
+// parseWeightKg 解析体重为千克
+func parseWeightKg(value, defaultUnit string) (float64, error) {
+	n, unit, ok := parseQuantity(value, defaultUnit)
+	if !ok {
+		return 0, fmt.Errorf("无法识别的体重: %s", value)
+	}
+	switch unit {
+	case "kg", "千克", "公斤":
+		return round2(n), nil
+	case "g", "克":
+		return round2(n / 1000), nil
+	case "lb", "lbs", "磅":
+		return round2(n * kgPerPound), nil
+	}
+	return 0, fmt.Errorf("不支持的体重单位: %s", value)
+}
+
+// parseLengthCm 解析身高/头围为厘米
+func parseLengthCm(value, defaultUnit string) (float64, error) {
+	n, unit, ok := parseQuantity(value, defaultUnit)
+	if !ok {
+		return 0, fmt.Errorf("无法识别的长度: %s", value)
+	}
+	switch unit {
+	case "cm", "厘米":
+		return round2(n), nil
+	case "mm", "毫米":
+		return round2(n / 10), nil
+	case "in", "inch", "inches", "英寸":
+		return round2(n * cmPerInch), nil
+	}
+	return 0, fmt.Errorf("不支持的长度单位: %s", value)
+}
+
+func round2(v float64) float64 {
+	return float64(int64(v*100+0.5)) / 100
+}
+
+// normalizeDiaperType 将各应用的尿布状态映射为 pee/poop/both
+func normalizeDiaperType(value string) (string, bool) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	hasPee := containsAny(v, "pee", "wet", "urine", "小便", "尿")
+	hasPoop := containsAny(v, "poo", "dirty", "bm", "stool", "大便", "便便")
+	switch {
+	case containsAny(v, "both", "mixed", "混合") || (hasPee && hasPoop):
+		return entity.DiaperTypeBoth, true
+	case hasPoop:
+		return entity.DiaperTypePoop, true
+	case hasPee:
+		return entity.DiaperTypePee, true
+	}
+	return "", false
+}
+
+// sleepTypeAt 按入睡时间推断睡眠类型: 19:00 ~ 次日 06:00 之间入睡视为夜间睡眠
+func sleepTypeAt(startMs int64, loc *time.Location) string {
+	hour := time.UnixMilli(startMs).In(loc).Hour()
+	if hour >= 19 || hour < 6 {
+		return entity.SleepTypeNight
+	}
+	return entity.SleepTypeNap
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func strPtr(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// csvTable 读取的 CSV 表格,表头已规范化
+type csvTable struct {
+	header map[string]int
+	rows   [][]string
+}
+
+// readCSV 读取 CSV,去除 UTF-8 BOM 并规范化表头
+func readCSV(data []byte) (*csvTable, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV 格式错误: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("文件为空")
+	}
+
+	table := &csvTable{header: make(map[string]int), rows: records[1:]}
+	for i, name := range records[0] {
+		key := normalizeHeader(name)
+		if _, exists := table.header[key]; !exists {
+			table.header[key] = i
+		}
+	}
+	return table, nil
+}
+
+// normalizeHeader 表头转小写并去除空白、下划线和括号中的单位说明
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, "(（"); i > 0 {
+		name = name[:i]
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// column 按别名查找列序号
+func (t *csvTable) column(aliases ...string) int {
+	for _, alias := range aliases {
+		if i, ok := t.header[normalizeHeader(alias)]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// cell 读取单元格,列不存在或越界返回空字符串
+func cell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// isBlankRow 判断是否为空行
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// validate 校验记录字段取值
+func validate(rec *Record, now time.Time) error {
+	t := rec.Time()
+	if t <= 0 {
+		return fmt.Errorf("时间无效")
+	}
+	if t > now.Add(futureTolerance).UnixMilli() {
+		return fmt.Errorf("时间晚于当前时间")
+	}
+
+	switch {
+	case rec.Feeding != nil:
+		f := rec.Feeding
+		switch f.FeedingType {
+		case entity.FeedingTypeBreast, entity.FeedingTypeBottle, entity.FeedingTypeFood:
+		default:
+			return fmt.Errorf("不支持的喂养类型: %s", f.FeedingType)
+		}
+		if f.Amount < 0 || f.Amount > maxFeedingAmountMl {
+			return fmt.Errorf("奶量超出范围: %dml", f.Amount)
+		}
+		if f.Duration < 0 || f.Duration > maxDurationSeconds {
+			return fmt.Errorf("喂养时长超出范围")
+		}
+	case rec.Sleep != nil:
+		s := rec.Sleep
+		if s.EndTime != nil && *s.EndTime < s.StartTime {
+			return fmt.Errorf("结束时间早于开始时间")
+		}
+		if s.Duration != nil && (*s.Duration < 0 || *s.Duration > maxDurationSeconds) {
+			return fmt.Errorf("睡眠时长超出范围")
+		}
+		if s.Type != entity.SleepTypeNap && s.Type != entity.SleepTypeNight {
+			return fmt.Errorf("不支持的睡眠类型: %s", s.Type)
+		}
+	case rec.Diaper != nil:
+		switch rec.Diaper.Type {
+		case entity.DiaperTypePee, entity.DiaperTypePoop, entity.DiaperTypeBoth:
+		default:
+			return fmt.Errorf("不支持的尿布类型: %s", rec.Diaper.Type)
+		}
+	case rec.Growth != nil:
+		g := rec.Growth
+		if g.Height == nil && g.Weight == nil && g.HeadCircumference == nil {
+			return fmt.Errorf("缺少身高、体重或头围")
+		}
+		if g.Weight != nil && (*g.Weight < minWeightKg || *g.Weight > maxWeightKg) {
+			return fmt.Errorf("体重超出范围: %.2fkg", *g.Weight)
+		}
+		if g.Height != nil && (*g.Height < minHeightCm || *g.Height > maxHeightCm) {
+			return fmt.Errorf("身高超出范围: %.1fcm", *g.Height)
+		}
+		if g.HeadCircumference != nil && (*g.HeadCircumference < minHeadCm || *g.HeadCircumference > maxHeadCm) {
+			return fmt.Errorf("头围超出范围: %.1fcm", *g.HeadCircumference)
+		}
+	default:
+		return fmt.Errorf("记录为空")
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package importer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// GenericCSVParser 通用 CSV 解析器
+//
+// 按列名别名匹配字段(大小写、空格、下划线及括号中的单位说明不敏感),适用于多数应用的表格导出:
+//   - 单文件多类型: 通过 Type/Activity/类型 列区分喂养、睡眠、尿布、成长记录
+//   - 单类型文件: 文件名包含 feeding/sleep/diaper/growth 等关键字时按文件类型解析,
+//     此时 Type 列表示子类型(如本应用导出的 feedings.csv、sleeps.csv)
+//
+// 无单位的数值按 毫升/分钟/千克/厘米 解析。
+type GenericCSVParser struct{}
+
+// Name 解析器标识
+func (p *GenericCSVParser) Name() string { return "generic-csv" }
+
+// Description 解析器说明
+func (p *GenericCSVParser) Description() string {
+	return "通用 CSV(含时间列,按类型列或文件名区分记录类型)"
+}
+
+// 列名别名
+var (
+	genericTypeColumns     = []string{"record type", "activity", "category", "event", "type", "记录类型", "类型"}
+	genericTimeColumns     = []string{"time", "start time", "start", "datetime", "date/time", "date", "时间", "开始时间", "日期"}
+	genericEndColumns      = []string{"end time", "end", "结束时间"}
+	genericSecondsColumns  = []string{"duration_seconds", "duration seconds"}
+	genericDurationColumns = []string{"duration", "total duration", "时长"}
+	genericAmountColumns   = []string{"amount_ml", "amount", "quantity", "volume", "奶量"}
+	genericUnitColumns     = []string{"unit", "units", "单位"}
+	genericSideColumns     = []string{"side", "start side", "breast side", "侧别"}
+	genericLeftColumns     = []string{"left duration", "left", "左侧时长"}
+	genericRightColumns    = []string{"right duration", "right", "右侧时长"}
+	genericFeedingColumns  = []string{"feeding_type", "feeding type", "feed type", "method", "subtype", "喂养方式", "喂养类型"}
+	genericSleepColumns    = []string{"sleep type", "睡眠类型"}
+	genericDiaperColumns   = []string{"diaper type", "status", "diaper", "尿布类型", "状态"}
+	genericColorColumns    = []string{"poop_color", "poop color", "color", "颜色"}
+	genericTextureColumns  = []string{"poop_texture", "poop texture", "texture", "consistency", "质地"}
+	genericFoodColumns     = []string{"food name", "food", "食物"}
+	genericWeightColumns   = []string{"weight_kg", "weight", "体重"}
+	genericHeightColumns   = []string{"height_cm", "height", "length", "身高", "身长"}
+	genericHeadColumns     = []string{"head_circumference_cm", "head circumference", "head", "头围"}
+	genericNoteColumns     = []string{"note", "notes", "memo", "comment", "备注"}
+)
+
+// genericColumns 已定位的列序号
+type genericColumns struct {
+	recordType, time, end, seconds, duration, amount, unit, side, left, right int
+	feedingType, sleepType, diaperType, color, texture, food                  int
+	weight, height, head, note                                                int
+}
+
+// Detect 可读取为 CSV 且具备时间列,并能从类型列或文件名确定记录类型
+func (p *GenericCSVParser) Detect(filename string, data []byte) bool {
+	table, err := readCSV(data)
+	if err != nil || len(table.header) < 2 {
+		return false
+	}
+	if table.column(genericTimeColumns...) < 0 {
+		return false
+	}
+	return recordTypeFromFilename(filename) != "" || table.column(genericTypeColumns...) >= 0
+}
+
+// Parse 解析通用 CSV
+func (p *GenericCSVParser) Parse(filename string, data []byte, opts Options) (*Result, error) {
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fileType := recordTypeFromFilename(filename)
+	cols := genericColumns{
+		time:     table.column(genericTimeColumns...),
+		end:      table.column(genericEndColumns...),
+		seconds:  table.column(genericSecondsColumns...),
+		duration: table.column(genericDurationColumns...),
+		amount:   table.column(genericAmountColumns...),
+		unit:     table.column(genericUnitColumns...),
+		side:     table.column(genericSideColumns...),
+		left:     table.column(genericLeftColumns...),
+		right:    table.column(genericRightColumns...),
+		color:    table.column(genericColorColumns...),
+		texture:  table.column(genericTextureColumns...),
+		food:     table.column(genericFoodColumns...),
+		weight:   table.column(genericWeightColumns...),
+		height:   table.column(genericHeightColumns...),
+		head:     table.column(genericHeadColumns...),
+		note:     table.column(genericNoteColumns...),
+	}
+	if cols.time < 0 {
+		return nil, fmt.Errorf("缺少时间列")
+	}
+	cols.recordType = -1
+	cols.feedingType = table.column(genericFeedingColumns...)
+	cols.sleepType = table.column(genericSleepColumns...)
+	cols.diaperType = table.column(genericDiaperColumns...)
+	if fileType == "" {
+		if cols.recordType = table.column(genericTypeColumns...); cols.recordType < 0 {
+			return nil, fmt.Errorf("缺少记录类型列,且无法从文件名判断记录类型")
+		}
+	} else if typeCol := table.column("type", "类型"); typeCol >= 0 {
+		// 单类型文件中的 Type 列为子类型
+		switch fileType {
+		case RecordTypeFeeding:
+			if cols.feedingType < 0 {
+				cols.feedingType = typeCol
+			}
+		case RecordTypeSleep:
+			if cols.sleepType < 0 {
+				cols.sleepType = typeCol
+			}
+		case RecordTypeDiaper:
+			if cols.diaperType < 0 {
+				cols.diaperType = typeCol
+			}
+		}
+	}
+
+	source := path.Base(filename)
+	loc := opts.location()
+	res := &Result{}
+	for i, row := range table.rows {
+		if isBlankRow(row) {
+			continue
+		}
+		rowNum := i + 2 // 表头为第1行
+
+		kind, subtype := fileType, ""
+		if cols.recordType >= 0 {
+			kind, subtype = normalizeRecordType(cell(row, cols.recordType))
+			if kind == "" {
+				res.Skipped++
+				continue
+			}
+		}
+
+		start, err := parseTime(cell(row, cols.time), loc)
+		if err != nil {
+			res.fail(rowNum, source, err.Error())
+			continue
+		}
+
+		rec := &Record{Row: rowNum, Source: source}
+		switch kind {
+		case RecordTypeFeeding:
+			rec.Feeding, err = genericFeeding(row, cols, start, subtype)
+		case RecordTypeSleep:
+			rec.Sleep, err = genericSleep(row, cols, start, subtype, opts)
+		case RecordTypeDiaper:
+			rec.Diaper, err = genericDiaper(row, cols, start, subtype)
+		case RecordTypeGrowth:
+			rec.Growth, err = genericGrowth(row, cols, start)
+		}
+		if err != nil {
+			res.fail(rowNum, source, err.Error())
+			continue
+		}
+		res.add(rec, opts)
+	}
+	return res, nil
+}
+
+// rowDuration 读取时长(秒),优先使用以秒为单位的列
+func rowDuration(row []string, cols genericColumns) (int, error) {
+	if v := cell(row, cols.seconds); v != "" {
+		return parseDuration(v + "s")
+	}
+	return parseDuration(cell(row, cols.duration))
+}
+
+func genericFeeding(row []string, cols genericColumns, start int64, implied string) (*entity.FeedingRecord, error) {
+	feedingType := implied
+	if v := cell(row, cols.feedingType); v != "" {
+		if feedingType = normalizeFeedingType(v); feedingType == "" {
+			return nil, fmt.Errorf("无法识别的喂养类型: %s", v)
+		}
+	}
+	amountStr := cell(row, cols.amount)
+	if feedingType == "" {
+		if amountStr != "" {
+			feedingType = entity.FeedingTypeBottle
+		} else {
+			feedingType = entity.FeedingTypeBreast
+		}
+	}
+
+	detail := entity.FeedingDetail{"type": feedingType}
+	record := &entity.FeedingRecord{Time: start, FeedingType: feedingType, Detail: detail}
+	if note := cell(row, cols.note); note != "" {
+		detail["note"] = note
+	}
+
+	duration, err := rowDuration(row, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	switch feedingType {
+	case entity.FeedingTypeBreast:
+		left, err := parseDuration(cell(row, cols.left))
+		if err != nil {
+			return nil, err
+		}
+		right, err := parseDuration(cell(row, cols.right))
+		if err != nil {
+			return nil, err
+		}
+		if duration == 0 {
+			duration = left + right
+		}
+		if left > 0 {
+			detail["leftDuration"] = left
+		}
+		if right > 0 {
+			detail["rightDuration"] = right
+		}
+		if side := normalizeSide(cell(row, cols.side)); side != "" {
+			detail["side"] = side
+		} else if left > 0 && right > 0 {
+			detail["side"] = "both"
+		} else if left > 0 {
+			detail["side"] = "left"
+		} else if right > 0 {
+			detail["side"] = "right"
+		}
+		record.Duration = duration
+		detail["duration"] = duration
+	case entity.FeedingTypeBottle:
+		if amountStr != "" {
+			unit := strings.ToLower(cell(row, cols.unit))
+			if unit == "" {
+				unit = "ml"
+			}
+			amount, err := parseAmountMl(amountStr, unit)
+			if err != nil {
+				return nil, err
+			}
+			record.Amount = amount
+			detail["amount"] = amount
+			detail["unit"] = "ml"
+		}
+		record.Duration = duration
+	case entity.FeedingTypeFood:
+		if food := cell(row, cols.food); food != "" {
+			detail["foodName"] = food
+		}
+		record.Duration = duration
+	}
+	return record, nil
+}
+
+func genericSleep(row []string, cols genericColumns, start int64, implied string, opts Options) (*entity.SleepRecord, error) {
+	sleep := &entity.SleepRecord{StartTime: start}
+
+	if endStr := cell(row, cols.end); endStr != "" {
+		end, err := parseTime(endStr, opts.location())
+		if err != nil {
+			return nil, err
+		}
+		sleep.EndTime = &end
+	}
+	duration, err := rowDuration(row, cols)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case sleep.EndTime != nil:
+		d := int((*sleep.EndTime - start) / 1000)
+		sleep.Duration = &d
+	case duration > 0:
+		end := start + int64(duration)*1000
+		sleep.EndTime = &end
+		sleep.Duration = &duration
+	}
+
+	sleepType := implied
+	if v := strings.ToLower(cell(row, cols.sleepType)); v != "" {
+		switch {
+		case containsAny(v, "night", "夜"):
+			sleepType = entity.SleepTypeNight
+		case containsAny(v, "nap", "小睡", "午睡"):
+			sleepType = entity.SleepTypeNap
+		}
+	}
+	if sleepType == "" {
+		sleepType = sleepTypeAt(start, opts.location())
+	}
+	sleep.Type = sleepType
+	return sleep, nil
+}
+
+func genericDiaper(row []string, cols genericColumns, start int64, implied string) (*entity.DiaperRecord, error) {
+	diaperType := implied
+	if v := cell(row, cols.diaperType); v != "" {
+		t, ok := normalizeDiaperType(v)
+		if !ok {
+			return nil, fmt.Errorf("无法识别的尿布状态: %s", v)
+		}
+		diaperType = t
+	}
+	if diaperType == "" {
+		return nil, fmt.Errorf("缺少尿布状态")
+	}
+	return &entity.DiaperRecord{
+		Time:        start,
+		Type:        diaperType,
+		PoopColor:   strPtr(cell(row, cols.color)),
+		PoopTexture: strPtr(cell(row, cols.texture)),
+		Note:        strPtr(cell(row, cols.note)),
+	}, nil
+}
+
+func genericGrowth(row []string, cols genericColumns, start int64) (*entity.GrowthRecord, error) {
+	growth := &entity.GrowthRecord{Time: start, Note: strPtr(cell(row, cols.note))}
+	if v := cell(row, cols.weight); v != "" {
+		weight, err := parseWeightKg(v, "kg")
+		if err != nil {
+			return nil, err
+		}
+		growth.Weight = &weight
+	}
+	if v := cell(row, cols.height); v != "" {
+		height, err := parseLengthCm(v, "cm")
+		if err != nil {
+			return nil, err
+		}
+		growth.Height = &height
+	}
+	if v := cell(row, cols.head); v != "" {
+		head, err := parseLengthCm(v, "cm")
+		if err != nil {
+			return nil, err
+		}
+		growth.HeadCircumference = &head
+	}
+	return growth, nil
+}
+
+// recordTypeFromFilename 根据文件名判断单类型文件的记录类型
+func recordTypeFromFilename(filename string) string {
+	name := strings.ToLower(path.Base(filename))
+	switch {
+	case containsAny(name, "feed", "nursing", "bottle", "formula", "喂养"):
+		return RecordTypeFeeding
+	case containsAny(name, "sleep", "睡眠"):
+		return RecordTypeSleep
+	case containsAny(name, "diaper", "nappy", "尿布"):
+		return RecordTypeDiaper
+	case containsAny(name, "growth", "成长", "生长"):
+		return RecordTypeGrowth
+	}
+	return ""
+}
+
+// normalizeRecordType 将类型列的值映射为记录类型,并返回其隐含的子类型
+func normalizeRecordType(value string) (string, string) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if feedingType := normalizeFeedingType(v); feedingType != "" {
+		return RecordTypeFeeding, feedingType
+	}
+	switch {
+	case containsAny(v, "feed", "喂"):
+		return RecordTypeFeeding, ""
+	case containsAny(v, "nap", "小睡"):
+		return RecordTypeSleep, entity.SleepTypeNap
+	case containsAny(v, "sleep", "睡"):
+		return RecordTypeSleep, ""
+	case containsAny(v, "diaper", "nappy", "尿布"):
+		return RecordTypeDiaper, ""
+	case containsAny(v, "growth", "measure", "weight", "height", "成长", "生长"):
+		return RecordTypeGrowth, ""
+	}
+	if diaperType, ok := normalizeDiaperType(v); ok {
+		return RecordTypeDiaper, diaperType
+	}
+	return "", ""
+}
+
+// normalizeFeedingType 将喂养方式映射为 breast/bottle/food
+func normalizeFeedingType(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case containsAny(v, "bottle", "formula", "expressed", "pumped", "奶瓶", "配方"):
+		return entity.FeedingTypeBottle
+	case containsAny(v, "breast", "nursing", "母乳", "亲喂"):
+		return entity.FeedingTypeBreast
+	case containsAny(v, "solid", "food", "辅食"):
+		return entity.FeedingTypeFood
+	}
+	return ""
+}
+
+// normalizeSide 将母乳侧别映射为 left/right/both
+func normalizeSide(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case v == "":
+		return ""
+	case containsAny(v, "both", "两", "双"):
+		return "both"
+	case v == "l" || containsAny(v, "left", "左"):
+		return "left"
+	case v == "r" || containsAny(v, "right", "右"):
+		return "right"
+	}
+	return ""
+}
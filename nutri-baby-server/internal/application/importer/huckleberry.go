@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// HuckleberryParser Huckleberry 应用导出的 CSV 解析器
+//
+// 表头: Type,Start,End,Duration,Start Condition,Start Location,End Condition,Notes
+//   - Feed: Start Location 为 Breast/Bottle/Solids;母乳时 Start/End Condition 为 "mm:ssL"/"mm:ssR" 形式的左右侧时长,
+//     奶瓶时 Start Condition 为奶的种类(Formula/Breast Milk)、End Condition 为奶量,辅食时 Start Condition 为食物名称
+//   - Sleep: Start/End 为入睡与醒来时间
+//   - Diaper: End Condition 为状态(Pee/Poo/Both),Start Condition 为便便颜色/质地描述
+//   - Growth: Start Condition 为体重,Start Location 为身高,End Condition 为头围(均带单位)
+//
+// 其他类型(Pump、Medicine、Temp 等)不在导入范围内,计入 Skipped。
+type HuckleberryParser struct{}
+
+// Name 解析器标识
+func (p *HuckleberryParser) Name() string { return "huckleberry" }
+
+// Description 解析器说明
+func (p *HuckleberryParser) Description() string {
+	return "Huckleberry 导出的 CSV"
+}
+
+// Detect 根据 Huckleberry 特有的表头识别
+func (p *HuckleberryParser) Detect(filename string, data []byte) bool {
+	table, err := readCSV(data)
+	if err != nil {
+		return false
+	}
+	return table.column("Type") >= 0 && table.column("Start") >= 0 &&
+		table.column("Start Condition") >= 0 && table.column("End Condition") >= 0
+}
+
+// sideDurationPattern 母乳侧别时长,如 "10:30L"、"05:00 R"
+var sideDurationPattern = regexp.MustCompile(`(?i)^([\d:]+)\s*([LR])$`)
+
+// Parse 解析 Huckleberry CSV
+func (p *HuckleberryParser) Parse(filename string, data []byte, opts Options) (*Result, error) {
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	colType := table.column("Type")
+	colStart := table.column("Start")
+	if colType < 0 || colStart < 0 {
+		return nil, fmt.Errorf("缺少 Type 或 Start 列")
+	}
+	colEnd := table.column("End")
+	colDuration := table.column("Duration")
+	colStartCond := table.column("Start Condition")
+	colStartLoc := table.column("Start Location")
+	colEndCond := table.column("End Condition")
+	colNotes := table.column("Notes")
+
+	loc := opts.location()
+	res := &Result{}
+	for i, row := range table.rows {
+		if isBlankRow(row) {
+			continue
+		}
+		rowNum := i + 2 // 表头为第1行
+		kind := strings.ToLower(cell(row, colType))
+
+		start, err := parseTime(cell(row, colStart), loc)
+		if err != nil {
+			if isHuckleberrySupported(kind) {
+				res.fail(rowNum, filename, err.Error())
+			} else {
+				res.Skipped++
+			}
+			continue
+		}
+		startCond := cell(row, colStartCond)
+		startLoc := cell(row, colStartLoc)
+		endCond := cell(row, colEndCond)
+		note := strPtr(cell(row, colNotes))
+
+		rec := &Record{Row: rowNum, Source: filename}
+		switch kind {
+		case "feed", "feeding":
+			feeding, err := huckleberryFeeding(start, startCond, startLoc, endCond, cell(row, colDuration), note)
+			if err != nil {
+				res.fail(rowNum, filename, err.Error())
+				continue
+			}
+			rec.Feeding = feeding
+		case "sleep":
+			sleep := &entity.SleepRecord{StartTime: start, Type: sleepTypeAt(start, loc)}
+			if endStr := cell(row, colEnd); endStr != "" {
+				end, err := parseTime(endStr, loc)
+				if err != nil {
+					res.fail(rowNum, filename, err.Error())
+					continue
+				}
+				duration := int((end - start) / 1000)
+				sleep.EndTime = &end
+				sleep.Duration = &duration
+			}
+			rec.Sleep = sleep
+		case "diaper":
+			diaperType, ok := normalizeDiaperType(endCond)
+			if !ok {
+				res.fail(rowNum, filename, "无法识别的尿布状态: "+endCond)
+				continue
+			}
+			diaper := &entity.DiaperRecord{Time: start, Type: diaperType, Note: note}
+			if diaperType != entity.DiaperTypePee && startCond != "" {
+				diaper.Note = joinNotes(startCond, note)
+			}
+			rec.Diaper = diaper
+		case "growth":
+			growth, err := huckleberryGrowth(start, startCond, startLoc, endCond, note)
+			if err != nil {
+				res.fail(rowNum, filename, err.Error())
+				continue
+			}
+			rec.Growth = growth
+		default:
+			res.Skipped++
+			continue
+		}
+		res.add(rec, opts)
+	}
+	return res, nil
+}
+
+func isHuckleberrySupported(kind string) bool {
+	switch kind {
+	case "feed", "feeding", "sleep", "diaper", "growth":
+		return true
+	}
+	return false
+}
+
+func huckleberryFeeding(start int64, startCond, startLoc, endCond, durationStr string, note *string) (*entity.FeedingRecord, error) {
+	detail := entity.FeedingDetail{}
+	if note != nil {
+		detail["note"] = *note
+	}
+	record := &entity.FeedingRecord{Time: start, Detail: detail}
+
+	switch strings.ToLower(startLoc) {
+	case "breast", "":
+		record.FeedingType = entity.FeedingTypeBreast
+		left, right := 0, 0
+		for _, cond := range []string{startCond, endCond} {
+			m := sideDurationPattern.FindStringSubmatch(strings.TrimSpace(cond))
+			if m == nil {
+				continue
+			}
+			seconds, err := parseMinSec(m[1])
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(m[2], "L") {
+				left += seconds
+			} else {
+				right += seconds
+			}
+		}
+		total := left + right
+		if total == 0 {
+			var err error
+			if total, err = parseDuration(durationStr); err != nil {
+				return nil, err
+			}
+		}
+		record.Duration = total
+		detail["duration"] = total
+		switch {
+		case left > 0 && right > 0:
+			detail["side"] = "both"
+		case left > 0:
+			detail["side"] = "left"
+		case right > 0:
+			detail["side"] = "right"
+		}
+		if left > 0 {
+			detail["leftDuration"] = left
+		}
+		if right > 0 {
+			detail["rightDuration"] = right
+		}
+	case "bottle":
+		record.FeedingType = entity.FeedingTypeBottle
+		if endCond != "" {
+			amount, err := parseAmountMl(endCond, "ml")
+			if err != nil {
+				return nil, err
+			}
+			record.Amount = amount
+			detail["amount"] = amount
+			detail["unit"] = "ml"
+		}
+		if strings.Contains(strings.ToLower(startCond), "formula") {
+			detail["bottleType"] = "formula"
+		} else if startCond != "" {
+			detail["bottleType"] = "breast-milk"
+		}
+	case "solids", "solid":
+		record.FeedingType = entity.FeedingTypeFood
+		if startCond != "" {
+			detail["foodName"] = startCond
+		}
+	default:
+		return nil, fmt.Errorf("无法识别的喂养方式: %s", startLoc)
+	}
+	detail["type"] = record.FeedingType
+	return record, nil
+}
+
+func huckleberryGrowth(start int64, weightStr, heightStr, headStr string, note *string) (*entity.GrowthRecord, error) {
+	growth := &entity.GrowthRecord{Time: start, Note: note}
+	if weightStr != "" {
+		weight, err := parseWeightKg(weightStr, "kg")
+		if err != nil {
+			return nil, err
+		}
+		growth.Weight = &weight
+	}
+	if heightStr != "" {
+		height, err := parseLengthCm(heightStr, "cm")
+		if err != nil {
+			return nil, err
+		}
+		growth.Height = &height
+	}
+	if headStr != "" {
+		head, err := parseLengthCm(headStr, "cm")
+		if err != nil {
+			return nil, err
+		}
+		growth.HeadCircumference = &head
+	}
+	return growth, nil
+}
+
+// parseMinSec 解析 "分:秒" 或 "时:分:秒" 形式的侧别时长
+func parseMinSec(value string) (int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) == 2 {
+		value = "0:" + value
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("无法识别的时长: %s", value)
+	}
+	return parseDuration(value)
+}
+
+// joinNotes 合并附加描述与备注
+func joinNotes(extra string, note *string) *string {
+	if note == nil {
+		return strPtr(extra)
+	}
+	return strPtr(extra + "; " + *note)
+}
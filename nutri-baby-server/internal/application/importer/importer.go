@@ -0,0 +1,178 @@
+// Package importer 从其他育儿记录应用导入数据
+//
+// 每种来源格式实现一个 Parser,将源文件解析为喂养/睡眠/尿布/成长记录实体及逐行错误。
+// 解析器只负责格式转换和字段校验,宝宝归属、创建者、去重与入库由导入服务处理。
+package importer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// 导入记录类型
+const (
+	RecordTypeFeeding = "feeding"
+	RecordTypeSleep   = "sleep"
+	RecordTypeDiaper  = "diaper"
+	RecordTypeGrowth  = "growth"
+)
+
+// Options 解析选项
+type Options struct {
+	// Location 源文件中不带时区的时间按此时区解析,为空时使用服务器本地时区
+	Location *time.Location
+	// Now 当前时间,用于拒绝未来时间的记录,为空时使用 time.Now
+	Now func() time.Time
+}
+
+func (o Options) location() *time.Location {
+	if o.Location == nil {
+		return time.Local
+	}
+	return o.Location
+}
+
+func (o Options) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// Record 解析出的一条记录,Feeding/Sleep/Diaper/Growth 中恰好一个非空
+type Record struct {
+	Row     int    // 源文件中的行号(CSV 含表头从1开始计数;JSON 为数组下标+1)
+	Source  string // 来源分区,如 CSV 文件名或 JSON 数组名
+	Feeding *entity.FeedingRecord
+	Sleep   *entity.SleepRecord
+	Diaper  *entity.DiaperRecord
+	Growth  *entity.GrowthRecord
+}
+
+// Type 记录类型
+func (r *Record) Type() string {
+	switch {
+	case r.Feeding != nil:
+		return RecordTypeFeeding
+	case r.Sleep != nil:
+		return RecordTypeSleep
+	case r.Diaper != nil:
+		return RecordTypeDiaper
+	case r.Growth != nil:
+		return RecordTypeGrowth
+	}
+	return ""
+}
+
+// Time 记录时间(毫秒时间戳),睡眠记录取开始时间
+func (r *Record) Time() int64 {
+	switch {
+	case r.Feeding != nil:
+		return r.Feeding.Time
+	case r.Sleep != nil:
+		return r.Sleep.StartTime
+	case r.Diaper != nil:
+		return r.Diaper.Time
+	case r.Growth != nil:
+		return r.Growth.Time
+	}
+	return 0
+}
+
+// RowError 单行解析错误
+type RowError struct {
+	Row     int
+	Source  string
+	Message string
+}
+
+func (e RowError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s 第 %d 行: %s", e.Source, e.Row, e.Message)
+	}
+	return fmt.Sprintf("第 %d 行: %s", e.Row, e.Message)
+}
+
+// Result 解析结果
+type Result struct {
+	Records []*Record
+	Errors  []RowError
+	Skipped int // 不支持导入的记录类型(如吸奶、用药)被忽略的行数
+}
+
+// add 校验记录后加入结果,校验失败记为行错误
+func (res *Result) add(rec *Record, opts Options) {
+	if err := validate(rec, opts.now()); err != nil {
+		res.fail(rec.Row, rec.Source, err.Error())
+		return
+	}
+	res.Records = append(res.Records, rec)
+}
+
+func (res *Result) fail(row int, source, message string) {
+	res.Errors = append(res.Errors, RowError{Row: row, Source: source, Message: message})
+}
+
+// Parser 导入文件解析器
+type Parser interface {
+	// Name 解析器标识,用于接口中显式指定来源格式
+	Name() string
+	// Description 解析器说明
+	Description() string
+	// Detect 判断文件是否为该解析器支持的格式
+	Detect(filename string, data []byte) bool
+	// Parse 解析文件;文件整体无法识别时返回 error,单行问题记录在 Result.Errors 中
+	Parse(filename string, data []byte, opts Options) (*Result, error)
+}
+
+// Registry 解析器注册表,自动识别时按注册顺序匹配
+type Registry struct {
+	parsers []Parser
+}
+
+// NewRegistry 创建解析器注册表
+func NewRegistry(parsers ...Parser) *Registry {
+	return &Registry{parsers: parsers}
+}
+
+// NewDefaultRegistry 创建包含全部内置解析器的注册表
+// 通用 CSV 解析器匹配范围最宽,放在最后
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(
+		&NutriBabyParser{},
+		&HuckleberryParser{},
+		&GenericCSVParser{},
+	)
+}
+
+// Register 注册解析器
+func (r *Registry) Register(parser Parser) {
+	r.parsers = append(r.parsers, parser)
+}
+
+// Parsers 已注册的解析器
+func (r *Registry) Parsers() []Parser {
+	return r.parsers
+}
+
+// Get 按名称查找解析器
+func (r *Registry) Get(name string) (Parser, bool) {
+	for _, p := range r.parsers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Detect 自动识别文件格式
+func (r *Registry) Detect(filename string, data []byte) (Parser, bool) {
+	for _, p := range r.parsers {
+		if p.Detect(filename, data) {
+			return p, true
+		}
+	}
+	return nil, false
+}
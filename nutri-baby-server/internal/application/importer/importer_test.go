@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+var testLoc = time.FixedZone("CST", 8*3600)
+
+func testOptions() Options {
+	return Options{
+		Location: testLoc,
+		Now:      func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, testLoc) },
+	}
+}
+
+func ms(year int, month time.Month, day, hour, min int) int64 {
+	return time.Date(year, month, day, hour, min, 0, 0, testLoc).UnixMilli()
+}
+
+func TestParseFields(t *testing.T) {
+	for input, want := range map[string]int{
+		"":        0,
+		"15":      900,
+		"00:20":   1200,
+		"1:05:30": 3930,
+		"1h 5m":   3900,
+		"15 min":  900,
+		"90s":     90,
+		"2小时":     7200,
+	} {
+		got, err := parseDuration(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := parseDuration("abc")
+	assert.Error(t, err)
+
+	amount, err := parseAmountMl("4 oz", "ml")
+	require.NoError(t, err)
+	assert.Equal(t, int64(118), amount)
+	amount, err = parseAmountMl("120", "ml")
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), amount)
+	_, err = parseAmountMl("3 cups", "ml")
+	assert.Error(t, err)
+
+	weight, err := parseWeightKg("11 lb", "kg")
+	require.NoError(t, err)
+	assert.Equal(t, 4.99, weight)
+	height, err := parseLengthCm("23.5in", "cm")
+	require.NoError(t, err)
+	assert.Equal(t, 59.69, height)
+
+	tm, err := parseTime("3/15/2024 8:30 PM", testLoc)
+	require.NoError(t, err)
+	assert.Equal(t, ms(2024, 3, 15, 20, 30), tm)
+}
+
+func TestHuckleberryParser(t *testing.T) {
+	csvData := "Type,Start,End,Duration,Start Condition,Start Location,End Condition,Notes\n" +
+		"Feed,2024-03-15 08:00,2024-03-15 08:20,00:20,10:00L,Breast,08:30R,\n" +
+		"Feed,2024-03-15 11:00,,,Formula,Bottle,4oz,hungry\n" +
+		"Sleep,2024-03-15 20:30,2024-03-16 06:00,09:30,,,,\n" +
+		"Diaper,2024-03-15 09:00,,,yellow,,Both,\n" +
+		"Growth,2024-03-15 10:00,,,5.2kg,58cm,38cm,\n" +
+		"Pump,2024-03-15 12:00,,,,,,\n" +
+		"Diaper,2024-03-15 13:00,,,,,???,\n"
+
+	p := &HuckleberryParser{}
+	require.True(t, p.Detect("huckleberry.csv", []byte(csvData)))
+
+	res, err := p.Parse("huckleberry.csv", []byte(csvData), testOptions())
+	require.NoError(t, err)
+	require.Len(t, res.Records, 5)
+	assert.Equal(t, 1, res.Skipped)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, 8, res.Errors[0].Row)
+
+	breast := res.Records[0].Feeding
+	require.NotNil(t, breast)
+	assert.Equal(t, entity.FeedingTypeBreast, breast.FeedingType)
+	assert.Equal(t, 1110, breast.Duration)
+	assert.Equal(t, "both", breast.Detail["side"])
+
+	bottle := res.Records[1].Feeding
+	require.NotNil(t, bottle)
+	assert.Equal(t, entity.FeedingTypeBottle, bottle.FeedingType)
+	assert.Equal(t, int64(118), bottle.Amount)
+	assert.Equal(t, "formula", bottle.Detail["bottleType"])
+
+	sleep := res.Records[2].Sleep
+	require.NotNil(t, sleep)
+	assert.Equal(t, entity.SleepTypeNight, sleep.Type)
+	assert.Equal(t, 34200, *sleep.Duration)
+
+	assert.Equal(t, entity.DiaperTypeBoth, res.Records[3].Diaper.Type)
+
+	growth := res.Records[4].Growth
+	assert.Equal(t, 5.2, *growth.Weight)
+	assert.Equal(t, 58.0, *growth.Height)
+	assert.Equal(t, 38.0, *growth.HeadCircumference)
+}
+
+func TestGenericCSVParser(t *testing.T) {
+	csvData := "\ufeffActivity,Time,End Time,Duration (min),Amount,Unit,Status,Weight,Notes\n" +
+		"Bottle,2024-03-15 08:00,,,120,ml,,,\n" +
+		"Nursing,2024-03-15 09:00,,15,,,,,\n" +
+		"Nap,2024-03-15 13:00,2024-03-15 14:30,,,,,,\n" +
+		"Diaper,2024-03-15 10:00,,,,,Wet,,\n" +
+		"Weight,2024-03-15 11:00,,,,,,12 lb,\n" +
+		"Bath,2024-03-15 19:00,,,,,,,\n" +
+		"Bottle,2030-01-01 08:00,,,120,ml,,,\n" +
+		"Bottle,not a time,,,120,ml,,,\n"
+
+	p := &GenericCSVParser{}
+	require.True(t, p.Detect("export.csv", []byte(csvData)))
+
+	res, err := p.Parse("export.csv", []byte(csvData), testOptions())
+	require.NoError(t, err)
+	require.Len(t, res.Records, 5)
+	assert.Equal(t, 1, res.Skipped)
+	require.Len(t, res.Errors, 2)
+	assert.Equal(t, 8, res.Errors[0].Row)
+	assert.Equal(t, 9, res.Errors[1].Row)
+
+	assert.Equal(t, int64(120), res.Records[0].Feeding.Amount)
+	assert.Equal(t, entity.FeedingTypeBreast, res.Records[1].Feeding.FeedingType)
+	assert.Equal(t, 900, res.Records[1].Feeding.Duration)
+	assert.Equal(t, entity.SleepTypeNap, res.Records[2].Sleep.Type)
+	assert.Equal(t, 5400, *res.Records[2].Sleep.Duration)
+	assert.Equal(t, entity.DiaperTypePee, res.Records[3].Diaper.Type)
+	assert.Equal(t, 5.44, *res.Records[4].Growth.Weight)
+}
+
+// TestGenericCSVParserOwnExport 本应用导出 ZIP 中的单类型 CSV 按文件名识别
+func TestGenericCSVParserOwnExport(t *testing.T) {
+	sleeps := "\ufeffrecord_id,start_time,end_time,duration_seconds,type,created_by_name\n" +
+		"1,2024-03-15 13:00:00,2024-03-15 14:00:00,3600,nap,妈妈\n"
+	res, err := (&GenericCSVParser{}).Parse("sleeps.csv", []byte(sleeps), testOptions())
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	assert.Equal(t, ms(2024, 3, 15, 13, 0), res.Records[0].Sleep.StartTime)
+	assert.Equal(t, entity.SleepTypeNap, res.Records[0].Sleep.Type)
+
+	diapers := "record_id,time,type,poop_color,poop_texture,note,created_by_name\n" +
+		"2,2024-03-15 10:00:00,poop,yellow,soft,,妈妈\n"
+	res, err = (&GenericCSVParser{}).Parse("diapers.csv", []byte(diapers), testOptions())
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	assert.Equal(t, entity.DiaperTypePoop, res.Records[0].Diaper.Type)
+	assert.Equal(t, "yellow", *res.Records[0].Diaper.PoopColor)
+}
+
+func TestNutriBabyParser(t *testing.T) {
+	weight := 6.1
+	doc := dto.ExportDocument{
+		Format:  dto.ExportFormatName,
+		Version: dto.ExportFormatVersion,
+		Feedings: []dto.ExportFeeding{
+			{RecordID: "1", Time: ms(2024, 3, 15, 8, 0), FeedingType: "bottle", AmountMl: 150},
+			{RecordID: "2", Time: ms(2024, 3, 15, 9, 0), FeedingType: "juice"},
+		},
+		Growths:  []dto.ExportGrowth{{RecordID: "3", Time: ms(2024, 3, 1, 0, 0), Weight: &weight}},
+		Vaccines: []dto.ExportVaccine{{ScheduleID: "4"}},
+	}
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("export.json")
+	require.NoError(t, err)
+	_, err = w.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	registry := NewDefaultRegistry()
+	for _, data := range [][]byte{raw, buf.Bytes()} {
+		p, ok := registry.Detect("export.zip", data)
+		require.True(t, ok)
+		require.Equal(t, "nutri-baby", p.Name())
+
+		res, err := p.Parse("export.zip", data, testOptions())
+		require.NoError(t, err)
+		require.Len(t, res.Records, 2)
+		assert.Equal(t, int64(150), res.Records[0].Feeding.Amount)
+		assert.Equal(t, RecordTypeGrowth, res.Records[1].Type())
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, RowError{Row: 2, Source: "feedings", Message: "不支持的喂养类型: juice"}, res.Errors[0])
+		assert.Equal(t, 1, res.Skipped)
+	}
+}
+
+func TestRegistryDetect(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	p, ok := registry.Detect("a.csv", []byte("Type,Start,End,Duration,Start Condition,Start Location,End Condition,Notes\n"))
+	require.True(t, ok)
+	assert.Equal(t, "huckleberry", p.Name())
+
+	p, ok = registry.Detect("feedings.csv", []byte("record_id,time,feeding_type,amount_ml\n"))
+	require.True(t, ok)
+	assert.Equal(t, "generic-csv", p.Name())
+
+	_, ok = registry.Detect("notes.txt", []byte("hello world"))
+	assert.False(t, ok)
+
+	_, ok = registry.Get("generic-csv")
+	assert.True(t, ok)
+}
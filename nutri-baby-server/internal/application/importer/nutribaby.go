@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// 导出 ZIP 中 JSON 文档的文件名
+const exportDocumentName = "export.json"
+
+// 导出文档最大解压大小,防止压缩炸弹
+const maxExportDocumentSize = 64 << 20
+
+// NutriBabyParser 本系统导出格式解析器
+// 支持导出 ZIP(读取其中的 export.json)或单独的 export.json;疫苗日程与里程碑不在导入范围内
+type NutriBabyParser struct{}
+
+// Name 解析器标识
+func (p *NutriBabyParser) Name() string { return "nutri-baby" }
+
+// Description 解析器说明
+func (p *NutriBabyParser) Description() string {
+	return "本应用导出的 ZIP 压缩包或 export.json"
+}
+
+// Detect 识别 ZIP 中的 export.json 或 format 字段为 nutri-baby-export 的 JSON
+func (p *NutriBabyParser) Detect(filename string, data []byte) bool {
+	raw, err := p.document(data)
+	if err != nil {
+		return false
+	}
+	var head struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(raw, &head) == nil && head.Format == dto.ExportFormatName
+}
+
+// Parse 解析导出文档
+func (p *NutriBabyParser) Parse(filename string, data []byte, opts Options) (*Result, error) {
+	raw, err := p.document(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc dto.ExportDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("导出文件格式错误: %w", err)
+	}
+	if doc.Format != dto.ExportFormatName {
+		return nil, fmt.Errorf("不是本应用的导出文件")
+	}
+	if doc.Version > dto.ExportFormatVersion {
+		return nil, fmt.Errorf("不支持的导出文件版本: %d", doc.Version)
+	}
+
+	res := &Result{}
+	for i, r := range doc.Feedings {
+		detail := entity.FeedingDetail(r.Detail)
+		if detail == nil {
+			detail = entity.FeedingDetail{"type": r.FeedingType}
+		}
+		res.add(&Record{Row: i + 1, Source: "feedings", Feeding: &entity.FeedingRecord{
+			Time:        r.Time,
+			FeedingType: r.FeedingType,
+			Amount:      r.AmountMl,
+			Duration:    r.Duration,
+			Detail:      detail,
+		}}, opts)
+	}
+	for i, r := range doc.Sleeps {
+		res.add(&Record{Row: i + 1, Source: "sleeps", Sleep: &entity.SleepRecord{
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+			Duration:  r.Duration,
+			Type:      r.Type,
+		}}, opts)
+	}
+	for i, r := range doc.Diapers {
+		res.add(&Record{Row: i + 1, Source: "diapers", Diaper: &entity.DiaperRecord{
+			Time:        r.Time,
+			Type:        r.Type,
+			PoopColor:   r.PoopColor,
+			PoopTexture: r.PoopTexture,
+			Note:        r.Note,
+		}}, opts)
+	}
+	for i, r := range doc.Growths {
+		res.add(&Record{Row: i + 1, Source: "growths", Growth: &entity.GrowthRecord{
+			Time:              r.Time,
+			Height:            r.Height,
+			Weight:            r.Weight,
+			HeadCircumference: r.HeadCircumference,
+			Note:              r.Note,
+		}}, opts)
+	}
+	res.Skipped = len(doc.Vaccines) + len(doc.Milestones)
+	return res, nil
+}
+
+// document 返回 JSON 文档内容: ZIP 时读取其中的 export.json
+func (p *NutriBabyParser) document(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return bytes.TrimPrefix(data, utf8BOM), nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("ZIP 文件损坏: %w", err)
+	}
+	for _, f := range zr.File {
+		if !strings.EqualFold(path.Base(f.Name), exportDocumentName) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		raw, err := io.ReadAll(io.LimitReader(rc, maxExportDocumentSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) > maxExportDocumentSize {
+			return nil, fmt.Errorf("导出文档过大")
+		}
+		return raw, nil
+	}
+	return nil, fmt.Errorf("ZIP 中未找到 %s", exportDocumentName)
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// 临时成员到期相关的订阅消息模板类型
+const (
+	collaboratorExpiryReminderTemplateType = "collaborator_expiry_reminder" // 到期前一天提醒管理员
+	collaboratorAccessEndedTemplateType    = "collaborator_access_ended"    // 到期撤销后通知临时成员
+)
+
+// collaboratorExpiryReminderLead 到期前多久提醒管理员
+const collaboratorExpiryReminderLead = 24 * time.Hour
+
+// AccessExpiryService 临时成员到期服务
+// 到期前一天提醒宝宝管理员(可延长期限),到期后撤销协作关系并通知临时成员
+type AccessExpiryService struct {
+	babyRepo           repository.BabyRepository
+	collaboratorRepo   repository.BabyCollaboratorRepository
+	userRepo           repository.UserRepository
+	subscribeService   *SubscribeService
+	reminderTemplateID string
+	endedTemplateID    string
+	logger             *zap.Logger
+}
+
+// NewAccessExpiryService 创建临时成员到期服务
+func NewAccessExpiryService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	subscribeService *SubscribeService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *AccessExpiryService {
+	return &AccessExpiryService{
+		babyRepo:           babyRepo,
+		collaboratorRepo:   collaboratorRepo,
+		userRepo:           userRepo,
+		subscribeService:   subscribeService,
+		reminderTemplateID: cfg.Wechat.SubscribeTemplates[collaboratorExpiryReminderTemplateType],
+		endedTemplateID:    cfg.Wechat.SubscribeTemplates[collaboratorAccessEndedTemplateType],
+		logger:             logger,
+	}
+}
+
+// ExtendAccess 管理员延长临时成员的访问期限
+// 已到期但尚未被定时任务撤销的成员也可以延长
+func (s *AccessExpiryService) ExtendAccess(ctx context.Context, babyID, openID, targetOpenID string, req *dto.ExtendCollaboratorAccessRequest) (*dto.CollaboratorDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	if req.ExpiresAt <= time.Now().UnixMilli() {
+		return nil, errors.New(errors.ParamError, "新的过期时间必须晚于当前时间")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin, err := s.collaboratorRepo.IsAdmin(ctx, babyIDInt64, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, errors.New(errors.PermissionDenied, "只有管理员可以延长访问期限")
+	}
+
+	targetUser, err := s.userRepo.FindByOpenID(ctx, targetOpenID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, targetUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if collaborator == nil {
+		return nil, errors.New(errors.NotFound, "亲友团成员不存在")
+	}
+	if collaborator.AccessType != "temporary" {
+		return nil, errors.New(errors.ParamError, "只有临时成员需要延长访问期限")
+	}
+
+	if err := s.collaboratorRepo.UpdateExpiry(ctx, babyIDInt64, targetUser.ID, req.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("延长临时成员访问期限",
+		zap.Int64("babyId", babyIDInt64),
+		zap.Int64("userId", targetUser.ID),
+		zap.Int64("expiresAt", req.ExpiresAt),
+		zap.String("operator", openID))
+
+	return &dto.CollaboratorDTO{
+		OpenID:       targetUser.OpenID,
+		NickName:     targetUser.NickName,
+		AvatarURL:    targetUser.AvatarURL,
+		Role:         collaborator.Role,
+		Relationship: collaborator.Relationship,
+		AccessType:   collaborator.AccessType,
+		ExpiresAt:    &req.ExpiresAt,
+		JoinTime:     collaborator.CreatedAt,
+	}, nil
+}
+
+// RemindUpcomingExpiries 提醒管理员:临时成员将在一天内到期(每位成员每个期限只提醒一次)
+func (s *AccessExpiryService) RemindUpcomingExpiries(ctx context.Context) error {
+	now := time.Now()
+	expiring, err := s.collaboratorRepo.FindExpiringBetween(ctx, now.UnixMilli(), now.Add(collaboratorExpiryReminderLead).UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, collaborator := range expiring {
+		baby, err := s.babyRepo.FindByID(ctx, collaborator.BabyID)
+		if err != nil {
+			s.logger.Warn("获取宝宝信息失败", zap.Int64("babyId", collaborator.BabyID), zap.Error(err))
+			continue
+		}
+		member, err := s.userRepo.FindByID(ctx, collaborator.UserID)
+		if err != nil {
+			s.logger.Warn("获取临时成员信息失败", zap.Int64("userId", collaborator.UserID), zap.Error(err))
+			continue
+		}
+		collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, collaborator.BabyID)
+		if err != nil {
+			s.logger.Warn("获取宝宝协作者列表失败", zap.Int64("babyId", collaborator.BabyID), zap.Error(err))
+			continue
+		}
+
+		messageData := map[string]any{
+			"thing1": baby.Name,                                  // 宝宝
+			"thing2": memberDisplayName(member, collaborator),    // 临时成员
+			"time3":  formatMessageTime(*collaborator.ExpiresAt), // 到期时间
+			"thing4": "如需继续协助,请在亲友团中延长访问期限",                      // 温馨提示
+		}
+		page := fmt.Sprintf("pages/baby/collaborators/collaborators?babyId=%d", baby.ID)
+
+		var sentCount int
+		for _, admin := range expiryReminderRecipients(collaborators, collaborator.UserID) {
+			if s.notify(ctx, admin.UserID, collaboratorExpiryReminderTemplateType, s.reminderTemplateID, messageData, page) {
+				sentCount++
+			}
+		}
+
+		// 无论是否有人接收都标记已提醒,避免每小时重复处理
+		if err := s.collaboratorRepo.MarkReminded(ctx, collaborator.ID, time.Now().UnixMilli()); err != nil {
+			s.logger.Error("标记到期提醒失败", zap.Int64("collaboratorId", collaborator.ID), zap.Error(err))
+			continue
+		}
+
+		s.logger.Info("临时成员到期提醒发送完成",
+			zap.Int64("babyId", collaborator.BabyID),
+			zap.Int64("userId", collaborator.UserID),
+			zap.Int("sentCount", sentCount))
+	}
+
+	return nil
+}
+
+// RevokeExpired 撤销已到期的临时成员,并通知对方访问已结束
+func (s *AccessExpiryService) RevokeExpired(ctx context.Context) error {
+	now := time.Now().UnixMilli()
+	expired, err := s.collaboratorRepo.FindExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, collaborator := range expired {
+		// 查询后可能已被续期或移除,删除时再次按到期条件判断
+		deleted, err := s.collaboratorRepo.DeleteExpired(ctx, collaborator.BabyID, collaborator.UserID, now)
+		if err != nil {
+			s.logger.Error("撤销过期临时成员失败",
+				zap.Int64("babyId", collaborator.BabyID),
+				zap.Int64("userId", collaborator.UserID),
+				zap.Error(err))
+			continue
+		}
+		if !deleted {
+			continue
+		}
+
+		member, err := s.userRepo.FindByID(ctx, collaborator.UserID)
+		if err != nil {
+			s.logger.Warn("获取临时成员信息失败", zap.Int64("userId", collaborator.UserID), zap.Error(err))
+			continue
+		}
+		resetDefaultBaby(ctx, s.collaboratorRepo, s.userRepo, member, collaborator.BabyID, s.logger)
+
+		babyName := ""
+		if baby, err := s.babyRepo.FindByID(ctx, collaborator.BabyID); err == nil {
+			babyName = baby.Name
+		}
+		messageData := map[string]any{
+			"thing1": babyName,                                   // 宝宝
+			"time2":  formatMessageTime(*collaborator.ExpiresAt), // 到期时间
+			"thing3": "临时访问已结束,如需继续请联系宝宝管理员",                     // 温馨提示
+		}
+		s.notify(ctx, collaborator.UserID, collaboratorAccessEndedTemplateType, s.endedTemplateID, messageData, "pages/index/index")
+
+		s.logger.Info("已撤销过期临时成员",
+			zap.Int64("babyId", collaborator.BabyID),
+			zap.Int64("userId", collaborator.UserID))
+	}
+
+	return nil
+}
+
+// notify 向已授权该模板的用户发送订阅消息,返回是否发送成功
+func (s *AccessExpiryService) notify(ctx context.Context, userID int64, templateType, templateID string, data map[string]any, page string) bool {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		s.logger.Warn("获取用户信息失败", zap.Int64("userID", userID), zap.Error(err))
+		return false
+	}
+
+	hasAuth, err := s.subscribeService.CheckAuthorizationStatus(ctx, user.OpenID, templateType)
+	if err != nil || !hasAuth {
+		return false
+	}
+
+	sendReq := &dto.SendMessageRequest{
+		OpenID:     user.OpenID,
+		TemplateID: templateID,
+		Data:       data,
+		Page:       page,
+	}
+	if err := s.subscribeService.SendSubscribeMessage(ctx, sendReq); err != nil {
+		s.logger.Warn("发送临时成员到期消息失败",
+			zap.String("openID", user.OpenID),
+			zap.String("templateType", templateType),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// expiryReminderRecipients 到期提醒的接收人: 除到期成员本人外的有效管理员
+func expiryReminderRecipients(collaborators []*entity.BabyCollaborator, expiringUserID int64) []*entity.BabyCollaborator {
+	var admins []*entity.BabyCollaborator
+	for _, collab := range collaborators {
+		if collab.UserID != expiringUserID && collab.IsAdmin() && !collab.IsExpired() {
+			admins = append(admins, collab)
+		}
+	}
+	return admins
+}
+
+// memberDisplayName 消息中展示的成员名称,优先带上与宝宝的关系
+func memberDisplayName(user *entity.User, collaborator *entity.BabyCollaborator) string {
+	if collaborator.Relationship == "" {
+		return user.NickName
+	}
+	if user.NickName == "" {
+		return collaborator.Relationship
+	}
+	return fmt.Sprintf("%s(%s)", user.NickName, collaborator.Relationship)
+}
+
+// formatMessageTime 订阅消息 time 类型字段格式
+func formatMessageTime(ms int64) string {
+	return time.UnixMilli(ms).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestCheckCollaboratorActive(t *testing.T) {
+	past := time.Now().Add(-time.Minute).UnixMilli()
+	future := time.Now().Add(time.Hour).UnixMilli()
+
+	assert.ErrorContains(t, checkCollaboratorActive(nil), "您没有权限访问该宝宝的记录")
+	// 过期但尚未被定时任务撤销的临时成员同样被拒绝,即使是管理员
+	assert.ErrorContains(t, checkCollaboratorActive(&entity.BabyCollaborator{
+		Role: entity.CollaboratorRoleAdmin, AccessType: "temporary", ExpiresAt: &past,
+	}), "临时访问权限已过期")
+	assert.NoError(t, checkCollaboratorActive(&entity.BabyCollaborator{
+		Role: entity.CollaboratorRoleCaregiver, AccessType: "temporary", ExpiresAt: &future,
+	}))
+	assert.NoError(t, checkCollaboratorActive(&entity.BabyCollaborator{Role: entity.CollaboratorRoleViewer, AccessType: "permanent"}))
+}
+
+func TestExpiryReminderRecipients(t *testing.T) {
+	past := time.Now().Add(-time.Minute).UnixMilli()
+	soon := time.Now().Add(12 * time.Hour).UnixMilli()
+	collaborators := []*entity.BabyCollaborator{
+		{UserID: 1, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"},
+		{UserID: 2, Role: entity.CollaboratorRoleEditor, AccessType: "permanent"},
+		{UserID: 3, Role: entity.CollaboratorRoleAdmin, AccessType: "temporary", ExpiresAt: &soon},
+		{UserID: 4, Role: entity.CollaboratorRoleAdmin, AccessType: "temporary", ExpiresAt: &past},
+	}
+
+	// 到期成员本人和已过期的管理员不接收提醒
+	recipients := expiryReminderRecipients(collaborators, 3)
+	assert.Len(t, recipients, 1)
+	assert.Equal(t, int64(1), recipients[0].UserID)
+
+	assert.Len(t, expiryReminderRecipients(collaborators, 2), 2)
+}
+
+func TestMemberDisplayName(t *testing.T) {
+	user := &entity.User{NickName: "小王"}
+	assert.Equal(t, "小王(育儿嫂)", memberDisplayName(user, &entity.BabyCollaborator{Relationship: "育儿嫂"}))
+	assert.Equal(t, "小王", memberDisplayName(user, &entity.BabyCollaborator{}))
+	assert.Equal(t, "育儿嫂", memberDisplayName(&entity.User{}, &entity.BabyCollaborator{Relationship: "育儿嫂"}))
+}
@@ -102,33 +102,43 @@ func (ct CustomTime) MarshalJSON() ([]byte, error) {
 }
 
 // AIAnalysisService AI分析服务接口
+// 面向用户的方法都需要传入调用者 openID,按协作者权限校验宝宝访问权限;
+// GenerateDailyTips 和 ProcessPendingAnalyses 仅供定时任务调用,不做权限校验
 type AIAnalysisService interface {
 	// 创建分析任务
-	CreateAnalysis(ctx context.Context, req *CreateAnalysisRequest) (*AnalysisResponse, error)
+	CreateAnalysis(ctx context.Context, openID string, req *CreateAnalysisRequest) (*AnalysisResponse, error)
 
-	// 生成每日建议
+	// 生成每日建议(定时任务)
 	GenerateDailyTips(ctx context.Context, babyID string, date time.Time) (*DailyTipsResponse, error)
 
-	// 处理待分析的任务
+	// 处理待分析的任务(定时任务)
 	ProcessPendingAnalyses(ctx context.Context) error
 
 	// 获取分析结果
-	GetAnalysisResult(ctx context.Context, analysisID string) (*AnalysisResponse, error)
+	GetAnalysisResult(ctx context.Context, openID, analysisID string) (*AnalysisResponse, error)
 
 	// 获取分析状态（用于轮询）
-	GetAnalysisStatus(ctx context.Context, analysisID string) (*AnalysisStatusResponse, error)
+	GetAnalysisStatus(ctx context.Context, openID, analysisID string) (*AnalysisStatusResponse, error)
 
 	// 获取最新分析
-	GetLatestAnalysis(ctx context.Context, babyID string, analysisType entity.AIAnalysisType) (*AnalysisResponse, error)
+	GetLatestAnalysis(ctx context.Context, openID, babyID string, analysisType entity.AIAnalysisType) (*AnalysisResponse, error)
 
 	// 批量分析
-	BatchAnalyze(ctx context.Context, req *BatchAnalysisRequest) (*BatchAnalysisResponse, error)
+	BatchAnalyze(ctx context.Context, openID string, req *BatchAnalysisRequest) (*BatchAnalysisResponse, error)
 
-	// 获取每日建议
-	GetDailyTips(ctx context.Context, babyID string, date time.Time) (*DailyTipsResponse, error)
+	// 获取每日建议(当日没有时生成)
+	GetDailyTips(ctx context.Context, openID, babyID string, date time.Time) (*DailyTipsResponse, error)
 
 	// 获取分析统计
-	GetAnalysisStats(ctx context.Context, babyID string, days int) (*AnalysisStatsResponse, error)
+	GetAnalysisStats(ctx context.Context, openID, babyID string, days int) (*AnalysisStatsResponse, error)
+}
+
+// aiAnalysisResources AI分析读取的记录类型,调用者需要全部类型的查看权限
+var aiAnalysisResources = []string{
+	entity.PermissionResourceFeeding,
+	entity.PermissionResourceSleep,
+	entity.PermissionResourceDiaper,
+	entity.PermissionResourceGrowth,
 }
 
 // aiAnalysisServiceImpl AI分析服务实现
@@ -136,6 +146,7 @@ type aiAnalysisServiceImpl struct {
 	aiAnalysisRepo repository.AIAnalysisRepository
 	dailyTipsRepo  repository.DailyTipsRepository
 	babyRepo       repository.BabyRepository
+	permissions    *PermissionService
 	chainBuilder   *chain.AnalysisChainBuilder
 	logger         *zap.Logger
 }
@@ -145,6 +156,8 @@ func NewAIAnalysisService(
 	aiAnalysisRepo repository.AIAnalysisRepository,
 	dailyTipsRepo repository.DailyTipsRepository,
 	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
 	chainBuilder *chain.AnalysisChainBuilder,
 	logger *zap.Logger,
 ) AIAnalysisService {
@@ -152,13 +165,18 @@ func NewAIAnalysisService(
 		aiAnalysisRepo: aiAnalysisRepo,
 		dailyTipsRepo:  dailyTipsRepo,
 		babyRepo:       babyRepo,
+		permissions:    NewPermissionService(collaboratorRepo, userRepo, logger),
 		chainBuilder:   chainBuilder,
 		logger:         logger,
 	}
 }
 
 // CreateAnalysis 创建分析任务（异步模式）
-func (s *aiAnalysisServiceImpl) CreateAnalysis(ctx context.Context, req *CreateAnalysisRequest) (*AnalysisResponse, error) {
+func (s *aiAnalysisServiceImpl) CreateAnalysis(ctx context.Context, openID string, req *CreateAnalysisRequest) (*AnalysisResponse, error) {
+	if err := s.authorize(ctx, openID, req.BabyID); err != nil {
+		return nil, err
+	}
+
 	// 验证宝宝是否存在
 	_, err := s.babyRepo.FindByID(ctx, req.BabyID)
 	if err != nil {
@@ -206,7 +224,7 @@ func (s *aiAnalysisServiceImpl) CreateAnalysis(ctx context.Context, req *CreateA
 	}, nil
 }
 
-// GenerateDailyTips 生成每日建议
+// GenerateDailyTips 生成每日建议(定时任务调用,不校验权限)
 func (s *aiAnalysisServiceImpl) GenerateDailyTips(ctx context.Context, babyID string, date time.Time) (*DailyTipsResponse, error) {
 	// 转换ID类型
 	id, err := strconv.ParseInt(babyID, 10, 64)
@@ -304,17 +322,17 @@ func (s *aiAnalysisServiceImpl) ProcessPendingAnalyses(ctx context.Context) erro
 }
 
 // GetAnalysisResult 获取分析结果
-func (s *aiAnalysisServiceImpl) GetAnalysisResult(ctx context.Context, analysisID string) (*AnalysisResponse, error) {
-	id, err := strconv.ParseInt(analysisID, 10, 64)
+func (s *aiAnalysisServiceImpl) GetAnalysisResult(ctx context.Context, openID, analysisID string) (*AnalysisResponse, error) {
+	analysis, err := s.findAuthorizedAnalysis(ctx, openID, analysisID)
 	if err != nil {
-		return nil, errors.Wrap(errors.ParamError, "无效的分析ID", err)
-	}
-
-	analysis, err := s.aiAnalysisRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, errors.Wrap(errors.NotFound, "获取分析记录失败", err)
+		return nil, err
 	}
+	return s.toAnalysisResponse(analysis), nil
+}
 
+// toAnalysisResponse 转换为分析结果响应,已完成的分析解析结果内容
+func (s *aiAnalysisServiceImpl) toAnalysisResponse(analysis *entity.AIAnalysis) *AnalysisResponse {
+	id := analysis.ID
 	response := &AnalysisResponse{
 		AnalysisID: id, // 使用int64类型的id
 		Status:     analysis.Status,
@@ -329,24 +347,19 @@ func (s *aiAnalysisServiceImpl) GetAnalysisResult(ctx context.Context, analysisI
 				zap.Int64("analysis_id", id),
 				zap.Error(err),
 			)
-			return response, nil
+			return response
 		}
 		response.Result = &result
 	}
 
-	return response, nil
+	return response
 }
 
 // GetAnalysisStatus 获取分析状态（用于轮询）
-func (s *aiAnalysisServiceImpl) GetAnalysisStatus(ctx context.Context, analysisID string) (*AnalysisStatusResponse, error) {
-	id, err := strconv.ParseInt(analysisID, 10, 64)
+func (s *aiAnalysisServiceImpl) GetAnalysisStatus(ctx context.Context, openID, analysisID string) (*AnalysisStatusResponse, error) {
+	analysis, err := s.findAuthorizedAnalysis(ctx, openID, analysisID)
 	if err != nil {
-		return nil, errors.Wrap(errors.ParamError, "无效的分析ID", err)
-	}
-
-	analysis, err := s.aiAnalysisRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, errors.Wrap(errors.NotFound, "获取分析记录失败", err)
+		return nil, err
 	}
 
 	// 根据状态计算进度和消息
@@ -381,22 +394,29 @@ func (s *aiAnalysisServiceImpl) GetAnalysisStatus(ctx context.Context, analysisI
 }
 
 // GetLatestAnalysis 获取最新分析
-func (s *aiAnalysisServiceImpl) GetLatestAnalysis(ctx context.Context, babyID string, analysisType entity.AIAnalysisType) (*AnalysisResponse, error) {
+func (s *aiAnalysisServiceImpl) GetLatestAnalysis(ctx context.Context, openID, babyID string, analysisType entity.AIAnalysisType) (*AnalysisResponse, error) {
 	id, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
 		return nil, errors.Wrap(errors.ParamError, "无效的宝宝ID", err)
 	}
+	if err := s.authorize(ctx, openID, id); err != nil {
+		return nil, err
+	}
 
 	analysis, err := s.aiAnalysisRepo.GetLatestByBabyIDAndType(ctx, id, analysisType)
 	if err != nil {
 		return nil, errors.Wrap(errors.NotFound, "获取最新分析失败", err)
 	}
 
-	return s.GetAnalysisResult(ctx, strconv.FormatInt(analysis.ID, 10))
+	return s.toAnalysisResponse(analysis), nil
 }
 
 // BatchAnalyze 批量分析（异步模式）
-func (s *aiAnalysisServiceImpl) BatchAnalyze(ctx context.Context, req *BatchAnalysisRequest) (*BatchAnalysisResponse, error) {
+func (s *aiAnalysisServiceImpl) BatchAnalyze(ctx context.Context, openID string, req *BatchAnalysisRequest) (*BatchAnalysisResponse, error) {
+	if err := s.authorize(ctx, openID, req.BabyID); err != nil {
+		return nil, err
+	}
+
 	// 验证宝宝是否存在
 	_, err := s.babyRepo.FindByID(ctx, req.BabyID)
 	if err != nil {
@@ -473,12 +493,28 @@ func (s *aiAnalysisServiceImpl) BatchAnalyze(ctx context.Context, req *BatchAnal
 }
 
 // GetDailyTips 获取每日建议
-func (s *aiAnalysisServiceImpl) GetDailyTips(ctx context.Context, babyID string, date time.Time) (*DailyTipsResponse, error) {
+func (s *aiAnalysisServiceImpl) GetDailyTips(ctx context.Context, openID, babyID string, date time.Time) (*DailyTipsResponse, error) {
+	id, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(errors.ParamError, "无效的宝宝ID", err)
+	}
+	if err := s.authorize(ctx, openID, id); err != nil {
+		return nil, err
+	}
+
 	return s.GenerateDailyTips(ctx, babyID, date)
 }
 
 // GetAnalysisStats 获取分析统计
-func (s *aiAnalysisServiceImpl) GetAnalysisStats(ctx context.Context, babyID string, days int) (*AnalysisStatsResponse, error) {
+func (s *aiAnalysisServiceImpl) GetAnalysisStats(ctx context.Context, openID, babyID string, days int) (*AnalysisStatsResponse, error) {
+	id, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(errors.ParamError, "无效的宝宝ID", err)
+	}
+	if err := s.authorize(ctx, openID, id); err != nil {
+		return nil, err
+	}
+
 	// 简化实现，返回基本统计信息
 	stats := &AnalysisStatsResponse{
 		TotalAnalyses: 0,
@@ -497,6 +533,33 @@ func (s *aiAnalysisServiceImpl) GetAnalysisStats(ctx context.Context, babyID str
 	return stats, nil
 }
 
+// authorize 检查调用者是否为宝宝的有效协作者,且能查看AI分析用到的全部记录类型
+func (s *aiAnalysisServiceImpl) authorize(ctx context.Context, openID string, babyID int64) error {
+	if openID == "" {
+		return errors.ErrUnauthorized
+	}
+	_, err := s.permissions.AuthorizeAll(ctx, babyID, openID, entity.PermissionActionRead, aiAnalysisResources...)
+	return err
+}
+
+// findAuthorizedAnalysis 获取分析记录并校验调用者对所属宝宝的访问权限
+func (s *aiAnalysisServiceImpl) findAuthorizedAnalysis(ctx context.Context, openID, analysisID string) (*entity.AIAnalysis, error) {
+	id, err := strconv.ParseInt(analysisID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(errors.ParamError, "无效的分析ID", err)
+	}
+
+	analysis, err := s.aiAnalysisRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(errors.NotFound, "获取分析记录失败", err)
+	}
+
+	if err := s.authorize(ctx, openID, analysis.BabyID); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
 // processAnalysis 处理单个分析任务
 func (s *aiAnalysisServiceImpl) processAnalysis(ctx context.Context, analysisID int64) error {
 	// 更新状态为分析中
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// 以下桩只实现权限校验会用到的方法,未实现的方法被调用时会 panic,
+// 以此保证被拒绝的请求不会创建分析任务或读取其他家庭的数据
+
+type stubAIUserRepository struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *stubAIUserRepository) FindByOpenID(ctx context.Context, openID string) (*entity.User, error) {
+	if user, ok := r.users[openID]; ok {
+		return user, nil
+	}
+	return nil, errors.ErrNotFound
+}
+
+type stubAICollaboratorRepository struct {
+	repository.BabyCollaboratorRepository
+	collaborators []*entity.BabyCollaborator
+}
+
+func (r *stubAICollaboratorRepository) FindByBabyAndUser(ctx context.Context, babyID int64, userID int64) (*entity.BabyCollaborator, error) {
+	for _, c := range r.collaborators {
+		if c.BabyID == babyID && c.UserID == userID {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubAICollaboratorRepository) CheckPermission(ctx context.Context, babyID int64, userID int64) (*entity.BabyCollaborator, error) {
+	c, _ := r.FindByBabyAndUser(ctx, babyID, userID)
+	if c != nil && c.IsExpired() {
+		return nil, nil
+	}
+	return c, nil
+}
+
+type stubAIAnalysisRepository struct {
+	repository.AIAnalysisRepository
+	analyses map[int64]*entity.AIAnalysis
+}
+
+func (r *stubAIAnalysisRepository) GetByID(ctx context.Context, id int64) (*entity.AIAnalysis, error) {
+	if analysis, ok := r.analyses[id]; ok {
+		return analysis, nil
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (r *stubAIAnalysisRepository) GetLatestByBabyIDAndType(ctx context.Context, babyID int64, analysisType entity.AIAnalysisType) (*entity.AIAnalysis, error) {
+	for _, analysis := range r.analyses {
+		if analysis.BabyID == babyID && analysis.AnalysisType == analysisType {
+			return analysis, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+const (
+	aiTestBabyID     int64 = 1001 // A 家的宝宝
+	aiTestAnalysisID int64 = 5001 // A 家宝宝的分析记录
+)
+
+func newAIAnalysisServiceForTest() AIAnalysisService {
+	userRepo := &stubAIUserRepository{users: map[string]*entity.User{
+		"parent-a":    {ID: 1, OpenID: "parent-a"},
+		"parent-b":    {ID: 2, OpenID: "parent-b"},
+		"caregiver-a": {ID: 3, OpenID: "caregiver-a"},
+	}}
+	collaboratorRepo := &stubAICollaboratorRepository{collaborators: []*entity.BabyCollaborator{
+		{BabyID: aiTestBabyID, UserID: 1, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"},
+		{BabyID: 2002, UserID: 2, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"},
+		{BabyID: aiTestBabyID, UserID: 3, Role: entity.CollaboratorRoleCaregiver, AccessType: "permanent"},
+	}}
+	analysisRepo := &stubAIAnalysisRepository{analyses: map[int64]*entity.AIAnalysis{
+		aiTestAnalysisID: {
+			ID:           aiTestAnalysisID,
+			BabyID:       aiTestBabyID,
+			AnalysisType: entity.AIAnalysisTypeFeeding,
+			Status:       entity.AIAnalysisStatusPending,
+		},
+	}}
+	return NewAIAnalysisService(analysisRepo, nil, nil, collaboratorRepo, userRepo, nil, zap.NewNop())
+}
+
+// aiRouteCalls 每个 AI 路由对应的服务调用
+func aiRouteCalls() map[string]func(svc AIAnalysisService, openID string) error {
+	ctx := context.Background()
+	babyID := "1001"
+	analysisID := "5001"
+	now := time.Now()
+	period := func() (CustomTime, CustomTime) {
+		return CustomTime{Time: now.AddDate(0, 0, -7)}, CustomTime{Time: now}
+	}
+
+	return map[string]func(svc AIAnalysisService, openID string) error{
+		"POST /ai-analysis": func(svc AIAnalysisService, openID string) error {
+			start, end := period()
+			_, err := svc.CreateAnalysis(ctx, openID, &CreateAnalysisRequest{
+				BabyID: aiTestBabyID, AnalysisType: entity.AIAnalysisTypeFeeding, StartDate: start, EndDate: end,
+			})
+			return err
+		},
+		"GET /ai-analysis/:id": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetAnalysisResult(ctx, openID, analysisID)
+			return err
+		},
+		"GET /ai-analysis/:id/status": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetAnalysisStatus(ctx, openID, analysisID)
+			return err
+		},
+		"GET /ai-analysis/baby/:babyId/latest": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetLatestAnalysis(ctx, openID, babyID, entity.AIAnalysisTypeFeeding)
+			return err
+		},
+		"GET /ai-analysis/baby/:babyId/history": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetAnalysisStats(ctx, openID, babyID, 30)
+			return err
+		},
+		"POST /ai-analysis/batch": func(svc AIAnalysisService, openID string) error {
+			start, end := period()
+			_, err := svc.BatchAnalyze(ctx, openID, &BatchAnalysisRequest{BabyID: aiTestBabyID, StartDate: start, EndDate: end})
+			return err
+		},
+		"GET /ai-analysis/daily-tips/:babyId": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetDailyTips(ctx, openID, babyID, now)
+			return err
+		},
+		"POST /ai-analysis/daily-tips/:babyId/generate": func(svc AIAnalysisService, openID string) error {
+			_, err := svc.GetDailyTips(ctx, openID, babyID, now)
+			return err
+		},
+	}
+}
+
+func assertAIErrorCode(t *testing.T, err error, code errors.ErrorCode) {
+	t.Helper()
+	require.Error(t, err)
+	var appErr *errors.AppError
+	require.True(t, stderrors.As(err, &appErr), "unexpected error: %v", err)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func TestAIAnalysisDeniesCrossFamilyAccess(t *testing.T) {
+	svc := newAIAnalysisServiceForTest()
+	for route, call := range aiRouteCalls() {
+		t.Run(route, func(t *testing.T) {
+			assertAIErrorCode(t, call(svc, "parent-b"), errors.PermissionDenied)
+		})
+	}
+}
+
+func TestAIAnalysisDeniesCollaboratorWithoutReadPermission(t *testing.T) {
+	// 照护者看不到成长记录,不能使用汇总全部数据的AI分析
+	svc := newAIAnalysisServiceForTest()
+	for route, call := range aiRouteCalls() {
+		t.Run(route, func(t *testing.T) {
+			assertAIErrorCode(t, call(svc, "caregiver-a"), errors.PermissionDenied)
+		})
+	}
+}
+
+func TestAIAnalysisRequiresCaller(t *testing.T) {
+	svc := newAIAnalysisServiceForTest()
+	for route, call := range aiRouteCalls() {
+		t.Run(route, func(t *testing.T) {
+			assertAIErrorCode(t, call(svc, ""), errors.Unauthorized)
+		})
+	}
+}
+
+func TestAIAnalysisAllowsOwnFamily(t *testing.T) {
+	svc := newAIAnalysisServiceForTest()
+	ctx := context.Background()
+
+	result, err := svc.GetAnalysisResult(ctx, "parent-a", "5001")
+	require.NoError(t, err)
+	assert.Equal(t, aiTestAnalysisID, result.AnalysisID)
+
+	status, err := svc.GetAnalysisStatus(ctx, "parent-a", "5001")
+	require.NoError(t, err)
+	assert.Equal(t, entity.AIAnalysisStatusPending, status.Status)
+
+	latest, err := svc.GetLatestAnalysis(ctx, "parent-a", "1001", entity.AIAnalysisTypeFeeding)
+	require.NoError(t, err)
+	assert.Equal(t, aiTestAnalysisID, latest.AnalysisID)
+}
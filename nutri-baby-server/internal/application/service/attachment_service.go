@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/imageproc"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/snowflake"
+)
+
+// galleryMonthLayout 相册月份格式
+const galleryMonthLayout = "2006-01"
+
+// AttachmentService 照片附件服务
+type AttachmentService struct {
+	*BaseRecordService
+	attachmentRepo    repository.AttachmentRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	milestoneRepo     repository.BabyMilestoneRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	uploadService     *UploadService
+}
+
+// NewAttachmentService 创建照片附件服务
+func NewAttachmentService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	attachmentRepo repository.AttachmentRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	uploadService *UploadService,
+	logger *zap.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		attachmentRepo:    attachmentRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		milestoneRepo:     milestoneRepo,
+		scheduleRepo:      scheduleRepo,
+		uploadService:     uploadService,
+	}
+}
+
+// UploadAttachment 为记录上传照片附件
+func (s *AttachmentService) UploadAttachment(ctx context.Context, openID, recordType, recordID string, fileHeader *multipart.FileHeader) (*dto.AttachmentDTO, error) {
+	recordIDInt64, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+
+	babyID, eventTime, err := s.resolveRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// 附件权限跟随所属记录类型
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(babyID, 10), openID, recordType, entity.PermissionActionCreate); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedID := fmt.Sprintf("%s_%d_%d", recordType, recordIDInt64, snowflake.Generate())
+	result, err := s.uploadService.UploadFile(ctx, fileHeader, UploadTypeAttachment, relatedID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &entity.Attachment{
+		BabyID:       babyID,
+		RecordType:   recordType,
+		RecordID:     recordIDInt64,
+		URL:          result.URL,
+		DisplayURL:   result.VariantURL(imageproc.VariantDisplay),
+		ThumbnailURL: result.VariantURL(imageproc.VariantThumbnail),
+		Path:         result.Path,
+		Filename:     result.Filename,
+		Size:         result.Size,
+		Width:        result.Width,
+		Height:       result.Height,
+		TakenAt:      eventTime,
+		UploadedBy:   user.ID,
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		s.logger.Error("保存附件失败",
+			zap.String("recordType", recordType),
+			zap.String("recordID", recordID),
+			zap.Error(err))
+		// 回滚已写入的文件
+		_ = s.uploadService.DeleteFile(ctx, result.Path)
+		return nil, err
+	}
+
+	attachmentDTO := s.toAttachmentDTO(attachment)
+	return &attachmentDTO, nil
+}
+
+// GetRecordAttachments 获取记录的照片附件
+func (s *AttachmentService) GetRecordAttachments(ctx context.Context, openID, recordType, recordID string) ([]dto.AttachmentDTO, error) {
+	recordIDInt64, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+
+	babyID, _, err := s.resolveRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(babyID, 10), openID, recordType, entity.PermissionActionRead); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.FindByRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.AttachmentDTO, 0, len(attachments))
+	for _, attachment := range attachments {
+		result = append(result, s.toAttachmentDTO(attachment))
+	}
+	return result, nil
+}
+
+// DeleteAttachment 删除照片附件
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, openID, attachmentID string) error {
+	attachmentIDInt64, err := strconv.ParseInt(attachmentID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid attachment id format")
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(ctx, attachmentIDInt64)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(attachment.BabyID, 10), openID, attachment.RecordType, entity.PermissionActionDelete); err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachmentIDInt64); err != nil {
+		return err
+	}
+
+	// 主动删除的附件同时清理文件(记录级联删除只做软删除,保留文件)
+	if err := s.uploadService.DeleteFile(ctx, attachment.Path); err != nil {
+		s.logger.Warn("删除附件文件失败", zap.String("path", attachment.Path), zap.Error(err))
+	}
+
+	return nil
+}
+
+// GetGallery 获取宝宝相册(按月分页)
+func (s *AttachmentService) GetGallery(ctx context.Context, openID, babyID string, query *dto.GalleryQuery) (*dto.GalleryResponse, error) {
+	readable, err := s.ReadableResources(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	monthStart := time.Now()
+	if query.Month != "" {
+		monthStart, err = time.ParseInLocation(galleryMonthLayout, query.Month, time.Local)
+		if err != nil {
+			return nil, errors.New(errors.ParamError, "月份格式错误，应为YYYY-MM")
+		}
+	}
+	monthStart = time.Date(monthStart.Year(), monthStart.Month(), 1, 0, 0, 0, 0, time.Local)
+	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Millisecond)
+
+	attachments, err := s.attachmentRepo.FindByBabyIDInRange(ctx, babyIDInt64, monthStart.UnixMilli(), monthEnd.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.AttachmentDTO, 0, len(attachments))
+	for _, attachment := range attachments {
+		// 只展示有查看权限的记录类型的照片
+		if !readable[attachment.RecordType] {
+			continue
+		}
+		items = append(items, s.toAttachmentDTO(attachment))
+	}
+
+	resp := &dto.GalleryResponse{
+		Month: monthStart.Format(galleryMonthLayout),
+		Items: items,
+		Total: len(items),
+	}
+
+	// 相邻有照片的月份,便于前端跳过空月份翻页
+	prev, err := s.attachmentRepo.FindLatestBefore(ctx, babyIDInt64, monthStart.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		month := time.UnixMilli(prev.TakenAt).In(time.Local).Format(galleryMonthLayout)
+		resp.PrevMonth = &month
+	}
+
+	next, err := s.attachmentRepo.FindEarliestAfter(ctx, babyIDInt64, monthEnd.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if next != nil {
+		month := time.UnixMilli(next.TakenAt).In(time.Local).Format(galleryMonthLayout)
+		resp.NextMonth = &month
+	}
+
+	return resp, nil
+}
+
+// resolveRecord 解析附件关联的记录,返回所属宝宝ID和记录时间
+func (s *AttachmentService) resolveRecord(ctx context.Context, recordType string, recordID int64) (int64, int64, error) {
+	switch recordType {
+	case entity.AttachmentRecordTypeFeeding:
+		record, err := s.feedingRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return record.BabyID, record.Time, nil
+	case entity.AttachmentRecordTypeSleep:
+		record, err := s.sleepRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return record.BabyID, record.StartTime, nil
+	case entity.AttachmentRecordTypeDiaper:
+		record, err := s.diaperRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return record.BabyID, record.Time, nil
+	case entity.AttachmentRecordTypeGrowth:
+		record, err := s.growthRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return record.BabyID, record.Time, nil
+	case entity.AttachmentRecordTypeMilestone:
+		milestone, err := s.milestoneRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if milestone.AchievedDate != nil {
+			return milestone.BabyID, *milestone.AchievedDate, nil
+		}
+		return milestone.BabyID, time.Now().UnixMilli(), nil
+	case entity.AttachmentRecordTypeVaccine:
+		schedule, err := s.scheduleRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if schedule.VaccineDate != nil {
+			return schedule.BabyID, *schedule.VaccineDate, nil
+		}
+		return schedule.BabyID, schedule.ScheduledDate, nil
+	default:
+		return 0, 0, errors.New(errors.ParamError, "不支持的记录类型")
+	}
+}
+
+// toAttachmentDTO 转换为DTO
+// 照片为私密文件,每次返回时按存储路径重新生成限时签名URL
+func (s *AttachmentService) toAttachmentDTO(attachment *entity.Attachment) dto.AttachmentDTO {
+	url := s.uploadService.FileURL(attachment.Path)
+	displayURL, thumbnailURL := url, url
+	if attachment.DisplayURL != "" {
+		displayURL = s.uploadService.FileURL(s.uploadService.VariantPath(attachment.Path, imageproc.VariantDisplay))
+	}
+	if attachment.ThumbnailURL != "" {
+		thumbnailURL = s.uploadService.FileURL(s.uploadService.VariantPath(attachment.Path, imageproc.VariantThumbnail))
+	}
+
+	return dto.AttachmentDTO{
+		AttachmentID: strconv.FormatInt(attachment.ID, 10),
+		BabyID:       strconv.FormatInt(attachment.BabyID, 10),
+		RecordType:   attachment.RecordType,
+		RecordID:     strconv.FormatInt(attachment.RecordID, 10),
+		URL:          url,
+		DisplayURL:   displayURL,
+		ThumbnailURL: thumbnailURL,
+		Filename:     attachment.Filename,
+		Size:         attachment.Size,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+		TakenAt:      attachment.TakenAt,
+		UploadedBy:   strconv.FormatInt(attachment.UploadedBy, 10),
+		CreateTime:   attachment.CreatedAt,
+	}
+}
@@ -22,7 +22,13 @@ type BabyService struct {
 	collaboratorRepo       repository.BabyCollaboratorRepository
 	invitationRepo         repository.BabyInvitationRepository
 	userRepo               repository.UserRepository
+	invitationService      *InvitationService
+	ownershipService       *OwnershipService
+	permissions            *PermissionService // 有效协作者校验(统一拒绝过期的临时协作者)
+	dailyTipsRepo          repository.DailyTipsRepository
 	vaccineScheduleService *VaccineScheduleService
+	vaccinePlanService     *VaccinePlanService
+	milestoneService       *MilestoneService
 	wechatService          *WechatService
 	logger                 *zap.Logger
 }
@@ -33,7 +39,12 @@ func NewBabyService(
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	invitationRepo repository.BabyInvitationRepository,
 	userRepo repository.UserRepository,
+	invitationService *InvitationService,
+	ownershipService *OwnershipService,
+	dailyTipsRepo repository.DailyTipsRepository,
 	vaccineScheduleService *VaccineScheduleService,
+	vaccinePlanService *VaccinePlanService,
+	milestoneService *MilestoneService,
 	wechatService *WechatService,
 	logger *zap.Logger,
 ) *BabyService {
@@ -42,7 +53,13 @@ func NewBabyService(
 		collaboratorRepo:       collaboratorRepo,
 		invitationRepo:         invitationRepo,
 		userRepo:               userRepo,
+		invitationService:      invitationService,
+		ownershipService:       ownershipService,
+		permissions:            NewPermissionService(collaboratorRepo, userRepo, logger),
+		dailyTipsRepo:          dailyTipsRepo,
 		vaccineScheduleService: vaccineScheduleService,
+		vaccinePlanService:     vaccinePlanService,
+		milestoneService:       milestoneService,
 		wechatService:          wechatService,
 		logger:                 logger,
 	}
@@ -104,6 +121,12 @@ func (s *BabyService) CreateBaby(ctx context.Context, openID string, req *dto.Cr
 		s.logger.Error("初始化疫苗计划失败", zap.Error(err))
 	}
 
+	// 初始化发育里程碑
+	if err := s.milestoneService.InitializeMilestonesForBaby(ctx, strconv.FormatInt(baby.ID, 10), openID); err != nil {
+		// 记录错误但不影响创建宝宝
+		s.logger.Error("初始化发育里程碑失败", zap.Error(err))
+	}
+
 	// 如果该用户没有其他宝宝,直接将该宝宝设置为默认宝宝
 	if err := s.setDefaultBabyIfNeeded(ctx, openID, baby.ID); err != nil {
 		s.logger.Error("设置默认宝宝失败", zap.Error(err))
@@ -185,33 +208,37 @@ func (s *BabyService) GetBabyDetail(ctx context.Context, babyID, openID string)
 }
 
 // UpdateBaby 更新宝宝信息
-func (s *BabyService) UpdateBaby(ctx context.Context, babyID, openID string, req *dto.UpdateBabyRequest) error {
+// 出生日期变化时按新日期重新计算待接种的疫苗日程(已接种、已跳过、自定义的不变),
+// 并清除按旧月龄生成的每日建议
+func (s *BabyService) UpdateBaby(ctx context.Context, babyID, openID string, req *dto.UpdateBabyRequest) (*dto.UpdateBabyResultDTO, error) {
 	// 转换babyID from string to int64
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
-		return errors.New(errors.ParamError, "invalid baby id format")
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
 	}
 
 	// 获取用户信息以获取UserID
 	user, err := s.userRepo.FindByOpenID(ctx, openID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 检查编辑权限
 	canEdit, err := s.collaboratorRepo.CanEdit(ctx, babyIDInt64, user.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !canEdit {
-		return errors.New(errors.PermissionDenied, "您没有权限编辑该宝宝信息")
+		return nil, errors.New(errors.PermissionDenied, "您没有权限编辑该宝宝信息")
 	}
 
 	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	result := &dto.UpdateBabyResultDTO{}
+
 	// 更新字段
 	if req.Name != "" {
 		baby.Name = req.Name
@@ -224,15 +251,36 @@ func (s *BabyService) UpdateBaby(ctx context.Context, babyID, openID string, req
 	}
 	if req.BirthDate != "" {
 		if _, err := time.Parse("2006-01-02", req.BirthDate); err != nil {
-			return errors.New(errors.ParamError, "出生日期格式错误，应为YYYY-MM-DD")
+			return nil, errors.New(errors.ParamError, "出生日期格式错误，应为YYYY-MM-DD")
 		}
+		result.BirthDateChanged = req.BirthDate != baby.BirthDate
 		baby.BirthDate = req.BirthDate
 	}
 	if req.AvatarURL != "" {
 		baby.AvatarURL = req.AvatarURL
 	}
 
-	return s.babyRepo.Update(ctx, baby)
+	// 先调整疫苗日程再保存宝宝: 保存失败时重试仍会识别到出生日期变化,调整本身可重复执行
+	if result.BirthDateChanged {
+		result.VaccineSchedules, err = s.vaccinePlanService.RebaseBabySchedules(ctx, baby, openID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.babyRepo.Update(ctx, baby); err != nil {
+		return nil, err
+	}
+
+	if result.BirthDateChanged {
+		if err := s.dailyTipsRepo.DeleteByBabyID(ctx, baby.ID); err != nil {
+			s.logger.Warn("清除每日建议失败", zap.Int64("babyId", baby.ID), zap.Error(err))
+		} else {
+			result.DailyTipsCleared = true
+		}
+	}
+
+	return result, nil
 }
 
 // DeleteBaby 删除宝宝
@@ -306,7 +354,7 @@ func (s *BabyService) GetCollaborators(ctx context.Context, babyID, openID strin
 }
 
 // InviteCollaborator 邀请协作者 (微信分享/二维码)
-// 注意:同一用户对同一宝宝只有一个有效邀请,重复调用会返回已有的邀请
+// 注意:同一用户对同一宝宝以相同设置重复邀请时,会返回仍可用的已有邀请
 func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID string, req *dto.InviteCollaboratorRequest) (*dto.BabyInvitationDTO, error) {
 	// 转换babyID from string to int64
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
@@ -314,6 +362,10 @@ func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID str
 		return nil, errors.New(errors.ParamError, "invalid baby id format")
 	}
 
+	if req.LinkExpiresAt != nil && *req.LinkExpiresAt <= time.Now().UnixMilli() {
+		return nil, errors.New(errors.ParamError, "邀请链接过期时间必须晚于当前时间")
+	}
+
 	// 获取用户信息以获取UserID
 	user, err := s.userRepo.FindByOpenID(ctx, openID)
 	if err != nil {
@@ -335,14 +387,16 @@ func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID str
 		return nil, err
 	}
 
-	// 检查是否已经存在未使用的邀请 (邀请码唯一性)
+	// 检查是否已经存在未撤销的邀请 (邀请码唯一性)
 	existingInvitation, err := s.invitationRepo.FindByBabyAndInviter(ctx, babyIDInt64, user.ID)
 	if err != nil && !errors.Is(err, errors.ErrNotFound) {
 		return nil, err
 	}
 
-	// 如果存在未使用的邀请,直接返回已有邀请信息
-	if existingInvitation != nil {
+	// 如果存在仍可用且设置相同的邀请,直接返回已有邀请信息
+	if existingInvitation != nil &&
+		existingInvitation.Status(time.Now().UnixMilli()) == entity.InvitationStatusActive &&
+		sameInvitationSettings(existingInvitation, req) {
 		s.logger.Info("邀请码已存在,直接返回已有记录",
 			zap.String("babyID", babyID),
 			zap.String("inviterID", openID),
@@ -369,6 +423,11 @@ func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID str
 				Page:      "pages/baby/join/join",
 				QRCodeURL: qrcodeURL,
 			},
+			InvitationID:    strconv.FormatInt(existingInvitation.ID, 10),
+			LinkExpiresAt:   existingInvitation.LinkExpiresAt,
+			MaxUses:         existingInvitation.MaxUses,
+			UsedCount:       existingInvitation.UsedCount,
+			RequireApproval: existingInvitation.RequireApproval,
 		}, nil
 	}
 
@@ -384,15 +443,18 @@ func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID str
 
 	// 创建邀请记录 (ID由snowflake自动生成)
 	invitation := &entity.BabyInvitation{
-		BabyID:       babyIDInt64,
-		UserID:       user.ID,
-		Token:        token,
-		ShortCode:    shortCode,
-		InviteType:   req.InviteType,
-		Role:         req.Role,
-		Relationship: req.Relationship,
-		AccessType:   req.AccessType,
-		ExpiresAt:    req.ExpiresAt, // 只保留协作者权限的过期时间
+		BabyID:          babyIDInt64,
+		UserID:          user.ID,
+		Token:           token,
+		ShortCode:       shortCode,
+		InviteType:      req.InviteType,
+		Role:            req.Role,
+		Relationship:    req.Relationship,
+		AccessType:      req.AccessType,
+		ExpiresAt:       req.ExpiresAt, // 协作者权限的过期时间
+		LinkExpiresAt:   req.LinkExpiresAt,
+		MaxUses:         req.MaxUses,
+		RequireApproval: req.RequireApproval,
 	}
 
 	if err = s.invitationRepo.Create(ctx, invitation); err != nil {
@@ -401,12 +463,16 @@ func (s *BabyService) InviteCollaborator(ctx context.Context, babyID, openID str
 
 	// 构建返回信息
 	result := &dto.BabyInvitationDTO{
-		BabyID:      babyID,
-		Name:        baby.Name,
-		InviterName: user.NickName,
-		Role:        req.Role,
-		ExpiresAt:   req.ExpiresAt,
-		ShortCode:   shortCode,
+		BabyID:          babyID,
+		Name:            baby.Name,
+		InviterName:     user.NickName,
+		Role:            req.Role,
+		ExpiresAt:       req.ExpiresAt,
+		ShortCode:       shortCode,
+		InvitationID:    strconv.FormatInt(invitation.ID, 10),
+		LinkExpiresAt:   req.LinkExpiresAt,
+		MaxUses:         req.MaxUses,
+		RequireApproval: req.RequireApproval,
 	}
 
 	// 二维码参数 - 使用短码避免32字符限制
@@ -437,6 +503,11 @@ func (s *BabyService) GetInvitationByShortCode(ctx context.Context, shortCode st
 		return nil, err
 	}
 
+	// 已撤销、链接过期或次数用尽的邀请不再展示
+	if err := checkInvitationUsable(invitation, time.Now().UnixMilli()); err != nil {
+		return nil, err
+	}
+
 	// 获取宝宝信息
 	baby, err := s.babyRepo.FindByID(ctx, invitation.BabyID)
 	if err != nil {
@@ -452,49 +523,62 @@ func (s *BabyService) GetInvitationByShortCode(ctx context.Context, shortCode st
 	}
 
 	return &dto.InvitationDetailDTO{
-		BabyID:      strconv.FormatInt(baby.ID, 10),
-		BabyName:    baby.Name,
-		BabyAvatar:  baby.AvatarURL,
-		InviterName: inviter.NickName,
-		Role:        invitation.Role,
-		AccessType:  invitation.AccessType,
-		ExpiresAt:   invitation.ExpiresAt,
-		Token:       invitation.Token,
+		BabyID:          strconv.FormatInt(baby.ID, 10),
+		BabyName:        baby.Name,
+		BabyAvatar:      baby.AvatarURL,
+		InviterName:     inviter.NickName,
+		Role:            invitation.Role,
+		AccessType:      invitation.AccessType,
+		ExpiresAt:       invitation.ExpiresAt,
+		Token:           invitation.Token,
+		LinkExpiresAt:   invitation.LinkExpiresAt,
+		RequireApproval: invitation.RequireApproval,
 	}, nil
 }
 
 // JoinBaby 加入宝宝协作 (通过微信分享或二维码)
-func (s *BabyService) JoinBaby(ctx context.Context, openID string, req *dto.JoinBabyRequest) (*dto.BabyDTO, error) {
+// 邀请需要审批时不会立即加入,返回待审批的加入申请;否则返回宝宝信息
+func (s *BabyService) JoinBaby(ctx context.Context, openID string, req *dto.JoinBabyRequest) (*dto.BabyDTO, *dto.JoinRequestDTO, error) {
 	// 转换babyID from string to int64
 	babyIDInt64, err := strconv.ParseInt(req.BabyID, 10, 64)
 	if err != nil {
-		return nil, errors.New(errors.ParamError, "invalid baby id format")
+		return nil, nil, errors.New(errors.ParamError, "invalid baby id format")
 	}
 
 	// 查找邀请记录
 	invitation, err := s.invitationRepo.FindByToken(ctx, req.Token)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if invitation.BabyID != babyIDInt64 {
-		return nil, errors.New(errors.ParamError, "邀请参数不匹配")
+		return nil, nil, errors.New(errors.ParamError, "邀请参数不匹配")
 	}
 
 	// 获取用户信息以获取UserID
 	user, err := s.userRepo.FindByOpenID(ctx, openID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 检查用户是否已经是协作者
 	existing, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, user.ID)
 	if err != nil && !errors.Is(err, errors.ErrNotFound) {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if existing != nil {
-		return nil, errors.New(errors.ParamError, "您已经是该宝宝的协作者")
+	// 已过期的临时成员可以通过新的邀请重新加入
+	if existing != nil && !existing.IsExpired() {
+		return nil, nil, errors.New(errors.ParamError, "您已经是该宝宝的协作者")
+	}
+
+	// 校验邀请状态并占用一次使用次数,需要审批时返回待审批申请
+	joinRequest, err := s.invitationService.Redeem(ctx, invitation, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if joinRequest != nil {
+		return nil, joinRequest, nil
 	}
 
 	// 创建亲友团成员记录
@@ -507,8 +591,8 @@ func (s *BabyService) JoinBaby(ctx context.Context, openID string, req *dto.Join
 		ExpiresAt:    invitation.ExpiresAt,
 	}
 
-	if err := s.collaboratorRepo.Create(ctx, collaborator); err != nil {
-		return nil, err
+	if err := addCollaborator(ctx, s.collaboratorRepo, collaborator, existing); err != nil {
+		return nil, nil, err
 	}
 
 	// 如果该用户没有其他宝宝,直接将该宝宝设置为默认宝宝
@@ -517,7 +601,11 @@ func (s *BabyService) JoinBaby(ctx context.Context, openID string, req *dto.Join
 	}
 
 	// 返回宝宝信息
-	return s.GetBabyDetail(ctx, req.BabyID, openID)
+	baby, err := s.GetBabyDetail(ctx, req.BabyID, openID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return baby, nil, nil
 }
 
 // RemoveCollaborator 移除协作者
@@ -543,7 +631,7 @@ func (s *BabyService) RemoveCollaborator(ctx context.Context, babyID, openID, ta
 		return errors.New(errors.PermissionDenied, "只有管理员可以移除协作者")
 	}
 
-	// 不能移除创建者
+	// 不能移除所有者
 	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
 	if err != nil {
 		return err
@@ -556,10 +644,59 @@ func (s *BabyService) RemoveCollaborator(ctx context.Context, babyID, openID, ta
 	}
 
 	if baby.UserID == targetUser.ID {
-		return errors.New(errors.ParamError, "不能移除创建者")
+		return errors.New(errors.ParamError, "不能移除宝宝所有者")
 	}
 
-	return s.collaboratorRepo.Delete(ctx, babyIDInt64, targetUser.ID)
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, targetUser.ID)
+	if err != nil {
+		return err
+	}
+	if collaborator == nil {
+		return errors.New(errors.NotFound, "协作者不存在")
+	}
+
+	return s.deleteCollaborator(ctx, babyIDInt64, targetUser)
+}
+
+// LeaveBaby 成员主动退出宝宝的亲友团(不删除宝宝)
+// 所有者需先转让所有权;管理员退出时宝宝仍需保留至少一位管理员
+func (s *BabyService) LeaveBaby(ctx context.Context, babyID, openID string) error {
+	// 转换babyID from string to int64
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	// 获取用户信息以获取UserID
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return err
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, user.ID)
+	if err != nil {
+		return err
+	}
+	if collaborator == nil {
+		return errors.New(errors.NotFound, "您不是该宝宝的亲友团成员")
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return err
+	}
+	if baby.UserID == user.ID {
+		return errors.New(errors.ParamError, "宝宝所有者需先转让所有权才能退出")
+	}
+
+	if err := s.deleteCollaborator(ctx, babyIDInt64, user); err != nil {
+		return err
+	}
+
+	s.logger.Info("成员退出宝宝亲友团",
+		zap.Int64("babyId", babyIDInt64),
+		zap.Int64("userId", user.ID))
+	return nil
 }
 
 // UpdateCollaboratorRole 更新协作者角色
@@ -585,7 +722,7 @@ func (s *BabyService) UpdateCollaboratorRole(ctx context.Context, babyID, openID
 		return errors.New(errors.PermissionDenied, "只有管理员可以更新协作者角色")
 	}
 
-	// 不能修改创建者角色
+	// 不能修改所有者角色
 	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
 	if err != nil {
 		return err
@@ -598,7 +735,7 @@ func (s *BabyService) UpdateCollaboratorRole(ctx context.Context, babyID, openID
 	}
 
 	if baby.UserID == targetUser.ID {
-		return errors.New(errors.ParamError, "不能修改创建者的角色")
+		return errors.New(errors.ParamError, "不能修改宝宝所有者的角色")
 	}
 
 	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, targetUser.ID)
@@ -609,9 +746,10 @@ func (s *BabyService) UpdateCollaboratorRole(ctx context.Context, babyID, openID
 		return errors.New(errors.NotFound, "协作者不存在")
 	}
 
+	// 降级管理员时宝宝仍需保留至少一位管理员
 	collaborator.Role = newRole
 
-	return s.collaboratorRepo.Update(ctx, collaborator)
+	return s.updateCollaborator(ctx, collaborator)
 }
 
 // UpdateFamilyMember 更新亲友团成员信息 (角色和关系)
@@ -667,16 +805,16 @@ func (s *BabyService) UpdateFamilyMember(ctx context.Context, babyID, openID, ta
 		return errors.New(errors.NotFound, "亲友团成员不存在")
 	}
 
-	// 不能修改创建者角色
+	// 不能修改所有者角色
 	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
 	if err != nil {
 		return err
 	}
 
-	// 更新角色 (如果提供且不是创建者)
+	// 更新角色 (如果提供且不是所有者)
 	if req.Role != "" {
 		if baby.UserID == targetUser.ID {
-			return errors.New(errors.ParamError, "不能修改创建者的角色")
+			return errors.New(errors.ParamError, "不能修改宝宝所有者的角色")
 		}
 		collaborator.Role = req.Role
 	}
@@ -686,27 +824,76 @@ func (s *BabyService) UpdateFamilyMember(ctx context.Context, babyID, openID, ta
 		collaborator.Relationship = req.Relationship
 	}
 
-	return s.collaboratorRepo.Update(ctx, collaborator)
+	return s.updateCollaborator(ctx, collaborator)
 }
 
-// checkPermission 检查用户是否有权限访问宝宝
-func (s *BabyService) checkPermission(ctx context.Context, babyIDInt64 int64, openID string) error {
-	// 获取用户信息以获取UserID
-	user, err := s.userRepo.FindByOpenID(ctx, openID)
+// updateCollaborator 更新成员信息,降级管理员时宝宝仍需保留至少一位有效管理员
+func (s *BabyService) updateCollaborator(ctx context.Context, collaborator *entity.BabyCollaborator) error {
+	ok, err := s.collaboratorRepo.UpdateKeepingAdmin(ctx, collaborator)
 	if err != nil {
 		return err
 	}
+	if !ok {
+		return errors.New(errors.Conflict, "宝宝至少需要保留一位管理员")
+	}
+	return nil
+}
 
-	isCollaborator, err := s.collaboratorRepo.IsCollaborator(ctx, babyIDInt64, user.ID)
+// deleteCollaborator 移除成员,并清理涉及该成员的待处理所有权转让和默认宝宝
+// 移除管理员时宝宝仍需保留至少一位有效管理员
+func (s *BabyService) deleteCollaborator(ctx context.Context, babyIDInt64 int64, user *entity.User) error {
+	ok, err := s.collaboratorRepo.DeleteKeepingAdmin(ctx, babyIDInt64, user.ID)
 	if err != nil {
 		return err
 	}
-	if !isCollaborator {
-		return errors.New(errors.PermissionDenied, "您没有权限访问该宝宝")
+	if !ok {
+		return errors.New(errors.Conflict, "宝宝至少需要保留一位管理员")
 	}
+
+	if err := s.ownershipService.CancelPendingFor(ctx, babyIDInt64, user.ID); err != nil {
+		s.logger.Error("取消所有权转让失败", zap.Error(err))
+	}
+
+	resetDefaultBaby(ctx, s.collaboratorRepo, s.userRepo, user, babyIDInt64, s.logger)
 	return nil
 }
 
+// resetDefaultBaby 用户的默认宝宝是刚离开的宝宝时,改为剩余的第一个宝宝(没有则清空)
+func resetDefaultBaby(
+	ctx context.Context,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	user *entity.User,
+	babyIDInt64 int64,
+	logger *zap.Logger,
+) {
+	if user.DefaultBabyID != babyIDInt64 {
+		return
+	}
+
+	var nextBabyID int64
+	remaining, err := collaboratorRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error("查询用户宝宝失败", zap.Error(err))
+	}
+	for _, collab := range remaining {
+		if collab.BabyID != babyIDInt64 && !collab.IsExpired() {
+			nextBabyID = collab.BabyID
+			break
+		}
+	}
+
+	if err := userRepo.UpdateDefaultBabyID(ctx, user.OpenID, nextBabyID); err != nil {
+		logger.Error("更新默认宝宝失败", zap.Error(err))
+	}
+}
+
+// checkPermission 检查用户是否有权限访问宝宝
+func (s *BabyService) checkPermission(ctx context.Context, babyIDInt64 int64, openID string) error {
+	_, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	return err
+}
+
 // copyCollaborators 复制协作者列表到新宝宝
 func (s *BabyService) copyCollaborators(ctx context.Context, sourceBabyIDInt64, targetBabyIDInt64 int64, openID string) error {
 	// 检查源宝宝的权限
@@ -15,6 +15,7 @@ type BaseRecordService struct {
 	babyRepo         repository.BabyRepository
 	collaboratorRepo repository.BabyCollaboratorRepository
 	userRepo         repository.UserRepository
+	permissions      *PermissionService // 记录类型 × 操作 的权限矩阵
 	logger           *zap.Logger
 }
 
@@ -29,11 +30,13 @@ func NewBaseRecordService(
 		babyRepo:         babyRepo,
 		collaboratorRepo: collaboratorRepo,
 		userRepo:         userRepo,
+		permissions:      NewPermissionService(collaboratorRepo, userRepo, logger),
 		logger:           logger,
 	}
 }
 
-// CheckBabyAccess 检查用户对宝宝的访问权限 (去家庭化架构)
+// CheckBabyAccess 检查用户是否为宝宝的有效协作者 (去家庭化架构)
+// 只用于不属于任何记录类型的宝宝级信息,记录相关操作使用 CheckRecordPermission
 func (s *BaseRecordService) CheckBabyAccess(ctx context.Context, babyID, openID string) error {
 	// 转换babyID from string to int64
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
@@ -41,21 +44,75 @@ func (s *BaseRecordService) CheckBabyAccess(ctx context.Context, babyID, openID
 		return errors.New(errors.ParamError, "invalid baby id format")
 	}
 
-	// 获取用户信息以获取UserID
-	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	// 检查用户是否为宝宝的有效协作者
+	_, err = s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	return err
+}
+
+// CheckBabyEditAccess 检查用户对宝宝设置的编辑权限 (管理员/编辑者)
+func (s *BaseRecordService) CheckBabyEditAccess(ctx context.Context, babyID, openID string) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
-		return err
+		return errors.New(errors.ParamError, "invalid baby id format")
 	}
 
-	// 检查用户是否为宝宝的协作者
-	isCollaborator, err := s.collaboratorRepo.IsCollaborator(ctx, babyIDInt64, user.ID)
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
 	if err != nil {
 		return err
 	}
 
-	if !isCollaborator {
-		return errors.New(errors.PermissionDenied, "您没有权限访问该宝宝的记录")
+	if !collaborator.CanEdit() {
+		return errors.New(errors.PermissionDenied, "您没有权限修改该宝宝的信息")
 	}
 
 	return nil
 }
+
+// CheckRecordPermission 按权限矩阵检查用户对宝宝某类记录的操作权限
+func (s *BaseRecordService) CheckRecordPermission(ctx context.Context, babyID, openID, resource, action string) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	_, err = s.permissions.Authorize(ctx, babyIDInt64, openID, resource, action)
+	return err
+}
+
+// CheckRecordUpdatePermission 检查用户能否修改 ownerID 创建的记录
+// 拥有 update_any,或拥有 update_own 且记录由本人创建
+func (s *BaseRecordService) CheckRecordUpdatePermission(ctx context.Context, babyID, openID, resource string, ownerID int64) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	_, err = s.permissions.AuthorizeUpdate(ctx, babyIDInt64, openID, resource, ownerID)
+	return err
+}
+
+// CheckRecordsPermission 检查用户能否对多类记录执行同一操作(导出、报告等跨类型功能)
+func (s *BaseRecordService) CheckRecordsPermission(ctx context.Context, babyID, openID, action string, resources ...string) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	_, err = s.permissions.AuthorizeAll(ctx, babyIDInt64, openID, action, resources...)
+	return err
+}
+
+// ReadableResources 返回用户可查看的记录类型,用于时间线、统计等聚合查询按权限裁剪
+func (s *BaseRecordService) ReadableResources(ctx context.Context, babyID, openID string) (map[string]bool, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	return readableResources(collaborator), nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/ical"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/utils"
+)
+
+const (
+	calendarProdID       = "-//nutri-baby//vaccine calendar//CN"
+	calendarName         = "宝宝疫苗接种"
+	calendarTokenBytes   = 32 // 订阅密钥字节数,十六进制后为64位
+	calendarScheduleSize = 1000
+	calendarAlarmHour    = 9 // 提醒在当天上午9点触发
+)
+
+// CalendarService 日历订阅(ICS)服务
+// 目前发布疫苗接种日程;系统中尚无用药和就诊预约数据,后续加入时在 RenderFeed 中追加对应事件
+type CalendarService struct {
+	userRepo         repository.UserRepository
+	babyRepo         repository.BabyRepository
+	collaboratorRepo repository.BabyCollaboratorRepository
+	scheduleRepo     repository.BabyVaccineScheduleRepository
+	feedRepo         repository.CalendarFeedRepository
+	baseURL          string
+	logger           *zap.Logger
+}
+
+// NewCalendarService 创建日历订阅服务
+func NewCalendarService(
+	userRepo repository.UserRepository,
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	feedRepo repository.CalendarFeedRepository,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *CalendarService {
+	return &CalendarService{
+		userRepo:         userRepo,
+		babyRepo:         babyRepo,
+		collaboratorRepo: collaboratorRepo,
+		scheduleRepo:     scheduleRepo,
+		feedRepo:         feedRepo,
+		baseURL:          strings.TrimSuffix(cfg.Server.BaseURL, "/"),
+		logger:           logger,
+	}
+}
+
+// GetFeed 获取当前用户的日历订阅地址,首次调用时创建
+func (s *CalendarService) GetFeed(ctx context.Context, openID string) (*dto.CalendarFeedDTO, error) {
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := s.feedRepo.FindByUserID(ctx, user.ID)
+	if errors.Is(err, errors.ErrNotFound) {
+		token, genErr := utils.GenerateToken(calendarTokenBytes)
+		if genErr != nil {
+			return nil, errors.Wrap(errors.InternalError, "failed to generate calendar token", genErr)
+		}
+		feed = &entity.CalendarFeed{UserID: user.ID, Token: token}
+		err = s.feedRepo.Create(ctx, feed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toFeedDTO(feed), nil
+}
+
+// RegenerateFeed 重新生成订阅地址,旧地址立即失效
+func (s *CalendarService) RegenerateFeed(ctx context.Context, openID string) (*dto.CalendarFeedDTO, error) {
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateToken(calendarTokenBytes)
+	if err != nil {
+		return nil, errors.Wrap(errors.InternalError, "failed to generate calendar token", err)
+	}
+
+	feed, err := s.feedRepo.FindByUserID(ctx, user.ID)
+	switch {
+	case errors.Is(err, errors.ErrNotFound):
+		feed = &entity.CalendarFeed{UserID: user.ID, Token: token}
+		err = s.feedRepo.Create(ctx, feed)
+	case err == nil:
+		err = s.feedRepo.UpdateToken(ctx, feed.ID, token)
+		feed.Token = token
+		feed.UpdatedAt = time.Now().UnixMilli()
+		feed.LastAccessedAt = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("重新生成日历订阅地址", zap.Int64("userId", user.ID))
+
+	return s.toFeedDTO(feed), nil
+}
+
+// RenderFeed 根据订阅密钥生成 ICS 内容,包含用户协作的所有宝宝的疫苗接种日程
+func (s *CalendarService) RenderFeed(ctx context.Context, token string, req *dto.CalendarFeedQuery) ([]byte, error) {
+	if len(token) != calendarTokenBytes*2 {
+		return nil, errors.ErrNotFound
+	}
+
+	loc := time.Local
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.New(errors.ParamError, "无效的时区: "+req.Timezone)
+		}
+	}
+
+	feed, err := s.feedRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.collaboratorRepo.FindByUserID(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*ical.Event
+	for _, collaborator := range collaborators {
+		if !collaborator.Can(entity.PermissionResourceVaccine, entity.PermissionActionRead) {
+			continue
+		}
+		baby, err := s.babyRepo.FindByID(ctx, collaborator.BabyID)
+		if err != nil {
+			if errors.Is(err, errors.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		schedules, err := s.scheduleRepo.FindByBabyID(ctx, baby.ID, 1, calendarScheduleSize)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, vaccineEvents(baby, schedules, loc)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	cal := &ical.Calendar{ProdID: calendarProdID, Name: calendarName, Events: events}
+	if req.Timezone != "" {
+		cal.TZID = loc.String()
+	}
+
+	now := time.Now()
+	if err := s.feedRepo.TouchAccess(ctx, feed.ID, now.UnixMilli()); err != nil {
+		s.logger.Warn("更新日历订阅访问时间失败", zap.Int64("feedId", feed.ID), zap.Error(err))
+	}
+
+	return cal.Marshal(now), nil
+}
+
+func (s *CalendarService) toFeedDTO(feed *entity.CalendarFeed) *dto.CalendarFeedDTO {
+	path := "/v1/calendar/ics/" + feed.Token + ".ics"
+	url := s.baseURL + path
+	webcal := url
+	if i := strings.Index(url, "://"); i >= 0 {
+		webcal = "webcal" + url[i:]
+	}
+	return &dto.CalendarFeedDTO{
+		URL:            url,
+		WebcalURL:      webcal,
+		CreateTime:     feed.CreatedAt,
+		TokenUpdatedAt: feed.UpdatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}
+}
+
+// vaccineEvents 将疫苗日程转换为全天事件
+// 待接种的日程按计划日期发布,按 ReminderDays 提前提醒并在当天上午再提醒一次;
+// 已接种的按实际接种日期发布,不设提醒;跳过的不发布
+func vaccineEvents(baby *entity.Baby, schedules []*entity.BabyVaccineSchedule, loc *time.Location) []*ical.Event {
+	babyName := baby.Nickname
+	if babyName == "" {
+		babyName = baby.Name
+	}
+
+	events := make([]*ical.Event, 0, len(schedules))
+	for _, schedule := range schedules {
+		title := fmt.Sprintf("%s·%s(第%d剂)", babyName, schedule.VaccineName, schedule.DoseNumber)
+		event := &ical.Event{
+			UID:          "vaccine-" + strconv.FormatInt(schedule.ID, 10) + "@nutri-baby",
+			AllDay:       true,
+			Categories:   []string{"疫苗接种"},
+			LastModified: time.UnixMilli(schedule.UpdatedAt),
+		}
+
+		var details []string
+		switch {
+		case schedule.IsPending() && schedule.ScheduledDate > 0:
+			event.Summary = title
+			event.Start = startOfDay(time.UnixMilli(schedule.ScheduledDate).In(loc))
+			event.Status = "TENTATIVE"
+			details = append(details, fmt.Sprintf("计划接种年龄: %d月龄", schedule.AgeInMonths))
+			if !schedule.IsRequired {
+				details = append(details, "非必接种(自费)")
+			}
+			if schedule.Description != "" {
+				details = append(details, schedule.Description)
+			}
+			event.Alarms = vaccineAlarms(schedule.ReminderDays, title)
+		case schedule.IsCompleted() && schedule.VaccineDate != nil:
+			event.Summary = title + " 已接种"
+			event.Start = startOfDay(time.UnixMilli(*schedule.VaccineDate).In(loc))
+			event.Status = "CONFIRMED"
+			event.Location = utils.DerefString(schedule.Hospital)
+			if v := utils.DerefString(schedule.BatchNumber); v != "" {
+				details = append(details, "批次号: "+v)
+			}
+			if v := utils.DerefString(schedule.Doctor); v != "" {
+				details = append(details, "接种医生: "+v)
+			}
+			if v := utils.DerefString(schedule.Reaction); v != "" {
+				details = append(details, "接种反应: "+v)
+			}
+			if v := utils.DerefString(schedule.Note); v != "" {
+				details = append(details, "备注: "+v)
+			}
+		default:
+			continue
+		}
+		event.Description = strings.Join(details, "\n")
+		events = append(events, event)
+	}
+	return events
+}
+
+// vaccineAlarms 按提前天数生成提醒,均在上午9点触发
+func vaccineAlarms(reminderDays int, title string) []ical.Alarm {
+	onDay := time.Duration(calendarAlarmHour) * time.Hour
+	alarms := []ical.Alarm{{Trigger: onDay, Description: "今天接种: " + title}}
+	if reminderDays > 0 {
+		alarms = append(alarms, ical.Alarm{
+			Trigger:     onDay - time.Duration(reminderDays)*24*time.Hour,
+			Description: fmt.Sprintf("%d天后接种: %s", reminderDays, title),
+		})
+	}
+	return alarms
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
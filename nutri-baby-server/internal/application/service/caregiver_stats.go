@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	caregiverStatsDefaultDays = 7   // 默认统计最近7天
+	caregiverStatsMaxDays     = 92  // 统计周期上限(天)
+	caregiverStatsPageSize    = 500 // 分页读取记录的每页条数
+)
+
+// 夜间时段 22:00 - 次日 06:00 (按请求的时区,默认服务器时区)
+const (
+	nightDutyStartHour = 22
+	nightDutyEndHour   = 6
+)
+
+// GetCaregiverStatistics 获取照护人分工统计
+func (s *StatisticsService) GetCaregiverStatistics(ctx context.Context, babyID, openID string, query *dto.CaregiverStatsQuery) (*dto.CaregiverStatsResponse, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "无效的宝宝ID格式")
+	}
+
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := loadLocation(query.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := resolveCaregiverStatsRange(query.StartTime, query.EndTime, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getCaregiverStatistics(ctx, babyIDInt64, readableResources(collaborator), start, end, loc)
+}
+
+// getCaregiverStatistics 读取统计周期内的喂养、尿布、睡眠记录并按创建者汇总
+func (s *StatisticsService) getCaregiverStatistics(ctx context.Context, babyID int64, readable map[string]bool, start, end int64, loc *time.Location) (*dto.CaregiverStatsResponse, error) {
+	var feedings []*entity.FeedingRecord
+	if readable[entity.PermissionResourceFeeding] {
+		for page := 1; ; page++ {
+			records, _, err := s.feedingRecordRepo.FindByBabyID(ctx, babyID, start, end, page, caregiverStatsPageSize)
+			if err != nil {
+				return nil, errors.Wrap(errors.DatabaseError, "查询喂养记录失败", err)
+			}
+			feedings = append(feedings, records...)
+			if len(records) < caregiverStatsPageSize {
+				break
+			}
+		}
+	}
+
+	var diapers []*entity.DiaperRecord
+	if readable[entity.PermissionResourceDiaper] {
+		for page := 1; ; page++ {
+			records, _, err := s.diaperRecordRepo.FindByBabyID(ctx, babyID, start, end, page, caregiverStatsPageSize)
+			if err != nil {
+				return nil, errors.Wrap(errors.DatabaseError, "查询换尿布记录失败", err)
+			}
+			diapers = append(diapers, records...)
+			if len(records) < caregiverStatsPageSize {
+				break
+			}
+		}
+	}
+
+	var sleeps []*entity.SleepRecord
+	if readable[entity.PermissionResourceSleep] {
+		for page := 1; ; page++ {
+			records, _, err := s.sleepRecordRepo.FindByBabyID(ctx, babyID, start, end, page, caregiverStatsPageSize)
+			if err != nil {
+				return nil, errors.Wrap(errors.DatabaseError, "查询睡眠记录失败", err)
+			}
+			sleeps = append(sleeps, records...)
+			if len(records) < caregiverStatsPageSize {
+				break
+			}
+		}
+	}
+
+	collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCaregiverStats(start, end, loc, collaborators, feedings, diapers, sleeps), nil
+}
+
+// caregiverTally 汇总过程中的单个照护人
+type caregiverTally struct {
+	contribution dto.CaregiverContribution
+	hourly       [24]dto.CaregiverHourlyStat
+}
+
+// buildCaregiverStats 按记录创建者汇总次数和时段分布
+// 当前非只读成员即使没有记录也会列出,已离开成员的记录使用记录中冗余的昵称;小时按 loc 划分
+func buildCaregiverStats(
+	start, end int64,
+	loc *time.Location,
+	collaborators []*entity.BabyCollaborator,
+	feedings []*entity.FeedingRecord,
+	diapers []*entity.DiaperRecord,
+	sleeps []*entity.SleepRecord,
+) *dto.CaregiverStatsResponse {
+	tallies := make(map[int64]*caregiverTally)
+	var order []int64
+
+	tallyOf := func(userID int64, name, avatar string) *caregiverTally {
+		if tally, ok := tallies[userID]; ok {
+			return tally
+		}
+		tally := &caregiverTally{contribution: dto.CaregiverContribution{NickName: name, AvatarURL: avatar}}
+		tallies[userID] = tally
+		order = append(order, userID)
+		return tally
+	}
+
+	for _, collaborator := range collaborators {
+		if collaborator.Role == entity.CollaboratorRoleViewer || collaborator.IsExpired() {
+			continue
+		}
+		tally := tallyOf(collaborator.UserID, "", "")
+		tally.contribution.Relationship = collaborator.Relationship
+		if collaborator.User != nil {
+			tally.contribution.OpenID = collaborator.User.OpenID
+			tally.contribution.NickName = collaborator.User.NickName
+			tally.contribution.AvatarURL = collaborator.User.AvatarURL
+		}
+	}
+
+	total := &caregiverTally{}
+	for _, record := range feedings {
+		hour := time.UnixMilli(record.Time).In(loc).Hour()
+		tallyOf(record.CreatedBy, record.CreatedByName, record.CreatedByAvatar).add(entity.PermissionResourceFeeding, hour)
+		total.add(entity.PermissionResourceFeeding, hour)
+	}
+	for _, record := range diapers {
+		hour := time.UnixMilli(record.Time).In(loc).Hour()
+		tallyOf(record.CreatedBy, record.CreatedByName, record.CreatedByAvatar).add(entity.PermissionResourceDiaper, hour)
+		total.add(entity.PermissionResourceDiaper, hour)
+	}
+	for _, record := range sleeps {
+		hour := time.UnixMilli(record.StartTime).In(loc).Hour()
+		tallyOf(record.CreatedBy, record.CreatedByName, record.CreatedByAvatar).add(entity.PermissionResourceSleep, hour)
+		total.add(entity.PermissionResourceSleep, hour)
+	}
+
+	totalCount := contributionCount(&total.contribution)
+	totalNight := contributionNightCount(&total.contribution)
+
+	result := &dto.CaregiverStatsResponse{
+		StartTime:      start,
+		EndTime:        end,
+		NightStartHour: nightDutyStartHour,
+		NightEndHour:   nightDutyEndHour,
+		Timezone:       loc.String(),
+		Caregivers:     make([]dto.CaregiverContribution, 0, len(order)),
+		Total:          finishContribution(total, totalCount, totalNight),
+	}
+	for _, userID := range order {
+		result.Caregivers = append(result.Caregivers, finishContribution(tallies[userID], totalCount, totalNight))
+	}
+
+	sort.SliceStable(result.Caregivers, func(i, j int) bool {
+		return contributionCount(&result.Caregivers[i]) > contributionCount(&result.Caregivers[j])
+	})
+
+	return result
+}
+
+// add 记一次喂养/换尿布/哄睡(睡眠按开始时间计)
+func (t *caregiverTally) add(resource string, hour int) {
+	night := isNightDutyHour(hour)
+	c, h := &t.contribution, &t.hourly[hour]
+	switch resource {
+	case entity.PermissionResourceFeeding:
+		c.Feedings++
+		h.Feedings++
+		if night {
+			c.NightFeedings++
+		}
+	case entity.PermissionResourceDiaper:
+		c.Diapers++
+		h.Diapers++
+		if night {
+			c.NightDiapers++
+		}
+	case entity.PermissionResourceSleep:
+		c.SleepSettles++
+		h.SleepSettles++
+		if night {
+			c.NightSleepSettles++
+		}
+	}
+}
+
+// finishContribution 计算占比并展开 24 小时分布
+func finishContribution(tally *caregiverTally, totalCount, totalNight int) dto.CaregiverContribution {
+	contribution := tally.contribution
+	contribution.Share = percentOf(contributionCount(&contribution), totalCount)
+	contribution.NightShare = percentOf(contributionNightCount(&contribution), totalNight)
+
+	contribution.Hourly = make([]dto.CaregiverHourlyStat, 24)
+	for hour := range contribution.Hourly {
+		contribution.Hourly[hour] = tally.hourly[hour]
+		contribution.Hourly[hour].Hour = hour
+	}
+	return contribution
+}
+
+// contributionCount 喂养、换尿布、哄睡总次数
+func contributionCount(c *dto.CaregiverContribution) int {
+	return c.Feedings + c.Diapers + c.SleepSettles
+}
+
+// contributionNightCount 夜间喂养、换尿布、哄睡总次数
+func contributionNightCount(c *dto.CaregiverContribution) int {
+	return c.NightFeedings + c.NightDiapers + c.NightSleepSettles
+}
+
+// percentOf 百分比,保留一位小数
+func percentOf(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(whole)) / 10
+}
+
+// isNightDutyHour 是否处于夜间时段
+func isNightDutyHour(hour int) bool {
+	return hour >= nightDutyStartHour || hour < nightDutyEndHour
+}
+
+// resolveCaregiverStatsRange 解析统计周期,默认最近7天(含今天,按 now 所在时区划分日期)
+func resolveCaregiverStatsRange(startTime, endTime int64, now time.Time) (int64, int64, error) {
+	end := now.UnixMilli()
+	if endTime > 0 {
+		end = endTime
+	}
+
+	start := getTodayStart(time.UnixMilli(end).In(now.Location())).AddDate(0, 0, -(caregiverStatsDefaultDays - 1)).UnixMilli()
+	if startTime > 0 {
+		start = startTime
+	}
+
+	if start >= end {
+		return 0, 0, errors.New(errors.ParamError, "开始时间必须早于结束时间")
+	}
+	if end-start > int64(caregiverStatsMaxDays)*24*time.Hour.Milliseconds() {
+		return 0, 0, errors.New(errors.ParamError, "统计周期不能超过92天")
+	}
+	return start, end, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestBuildCaregiverStats(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) int64 {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute).UnixMilli()
+	}
+
+	collaborators := []*entity.BabyCollaborator{
+		{UserID: 1, Role: entity.CollaboratorRoleAdmin, Relationship: "妈妈", User: &entity.User{OpenID: "mom", NickName: "小红"}},
+		{UserID: 2, Role: entity.CollaboratorRoleEditor, Relationship: "爸爸", User: &entity.User{OpenID: "dad", NickName: "小明"}},
+		{UserID: 3, Role: entity.CollaboratorRoleViewer, User: &entity.User{OpenID: "grandma"}},
+		{UserID: 4, Role: entity.CollaboratorRoleCaregiver, User: &entity.User{OpenID: "nanny", NickName: "阿姨"}},
+	}
+	feedings := []*entity.FeedingRecord{
+		{CreatedBy: 1, Time: at(2, 10)},
+		{CreatedBy: 1, Time: at(9, 0)},
+		{CreatedBy: 2, Time: at(23, 30)},
+		{CreatedBy: 9, CreatedByName: "外婆", Time: at(14, 0)}, // 已离开的成员
+	}
+	diapers := []*entity.DiaperRecord{
+		{CreatedBy: 2, Time: at(23, 40)},
+		{CreatedBy: 2, Time: at(5, 59)},
+	}
+	sleeps := []*entity.SleepRecord{
+		{CreatedBy: 1, StartTime: at(6, 0)},
+	}
+
+	stats := buildCaregiverStats(at(0, 0), at(23, 59), time.Local, collaborators, feedings, diapers, sleeps)
+
+	assert.Equal(t, 22, stats.NightStartHour)
+	assert.Equal(t, 6, stats.NightEndHour)
+
+	// 只读成员不列出,没有记录的照护人也列出,已离开成员使用记录中的昵称;次数相同时保持成员顺序
+	require.Len(t, stats.Caregivers, 4)
+	mom, dad, grandma, nanny := stats.Caregivers[0], stats.Caregivers[1], stats.Caregivers[2], stats.Caregivers[3]
+
+	assert.Equal(t, "dad", dad.OpenID)
+	assert.Equal(t, 1, dad.Feedings)
+	assert.Equal(t, 1, dad.NightFeedings)
+	assert.Equal(t, 2, dad.Diapers)
+	assert.Equal(t, 2, dad.NightDiapers)
+
+	assert.Equal(t, "mom", mom.OpenID)
+	assert.Equal(t, "妈妈", mom.Relationship)
+	assert.Equal(t, 2, mom.Feedings)
+	assert.Equal(t, 1, mom.NightFeedings)
+	assert.Equal(t, 1, mom.SleepSettles)
+	assert.Equal(t, 0, mom.NightSleepSettles) // 06:00 不属于夜间
+
+	assert.Equal(t, "外婆", grandma.NickName)
+	assert.Empty(t, grandma.OpenID)
+	assert.Equal(t, "阿姨", nanny.NickName)
+	assert.Equal(t, 0, nanny.Feedings)
+
+	// 占比: 全家 7 次,夜间 4 次(爸爸 3 次、妈妈 1 次)
+	assert.Equal(t, 7, stats.Total.Feedings+stats.Total.Diapers+stats.Total.SleepSettles)
+	assert.Equal(t, 42.9, dad.Share)
+	assert.Equal(t, 75.0, dad.NightShare)
+	assert.Equal(t, 25.0, mom.NightShare)
+	assert.Equal(t, 0.0, nanny.Share)
+
+	// 24 小时分布
+	require.Len(t, dad.Hourly, 24)
+	assert.Equal(t, 23, dad.Hourly[23].Hour)
+	assert.Equal(t, 1, dad.Hourly[23].Feedings)
+	assert.Equal(t, 1, dad.Hourly[23].Diapers)
+	assert.Equal(t, 1, stats.Total.Hourly[6].SleepSettles)
+}
+
+func TestBuildCaregiverStatsTimezone(t *testing.T) {
+	shanghai := time.FixedZone("Asia/Shanghai", 8*3600)
+	// 北京时间 23:30 = UTC 15:30
+	feedings := []*entity.FeedingRecord{
+		{CreatedBy: 1, Time: time.Date(2026, 3, 2, 23, 30, 0, 0, shanghai).UnixMilli()},
+	}
+
+	local := buildCaregiverStats(0, 1, shanghai, nil, feedings, nil, nil)
+	assert.Equal(t, "Asia/Shanghai", local.Timezone)
+	assert.Equal(t, 1, local.Total.NightFeedings)
+	assert.Equal(t, 1, local.Total.Hourly[23].Feedings)
+
+	utc := buildCaregiverStats(0, 1, time.UTC, nil, feedings, nil, nil)
+	assert.Equal(t, 0, utc.Total.NightFeedings)
+	assert.Equal(t, 1, utc.Total.Hourly[15].Feedings)
+}
+
+func TestResolveCaregiverStatsRange(t *testing.T) {
+	now := time.Date(2026, 3, 8, 15, 0, 0, 0, time.Local)
+
+	start, end, err := resolveCaregiverStatsRange(0, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.UnixMilli(), end)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local).UnixMilli(), start)
+
+	_, _, err = resolveCaregiverStatsRange(now.UnixMilli(), now.Add(-time.Hour).UnixMilli(), now)
+	assert.ErrorContains(t, err, "开始时间必须早于结束时间")
+
+	_, _, err = resolveCaregiverStatsRange(now.AddDate(0, 0, -100).UnixMilli(), now.UnixMilli(), now)
+	assert.ErrorContains(t, err, "统计周期不能超过92天")
+}
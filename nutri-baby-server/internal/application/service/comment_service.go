@@ -0,0 +1,583 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// commentMentionTemplateType 评论中被@时的订阅消息模板类型
+const commentMentionTemplateType = "record_comment_mention"
+
+// timelineRecentComments 时间线每条记录附带的最近评论数
+const timelineRecentComments = 3
+
+// CommentService 记录评论与表情回应服务
+// 能查看某类记录的协作者即可评论和回应,评论随记录一起软删除
+type CommentService struct {
+	*BaseRecordService
+	commentRepo       repository.RecordCommentRepository
+	attachmentService *AttachmentService // 复用附件的记录解析(所属宝宝)
+	subscribeService  *SubscribeService
+	mentionTemplateID string
+}
+
+// NewCommentService 创建记录评论服务
+func NewCommentService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	commentRepo repository.RecordCommentRepository,
+	attachmentService *AttachmentService,
+	subscribeService *SubscribeService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *CommentService {
+	return &CommentService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		commentRepo:       commentRepo,
+		attachmentService: attachmentService,
+		subscribeService:  subscribeService,
+		mentionTemplateID: cfg.Wechat.SubscribeTemplates[commentMentionTemplateType],
+	}
+}
+
+// GetRecordComments 获取记录的评论(按楼层嵌套回复)和表情回应
+func (s *CommentService) GetRecordComments(ctx context.Context, openID, recordType, recordID string) (*dto.RecordCommentsResponse, error) {
+	recordIDInt64, collaborator, err := s.authorizeRecord(ctx, openID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindByRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := s.commentRepo.FindReactionsByRecords(ctx, recordType, []int64{recordIDInt64})
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.commentMembers(ctx, collaborator.BabyID, comments, reactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecordCommentsResponse{
+		RecordType:   recordType,
+		RecordID:     recordID,
+		Comments:     buildCommentThreads(comments, members, collaborator),
+		CommentCount: len(comments),
+		Reactions:    summarizeEmojiReactions(reactions, members, collaborator.UserID),
+	}, nil
+}
+
+// CreateComment 发表评论或回复,并通知被@的成员
+func (s *CommentService) CreateComment(ctx context.Context, openID, recordType, recordID string, req *dto.CreateCommentRequest) (*dto.CommentDTO, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New(errors.ParamError, "评论内容不能为空")
+	}
+	if utf8.RuneCountInString(content) > entity.RecordCommentMaxLength {
+		return nil, errors.New(errors.ParamError, fmt.Sprintf("评论内容不能超过%d字", entity.RecordCommentMaxLength))
+	}
+
+	recordIDInt64, collaborator, err := s.authorizeRecord(ctx, openID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &entity.RecordComment{
+		BabyID:     collaborator.BabyID,
+		RecordType: recordType,
+		RecordID:   recordIDInt64,
+		UserID:     collaborator.UserID,
+		Content:    content,
+	}
+
+	if req.ParentID != "" {
+		parentID, err := strconv.ParseInt(req.ParentID, 10, 64)
+		if err != nil {
+			return nil, errors.New(errors.ParamError, "invalid comment id format")
+		}
+		parent, err := s.commentRepo.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.RecordType != recordType || parent.RecordID != recordIDInt64 {
+			return nil, errors.New(errors.ParamError, "回复的评论不属于该记录")
+		}
+		comment.ParentID, comment.ReplyToUserID = replyTarget(parent)
+	}
+
+	collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, collaborator.BabyID)
+	if err != nil {
+		return nil, err
+	}
+	mentioned, err := resolveMentions(collaborators, req.MentionOpenIDs, recordType, collaborator.UserID)
+	if err != nil {
+		return nil, err
+	}
+	comment.MentionedUserIDs = joinUserIDs(mentioned)
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		s.logger.Error("保存评论失败",
+			zap.String("recordType", recordType),
+			zap.String("recordID", recordID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	members, err := s.commentMembers(ctx, collaborator.BabyID, []*entity.RecordComment{comment}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, comment, members[collaborator.UserID], mentioned)
+
+	commentDTO := toCommentDTO(comment, members, collaborator)
+	return &commentDTO, nil
+}
+
+// DeleteComment 删除评论(本人或管理员),顶层评论的回复一并删除
+func (s *CommentService) DeleteComment(ctx context.Context, openID, commentID string) error {
+	commentIDInt64, err := strconv.ParseInt(commentID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid comment id format")
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentIDInt64)
+	if err != nil {
+		return err
+	}
+
+	collaborator, err := s.permissions.Authorize(ctx, comment.BabyID, openID, comment.RecordType, entity.PermissionActionRead)
+	if err != nil {
+		return err
+	}
+	if !canDeleteComment(collaborator, comment) {
+		return errors.New(errors.PermissionDenied, "只能删除自己的评论")
+	}
+
+	return s.commentRepo.Delete(ctx, commentIDInt64)
+}
+
+// AddReaction 对记录添加表情回应,重复回应同一表情不会重复计数
+func (s *CommentService) AddReaction(ctx context.Context, openID, recordType, recordID string, req *dto.ReactionRequest) ([]dto.ReactionSummaryDTO, error) {
+	emoji, err := normalizeReactionEmoji(req.Emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	recordIDInt64, collaborator, err := s.authorizeRecord(ctx, openID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction := &entity.RecordReaction{
+		BabyID:     collaborator.BabyID,
+		RecordType: recordType,
+		RecordID:   recordIDInt64,
+		UserID:     collaborator.UserID,
+		Emoji:      emoji,
+	}
+	if _, err := s.commentRepo.AddReaction(ctx, reaction); err != nil {
+		return nil, err
+	}
+
+	return s.recordReactions(ctx, collaborator, recordType, recordIDInt64)
+}
+
+// RemoveReaction 取消自己的表情回应
+func (s *CommentService) RemoveReaction(ctx context.Context, openID, recordType, recordID string, req *dto.ReactionRequest) ([]dto.ReactionSummaryDTO, error) {
+	emoji, err := normalizeReactionEmoji(req.Emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	recordIDInt64, collaborator, err := s.authorizeRecord(ctx, openID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.commentRepo.RemoveReaction(ctx, recordType, recordIDInt64, collaborator.UserID, emoji); err != nil {
+		return nil, err
+	}
+
+	return s.recordReactions(ctx, collaborator, recordType, recordIDInt64)
+}
+
+// EnrichTimelineItems 为时间线的记录附上评论数、最近评论和表情回应
+// 时间线已按查看权限裁剪记录类型,这里只做批量查询
+func (s *CommentService) EnrichTimelineItems(ctx context.Context, openID, babyID string, items []dto.TimelineItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return err
+	}
+
+	recordIDs := make(map[string][]int64)
+	for _, item := range items {
+		recordID, err := strconv.ParseInt(item.RecordID, 10, 64)
+		if err != nil {
+			continue
+		}
+		recordIDs[item.RecordType] = append(recordIDs[item.RecordType], recordID)
+	}
+
+	var comments []*entity.RecordComment
+	var reactions []*entity.RecordReaction
+	for recordType, ids := range recordIDs {
+		typeComments, err := s.commentRepo.FindByRecords(ctx, recordType, ids)
+		if err != nil {
+			return err
+		}
+		typeReactions, err := s.commentRepo.FindReactionsByRecords(ctx, recordType, ids)
+		if err != nil {
+			return err
+		}
+		comments = append(comments, typeComments...)
+		reactions = append(reactions, typeReactions...)
+	}
+	if len(comments) == 0 && len(reactions) == 0 {
+		return nil
+	}
+
+	members, err := s.commentMembers(ctx, babyIDInt64, comments, reactions)
+	if err != nil {
+		return err
+	}
+
+	commentsByRecord := make(map[string][]*entity.RecordComment)
+	for _, comment := range comments {
+		key := recordKey(comment.RecordType, comment.RecordID)
+		commentsByRecord[key] = append(commentsByRecord[key], comment)
+	}
+	reactionsByRecord := make(map[string][]*entity.RecordReaction)
+	for _, reaction := range reactions {
+		key := recordKey(reaction.RecordType, reaction.RecordID)
+		reactionsByRecord[key] = append(reactionsByRecord[key], reaction)
+	}
+
+	for i := range items {
+		key := items[i].RecordType + ":" + items[i].RecordID
+		recordComments := commentsByRecord[key]
+		items[i].CommentCount = len(recordComments)
+		items[i].RecentComments = recentComments(buildCommentThreads(recordComments, members, collaborator), timelineRecentComments)
+		if recordReactions := reactionsByRecord[key]; len(recordReactions) > 0 {
+			items[i].Reactions = summarizeEmojiReactions(recordReactions, members, collaborator.UserID)
+		}
+	}
+
+	return nil
+}
+
+// authorizeRecord 解析记录所属宝宝并检查查看权限,返回记录ID和当前协作者
+func (s *CommentService) authorizeRecord(ctx context.Context, openID, recordType, recordID string) (int64, *entity.BabyCollaborator, error) {
+	recordIDInt64, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return 0, nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+
+	babyID, _, err := s.attachmentService.resolveRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	collaborator, err := s.permissions.Authorize(ctx, babyID, openID, recordType, entity.PermissionActionRead)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return recordIDInt64, collaborator, nil
+}
+
+// recordReactions 重新汇总记录的表情回应
+func (s *CommentService) recordReactions(ctx context.Context, collaborator *entity.BabyCollaborator, recordType string, recordID int64) ([]dto.ReactionSummaryDTO, error) {
+	reactions, err := s.commentRepo.FindReactionsByRecords(ctx, recordType, []int64{recordID})
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.commentMembers(ctx, collaborator.BabyID, nil, reactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeEmojiReactions(reactions, members, collaborator.UserID), nil
+}
+
+// commentMembers 评论和回应涉及的成员信息: 当前亲友团成员带关系,已离开的成员按用户信息补充
+func (s *CommentService) commentMembers(ctx context.Context, babyID int64, comments []*entity.RecordComment, reactions []*entity.RecordReaction) (map[int64]dto.CommentUserDTO, error) {
+	collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int64]dto.CommentUserDTO, len(collaborators))
+	for _, collaborator := range collaborators {
+		if collaborator.User == nil {
+			continue
+		}
+		members[collaborator.UserID] = dto.CommentUserDTO{
+			OpenID:       collaborator.User.OpenID,
+			NickName:     collaborator.User.NickName,
+			AvatarURL:    collaborator.User.AvatarURL,
+			Relationship: collaborator.Relationship,
+		}
+	}
+
+	var userIDs []int64
+	for _, comment := range comments {
+		userIDs = append(userIDs, comment.UserID, comment.ReplyToUserID)
+		userIDs = append(userIDs, splitUserIDs(comment.MentionedUserIDs)...)
+	}
+	for _, reaction := range reactions {
+		userIDs = append(userIDs, reaction.UserID)
+	}
+
+	for _, userID := range userIDs {
+		if _, ok := members[userID]; ok || userID == 0 {
+			continue
+		}
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			s.logger.Warn("获取评论成员信息失败", zap.Int64("userId", userID), zap.Error(err))
+			members[userID] = dto.CommentUserDTO{}
+			continue
+		}
+		members[userID] = dto.CommentUserDTO{
+			OpenID:    user.OpenID,
+			NickName:  user.NickName,
+			AvatarURL: user.AvatarURL,
+		}
+	}
+
+	return members, nil
+}
+
+// notifyMentions 通知被@的成员(需已授权订阅消息)
+func (s *CommentService) notifyMentions(ctx context.Context, comment *entity.RecordComment, author dto.CommentUserDTO, mentioned []*entity.BabyCollaborator) {
+	if len(mentioned) == 0 {
+		return
+	}
+
+	babyName := ""
+	if baby, err := s.babyRepo.FindByID(ctx, comment.BabyID); err == nil {
+		babyName = baby.Name
+	}
+
+	authorName := author.NickName
+	if author.Relationship != "" {
+		authorName = author.Relationship
+	}
+
+	messageData := map[string]any{
+		"thing1": truncateRunes(babyName, 20),        // 宝宝
+		"thing2": truncateRunes(authorName, 20),      // 评论人
+		"thing3": truncateRunes(comment.Content, 20), // 评论内容
+		"time4":  formatMessageTime(comment.CreatedAt),
+	}
+	page := fmt.Sprintf("pages/record/comments/comments?recordType=%s&recordId=%d", comment.RecordType, comment.RecordID)
+
+	for _, collaborator := range mentioned {
+		if collaborator.User == nil {
+			continue
+		}
+		hasAuth, err := s.subscribeService.CheckAuthorizationStatus(ctx, collaborator.User.OpenID, commentMentionTemplateType)
+		if err != nil || !hasAuth {
+			continue
+		}
+
+		sendReq := &dto.SendMessageRequest{
+			OpenID:     collaborator.User.OpenID,
+			TemplateID: s.mentionTemplateID,
+			Data:       messageData,
+			Page:       page,
+		}
+		if err := s.subscribeService.SendSubscribeMessage(ctx, sendReq); err != nil {
+			s.logger.Warn("发送评论提及消息失败",
+				zap.String("openID", collaborator.User.OpenID),
+				zap.Int64("commentId", comment.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// replyTarget 回复归入的顶层评论和被回复的用户,回复的回复仍挂在同一顶层评论下
+func replyTarget(parent *entity.RecordComment) (int64, int64) {
+	if parent.ParentID != 0 {
+		return parent.ParentID, parent.UserID
+	}
+	return parent.ID, parent.UserID
+}
+
+// canDeleteComment 评论者本人或管理员可以删除评论
+func canDeleteComment(collaborator *entity.BabyCollaborator, comment *entity.RecordComment) bool {
+	return collaborator.UserID == comment.UserID || collaborator.IsAdmin()
+}
+
+// resolveMentions 解析@的成员: 只能@能查看该类记录的有效成员,忽略自己和重复项
+func resolveMentions(collaborators []*entity.BabyCollaborator, openIDs []string, resource string, authorUserID int64) ([]*entity.BabyCollaborator, error) {
+	byOpenID := make(map[string]*entity.BabyCollaborator, len(collaborators))
+	for _, collaborator := range collaborators {
+		if collaborator.User != nil {
+			byOpenID[collaborator.User.OpenID] = collaborator
+		}
+	}
+
+	var mentioned []*entity.BabyCollaborator
+	seen := make(map[int64]bool)
+	for _, openID := range openIDs {
+		collaborator, ok := byOpenID[openID]
+		if !ok || collaborator.IsExpired() {
+			return nil, errors.New(errors.ParamError, "只能@宝宝的亲友团成员")
+		}
+		if !collaborator.Can(resource, entity.PermissionActionRead) {
+			return nil, errors.New(errors.ParamError, "被@的成员无权查看该记录")
+		}
+		if collaborator.UserID == authorUserID || seen[collaborator.UserID] {
+			continue
+		}
+		seen[collaborator.UserID] = true
+		mentioned = append(mentioned, collaborator)
+	}
+	return mentioned, nil
+}
+
+// normalizeReactionEmoji 校验表情回应: 去掉首尾空白后不能为空、不能含文字,长度受限
+func normalizeReactionEmoji(emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || utf8.RuneCountInString(emoji) > entity.RecordReactionMaxRunes {
+		return "", errors.New(errors.ParamError, "无效的表情")
+	}
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsSpace(r) {
+			return "", errors.New(errors.ParamError, "无效的表情")
+		}
+	}
+	return emoji, nil
+}
+
+// buildCommentThreads 按楼层组织评论: 顶层评论按时间正序,回复嵌套在所属顶层评论下
+// 顶层评论已不存在的回复按顶层评论展示
+func buildCommentThreads(comments []*entity.RecordComment, members map[int64]dto.CommentUserDTO, viewer *entity.BabyCollaborator) []dto.CommentDTO {
+	roots := make(map[int64]bool)
+	for _, comment := range comments {
+		if comment.ParentID == 0 {
+			roots[comment.ID] = true
+		}
+	}
+
+	replies := make(map[int64][]dto.CommentDTO)
+	for _, comment := range comments {
+		if comment.ParentID != 0 && roots[comment.ParentID] {
+			replies[comment.ParentID] = append(replies[comment.ParentID], toCommentDTO(comment, members, viewer))
+		}
+	}
+
+	threads := make([]dto.CommentDTO, 0, len(roots))
+	for _, comment := range comments {
+		if comment.ParentID != 0 && roots[comment.ParentID] {
+			continue
+		}
+		thread := toCommentDTO(comment, members, viewer)
+		thread.Replies = replies[comment.ID]
+		threads = append(threads, thread)
+	}
+	return threads
+}
+
+// recentComments 最近的 limit 条顶层评论(保持时间正序)
+func recentComments(threads []dto.CommentDTO, limit int) []dto.CommentDTO {
+	if len(threads) > limit {
+		return threads[len(threads)-limit:]
+	}
+	return threads
+}
+
+// summarizeEmojiReactions 按表情汇总回应,表情按首次回应时间排序
+func summarizeEmojiReactions(reactions []*entity.RecordReaction, members map[int64]dto.CommentUserDTO, viewerUserID int64) []dto.ReactionSummaryDTO {
+	summaries := make([]dto.ReactionSummaryDTO, 0)
+	index := make(map[string]int)
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, dto.ReactionSummaryDTO{Emoji: reaction.Emoji, NickNames: []string{}})
+		}
+		summary := &summaries[i]
+		summary.Count++
+		summary.Reacted = summary.Reacted || reaction.UserID == viewerUserID
+		summary.NickNames = append(summary.NickNames, members[reaction.UserID].NickName)
+	}
+	return summaries
+}
+
+// toCommentDTO 转换为DTO
+func toCommentDTO(comment *entity.RecordComment, members map[int64]dto.CommentUserDTO, viewer *entity.BabyCollaborator) dto.CommentDTO {
+	commentDTO := dto.CommentDTO{
+		CommentID:  strconv.FormatInt(comment.ID, 10),
+		RecordType: comment.RecordType,
+		RecordID:   strconv.FormatInt(comment.RecordID, 10),
+		Author:     members[comment.UserID],
+		Content:    comment.Content,
+		CanDelete:  canDeleteComment(viewer, comment),
+		CreateTime: comment.CreatedAt,
+	}
+	if comment.ParentID != 0 {
+		commentDTO.ParentID = strconv.FormatInt(comment.ParentID, 10)
+	}
+	if comment.ReplyToUserID != 0 {
+		replyTo := members[comment.ReplyToUserID]
+		commentDTO.ReplyTo = &replyTo
+	}
+	for _, userID := range splitUserIDs(comment.MentionedUserIDs) {
+		commentDTO.Mentions = append(commentDTO.Mentions, members[userID])
+	}
+	return commentDTO
+}
+
+// joinUserIDs 被@成员的用户ID,逗号分隔存储
+func joinUserIDs(collaborators []*entity.BabyCollaborator) string {
+	ids := make([]string, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		ids = append(ids, strconv.FormatInt(collaborator.UserID, 10))
+	}
+	return strings.Join(ids, ",")
+}
+
+// splitUserIDs 解析逗号分隔的用户ID
+func splitUserIDs(value string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// recordKey 记录类型与记录ID组成的键
+func recordKey(recordType string, recordID int64) string {
+	return recordType + ":" + strconv.FormatInt(recordID, 10)
+}
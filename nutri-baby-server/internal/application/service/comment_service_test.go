@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestReplyTarget(t *testing.T) {
+	root := &entity.RecordComment{ID: 10, UserID: 1}
+	parentID, replyTo := replyTarget(root)
+	assert.Equal(t, int64(10), parentID)
+	assert.Equal(t, int64(1), replyTo)
+
+	// 回复的回复仍挂在同一顶层评论下
+	reply := &entity.RecordComment{ID: 11, ParentID: 10, UserID: 2}
+	parentID, replyTo = replyTarget(reply)
+	assert.Equal(t, int64(10), parentID)
+	assert.Equal(t, int64(2), replyTo)
+}
+
+func TestCanDeleteComment(t *testing.T) {
+	comment := &entity.RecordComment{UserID: 1}
+
+	assert.True(t, canDeleteComment(&entity.BabyCollaborator{UserID: 1, Role: entity.CollaboratorRoleViewer}, comment))
+	assert.True(t, canDeleteComment(&entity.BabyCollaborator{UserID: 2, Role: entity.CollaboratorRoleAdmin}, comment))
+	assert.False(t, canDeleteComment(&entity.BabyCollaborator{UserID: 3, Role: entity.CollaboratorRoleEditor}, comment))
+}
+
+func TestResolveMentions(t *testing.T) {
+	collaborators := []*entity.BabyCollaborator{
+		{UserID: 1, Role: entity.CollaboratorRoleAdmin, User: &entity.User{OpenID: "mom"}},
+		{UserID: 2, Role: entity.CollaboratorRoleEditor, User: &entity.User{OpenID: "dad"}},
+		{UserID: 3, Role: entity.CollaboratorRoleCaregiver, User: &entity.User{OpenID: "nanny"}},
+	}
+
+	mentioned, err := resolveMentions(collaborators, []string{"dad", "mom", "dad"}, entity.PermissionResourceFeeding, 1)
+	require.NoError(t, err)
+	require.Len(t, mentioned, 1) // 忽略自己和重复项
+	assert.Equal(t, int64(2), mentioned[0].UserID)
+	assert.Equal(t, "2", joinUserIDs(mentioned))
+
+	_, err = resolveMentions(collaborators, []string{"stranger"}, entity.PermissionResourceFeeding, 1)
+	assert.ErrorContains(t, err, "只能@宝宝的亲友团成员")
+
+	// 照护者默认看不到成长记录
+	_, err = resolveMentions(collaborators, []string{"nanny"}, entity.PermissionResourceGrowth, 1)
+	assert.ErrorContains(t, err, "被@的成员无权查看该记录")
+}
+
+func TestNormalizeReactionEmoji(t *testing.T) {
+	for _, emoji := range []string{"👍", " ❤️ ", "👨‍👩‍👧", "1️⃣"} {
+		_, err := normalizeReactionEmoji(emoji)
+		assert.NoError(t, err, emoji)
+	}
+
+	emoji, err := normalizeReactionEmoji(" 🎉 ")
+	require.NoError(t, err)
+	assert.Equal(t, "🎉", emoji)
+
+	for _, emoji := range []string{"", "  ", "ok", "好", "👍 👍", "👍👍👍👍👍👍👍👍👍"} {
+		_, err := normalizeReactionEmoji(emoji)
+		assert.ErrorContains(t, err, "无效的表情", emoji)
+	}
+}
+
+func TestBuildCommentThreads(t *testing.T) {
+	members := map[int64]dto.CommentUserDTO{
+		1: {OpenID: "mom", NickName: "小红", Relationship: "妈妈"},
+		2: {OpenID: "dad", NickName: "小明", Relationship: "爸爸"},
+	}
+	comments := []*entity.RecordComment{
+		{ID: 10, RecordType: "feeding", RecordID: 5, UserID: 1, Content: "吐奶了", MentionedUserIDs: "2", CreatedAt: 100},
+		{ID: 11, RecordType: "feeding", RecordID: 5, UserID: 2, Content: "第二条", CreatedAt: 200},
+		{ID: 12, RecordType: "feeding", RecordID: 5, ParentID: 10, ReplyToUserID: 1, UserID: 2, Content: "收到", CreatedAt: 300},
+		{ID: 13, RecordType: "feeding", RecordID: 5, ParentID: 99, UserID: 2, Content: "顶层已不存在", CreatedAt: 400},
+	}
+	viewer := &entity.BabyCollaborator{UserID: 2, Role: entity.CollaboratorRoleEditor}
+
+	threads := buildCommentThreads(comments, members, viewer)
+	require.Len(t, threads, 3)
+
+	first := threads[0]
+	assert.Equal(t, "10", first.CommentID)
+	assert.Equal(t, "妈妈", first.Author.Relationship)
+	assert.False(t, first.CanDelete)
+	require.Len(t, first.Mentions, 1)
+	assert.Equal(t, "dad", first.Mentions[0].OpenID)
+	require.Len(t, first.Replies, 1)
+	assert.Equal(t, "10", first.Replies[0].ParentID)
+	require.NotNil(t, first.Replies[0].ReplyTo)
+	assert.Equal(t, "mom", first.Replies[0].ReplyTo.OpenID)
+	assert.True(t, first.Replies[0].CanDelete)
+
+	assert.Equal(t, "11", threads[1].CommentID)
+	assert.Equal(t, "13", threads[2].CommentID)
+
+	recent := recentComments(threads, 2)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "11", recent[0].CommentID)
+}
+
+func TestSummarizeEmojiReactions(t *testing.T) {
+	members := map[int64]dto.CommentUserDTO{
+		1: {NickName: "小红"},
+		2: {NickName: "小明"},
+	}
+	reactions := []*entity.RecordReaction{
+		{UserID: 1, Emoji: "👏"},
+		{UserID: 2, Emoji: "❤️"},
+		{UserID: 2, Emoji: "👏"},
+	}
+
+	summaries := summarizeEmojiReactions(reactions, members, 1)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, dto.ReactionSummaryDTO{Emoji: "👏", Count: 2, Reacted: true, NickNames: []string{"小红", "小明"}}, summaries[0])
+	assert.Equal(t, dto.ReactionSummaryDTO{Emoji: "❤️", Count: 1, Reacted: false, NickNames: []string{"小明"}}, summaries[1])
+
+	assert.Empty(t, summarizeEmojiReactions(nil, members, 1))
+}
+
+func TestSplitUserIDs(t *testing.T) {
+	assert.Equal(t, []int64{1, 2}, splitUserIDs("1, 2"))
+	assert.Empty(t, splitUserIDs(""))
+}
@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
 	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
 	"github.com/wxlbd/nutri-baby-server/pkg/errors"
 )
@@ -43,8 +44,9 @@ func NewDailyStatsService(
 
 // GetDailyStats 获取按日统计数据
 func (s *DailyStatsService) GetDailyStats(ctx context.Context, openID string, req *dto.DailyStatsRequest) (*dto.DailyStatsResponse, error) {
-	// 验证宝宝访问权限
-	if err := s.CheckBabyAccess(ctx, req.BabyID, openID); err != nil {
+	// 验证宝宝访问权限: 只统计当前用户有查看权限的记录类型
+	readable, err := s.ReadableResources(ctx, req.BabyID, openID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -54,8 +56,15 @@ func (s *DailyStatsService) GetDailyStats(ctx context.Context, openID string, re
 		return nil, errors.New(errors.ParamError, "无效的宝宝ID格式")
 	}
 
-	// 解析统计类型
-	types := parseStatsTypes(req.Types)
+	// 解析统计类型,明确指定但无权查看的类型直接拒绝,默认全部时跳过无权查看的类型
+	var types []string
+	for _, t := range parseStatsTypes(req.Types) {
+		if readable[t] {
+			types = append(types, t)
+		} else if req.Types != "" && entity.IsValidPermission(t, entity.PermissionActionRead) {
+			return nil, permissionDenied(t, entity.PermissionActionRead)
+		}
+	}
 
 	response := &dto.DailyStatsResponse{}
 
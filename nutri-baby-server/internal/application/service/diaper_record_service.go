@@ -16,6 +16,9 @@ import (
 type DiaperRecordService struct {
 	*BaseRecordService
 	diaperRecordRepo repository.DiaperRecordRepository
+	attachmentRepo   repository.AttachmentRepository
+	commentRepo      repository.RecordCommentRepository
+	revisionService  *RevisionService
 }
 
 // NewDiaperRecordService 创建尿布记录服务
@@ -24,17 +27,23 @@ func NewDiaperRecordService(
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	userRepo repository.UserRepository,
 	diaperRecordRepo repository.DiaperRecordRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
+	revisionService *RevisionService,
 	logger *zap.Logger,
 ) *DiaperRecordService {
 	return &DiaperRecordService{
 		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
 		diaperRecordRepo:  diaperRecordRepo,
+		attachmentRepo:    attachmentRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
 	}
 }
 
 // CreateDiaperRecord 创建尿布记录
 func (s *DiaperRecordService) CreateDiaperRecord(ctx context.Context, openID string, req *dto.CreateDiaperRecordRequest) (*dto.DiaperRecordDTO, error) {
-	if err := s.CheckBabyAccess(ctx, req.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, req.BabyID, openID, entity.PermissionResourceDiaper, entity.PermissionActionCreate); err != nil {
 		return nil, err
 	}
 
@@ -74,6 +83,10 @@ func (s *DiaperRecordService) CreateDiaperRecord(ctx context.Context, openID str
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeDiaper, record.ID, nil); err != nil {
+		return nil, err
+	}
+
 	resultNote := ""
 	if record.Note != nil {
 		resultNote = *record.Note
@@ -92,7 +105,7 @@ func (s *DiaperRecordService) CreateDiaperRecord(ctx context.Context, openID str
 
 // GetDiaperRecords 获取尿布记录列表
 func (s *DiaperRecordService) GetDiaperRecords(ctx context.Context, openID string, query *dto.RecordListQuery) ([]dto.DiaperRecordDTO, int64, error) {
-	if err := s.CheckBabyAccess(ctx, query.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, query.BabyID, openID, entity.PermissionResourceDiaper, entity.PermissionActionRead); err != nil {
 		return nil, 0, err
 	}
 
@@ -153,7 +166,7 @@ func (s *DiaperRecordService) GetDiaperRecordById(ctx context.Context, openID, r
 	}
 
 	// 验证用户是否有权限访问该宝宝的记录
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceDiaper, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
@@ -190,8 +203,10 @@ func (s *DiaperRecordService) UpdateDiaperRecord(ctx context.Context, openID, re
 		return nil, err
 	}
 
+	before := revisionSnapshotOf(record)
+
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordUpdatePermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceDiaper, record.CreatedBy); err != nil {
 		return nil, err
 	}
 
@@ -228,6 +243,10 @@ func (s *DiaperRecordService) UpdateDiaperRecord(ctx context.Context, openID, re
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeDiaper, record.ID, before); err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("尿布记录更新成功",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
@@ -254,7 +273,7 @@ func (s *DiaperRecordService) DeleteDiaperRecord(ctx context.Context, openID, re
 	}
 
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceDiaper, entity.PermissionActionDelete); err != nil {
 		return err
 	}
 
@@ -266,6 +285,24 @@ func (s *DiaperRecordService) DeleteDiaperRecord(ctx context.Context, openID, re
 		return err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeDiaper, record.ID, revisionSnapshotOf(record)); err != nil {
+		return err
+	}
+
+	// 同步软删除记录的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeDiaper, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录附件失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
+	// 同步软删除记录的评论和表情回应
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeDiaper, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录评论失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
 	s.logger.Info("尿布记录删除成功",
 		zap.String("recordID", recordID),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
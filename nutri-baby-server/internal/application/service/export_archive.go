@@ -0,0 +1,219 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/xlsx"
+)
+
+// exportTimeLayout 导出 CSV/XLSX 中的时间格式(服务器本地时区)
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// exportTable 单类记录的表格数据,同时用于 CSV 和 XLSX 工作表
+type exportTable struct {
+	name   string // 文件名/工作表名
+	header []string
+	rows   [][]any
+}
+
+// buildExportArchive 生成导出 ZIP: 每类记录一个 CSV、规范化的 export.json 及可选的 XLSX 工作簿
+func buildExportArchive(doc *dto.ExportDocument, includeXLSX bool) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	jsonData, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "export.json", jsonData); err != nil {
+		return nil, err
+	}
+
+	tables := exportTables(doc)
+	for _, table := range tables {
+		csvData, err := table.csv()
+		if err != nil {
+			return nil, err
+		}
+		if err := writeZipFile(zw, table.name+".csv", csvData); err != nil {
+			return nil, err
+		}
+	}
+
+	if includeXLSX {
+		workbook := xlsx.NewWorkbook()
+		for _, table := range tables {
+			workbook.AddSheet(table.name, table.header, table.rows)
+		}
+		var xlsxBuf bytes.Buffer
+		if err := workbook.Write(&xlsxBuf); err != nil {
+			return nil, err
+		}
+		if err := writeZipFile(zw, "export.xlsx", xlsxBuf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// csv 生成带 UTF-8 BOM 的 CSV,便于 Excel 正确识别中文
+func (t *exportTable) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.header); err != nil {
+		return nil, err
+	}
+	for _, row := range t.rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatExportValue(value)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// formatExportValue 将单元格值格式化为 CSV 字符串
+func formatExportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatExportTime 毫秒时间戳格式化为本地时间,0 或空值返回空字符串
+func formatExportTime(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).In(time.Local).Format(exportTimeLayout)
+}
+
+func formatExportTimePtr(ms *int64) string {
+	if ms == nil {
+		return ""
+	}
+	return formatExportTime(*ms)
+}
+
+// exportTables 将导出文档转换为各类记录的表格
+func exportTables(doc *dto.ExportDocument) []*exportTable {
+	feedings := &exportTable{
+		name:   "feedings",
+		header: []string{"record_id", "time", "feeding_type", "amount_ml", "duration_seconds", "created_by_name"},
+	}
+	for _, r := range doc.Feedings {
+		feedings.rows = append(feedings.rows, []any{
+			r.RecordID, formatExportTime(r.Time), r.FeedingType, r.AmountMl, r.Duration, r.CreatedByName,
+		})
+	}
+
+	sleeps := &exportTable{
+		name:   "sleeps",
+		header: []string{"record_id", "start_time", "end_time", "duration_seconds", "type", "created_by_name"},
+	}
+	for _, r := range doc.Sleeps {
+		sleeps.rows = append(sleeps.rows, []any{
+			r.RecordID, formatExportTime(r.StartTime), formatExportTimePtr(r.EndTime), r.Duration, r.Type, r.CreatedByName,
+		})
+	}
+
+	diapers := &exportTable{
+		name:   "diapers",
+		header: []string{"record_id", "time", "type", "poop_color", "poop_texture", "note", "created_by_name"},
+	}
+	for _, r := range doc.Diapers {
+		diapers.rows = append(diapers.rows, []any{
+			r.RecordID, formatExportTime(r.Time), r.Type, r.PoopColor, r.PoopTexture, r.Note, r.CreatedByName,
+		})
+	}
+
+	growths := &exportTable{
+		name:   "growths",
+		header: []string{"record_id", "time", "height_cm", "weight_kg", "head_circumference_cm", "note", "created_by_name"},
+	}
+	for _, r := range doc.Growths {
+		growths.rows = append(growths.rows, []any{
+			r.RecordID, formatExportTime(r.Time), r.Height, r.Weight, r.HeadCircumference, r.Note, r.CreatedByName,
+		})
+	}
+
+	vaccines := &exportTable{
+		name: "vaccines",
+		header: []string{"schedule_id", "vaccine_type", "vaccine_name", "dose_number", "age_in_months",
+			"scheduled_date", "status", "vaccine_date", "hospital", "batch_number", "doctor", "reaction", "note"},
+	}
+	for _, r := range doc.Vaccines {
+		vaccines.rows = append(vaccines.rows, []any{
+			r.ScheduleID, r.VaccineType, r.VaccineName, r.DoseNumber, r.AgeInMonths,
+			formatExportTime(r.ScheduledDate), r.VaccinationStatus, formatExportTimePtr(r.VaccineDate),
+			r.Hospital, r.BatchNumber, r.Doctor, r.Reaction, r.Note,
+		})
+	}
+
+	milestones := &exportTable{
+		name:   "milestones",
+		header: []string{"milestone_id", "category", "title", "age_in_months", "status", "achieved_date", "note"},
+	}
+	for _, r := range doc.Milestones {
+		milestones.rows = append(milestones.rows, []any{
+			r.MilestoneID, r.Category, r.Title, r.AgeInMonths, r.Status, formatExportTimePtr(r.AchievedDate), r.Note,
+		})
+	}
+
+	return []*exportTable{feedings, sleeps, diapers, growths, vaccines, milestones}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+)
+
+func TestBuildExportArchive(t *testing.T) {
+	weight := 6.5
+	note := "备注,含逗号"
+	doc := &dto.ExportDocument{
+		Format:  dto.ExportFormatName,
+		Version: dto.ExportFormatVersion,
+		Baby:    dto.ExportBaby{BabyID: "1", Name: "小明"},
+		Feedings: []dto.ExportFeeding{
+			{RecordID: "10", Time: 1700000000000, FeedingType: "bottle", AmountMl: 120, CreatedByName: "妈妈"},
+		},
+		Growths: []dto.ExportGrowth{
+			{RecordID: "20", Time: 1700000000000, Weight: &weight, Note: &note},
+		},
+	}
+
+	data, err := buildExportArchive(doc, true)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = content
+	}
+
+	for _, name := range []string{"export.json", "export.xlsx", "feedings.csv", "sleeps.csv", "diapers.csv",
+		"growths.csv", "vaccines.csv", "milestones.csv"} {
+		assert.Contains(t, files, name)
+	}
+
+	// JSON 可以还原为导出文档
+	var decoded dto.ExportDocument
+	require.NoError(t, json.Unmarshal(files["export.json"], &decoded))
+	assert.Equal(t, dto.ExportFormatName, decoded.Format)
+	assert.Len(t, decoded.Feedings, 1)
+
+	// CSV 带 BOM,字段正确转义
+	growthCSV := string(files["growths.csv"])
+	require.True(t, strings.HasPrefix(growthCSV, "\ufeff"))
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(growthCSV, "\ufeff"))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "weight_kg", rows[0][3])
+	assert.Equal(t, "6.5", rows[1][3])
+	assert.Equal(t, note, rows[1][5])
+
+	// XLSX 是包含每类记录工作表的 zip 包
+	xr, err := zip.NewReader(bytes.NewReader(files["export.xlsx"]), int64(len(files["export.xlsx"])))
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, f := range xr.File {
+		names[f.Name] = true
+	}
+	assert.True(t, names["xl/workbook.xml"])
+	assert.True(t, names["xl/worksheets/sheet6.xml"])
+}
+
+func TestBuildExportArchiveWithoutXLSX(t *testing.T) {
+	data, err := buildExportArchive(&dto.ExportDocument{Format: dto.ExportFormatName}, false)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, f := range zr.File {
+		assert.NotEqual(t, "export.xlsx", f.Name)
+	}
+}
@@ -0,0 +1,521 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	exportDownloadTTL  = 24 * time.Hour       // 导出文件下载链接有效期
+	exportJobTimeout   = 10 * time.Minute     // 单个导出任务最长执行时间
+	exportPageSize     = 500                  // 分页读取记录的每页条数
+	exportListLimit    = 20                   // 导出历史最多返回条数
+	exportVaccineLimit = 1000                 // 疫苗日程最多读取条数
+	exportWorkers      = 2                    // 同时生成导出文件的 worker 数
+	exportQueueSize    = 100                  // 等待生成的任务队列长度,队列满时任务留在数据库中等待定时任务补充入队
+	exportRequeueDelay = time.Minute          // 排队超过该时间仍未领取的任务由定时任务重新入队
+	exportStaleAfter   = 3 * exportJobTimeout // 生成中超过该时间未更新的任务视为中断(如服务重启)
+)
+
+// ExportService 宝宝数据导出服务
+// 导出任务异步生成 ZIP(每类记录一个 CSV + 规范化 JSON + 可选 XLSX),完成后提供限时下载链接
+// 任务通过有界队列交给固定数量的 worker 生成,避免并发导出过多占满内存和数据库连接
+type ExportService struct {
+	*BaseRecordService
+	exportRepo        repository.ExportJobRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	milestoneRepo     repository.BabyMilestoneRepository
+	storage           storage.Storage
+	queue             chan int64
+}
+
+// NewExportService 创建数据导出服务
+func NewExportService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	exportRepo repository.ExportJobRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	store storage.Storage,
+	logger *zap.Logger,
+) *ExportService {
+	s := &ExportService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		exportRepo:        exportRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		scheduleRepo:      scheduleRepo,
+		milestoneRepo:     milestoneRepo,
+		storage:           store,
+		queue:             make(chan int64, exportQueueSize),
+	}
+	for i := 0; i < exportWorkers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// CreateExport 创建导出任务(异步生成)
+func (s *ExportService) CreateExport(ctx context.Context, openID, babyID string, req *dto.CreateExportRequest) (*dto.ExportJobDTO, error) {
+	// 导出包含全部记录类型,需要全部类型的查看权限
+	if err := s.CheckRecordsPermission(ctx, babyID, openID, entity.PermissionActionRead, entity.PermissionResources...); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := req.EndTime
+	if endTime <= 0 {
+		endTime = time.Now().UnixMilli()
+	}
+	if req.StartTime < 0 || req.StartTime > endTime {
+		return nil, errors.New(errors.ParamError, "开始时间不能晚于结束时间")
+	}
+
+	job := &entity.ExportJob{
+		BabyID:      babyIDInt64,
+		RequestedBy: user.ID,
+		StartTime:   req.StartTime,
+		EndTime:     endTime,
+		IncludeXLSX: req.IncludeXLSX,
+		Status:      entity.ExportStatusPending,
+	}
+	if err := s.exportRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建导出任务",
+		zap.Int64("exportId", job.ID),
+		zap.String("babyId", babyID),
+		zap.Int64("startTime", job.StartTime),
+		zap.Int64("endTime", job.EndTime))
+
+	s.enqueue(job.ID)
+
+	return s.toExportJobDTO(job), nil
+}
+
+// GetExport 查询导出任务状态,完成后返回下载链接
+func (s *ExportService) GetExport(ctx context.Context, openID, exportID string) (*dto.ExportJobDTO, error) {
+	job, err := s.findOwnJob(ctx, openID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toExportJobDTO(job), nil
+}
+
+// ListExports 获取用户为宝宝发起的导出任务
+func (s *ExportService) ListExports(ctx context.Context, openID, babyID string) ([]*dto.ExportJobDTO, error) {
+	if err := s.CheckBabyAccess(ctx, babyID, openID); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.exportRepo.FindByBabyAndUser(ctx, babyIDInt64, user.ID, exportListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ExportJobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, s.toExportJobDTO(job))
+	}
+	return result, nil
+}
+
+// CleanupExpiredExports 清理下载链接已过期的导出文件(定时任务调用)
+func (s *ExportService) CleanupExpiredExports(ctx context.Context) error {
+	jobs, err := s.exportRepo.FindExpired(ctx, time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.FileKey != "" {
+			if err := s.storage.Delete(ctx, job.FileKey); err != nil {
+				s.logger.Warn("删除过期导出文件失败", zap.Int64("exportId", job.ID), zap.Error(err))
+				continue
+			}
+		}
+		job.Status = entity.ExportStatusExpired
+		job.FileKey = ""
+		if err := s.exportRepo.Update(ctx, job); err != nil {
+			s.logger.Warn("更新导出任务状态失败", zap.Int64("exportId", job.ID), zap.Error(err))
+		}
+	}
+
+	if len(jobs) > 0 {
+		s.logger.Info("已清理过期导出文件", zap.Int("count", len(jobs)))
+	}
+	return nil
+}
+
+// RecoverExports 恢复排队和中断的导出任务(定时任务调用)
+// 排队超时仍未领取的任务(队列已满或服务重启前未开始)重新入队;生成中但长时间未更新的任务标记为失败
+func (s *ExportService) RecoverExports(ctx context.Context) error {
+	now := time.Now()
+
+	failed, err := s.exportRepo.FailStaleProcessing(ctx, now.Add(-exportStaleAfter).UnixMilli(), "导出任务中断,请重新导出")
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		s.logger.Warn("已将中断的导出任务标记为失败", zap.Int64("count", failed))
+	}
+
+	jobs, err := s.exportRepo.FindPendingBefore(ctx, now.Add(-exportRequeueDelay).UnixMilli(), exportQueueSize)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if !s.enqueue(job.ID) {
+			break
+		}
+	}
+	return nil
+}
+
+// enqueue 将任务放入生成队列,队列已满时返回 false,任务保持排队状态等待 RecoverExports 重新入队
+func (s *ExportService) enqueue(jobID int64) bool {
+	select {
+	case s.queue <- jobID:
+		return true
+	default:
+		s.logger.Warn("导出队列已满,任务稍后处理", zap.Int64("exportId", jobID))
+		return false
+	}
+}
+
+// worker 从队列中依次领取并生成导出任务
+func (s *ExportService) worker() {
+	for jobID := range s.queue {
+		s.processJob(jobID)
+	}
+}
+
+// processJob 生成导出文件(后台执行)
+// 先领取任务,同一任务被重复入队时只会生成一次
+func (s *ExportService) processJob(jobID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportJobTimeout)
+	defer cancel()
+
+	claimed, err := s.exportRepo.Claim(ctx, jobID)
+	if err != nil {
+		s.logger.Error("领取导出任务失败", zap.Int64("exportId", jobID), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	job, err := s.exportRepo.FindByID(ctx, jobID)
+	if err != nil {
+		s.logger.Error("读取导出任务失败", zap.Int64("exportId", jobID), zap.Error(err))
+		return
+	}
+
+	if err := s.generate(ctx, job); err != nil {
+		s.logger.Error("生成导出文件失败", zap.Int64("exportId", jobID), zap.Error(err))
+		message := err.Error()
+		job.Status = entity.ExportStatusFailed
+		job.ErrorMessage = &message
+	} else {
+		now := time.Now()
+		completedAt := now.UnixMilli()
+		expiresAt := now.Add(exportDownloadTTL).UnixMilli()
+		job.Status = entity.ExportStatusCompleted
+		job.CompletedAt = &completedAt
+		job.ExpiresAt = &expiresAt
+	}
+
+	if err := s.exportRepo.Update(ctx, job); err != nil {
+		s.logger.Error("更新导出任务状态失败", zap.Int64("exportId", jobID), zap.Error(err))
+	}
+}
+
+// generate 读取数据、打包并写入存储
+func (s *ExportService) generate(ctx context.Context, job *entity.ExportJob) error {
+	doc, err := s.buildDocument(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	archive, err := buildExportArchive(doc, job.IncludeXLSX)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("exports/%d/nutri-baby-export_%d_%d_%s.zip",
+		job.BabyID, job.BabyID, job.ID, time.Now().Format("20060102"))
+	if err := s.storage.Put(ctx, key, archive, "application/zip"); err != nil {
+		return err
+	}
+
+	job.FileKey = key
+	job.FileSize = int64(len(archive))
+	job.RecordCount = len(doc.Feedings) + len(doc.Sleeps) + len(doc.Diapers) + len(doc.Growths) +
+		len(doc.Vaccines) + len(doc.Milestones)
+	return nil
+}
+
+// buildDocument 读取导出范围内的全部数据
+// 喂养/睡眠/尿布/成长记录按时间范围筛选;疫苗日程和里程碑属于宝宝的整体计划,全部导出
+func (s *ExportService) buildDocument(ctx context.Context, job *entity.ExportJob) (*dto.ExportDocument, error) {
+	baby, err := s.babyRepo.FindByID(ctx, job.BabyID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &dto.ExportDocument{
+		Format:     dto.ExportFormatName,
+		Version:    dto.ExportFormatVersion,
+		ExportedAt: time.Now().UnixMilli(),
+		StartTime:  job.StartTime,
+		EndTime:    job.EndTime,
+		Baby: dto.ExportBaby{
+			BabyID:    strconv.FormatInt(baby.ID, 10),
+			Name:      baby.Name,
+			Nickname:  baby.Nickname,
+			Gender:    baby.Gender,
+			BirthDate: baby.BirthDate,
+		},
+		Feedings:   []dto.ExportFeeding{},
+		Sleeps:     []dto.ExportSleep{},
+		Diapers:    []dto.ExportDiaper{},
+		Growths:    []dto.ExportGrowth{},
+		Vaccines:   []dto.ExportVaccine{},
+		Milestones: []dto.ExportMilestone{},
+	}
+
+	for page := 1; ; page++ {
+		records, _, err := s.feedingRecordRepo.FindByBabyID(ctx, job.BabyID, job.StartTime, job.EndTime, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			doc.Feedings = append(doc.Feedings, dto.ExportFeeding{
+				RecordID:      strconv.FormatInt(r.ID, 10),
+				Time:          r.Time,
+				FeedingType:   r.FeedingType,
+				AmountMl:      r.Amount,
+				Duration:      r.Duration,
+				Detail:        r.Detail,
+				CreatedBy:     strconv.FormatInt(r.CreatedBy, 10),
+				CreatedByName: r.CreatedByName,
+				CreateTime:    r.CreatedAt,
+			})
+		}
+		if len(records) < exportPageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		records, _, err := s.sleepRecordRepo.FindByBabyID(ctx, job.BabyID, job.StartTime, job.EndTime, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			doc.Sleeps = append(doc.Sleeps, dto.ExportSleep{
+				RecordID:      strconv.FormatInt(r.ID, 10),
+				StartTime:     r.StartTime,
+				EndTime:       r.EndTime,
+				Duration:      r.Duration,
+				Type:          r.Type,
+				CreatedBy:     strconv.FormatInt(r.CreatedBy, 10),
+				CreatedByName: r.CreatedByName,
+				CreateTime:    r.CreatedAt,
+			})
+		}
+		if len(records) < exportPageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		records, _, err := s.diaperRecordRepo.FindByBabyID(ctx, job.BabyID, job.StartTime, job.EndTime, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			doc.Diapers = append(doc.Diapers, dto.ExportDiaper{
+				RecordID:      strconv.FormatInt(r.ID, 10),
+				Time:          r.Time,
+				Type:          r.Type,
+				PoopColor:     r.PoopColor,
+				PoopTexture:   r.PoopTexture,
+				Note:          r.Note,
+				CreatedBy:     strconv.FormatInt(r.CreatedBy, 10),
+				CreatedByName: r.CreatedByName,
+				CreateTime:    r.CreatedAt,
+			})
+		}
+		if len(records) < exportPageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		records, _, err := s.growthRecordRepo.FindByBabyID(ctx, job.BabyID, job.StartTime, job.EndTime, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			doc.Growths = append(doc.Growths, dto.ExportGrowth{
+				RecordID:          strconv.FormatInt(r.ID, 10),
+				Time:              r.Time,
+				Height:            r.Height,
+				Weight:            r.Weight,
+				HeadCircumference: r.HeadCircumference,
+				Note:              r.Note,
+				CreatedBy:         strconv.FormatInt(r.CreatedBy, 10),
+				CreatedByName:     r.CreatedByName,
+				CreateTime:        r.CreatedAt,
+			})
+		}
+		if len(records) < exportPageSize {
+			break
+		}
+	}
+
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, job.BabyID, 1, exportVaccineLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range schedules {
+		doc.Vaccines = append(doc.Vaccines, dto.ExportVaccine{
+			ScheduleID:        strconv.FormatInt(sc.ID, 10),
+			VaccineType:       sc.VaccineType,
+			VaccineName:       sc.VaccineName,
+			DoseNumber:        sc.DoseNumber,
+			AgeInMonths:       sc.AgeInMonths,
+			ScheduledDate:     sc.ScheduledDate,
+			VaccinationStatus: sc.VaccinationStatus,
+			VaccineDate:       sc.VaccineDate,
+			Hospital:          sc.Hospital,
+			BatchNumber:       sc.BatchNumber,
+			Doctor:            sc.Doctor,
+			Reaction:          sc.Reaction,
+			Note:              sc.Note,
+		})
+	}
+
+	milestones, err := s.milestoneRepo.FindByBabyID(ctx, job.BabyID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range milestones {
+		doc.Milestones = append(doc.Milestones, dto.ExportMilestone{
+			MilestoneID:  strconv.FormatInt(m.ID, 10),
+			Category:     m.Category,
+			Title:        m.Title,
+			AgeInMonths:  m.AgeInMonths,
+			Status:       m.Status,
+			AchievedDate: m.AchievedDate,
+			Note:         m.Note,
+		})
+	}
+
+	return doc, nil
+}
+
+// findOwnJob 查找当前用户发起的导出任务,并校验其仍有宝宝的访问权限
+func (s *ExportService) findOwnJob(ctx context.Context, openID, exportID string) (*entity.ExportJob, error) {
+	exportIDInt64, err := strconv.ParseInt(exportID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid export id format")
+	}
+
+	job, err := s.exportRepo.FindByID(ctx, exportIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+	if job.RequestedBy != user.ID {
+		return nil, errors.New(errors.NotFound, "导出任务不存在")
+	}
+
+	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(job.BabyID, 10), openID); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// toExportJobDTO 转换为DTO,已完成且未过期的任务生成限时下载链接
+func (s *ExportService) toExportJobDTO(job *entity.ExportJob) *dto.ExportJobDTO {
+	result := &dto.ExportJobDTO{
+		ExportID:     strconv.FormatInt(job.ID, 10),
+		BabyID:       strconv.FormatInt(job.BabyID, 10),
+		StartTime:    job.StartTime,
+		EndTime:      job.EndTime,
+		IncludeXLSX:  job.IncludeXLSX,
+		Status:       job.Status,
+		RecordCount:  job.RecordCount,
+		FileSize:     job.FileSize,
+		ExpiresAt:    job.ExpiresAt,
+		ErrorMessage: job.ErrorMessage,
+		CompletedAt:  job.CompletedAt,
+		CreateTime:   job.CreatedAt,
+	}
+
+	if job.Status != entity.ExportStatusCompleted || job.FileKey == "" || job.ExpiresAt == nil {
+		return result
+	}
+
+	remaining := time.Until(time.UnixMilli(*job.ExpiresAt))
+	if remaining <= 0 {
+		result.Status = entity.ExportStatusExpired
+		return result
+	}
+
+	downloadURL, err := s.storage.SignedURL(job.FileKey, remaining)
+	if err != nil {
+		s.logger.Warn("生成导出下载链接失败", zap.Int64("exportId", job.ID), zap.Error(err))
+		return result
+	}
+	result.DownloadURL = &downloadURL
+	return result
+}
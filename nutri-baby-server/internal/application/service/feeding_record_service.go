@@ -18,6 +18,9 @@ import (
 type FeedingRecordService struct {
 	*BaseRecordService
 	feedingRecordRepo repository.FeedingRecordRepository
+	attachmentRepo    repository.AttachmentRepository
+	commentRepo       repository.RecordCommentRepository
+	revisionService   *RevisionService
 	schedulerService  *SchedulerService
 }
 
@@ -27,20 +30,45 @@ func NewFeedingRecordService(
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	userRepo repository.UserRepository,
 	feedingRecordRepo repository.FeedingRecordRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
+	revisionService *RevisionService,
 	schedulerService *SchedulerService,
 	logger *zap.Logger,
 ) *FeedingRecordService {
-	return &FeedingRecordService{
+	s := &FeedingRecordService{
 		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
 		feedingRecordRepo: feedingRecordRepo,
+		attachmentRepo:    attachmentRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
 		schedulerService:  schedulerService,
 	}
+	revisionService.OnRestore(entity.AttachmentRecordTypeFeeding, s.rescheduleReminder)
+	return s
+}
+
+// rescheduleReminder 喂养记录恢复到历史版本后重新安排提醒:取消原有的提醒任务,提醒未发送时按新的提醒时间添加
+func (s *FeedingRecordService) rescheduleReminder(ctx context.Context, record any) {
+	feeding, ok := record.(*entity.FeedingRecord)
+	if !ok || s.schedulerService == nil {
+		return
+	}
+	s.schedulerService.CancelFeedingReminderTask(feedingReminderJobTag(feeding.ID))
+	if feeding.NextReminderTime == nil || feeding.ReminderSent {
+		return
+	}
+	if _, err := s.schedulerService.AddFeedingReminderTask(ctx, feeding); err != nil {
+		s.logger.Warn("重新安排喂养提醒失败",
+			zap.String("recordID", strconv.FormatInt(feeding.ID, 10)),
+			zap.Error(err))
+	}
 }
 
 // CreateFeedingRecord 创建喂养记录
 func (s *FeedingRecordService) CreateFeedingRecord(ctx context.Context, openID string, req *dto.CreateFeedingRecordRequest) (*dto.FeedingRecordDTO, error) {
 	// 验证宝宝存在且用户有权限
-	if err := s.CheckBabyAccess(ctx, req.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, req.BabyID, openID, entity.PermissionResourceFeeding, entity.PermissionActionCreate); err != nil {
 		return nil, err
 	}
 
@@ -124,6 +152,10 @@ func (s *FeedingRecordService) CreateFeedingRecord(ctx context.Context, openID s
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeFeeding, record.ID, nil); err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("喂养记录创建成功",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)),
@@ -163,7 +195,7 @@ func (s *FeedingRecordService) CreateFeedingRecord(ctx context.Context, openID s
 // GetFeedingRecords 获取喂养记录列表
 func (s *FeedingRecordService) GetFeedingRecords(ctx context.Context, openID string, query *dto.RecordListQuery) ([]dto.FeedingRecordDTO, int64, error) {
 	// 验证宝宝访问权限
-	if err := s.CheckBabyAccess(ctx, query.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, query.BabyID, openID, entity.PermissionResourceFeeding, entity.PermissionActionRead); err != nil {
 		return nil, 0, err
 	}
 
@@ -250,7 +282,7 @@ func (s *FeedingRecordService) GetFeedingRecordById(ctx context.Context, openID,
 	}
 
 	// 验证用户是否有权限访问该宝宝的记录
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceFeeding, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
@@ -312,8 +344,10 @@ func (s *FeedingRecordService) UpdateFeedingRecord(ctx context.Context, openID,
 		return nil, err
 	}
 
+	before := revisionSnapshotOf(record)
+
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordUpdatePermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceFeeding, record.CreatedBy); err != nil {
 		return nil, err
 	}
 
@@ -397,6 +431,10 @@ func (s *FeedingRecordService) UpdateFeedingRecord(ctx context.Context, openID,
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeFeeding, record.ID, before); err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("喂养记录更新成功",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
@@ -423,7 +461,7 @@ func (s *FeedingRecordService) DeleteFeedingRecord(ctx context.Context, openID,
 	}
 
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceFeeding, entity.PermissionActionDelete); err != nil {
 		return err
 	}
 
@@ -435,6 +473,24 @@ func (s *FeedingRecordService) DeleteFeedingRecord(ctx context.Context, openID,
 		return err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeFeeding, record.ID, revisionSnapshotOf(record)); err != nil {
+		return err
+	}
+
+	// 同步软删除记录的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeFeeding, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录附件失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
+	// 同步软删除记录的评论和表情回应
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeFeeding, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录评论失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
 	s.logger.Info("喂养记录删除成功",
 		zap.String("recordID", recordID),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
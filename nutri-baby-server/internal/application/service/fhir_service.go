@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/fhir"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	fhirPageSize      = 500  // 分页读取成长记录的每页条数
+	fhirVaccineLimit  = 1000 // 疫苗日程最多读取条数
+	fhirActionMatched = "matched"
+	fhirActionCreated = "created"
+	fhirActionDup     = "duplicate"
+)
+
+// FHIRService FHIR R4 导出与接种记录导入服务
+type FHIRService struct {
+	*BaseRecordService
+	growthRecordRepo repository.GrowthRecordRepository
+	scheduleRepo     repository.BabyVaccineScheduleRepository
+	revisionService  *RevisionService // 修订历史
+}
+
+// NewFHIRService 创建 FHIR 服务
+func NewFHIRService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	revisionService *RevisionService,
+	logger *zap.Logger,
+) *FHIRService {
+	return &FHIRService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		growthRecordRepo:  growthRecordRepo,
+		scheduleRepo:      scheduleRepo,
+		revisionService:   revisionService,
+	}
+}
+
+// Export 导出宝宝的 FHIR R4 Bundle(Patient + Immunization + Observation),返回 JSON 和建议的文件名
+func (s *FHIRService) Export(ctx context.Context, openID, babyID string, req *dto.FHIRRequest) ([]byte, string, error) {
+	if err := s.CheckRecordsPermission(ctx, babyID, openID, entity.PermissionActionRead,
+		entity.PermissionResourceGrowth, entity.PermissionResourceVaccine); err != nil {
+		return nil, "", err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, "", errors.New(errors.ParamError, "invalid baby id format")
+	}
+	loc, err := loadLocation(req.Timezone)
+	if err != nil {
+		return nil, "", err
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, "", err
+	}
+
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, babyIDInt64, 1, fhirVaccineLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var growths []*entity.GrowthRecord
+	for page := 1; ; page++ {
+		records, _, err := s.growthRecordRepo.FindByBabyID(ctx, babyIDInt64, 0, 0, page, fhirPageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		growths = append(growths, records...)
+		if len(records) < fhirPageSize {
+			break
+		}
+	}
+	sort.Slice(growths, func(i, j int) bool { return growths[i].Time < growths[j].Time })
+
+	now := time.Now()
+	bundle, err := fhir.NewBundle(baby, schedules, growths, fhir.ExportOptions{Location: loc, Now: now})
+	if err != nil {
+		return nil, "", errors.Wrap(errors.InternalError, "failed to build FHIR bundle", err)
+	}
+	content, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, "", errors.Wrap(errors.InternalError, "failed to encode FHIR bundle", err)
+	}
+
+	filename := fmt.Sprintf("nutri-baby-fhir_%d_%s.json", baby.ID, now.In(loc).Format("20060102"))
+	return content, filename, nil
+}
+
+// ImportImmunizations 从 FHIR Immunization Bundle 预填疫苗接种史
+// 按疫苗类型(或名称)和剂次匹配宝宝的接种日程: 未完成的日程标记为已接种,已有接种记录的视为重复,
+// 找不到对应日程的创建为已接种的自定义日程。全部变更在同一事务中写入,并按补种规则重新计算涉及疫苗的剩余剂次。
+// dryRun 时只返回匹配结果
+func (s *FHIRService) ImportImmunizations(ctx context.Context, openID, babyID string, data []byte, req *dto.FHIRImportRequest) (*dto.FHIRImportResultDTO, error) {
+	if err := s.CheckRecordsPermission(ctx, babyID, openID, entity.PermissionActionCreate,
+		entity.PermissionResourceVaccine); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+	loc, err := loadLocation(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := fhir.ParseImmunizations(data, loc)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, err.Error())
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	birth, err := time.Parse("2006-01-02", baby.BirthDate)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "宝宝出生日期格式错误")
+	}
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, babyIDInt64, 1, fhirVaccineLimit)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(schedules, func(i, j int) bool { return schedules[i].DoseNumber < schedules[j].DoseNumber })
+	befores := revisionSnapshotsByID(schedules)
+
+	result := &dto.FHIRImportResultDTO{
+		DryRun:      req.DryRun,
+		Skipped:     parsed.Skipped,
+		Items:       []dto.FHIRImportItemDTO{},
+		Errors:      []dto.FHIRImportErrorDTO{},
+		Rescheduled: []dto.VaccinePlanChangeDTO{},
+	}
+	for _, e := range parsed.Errors {
+		result.Errors = append(result.Errors, dto.FHIRImportErrorDTO{Entry: e.Entry, Message: e.Message})
+	}
+
+	// 按条目顺序在内存中应用,后续条目的匹配、判重和接种时间校验都基于此前条目导入后的状态
+	var completes, creates []*entity.BabyVaccineSchedule
+	var createdItems []int // creates 中各日程对应的 result.Items 下标
+	affected := make(map[string]bool)
+	used := make(map[int64]bool)
+	for i := range parsed.Records {
+		rec := &parsed.Records[i]
+		item := dto.FHIRImportItemDTO{
+			Entry:       rec.Entry,
+			VaccineType: rec.VaccineType,
+			VaccineName: rec.VaccineName,
+			DoseNumber:  rec.DoseNumber,
+			VaccineDate: rec.Date.UnixMilli(),
+			Warnings:    []string{},
+		}
+
+		schedule, action := matchImmunization(schedules, used, rec)
+		item.Action = action
+		switch action {
+		case fhirActionDup:
+			result.Duplicates++
+		case fhirActionMatched:
+			used[schedule.ID] = true
+			result.Matched++
+			item.Warnings = append(item.Warnings, doseTimingWarnings(birth, schedules, schedule, item.VaccineDate)...)
+			completeImported(schedule, user, rec)
+			completes = append(completes, schedule)
+			affected[schedule.VaccineType] = true
+		case fhirActionCreated:
+			result.Created++
+			schedule = newImportedSchedule(baby, user, rec)
+			item.Warnings = append(item.Warnings, doseTimingWarnings(birth, schedules, schedule, item.VaccineDate)...)
+			creates = append(creates, schedule)
+			createdItems = append(createdItems, len(result.Items))
+			affected[schedule.VaccineType] = true
+			// 同一文件中后续的同种疫苗记录可据此判重
+			schedules = append(schedules, schedule)
+		}
+		if schedule != nil && schedule.ID != 0 {
+			item.ScheduleID = strconv.FormatInt(schedule.ID, 10)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	// 按补种规则重新计算涉及疫苗的剩余剂次
+	var series []*entity.BabyVaccineSchedule
+	for _, schedule := range schedules {
+		if affected[schedule.VaccineType] {
+			series = append(series, schedule)
+		}
+	}
+	rescheduled := catchUpSchedules(birth, series)
+	result.Rescheduled = append(result.Rescheduled, toPlanChangeDTOs(rescheduled)...)
+
+	if req.DryRun {
+		return result, nil
+	}
+
+	if err := s.scheduleRepo.ImportCompleted(ctx, completes, creates, rescheduled); err != nil {
+		return nil, err
+	}
+
+	recordType := entity.AttachmentRecordTypeVaccine
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionUpdate, recordType, revisionRecords(completes), befores); err != nil {
+		return nil, err
+	}
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, recordType, revisionRecords(creates), nil); err != nil {
+		return nil, err
+	}
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionUpdate, recordType, revisionRecords(rescheduled), befores); err != nil {
+		return nil, err
+	}
+
+	// 新建的自定义日程在写入后才有ID
+	for i, schedule := range creates {
+		result.Items[createdItems[i]].ScheduleID = strconv.FormatInt(schedule.ID, 10)
+	}
+
+	s.logger.Info("导入FHIR接种记录",
+		zap.String("babyId", babyID),
+		zap.Int("matched", result.Matched),
+		zap.Int("created", result.Created),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("rescheduled", len(rescheduled)),
+		zap.Int("errors", len(result.Errors)))
+
+	return result, nil
+}
+
+// matchImmunization 为一条接种记录查找对应的日程
+// 剂次已知时只匹配同剂次;剂次未知时同一天已有接种记录视为重复,否则匹配剂次最小的未完成日程
+func matchImmunization(schedules []*entity.BabyVaccineSchedule, used map[int64]bool, rec *fhir.ImmunizationRecord) (*entity.BabyVaccineSchedule, string) {
+	sameVaccine := func(s *entity.BabyVaccineSchedule) bool {
+		if rec.VaccineType != "" && s.VaccineType == rec.VaccineType {
+			return true
+		}
+		return s.VaccineName == rec.VaccineName
+	}
+	sameDay := func(s *entity.BabyVaccineSchedule) bool {
+		if s.VaccineDate == nil {
+			return false
+		}
+		return time.UnixMilli(*s.VaccineDate).In(rec.Date.Location()).Format("2006-01-02") == rec.Date.Format("2006-01-02")
+	}
+
+	var pending *entity.BabyVaccineSchedule
+	for _, s := range schedules {
+		if !sameVaccine(s) || used[s.ID] {
+			continue
+		}
+		if rec.DoseNumber > 0 && s.DoseNumber != rec.DoseNumber {
+			continue
+		}
+		if s.IsCompleted() {
+			if rec.DoseNumber > 0 || sameDay(s) {
+				return s, fhirActionDup
+			}
+			continue
+		}
+		if pending == nil {
+			pending = s
+		}
+	}
+	if pending != nil {
+		return pending, fhirActionMatched
+	}
+	return nil, fhirActionCreated
+}
+
+// newImportedSchedule 为找不到对应日程的接种记录创建已完成的自定义日程
+func newImportedSchedule(baby *entity.Baby, user *entity.User, rec *fhir.ImmunizationRecord) *entity.BabyVaccineSchedule {
+	vaccineType := rec.VaccineType
+	if vaccineType == "" {
+		vaccineType = "Other"
+	}
+	doseNumber := rec.DoseNumber
+	if doseNumber <= 0 {
+		doseNumber = 1
+	}
+
+	schedule := &entity.BabyVaccineSchedule{
+		BabyID:        baby.ID,
+		VaccineType:   vaccineType,
+		VaccineName:   rec.VaccineName,
+		AgeInMonths:   babyAgeInMonths(baby.BirthDate, rec.Date),
+		DoseNumber:    doseNumber,
+		IsRequired:    false,
+		IsCustom:      true,
+		ScheduledDate: rec.Date.UnixMilli(),
+		ReminderSent:  true, // 历史接种无需提醒
+		CreatedBy:     user.ID,
+	}
+	completeImported(schedule, user, rec)
+	return schedule
+}
+
+// completeImported 按接种记录将日程标记为已接种
+func completeImported(schedule *entity.BabyVaccineSchedule, user *entity.User, rec *fhir.ImmunizationRecord) {
+	vaccineDate := rec.Date.UnixMilli()
+	completedTime := time.Now().UnixMilli()
+
+	schedule.VaccinationStatus = entity.VaccinationStatusCompleted
+	schedule.VaccineDate = &vaccineDate
+	schedule.Hospital = optionalString(rec.Hospital)
+	schedule.BatchNumber = optionalString(rec.LotNumber)
+	schedule.Reaction = optionalString(rec.Reaction)
+	schedule.Note = optionalString(rec.Note)
+	schedule.CompletedBy = &user.ID
+	schedule.CompletedByName = &user.NickName
+	schedule.CompletedByAvatar = &user.AvatarURL
+	schedule.CompletedTime = &completedTime
+}
+
+// loadLocation 解析时区参数,为空时使用服务器时区
+func loadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "无效的时区: "+timezone)
+	}
+	return loc, nil
+}
+
+// optionalString 去除首尾空白,空字符串返回 nil
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -16,6 +16,9 @@ import (
 type GrowthRecordService struct {
 	*BaseRecordService
 	growthRecordRepo repository.GrowthRecordRepository
+	attachmentRepo   repository.AttachmentRepository
+	commentRepo      repository.RecordCommentRepository
+	revisionService  *RevisionService
 }
 
 // NewGrowthRecordService 创建成长记录服务
@@ -24,17 +27,23 @@ func NewGrowthRecordService(
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	userRepo repository.UserRepository,
 	growthRecordRepo repository.GrowthRecordRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
+	revisionService *RevisionService,
 	logger *zap.Logger,
 ) *GrowthRecordService {
 	return &GrowthRecordService{
 		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
 		growthRecordRepo:  growthRecordRepo,
+		attachmentRepo:    attachmentRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
 	}
 }
 
 // CreateGrowthRecord 创建生长记录
 func (s *GrowthRecordService) CreateGrowthRecord(ctx context.Context, openID string, req *dto.CreateGrowthRecordRequest) (*dto.GrowthRecordDTO, error) {
-	if err := s.CheckBabyAccess(ctx, req.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, req.BabyID, openID, entity.PermissionResourceGrowth, entity.PermissionActionCreate); err != nil {
 		return nil, err
 	}
 
@@ -94,6 +103,10 @@ func (s *GrowthRecordService) CreateGrowthRecord(ctx context.Context, openID str
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeGrowth, record.ID, nil); err != nil {
+		return nil, err
+	}
+
 	resultNote := ""
 	if record.Note != nil {
 		resultNote = *record.Note
@@ -114,7 +127,7 @@ func (s *GrowthRecordService) CreateGrowthRecord(ctx context.Context, openID str
 
 // GetGrowthRecords 获取生长记录列表
 func (s *GrowthRecordService) GetGrowthRecords(ctx context.Context, openID string, query *dto.RecordListQuery) ([]dto.GrowthRecordDTO, int64, error) {
-	if err := s.CheckBabyAccess(ctx, query.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, query.BabyID, openID, entity.PermissionResourceGrowth, entity.PermissionActionRead); err != nil {
 		return nil, 0, err
 	}
 
@@ -177,7 +190,7 @@ func (s *GrowthRecordService) GetGrowthRecordById(ctx context.Context, openID, r
 	}
 
 	// 验证用户是否有权限访问该宝宝的记录
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceGrowth, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
@@ -216,8 +229,10 @@ func (s *GrowthRecordService) UpdateGrowthRecord(ctx context.Context, openID, re
 		return nil, err
 	}
 
+	before := revisionSnapshotOf(record)
+
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordUpdatePermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceGrowth, record.CreatedBy); err != nil {
 		return nil, err
 	}
 
@@ -264,6 +279,10 @@ func (s *GrowthRecordService) UpdateGrowthRecord(ctx context.Context, openID, re
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeGrowth, record.ID, before); err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("生长记录更新成功",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
@@ -290,7 +309,7 @@ func (s *GrowthRecordService) DeleteGrowthRecord(ctx context.Context, openID, re
 	}
 
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceGrowth, entity.PermissionActionDelete); err != nil {
 		return err
 	}
 
@@ -302,6 +321,24 @@ func (s *GrowthRecordService) DeleteGrowthRecord(ctx context.Context, openID, re
 		return err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeGrowth, record.ID, revisionSnapshotOf(record)); err != nil {
+		return err
+	}
+
+	// 同步软删除记录的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeGrowth, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录附件失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
+	// 同步软删除记录的评论和表情回应
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeGrowth, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录评论失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
 	s.logger.Info("生长记录删除成功",
 		zap.String("recordID", recordID),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
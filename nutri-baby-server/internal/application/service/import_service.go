@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/importer"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	importMaxFileSize     = 20 << 20    // 导入文件最大 20MB
+	importDuplicateWindow = time.Minute // 同类型记录时间相差不超过该值视为重复
+	importPageSize        = 500         // 读取已有记录的每页条数
+	importPreviewLimit    = 200         // 预览最多返回条数
+)
+
+// ImportService 从其他育儿应用导入记录
+// 解析 → 去重 → 预览/导入;正式导入在单个事务中完成,任一记录写入失败则整体回滚
+type ImportService struct {
+	*BaseRecordService
+	registry          *importer.Registry
+	importRepo        repository.RecordImportRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	revisionService   *RevisionService // 修订历史
+}
+
+// NewImportService 创建数据导入服务
+func NewImportService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	importRepo repository.RecordImportRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	revisionService *RevisionService,
+	logger *zap.Logger,
+) *ImportService {
+	return &ImportService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		registry:          importer.NewDefaultRegistry(),
+		importRepo:        importRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		revisionService:   revisionService,
+	}
+}
+
+// ListParsers 获取支持的导入格式
+func (s *ImportService) ListParsers() []dto.ImportParserDTO {
+	parsers := s.registry.Parsers()
+	result := make([]dto.ImportParserDTO, 0, len(parsers))
+	for _, p := range parsers {
+		result = append(result, dto.ImportParserDTO{Name: p.Name(), Description: p.Description()})
+	}
+	return result
+}
+
+// Import 解析上传的文件并导入到宝宝名下;DryRun 时只返回预览
+func (s *ImportService) Import(
+	ctx context.Context,
+	openID, babyID string,
+	fileHeader *multipart.FileHeader,
+	req *dto.ImportRequest,
+) (*dto.ImportResultDTO, error) {
+	if err := s.CheckBabyAccess(ctx, babyID, openID); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	data, err := readImportFile(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := s.selectParser(req.Parser, fileHeader.Filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := importer.Options{}
+	if req.Timezone != "" {
+		loc, err := time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, errors.New(errors.ParamError, "无效的时区: "+req.Timezone)
+		}
+		opts.Location = loc
+	}
+
+	parsed, err := parser.Parse(fileHeader.Filename, data, opts)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "文件解析失败: "+err.Error())
+	}
+	if len(parsed.Records) == 0 && len(parsed.Errors) == 0 {
+		return nil, errors.New(errors.ParamError, "文件中没有可导入的记录")
+	}
+	if err := s.CheckRecordsPermission(ctx, babyID, openID, entity.PermissionActionCreate, importRecordTypes(parsed.Records)...); err != nil {
+		return nil, err
+	}
+
+	duplicates, err := s.findDuplicates(ctx, babyIDInt64, parsed.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ImportResultDTO{
+		Parser:  parser.Name(),
+		DryRun:  req.DryRun,
+		Skipped: parsed.Skipped,
+		Errors:  make([]dto.ImportRowErrorDTO, 0, len(parsed.Errors)),
+		Items:   make([]dto.ImportPreviewItemDTO, 0, min(len(parsed.Records), importPreviewLimit)),
+	}
+	for _, e := range parsed.Errors {
+		result.Errors = append(result.Errors, dto.ImportRowErrorDTO{Source: e.Source, Row: e.Row, Message: e.Message})
+	}
+
+	batch := &repository.RecordBatch{}
+	for i, rec := range parsed.Records {
+		if len(result.Items) < importPreviewLimit {
+			result.Items = append(result.Items, dto.ImportPreviewItemDTO{
+				Source:     rec.Source,
+				Row:        rec.Row,
+				RecordType: rec.Type(),
+				Time:       rec.Time(),
+				Summary:    importSummary(rec),
+				Duplicate:  duplicates[i],
+			})
+		}
+		if duplicates[i] {
+			result.Duplicates++
+			continue
+		}
+		addImportCount(&result.Importable, rec.Type())
+		switch {
+		case rec.Feeding != nil:
+			batch.Feedings = append(batch.Feedings, rec.Feeding)
+		case rec.Sleep != nil:
+			batch.Sleeps = append(batch.Sleeps, rec.Sleep)
+		case rec.Diaper != nil:
+			batch.Diapers = append(batch.Diapers, rec.Diaper)
+		case rec.Growth != nil:
+			batch.Growths = append(batch.Growths, rec.Growth)
+		}
+	}
+
+	if req.DryRun || batch.Len() == 0 || (len(result.Errors) > 0 && !req.SkipErrors) {
+		return result, nil
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+	stampImportBatch(batch, babyIDInt64, user)
+
+	if err := s.importRepo.ImportRecords(ctx, batch); err != nil {
+		s.logger.Error("导入记录失败",
+			zap.String("babyID", babyID),
+			zap.String("parser", parser.Name()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeFeeding, revisionRecords(batch.Feedings), nil); err != nil {
+		return nil, err
+	}
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeSleep, revisionRecords(batch.Sleeps), nil); err != nil {
+		return nil, err
+	}
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeDiaper, revisionRecords(batch.Diapers), nil); err != nil {
+		return nil, err
+	}
+	if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeGrowth, revisionRecords(batch.Growths), nil); err != nil {
+		return nil, err
+	}
+
+	result.Committed = true
+	result.Imported = result.Importable
+
+	s.logger.Info("导入记录成功",
+		zap.String("babyID", babyID),
+		zap.String("openID", openID),
+		zap.String("parser", parser.Name()),
+		zap.Int("imported", result.Imported.Total),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("errors", len(result.Errors)))
+
+	return result, nil
+}
+
+// selectParser 按名称选择解析器,未指定时自动识别
+func (s *ImportService) selectParser(name, filename string, data []byte) (importer.Parser, error) {
+	if name != "" {
+		parser, ok := s.registry.Get(name)
+		if !ok {
+			return nil, errors.New(errors.ParamError, "不支持的导入格式: "+name)
+		}
+		return parser, nil
+	}
+	parser, ok := s.registry.Detect(filename, data)
+	if !ok {
+		return nil, errors.New(errors.ParamError, "无法识别的文件格式,请指定导入格式")
+	}
+	return parser, nil
+}
+
+// findDuplicates 标记与已有记录或文件中前面的记录重复的条目
+// 同类型(喂养按喂养方式、尿布按类型细分)且时间相差不超过 importDuplicateWindow 视为重复
+func (s *ImportService) findDuplicates(ctx context.Context, babyID int64, records []*importer.Record) ([]bool, error) {
+	duplicates := make([]bool, len(records))
+	if len(records) == 0 {
+		return duplicates, nil
+	}
+
+	// 各记录类型的时间范围,只读取范围内的已有记录
+	ranges := make(map[string][2]int64)
+	for _, rec := range records {
+		t := rec.Time()
+		r, ok := ranges[rec.Type()]
+		if !ok {
+			r = [2]int64{t, t}
+		}
+		r[0], r[1] = min(r[0], t), max(r[1], t)
+		ranges[rec.Type()] = r
+	}
+
+	index := make(importTimeIndex)
+	window := importDuplicateWindow.Milliseconds()
+	for recordType, r := range ranges {
+		if err := s.loadExisting(ctx, babyID, recordType, r[0]-window, r[1]+window, index); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, rec := range records {
+		key := importDuplicateKey(rec)
+		if index.contains(key, rec.Time(), window) {
+			duplicates[i] = true
+			continue
+		}
+		index.add(key, rec.Time())
+	}
+	return duplicates, nil
+}
+
+// loadExisting 读取时间范围内的已有记录加入索引
+func (s *ImportService) loadExisting(ctx context.Context, babyID int64, recordType string, startTime, endTime int64, index importTimeIndex) error {
+	for page := 1; ; page++ {
+		var n int
+		switch recordType {
+		case importer.RecordTypeFeeding:
+			records, _, err := s.feedingRecordRepo.FindByBabyID(ctx, babyID, startTime, endTime, page, importPageSize)
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				index.add(importer.RecordTypeFeeding+":"+r.FeedingType, r.Time)
+			}
+			n = len(records)
+		case importer.RecordTypeSleep:
+			records, _, err := s.sleepRecordRepo.FindByBabyID(ctx, babyID, startTime, endTime, page, importPageSize)
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				index.add(importer.RecordTypeSleep, r.StartTime)
+			}
+			n = len(records)
+		case importer.RecordTypeDiaper:
+			records, _, err := s.diaperRecordRepo.FindByBabyID(ctx, babyID, startTime, endTime, page, importPageSize)
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				index.add(importer.RecordTypeDiaper+":"+r.Type, r.Time)
+			}
+			n = len(records)
+		case importer.RecordTypeGrowth:
+			records, _, err := s.growthRecordRepo.FindByBabyID(ctx, babyID, startTime, endTime, page, importPageSize)
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				index.add(importer.RecordTypeGrowth, r.Time)
+			}
+			n = len(records)
+		}
+		if n < importPageSize {
+			return nil
+		}
+	}
+}
+
+// importTimeIndex 按去重键分组的有序时间列表
+type importTimeIndex map[string][]int64
+
+func (idx importTimeIndex) add(key string, t int64) {
+	times := idx[key]
+	i := sort.Search(len(times), func(i int) bool { return times[i] >= t })
+	times = append(times, 0)
+	copy(times[i+1:], times[i:])
+	times[i] = t
+	idx[key] = times
+}
+
+func (idx importTimeIndex) contains(key string, t, window int64) bool {
+	times := idx[key]
+	i := sort.Search(len(times), func(i int) bool { return times[i] >= t-window })
+	return i < len(times) && times[i] <= t+window
+}
+
+func importDuplicateKey(rec *importer.Record) string {
+	switch {
+	case rec.Feeding != nil:
+		return importer.RecordTypeFeeding + ":" + rec.Feeding.FeedingType
+	case rec.Diaper != nil:
+		return importer.RecordTypeDiaper + ":" + rec.Diaper.Type
+	}
+	return rec.Type()
+}
+
+// stampImportBatch 设置宝宝归属和创建者
+// 导入的喂养记录均为历史数据,标记为已提醒,避免触发喂养提醒
+func stampImportBatch(batch *repository.RecordBatch, babyID int64, user *entity.User) {
+	for _, r := range batch.Feedings {
+		r.BabyID, r.CreatedBy, r.CreatedByName, r.CreatedByAvatar = babyID, user.ID, user.NickName, user.AvatarURL
+		r.ReminderSent = true
+	}
+	for _, r := range batch.Sleeps {
+		r.BabyID, r.CreatedBy, r.CreatedByName, r.CreatedByAvatar = babyID, user.ID, user.NickName, user.AvatarURL
+	}
+	for _, r := range batch.Diapers {
+		r.BabyID, r.CreatedBy, r.CreatedByName, r.CreatedByAvatar = babyID, user.ID, user.NickName, user.AvatarURL
+	}
+	for _, r := range batch.Growths {
+		r.BabyID, r.CreatedBy, r.CreatedByName, r.CreatedByAvatar = babyID, user.ID, user.NickName, user.AvatarURL
+	}
+}
+
+func addImportCount(counts *dto.ImportCountsDTO, recordType string) {
+	switch recordType {
+	case importer.RecordTypeFeeding:
+		counts.Feeding++
+	case importer.RecordTypeSleep:
+		counts.Sleep++
+	case importer.RecordTypeDiaper:
+		counts.Diaper++
+	case importer.RecordTypeGrowth:
+		counts.Growth++
+	}
+	counts.Total++
+}
+
+// importRecordTypes 文件中出现的记录类型(导入记录类型与权限矩阵的记录类型取值一致)
+func importRecordTypes(records []*importer.Record) []string {
+	seen := make(map[string]bool)
+	types := make([]string, 0, 4)
+	for _, rec := range records {
+		if t := rec.Type(); t != "" && !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// importSummary 预览条目的简要描述
+func importSummary(rec *importer.Record) string {
+	switch {
+	case rec.Feeding != nil:
+		f := rec.Feeding
+		switch f.FeedingType {
+		case entity.FeedingTypeBreast:
+			return fmt.Sprintf("母乳 %d分钟", f.Duration/60)
+		case entity.FeedingTypeBottle:
+			return fmt.Sprintf("奶瓶 %dml", f.Amount)
+		default:
+			if name, ok := f.Detail["foodName"].(string); ok && name != "" {
+				return "辅食 " + name
+			}
+			return "辅食"
+		}
+	case rec.Sleep != nil:
+		label := "小睡"
+		if rec.Sleep.Type == entity.SleepTypeNight {
+			label = "夜间睡眠"
+		}
+		if rec.Sleep.Duration != nil {
+			return fmt.Sprintf("%s %d分钟", label, *rec.Sleep.Duration/60)
+		}
+		return label
+	case rec.Diaper != nil:
+		switch rec.Diaper.Type {
+		case entity.DiaperTypePee:
+			return "小便"
+		case entity.DiaperTypePoop:
+			return "大便"
+		default:
+			return "大小便"
+		}
+	case rec.Growth != nil:
+		var parts []string
+		if rec.Growth.Weight != nil {
+			parts = append(parts, fmt.Sprintf("体重 %gkg", *rec.Growth.Weight))
+		}
+		if rec.Growth.Height != nil {
+			parts = append(parts, fmt.Sprintf("身高 %gcm", *rec.Growth.Height))
+		}
+		if rec.Growth.HeadCircumference != nil {
+			parts = append(parts, fmt.Sprintf("头围 %gcm", *rec.Growth.HeadCircumference))
+		}
+		return strings.Join(parts, " ")
+	}
+	return ""
+}
+
+// readImportFile 读取上传的导入文件
+func readImportFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	if fileHeader.Size > importMaxFileSize {
+		return nil, errors.New(errors.ParamError, fmt.Sprintf("文件大小不能超过 %dMB", importMaxFileSize>>20))
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.Wrap(errors.InternalError, "failed to open import file", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, importMaxFileSize+1))
+	if err != nil {
+		return nil, errors.Wrap(errors.InternalError, "failed to read import file", err)
+	}
+	if len(data) > importMaxFileSize {
+		return nil, errors.New(errors.ParamError, fmt.Sprintf("文件大小不能超过 %dMB", importMaxFileSize>>20))
+	}
+	return data, nil
+}
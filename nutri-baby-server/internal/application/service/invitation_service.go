@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// InvitationService 邀请管理服务: 邀请列表与撤销、使用次数、加入审批
+type InvitationService struct {
+	invitationRepo   repository.BabyInvitationRepository
+	joinRequestRepo  repository.BabyJoinRequestRepository
+	collaboratorRepo repository.BabyCollaboratorRepository
+	userRepo         repository.UserRepository
+	logger           *zap.Logger
+}
+
+// NewInvitationService 创建邀请管理服务
+func NewInvitationService(
+	invitationRepo repository.BabyInvitationRepository,
+	joinRequestRepo repository.BabyJoinRequestRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	logger *zap.Logger,
+) *InvitationService {
+	return &InvitationService{
+		invitationRepo:   invitationRepo,
+		joinRequestRepo:  joinRequestRepo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// ListInvitations 获取宝宝当前可用的邀请(仅管理员)
+func (s *InvitationService) ListInvitations(ctx context.Context, openID, babyID string) ([]dto.InvitationDTO, error) {
+	babyIDInt64, _, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.FindByBabyID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	inviters := make(map[int64]*entity.User)
+	result := make([]dto.InvitationDTO, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.Status(now) != entity.InvitationStatusActive {
+			continue
+		}
+		inviter, ok := inviters[invitation.UserID]
+		if !ok {
+			inviter, err = s.userRepo.FindByID(ctx, invitation.UserID)
+			if err != nil {
+				s.logger.Warn("获取邀请人信息失败", zap.Int64("userId", invitation.UserID), zap.Error(err))
+				inviter = nil
+			}
+			inviters[invitation.UserID] = inviter
+		}
+		result = append(result, toInvitationDTO(invitation, inviter, now))
+	}
+
+	return result, nil
+}
+
+// RevokeInvitation 撤销邀请,撤销后链接和小程序码立即失效,待审批的申请一并取消
+// 管理员可撤销任何邀请,邀请人可撤销自己发出的邀请
+func (s *InvitationService) RevokeInvitation(ctx context.Context, openID, babyID, invitationID string) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+	invitationIDInt64, err := strconv.ParseInt(invitationID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid invitation id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationIDInt64)
+	if err != nil {
+		return err
+	}
+	if invitation.BabyID != babyIDInt64 {
+		return errors.New(errors.NotFound, "邀请不存在")
+	}
+
+	if invitation.UserID != user.ID {
+		isAdmin, err := s.collaboratorRepo.IsAdmin(ctx, babyIDInt64, user.ID)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errors.New(errors.PermissionDenied, "只有管理员或邀请人可以撤销邀请")
+		}
+	}
+
+	if invitation.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.invitationRepo.Revoke(ctx, invitation.ID, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+	if err := s.joinRequestRepo.CancelPendingByInvitation(ctx, invitation.ID); err != nil {
+		return err
+	}
+
+	s.logger.Info("撤销邀请",
+		zap.Int64("invitationId", invitation.ID),
+		zap.Int64("babyId", babyIDInt64),
+		zap.String("operator", openID))
+	return nil
+}
+
+// Redeem 使用邀请: 占用一次使用次数,需要审批的邀请生成待审批申请并返回
+// 返回 nil 表示调用方可以直接创建协作者
+func (s *InvitationService) Redeem(ctx context.Context, invitation *entity.BabyInvitation, user *entity.User) (*dto.JoinRequestDTO, error) {
+	if err := checkInvitationUsable(invitation, time.Now().UnixMilli()); err != nil {
+		return nil, err
+	}
+
+	if invitation.RequireApproval {
+		// 重复扫码不重复占用次数
+		pending, err := s.joinRequestRepo.FindPendingByBabyAndUser(ctx, invitation.BabyID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if pending != nil {
+			return toJoinRequestDTO(pending, user), nil
+		}
+	}
+
+	ok, err := s.invitationRepo.IncrementUsedCount(ctx, invitation.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.NotFound, "邀请使用次数已达上限")
+	}
+
+	if !invitation.RequireApproval {
+		return nil, nil
+	}
+
+	request := &entity.BabyJoinRequest{
+		BabyID:       invitation.BabyID,
+		InvitationID: invitation.ID,
+		UserID:       user.ID,
+		Role:         invitation.Role,
+		Relationship: invitation.Relationship,
+		AccessType:   invitation.AccessType,
+		ExpiresAt:    invitation.ExpiresAt,
+		Status:       entity.JoinRequestStatusPending,
+	}
+	if err := s.joinRequestRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("提交加入申请",
+		zap.Int64("requestId", request.ID),
+		zap.Int64("babyId", invitation.BabyID),
+		zap.Int64("userId", user.ID))
+	return toJoinRequestDTO(request, user), nil
+}
+
+// ListJoinRequests 获取宝宝待审批的加入申请(仅管理员)
+func (s *InvitationService) ListJoinRequests(ctx context.Context, openID, babyID string) ([]dto.JoinRequestDTO, error) {
+	babyIDInt64, _, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.joinRequestRepo.FindPendingByBabyID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.JoinRequestDTO, 0, len(requests))
+	for _, request := range requests {
+		applicant, err := s.userRepo.FindByID(ctx, request.UserID)
+		if err != nil {
+			s.logger.Warn("获取申请人信息失败", zap.Int64("userId", request.UserID), zap.Error(err))
+			continue
+		}
+		result = append(result, *toJoinRequestDTO(request, applicant))
+	}
+
+	return result, nil
+}
+
+// ApproveJoinRequest 通过加入申请并创建协作者(仅管理员)
+func (s *InvitationService) ApproveJoinRequest(ctx context.Context, openID, babyID, requestID string) (*dto.JoinRequestDTO, error) {
+	request, admin, err := s.findPendingRequest(ctx, openID, babyID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	applicant, err := s.userRepo.FindByID(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, request.InvitationID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	if err := checkJoinRequestApprovable(request, invitation, now); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.collaboratorRepo.FindByBabyAndUser(ctx, request.BabyID, request.UserID)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+
+	ok, err := s.joinRequestRepo.Review(ctx, request.ID, entity.JoinRequestStatusApproved, admin.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.Conflict, "该申请已被处理")
+	}
+	request.Status = entity.JoinRequestStatusApproved
+	request.ReviewedAt = &now
+
+	// 申请期间已通过其他途径加入的,不重复创建;已过期的临时成员按本次申请重新加入
+	if existing == nil || existing.IsExpired() {
+		collaborator := &entity.BabyCollaborator{
+			BabyID:       request.BabyID,
+			UserID:       request.UserID,
+			Role:         request.Role,
+			Relationship: request.Relationship,
+			AccessType:   request.AccessType,
+			ExpiresAt:    request.ExpiresAt,
+		}
+		if err := addCollaborator(ctx, s.collaboratorRepo, collaborator, existing); err != nil {
+			return nil, err
+		}
+
+		// 申请人没有其他宝宝时,设为默认宝宝
+		if applicant.DefaultBabyID == 0 {
+			if err := s.userRepo.UpdateDefaultBabyID(ctx, applicant.OpenID, request.BabyID); err != nil {
+				s.logger.Error("设置默认宝宝失败", zap.Error(err))
+			}
+		}
+	}
+
+	s.logger.Info("通过加入申请",
+		zap.Int64("requestId", request.ID),
+		zap.Int64("babyId", request.BabyID),
+		zap.String("operator", openID))
+	return toJoinRequestDTO(request, applicant), nil
+}
+
+// RejectJoinRequest 拒绝加入申请(仅管理员)
+func (s *InvitationService) RejectJoinRequest(ctx context.Context, openID, babyID, requestID string) (*dto.JoinRequestDTO, error) {
+	request, admin, err := s.findPendingRequest(ctx, openID, babyID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	applicant, err := s.userRepo.FindByID(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	ok, err := s.joinRequestRepo.Review(ctx, request.ID, entity.JoinRequestStatusRejected, admin.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.Conflict, "该申请已被处理")
+	}
+	request.Status = entity.JoinRequestStatusRejected
+	request.ReviewedAt = &now
+
+	return toJoinRequestDTO(request, applicant), nil
+}
+
+// findPendingRequest 查找属于该宝宝的待审批申请(仅管理员)
+func (s *InvitationService) findPendingRequest(ctx context.Context, openID, babyID, requestID string) (*entity.BabyJoinRequest, *entity.User, error) {
+	babyIDInt64, admin, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requestIDInt64, err := strconv.ParseInt(requestID, 10, 64)
+	if err != nil {
+		return nil, nil, errors.New(errors.ParamError, "invalid request id format")
+	}
+
+	request, err := s.joinRequestRepo.FindByID(ctx, requestIDInt64)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request.BabyID != babyIDInt64 {
+		return nil, nil, errors.New(errors.NotFound, "加入申请不存在")
+	}
+	if request.Status != entity.JoinRequestStatusPending {
+		return nil, nil, errors.New(errors.Conflict, "该申请已被处理")
+	}
+
+	return request, admin, nil
+}
+
+// checkBabyAdmin 检查用户是否为宝宝管理员
+func (s *InvitationService) checkBabyAdmin(ctx context.Context, babyID, openID string) (int64, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return 0, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	isAdmin, err := s.collaboratorRepo.IsAdmin(ctx, babyIDInt64, user.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !isAdmin {
+		return 0, nil, errors.New(errors.PermissionDenied, "只有管理员可以管理邀请")
+	}
+
+	return babyIDInt64, user, nil
+}
+
+// checkInvitationUsable 检查邀请在 now(毫秒时间戳) 时能否使用
+func checkInvitationUsable(invitation *entity.BabyInvitation, now int64) error {
+	switch invitation.Status(now) {
+	case entity.InvitationStatusRevoked:
+		return errors.New(errors.NotFound, "邀请已被撤销")
+	case entity.InvitationStatusExpired:
+		return errors.New(errors.NotFound, "邀请链接已过期")
+	case entity.InvitationStatusUsedUp:
+		return errors.New(errors.NotFound, "邀请使用次数已达上限")
+	}
+	return nil
+}
+
+// checkJoinRequestApprovable 审批时重新校验: 邀请未被撤销、申请的临时访问权限尚未过期
+// 邀请链接过期或次数用完不影响已提交的申请
+func checkJoinRequestApprovable(request *entity.BabyJoinRequest, invitation *entity.BabyInvitation, now int64) error {
+	if invitation.RevokedAt != nil {
+		return errors.New(errors.Conflict, "邀请已被撤销,无法通过该申请")
+	}
+	if request.AccessType == "temporary" && request.ExpiresAt != nil && *request.ExpiresAt <= now {
+		return errors.New(errors.Conflict, "申请的临时访问权限已过期,请重新邀请")
+	}
+	return nil
+}
+
+// addCollaborator 创建协作者;同一用户留有已过期的临时成员记录时,先移除旧记录再创建
+func addCollaborator(ctx context.Context, collaboratorRepo repository.BabyCollaboratorRepository, collaborator, expired *entity.BabyCollaborator) error {
+	if expired != nil {
+		if err := collaboratorRepo.Delete(ctx, expired.BabyID, expired.UserID); err != nil {
+			return err
+		}
+	}
+	return collaboratorRepo.Create(ctx, collaborator)
+}
+
+// sameInvitationSettings 已有邀请的设置是否与新的邀请请求一致(一致时复用已有邀请码)
+func sameInvitationSettings(invitation *entity.BabyInvitation, req *dto.InviteCollaboratorRequest) bool {
+	return invitation.InviteType == req.InviteType &&
+		invitation.Role == req.Role &&
+		invitation.Relationship == req.Relationship &&
+		invitation.AccessType == req.AccessType &&
+		equalInt64Ptr(invitation.ExpiresAt, req.ExpiresAt) &&
+		equalInt64Ptr(invitation.LinkExpiresAt, req.LinkExpiresAt) &&
+		invitation.MaxUses == req.MaxUses &&
+		invitation.RequireApproval == req.RequireApproval
+}
+
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// toInvitationDTO 转换为邀请列表项
+func toInvitationDTO(invitation *entity.BabyInvitation, inviter *entity.User, now int64) dto.InvitationDTO {
+	result := dto.InvitationDTO{
+		InvitationID:    strconv.FormatInt(invitation.ID, 10),
+		InviteType:      invitation.InviteType,
+		ShortCode:       invitation.ShortCode,
+		Role:            invitation.Role,
+		Relationship:    invitation.Relationship,
+		AccessType:      invitation.AccessType,
+		ExpiresAt:       invitation.ExpiresAt,
+		LinkExpiresAt:   invitation.LinkExpiresAt,
+		MaxUses:         invitation.MaxUses,
+		UsedCount:       invitation.UsedCount,
+		RequireApproval: invitation.RequireApproval,
+		Status:          invitation.Status(now),
+		CreatedAt:       invitation.CreatedAt,
+	}
+	if inviter != nil {
+		result.InviterOpenID = inviter.OpenID
+		result.InviterName = inviter.NickName
+	}
+	return result
+}
+
+// toJoinRequestDTO 转换为加入申请DTO
+func toJoinRequestDTO(request *entity.BabyJoinRequest, applicant *entity.User) *dto.JoinRequestDTO {
+	return &dto.JoinRequestDTO{
+		RequestID:    strconv.FormatInt(request.ID, 10),
+		BabyID:       strconv.FormatInt(request.BabyID, 10),
+		InvitationID: strconv.FormatInt(request.InvitationID, 10),
+		OpenID:       applicant.OpenID,
+		NickName:     applicant.NickName,
+		AvatarURL:    applicant.AvatarURL,
+		Role:         request.Role,
+		Relationship: request.Relationship,
+		AccessType:   request.AccessType,
+		ExpiresAt:    request.ExpiresAt,
+		Status:       request.Status,
+		CreatedAt:    request.CreatedAt,
+		ReviewedAt:   request.ReviewedAt,
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestInvitationStatus(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	past, future := now-1, now+1
+
+	assert.Equal(t, entity.InvitationStatusActive, (&entity.BabyInvitation{}).Status(now))
+	assert.Equal(t, entity.InvitationStatusActive, (&entity.BabyInvitation{LinkExpiresAt: &future, MaxUses: 3, UsedCount: 2}).Status(now))
+
+	// 撤销优先于过期和次数用尽
+	assert.Equal(t, entity.InvitationStatusRevoked, (&entity.BabyInvitation{RevokedAt: &past, LinkExpiresAt: &past}).Status(now))
+	assert.Equal(t, entity.InvitationStatusExpired, (&entity.BabyInvitation{LinkExpiresAt: &past, MaxUses: 1, UsedCount: 1}).Status(now))
+	assert.Equal(t, entity.InvitationStatusUsedUp, (&entity.BabyInvitation{MaxUses: 1, UsedCount: 1}).Status(now))
+
+	// 链接过期与协作权限过期相互独立
+	assert.Equal(t, entity.InvitationStatusActive, (&entity.BabyInvitation{ExpiresAt: &past}).Status(now))
+}
+
+func TestCheckInvitationUsable(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	past := now - 1
+
+	assert.NoError(t, checkInvitationUsable(&entity.BabyInvitation{MaxUses: 2, UsedCount: 1}, now))
+	assert.ErrorContains(t, checkInvitationUsable(&entity.BabyInvitation{RevokedAt: &past}, now), "邀请已被撤销")
+	assert.ErrorContains(t, checkInvitationUsable(&entity.BabyInvitation{LinkExpiresAt: &past}, now), "邀请链接已过期")
+	assert.ErrorContains(t, checkInvitationUsable(&entity.BabyInvitation{MaxUses: 2, UsedCount: 2}, now), "邀请使用次数已达上限")
+}
+
+func TestSameInvitationSettings(t *testing.T) {
+	linkExpiry := int64(1_700_000_000_000)
+	invitation := &entity.BabyInvitation{
+		InviteType:    "share",
+		Role:          entity.CollaboratorRoleCaregiver,
+		Relationship:  "保姆",
+		AccessType:    "permanent",
+		LinkExpiresAt: &linkExpiry,
+		MaxUses:       1,
+	}
+	req := &dto.InviteCollaboratorRequest{
+		InviteType:    "share",
+		Role:          entity.CollaboratorRoleCaregiver,
+		Relationship:  "保姆",
+		AccessType:    "permanent",
+		LinkExpiresAt: &linkExpiry,
+		MaxUses:       1,
+	}
+	assert.True(t, sameInvitationSettings(invitation, req))
+
+	req.RequireApproval = true
+	assert.False(t, sameInvitationSettings(invitation, req))
+
+	req.RequireApproval = false
+	req.LinkExpiresAt = nil
+	assert.False(t, sameInvitationSettings(invitation, req))
+}
+
+func TestCheckJoinRequestApprovable(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	past, future := now-1, now+1
+
+	request := &entity.BabyJoinRequest{AccessType: "temporary", ExpiresAt: &future}
+	assert.NoError(t, checkJoinRequestApprovable(request, &entity.BabyInvitation{}, now))
+
+	// 邀请链接过期或次数用完不影响已提交的申请
+	assert.NoError(t, checkJoinRequestApprovable(request, &entity.BabyInvitation{LinkExpiresAt: &past, MaxUses: 1, UsedCount: 1}, now))
+
+	assert.Error(t, checkJoinRequestApprovable(request, &entity.BabyInvitation{RevokedAt: &past}, now))
+	assert.Error(t, checkJoinRequestApprovable(&entity.BabyJoinRequest{AccessType: "temporary", ExpiresAt: &past}, &entity.BabyInvitation{}, now))
+	assert.NoError(t, checkJoinRequestApprovable(&entity.BabyJoinRequest{AccessType: "permanent"}, &entity.BabyInvitation{}, now))
+}
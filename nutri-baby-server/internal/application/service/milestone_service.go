@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// MilestoneService 发育里程碑服务
+type MilestoneService struct {
+	*BaseRecordService
+	milestoneRepo   repository.BabyMilestoneRepository
+	attachmentRepo  repository.AttachmentRepository
+	commentRepo     repository.RecordCommentRepository
+	revisionService *RevisionService
+}
+
+// NewMilestoneService 创建发育里程碑服务
+func NewMilestoneService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
+	revisionService *RevisionService,
+	logger *zap.Logger,
+) *MilestoneService {
+	return &MilestoneService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		milestoneRepo:     milestoneRepo,
+		attachmentRepo:    attachmentRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
+	}
+}
+
+// InitializeMilestonesForBaby 为新宝宝初始化发育里程碑(从模板)
+// 此方法应该在创建宝宝后立即调用
+func (s *MilestoneService) InitializeMilestonesForBaby(ctx context.Context, babyID, openID string) error {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return err
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return err
+	}
+
+	if baby.UserID != user.ID {
+		return errors.New(errors.PermissionDenied, "无权为该宝宝初始化里程碑")
+	}
+
+	return s.initializeIfNeeded(ctx, babyIDInt64, user.ID)
+}
+
+// initializeIfNeeded 宝宝尚无里程碑时从模板初始化
+func (s *MilestoneService) initializeIfNeeded(ctx context.Context, babyID, createdBy int64) error {
+	count, err := s.milestoneRepo.CountByBabyID(ctx, babyID)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		// 已初始化,跳过
+		return nil
+	}
+
+	return s.milestoneRepo.InitializeFromTemplates(ctx, babyID, createdBy)
+}
+
+// GetMilestones 获取宝宝的里程碑列表
+func (s *MilestoneService) GetMilestones(ctx context.Context, babyID, openID string, query *dto.MilestoneListQuery) ([]dto.MilestoneDTO, error) {
+	if err := s.CheckRecordPermission(ctx, babyID, openID, entity.PermissionResourceMilestone, entity.PermissionActionRead); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// 兼容功能上线前创建的宝宝: 首次查询时从模板初始化
+	if err := s.initializeIfNeeded(ctx, babyIDInt64, baby.UserID); err != nil {
+		s.logger.Warn("初始化里程碑失败", zap.String("babyId", babyID), zap.Error(err))
+	}
+
+	// 超过最晚达成月龄的里程碑状态由月龄推导,先查询全部再按推导后的状态过滤
+	milestones, err := s.milestoneRepo.FindByBabyID(ctx, babyIDInt64, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ageInMonths := babyAgeInMonths(baby.BirthDate, time.Now())
+	result := make([]dto.MilestoneDTO, 0, len(milestones))
+	for _, milestone := range milestones {
+		if query.Category != "" && milestone.Category != query.Category {
+			continue
+		}
+		if query.Status != "" && milestoneStatus(milestone, ageInMonths) != query.Status {
+			continue
+		}
+		result = append(result, s.toMilestoneDTO(milestone, ageInMonths))
+	}
+
+	return result, nil
+}
+
+// CreateMilestone 创建自定义里程碑
+func (s *MilestoneService) CreateMilestone(ctx context.Context, babyID, openID string, req *dto.CreateMilestoneRequest) (*dto.MilestoneDTO, error) {
+	if err := s.CheckRecordPermission(ctx, babyID, openID, entity.PermissionResourceMilestone, entity.PermissionActionCreate); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MaxAgeInMonths > 0 && req.MaxAgeInMonths < req.AgeInMonths {
+		return nil, errors.New(errors.ParamError, "最晚达成月龄不能小于通常达成月龄")
+	}
+
+	milestone := &entity.BabyMilestone{
+		BabyID:         babyIDInt64,
+		Category:       req.Category,
+		Title:          req.Title,
+		Description:    req.Description,
+		AgeInMonths:    req.AgeInMonths,
+		MaxAgeInMonths: req.MaxAgeInMonths,
+		Status:         entity.MilestoneStatusUpcoming,
+		Photos:         entity.MilestonePhotos(req.Photos),
+		Note:           req.Note,
+		CreatedBy:      user.ID,
+	}
+	if req.AchievedDate != nil {
+		milestone.Status = entity.MilestoneStatusAchieved
+		milestone.AchievedDate = req.AchievedDate
+		milestone.UpdatedBy = &user.ID
+	}
+
+	if err := s.milestoneRepo.Create(ctx, milestone); err != nil {
+		s.logger.Error("创建里程碑失败", zap.String("babyId", babyID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeMilestone, milestone.ID, nil); err != nil {
+		return nil, err
+	}
+
+	result := s.toMilestoneDTO(milestone, babyAgeInMonths(baby.BirthDate, time.Now()))
+	return &result, nil
+}
+
+// UpdateMilestoneStatus 标记里程碑状态(即将到来/已达成/需与医生沟通)
+func (s *MilestoneService) UpdateMilestoneStatus(ctx context.Context, babyID, milestoneID, openID string, req *dto.UpdateMilestoneStatusRequest) (*dto.MilestoneDTO, error) {
+	milestone, err := s.findBabyMilestone(ctx, babyID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckRecordUpdatePermission(ctx, babyID, openID, entity.PermissionResourceMilestone, milestone.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := revisionSnapshotOf(milestone)
+	milestone.Status = req.Status
+	milestone.UpdatedBy = &user.ID
+	switch req.Status {
+	case entity.MilestoneStatusAchieved:
+		achievedDate := time.Now().UnixMilli()
+		if req.AchievedDate != nil {
+			achievedDate = *req.AchievedDate
+		}
+		milestone.AchievedDate = &achievedDate
+	default:
+		milestone.AchievedDate = nil
+	}
+	if req.Photos != nil {
+		milestone.Photos = entity.MilestonePhotos(req.Photos)
+	}
+	if req.Note != nil {
+		milestone.Note = req.Note
+	}
+
+	if err := s.milestoneRepo.Update(ctx, milestone); err != nil {
+		s.logger.Error("更新里程碑状态失败", zap.String("milestoneId", milestoneID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeMilestone, milestone.ID, before); err != nil {
+		return nil, err
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, milestone.BabyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.toMilestoneDTO(milestone, babyAgeInMonths(baby.BirthDate, time.Now()))
+	return &result, nil
+}
+
+// DeleteMilestone 删除里程碑
+func (s *MilestoneService) DeleteMilestone(ctx context.Context, babyID, milestoneID, openID string) error {
+	if err := s.CheckRecordPermission(ctx, babyID, openID, entity.PermissionResourceMilestone, entity.PermissionActionDelete); err != nil {
+		return err
+	}
+
+	milestone, err := s.findBabyMilestone(ctx, babyID, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.milestoneRepo.Delete(ctx, milestone.ID); err != nil {
+		return err
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeMilestone, milestone.ID, revisionSnapshotOf(milestone)); err != nil {
+		return err
+	}
+
+	// 同步软删除里程碑的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeMilestone, milestone.ID); err != nil {
+		s.logger.Warn("删除里程碑附件失败", zap.String("milestoneId", milestoneID), zap.Error(err))
+	}
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeMilestone, milestone.ID); err != nil {
+		s.logger.Warn("删除里程碑评论失败", zap.String("milestoneId", milestoneID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// GetAchievedMilestones 获取时间范围内已达成的里程碑(供时间线聚合使用)
+func (s *MilestoneService) GetAchievedMilestones(ctx context.Context, openID string, babyID string, startTime, endTime int64) ([]dto.MilestoneDTO, error) {
+	if err := s.CheckRecordPermission(ctx, babyID, openID, entity.PermissionResourceMilestone, entity.PermissionActionRead); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	milestones, err := s.milestoneRepo.FindAchievedByBabyID(ctx, babyIDInt64, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.MilestoneDTO, 0, len(milestones))
+	for _, milestone := range milestones {
+		result = append(result, s.toMilestoneDTO(milestone, 0))
+	}
+	return result, nil
+}
+
+// findBabyMilestone 查找并校验里程碑属于该宝宝
+func (s *MilestoneService) findBabyMilestone(ctx context.Context, babyID, milestoneID string) (*entity.BabyMilestone, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	milestoneIDInt64, err := strconv.ParseInt(milestoneID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid milestone id format")
+	}
+
+	milestone, err := s.milestoneRepo.FindByID(ctx, milestoneIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	if milestone.BabyID != babyIDInt64 {
+		return nil, errors.New(errors.NotFound, "里程碑不存在")
+	}
+
+	return milestone, nil
+}
+
+// toMilestoneDTO 转换为DTO
+// ageInMonths 为宝宝当前月龄,用于判断是否超过最晚达成月龄
+func (s *MilestoneService) toMilestoneDTO(milestone *entity.BabyMilestone, ageInMonths int) dto.MilestoneDTO {
+	var templateID *string
+	if milestone.TemplateID != nil {
+		id := strconv.FormatInt(*milestone.TemplateID, 10)
+		templateID = &id
+	}
+
+	photos := []string(milestone.Photos)
+	if photos == nil {
+		photos = []string{}
+	}
+
+	return dto.MilestoneDTO{
+		MilestoneID:    strconv.FormatInt(milestone.ID, 10),
+		BabyID:         strconv.FormatInt(milestone.BabyID, 10),
+		TemplateID:     templateID,
+		Category:       milestone.Category,
+		Title:          milestone.Title,
+		Description:    milestone.Description,
+		AgeInMonths:    milestone.AgeInMonths,
+		MaxAgeInMonths: milestone.MaxAgeInMonths,
+		IsCustom:       milestone.TemplateID == nil,
+		Status:         milestoneStatus(milestone, ageInMonths),
+		IsOverdue:      milestoneOverdue(milestone, ageInMonths),
+		AchievedDate:   milestone.AchievedDate,
+		Photos:         photos,
+		Note:           milestone.Note,
+		CreateBy:       strconv.FormatInt(milestone.CreatedBy, 10),
+		CreateTime:     milestone.CreatedAt,
+		UpdateTime:     milestone.UpdatedAt,
+	}
+}
+
+// milestoneOverdue 是否已超过最晚达成月龄仍未达成
+func milestoneOverdue(milestone *entity.BabyMilestone, ageInMonths int) bool {
+	return !milestone.IsAchieved() && milestone.MaxAgeInMonths > 0 && ageInMonths > milestone.MaxAgeInMonths
+}
+
+// milestoneStatus 对外展示的状态: 未达成且已超过最晚达成月龄时为需与医生沟通
+func milestoneStatus(milestone *entity.BabyMilestone, ageInMonths int) string {
+	if milestone.Status == entity.MilestoneStatusUpcoming && milestoneOverdue(milestone, ageInMonths) {
+		return entity.MilestoneStatusDiscussWithDoctor
+	}
+	return milestone.Status
+}
+
+// babyAgeInMonths 根据出生日期计算月龄(满月计),日期无效时返回0
+func babyAgeInMonths(birthDate string, now time.Time) int {
+	birthTime, err := time.Parse("2006-01-02", birthDate)
+	if err != nil {
+		return 0
+	}
+
+	months := (now.Year()-birthTime.Year())*12 + int(now.Month()-birthTime.Month())
+	if now.Day() < birthTime.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestMilestoneStatus(t *testing.T) {
+	upcoming := &entity.BabyMilestone{Status: entity.MilestoneStatusUpcoming, AgeInMonths: 6, MaxAgeInMonths: 9}
+
+	assert.Equal(t, entity.MilestoneStatusUpcoming, milestoneStatus(upcoming, 9))
+	assert.False(t, milestoneOverdue(upcoming, 9))
+
+	// 超过最晚达成月龄仍未达成,需与医生沟通
+	assert.Equal(t, entity.MilestoneStatusDiscussWithDoctor, milestoneStatus(upcoming, 10))
+	assert.True(t, milestoneOverdue(upcoming, 10))
+
+	achieved := &entity.BabyMilestone{Status: entity.MilestoneStatusAchieved, MaxAgeInMonths: 9}
+	assert.Equal(t, entity.MilestoneStatusAchieved, milestoneStatus(achieved, 12))
+	assert.False(t, milestoneOverdue(achieved, 12))
+
+	// 没有最晚达成月龄的自定义里程碑不会逾期
+	custom := &entity.BabyMilestone{Status: entity.MilestoneStatusUpcoming}
+	assert.Equal(t, entity.MilestoneStatusUpcoming, milestoneStatus(custom, 36))
+}
+
+func TestBabyAgeInMonths(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 5, babyAgeInMonths("2024-01-15", now))
+	assert.Equal(t, 4, babyAgeInMonths("2024-01-16", now))
+	assert.Equal(t, 0, babyAgeInMonths("2024-07-01", now))
+	assert.Equal(t, 0, babyAgeInMonths("invalid", now))
+}
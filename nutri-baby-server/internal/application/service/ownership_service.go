@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// OwnershipService 宝宝所有权转让服务
+// 所有者(Baby.UserID)提名一位长期成员,对方接受后成为新所有者并获得管理员角色,原所有者保留管理员角色
+type OwnershipService struct {
+	babyRepo         repository.BabyRepository
+	collaboratorRepo repository.BabyCollaboratorRepository
+	transferRepo     repository.BabyOwnershipTransferRepository
+	userRepo         repository.UserRepository
+	logger           *zap.Logger
+}
+
+// NewOwnershipService 创建所有权转让服务
+func NewOwnershipService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	transferRepo repository.BabyOwnershipTransferRepository,
+	userRepo repository.UserRepository,
+	logger *zap.Logger,
+) *OwnershipService {
+	return &OwnershipService{
+		babyRepo:         babyRepo,
+		collaboratorRepo: collaboratorRepo,
+		transferRepo:     transferRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// NominateOwner 所有者提名新所有者,已有的待处理提名会被取消
+func (s *OwnershipService) NominateOwner(ctx context.Context, openID, babyID string, req *dto.NominateOwnerRequest) (*dto.OwnershipTransferDTO, error) {
+	baby, owner, err := s.findOwnedBaby(ctx, openID, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.userRepo.FindByOpenID(ctx, req.TargetOpenID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == owner.ID {
+		return nil, errors.New(errors.ParamError, "不能将所有权转让给自己")
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, baby.ID, target.ID)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if err := checkOwnerCandidate(collaborator); err != nil {
+		return nil, err
+	}
+
+	if err := s.transferRepo.CancelPendingByBaby(ctx, baby.ID); err != nil {
+		return nil, err
+	}
+
+	transfer := &entity.BabyOwnershipTransfer{
+		BabyID:     baby.ID,
+		FromUserID: owner.ID,
+		ToUserID:   target.ID,
+		Status:     entity.OwnershipTransferStatusPending,
+	}
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("提名宝宝新所有者",
+		zap.Int64("babyId", baby.ID),
+		zap.Int64("fromUserId", owner.ID),
+		zap.Int64("toUserId", target.ID))
+	return toOwnershipTransferDTO(transfer, baby, owner, target), nil
+}
+
+// GetPendingTransfer 获取宝宝待处理的转让(成员可见),没有时返回 nil
+func (s *OwnershipService) GetPendingTransfer(ctx context.Context, openID, babyID string) (*dto.OwnershipTransferDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	isCollaborator, err := s.collaboratorRepo.IsCollaborator(ctx, babyIDInt64, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isCollaborator {
+		return nil, errors.New(errors.PermissionDenied, "您没有权限访问该宝宝")
+	}
+
+	transfer, err := s.transferRepo.FindPendingByBabyID(ctx, babyIDInt64)
+	if err != nil || transfer == nil {
+		return nil, err
+	}
+
+	return s.buildTransferDTO(ctx, transfer)
+}
+
+// ListIncomingTransfers 获取提名给当前用户、等待接受的转让
+func (s *OwnershipService) ListIncomingTransfers(ctx context.Context, openID string) ([]dto.OwnershipTransferDTO, error) {
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := s.transferRepo.FindPendingByToUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.OwnershipTransferDTO, 0, len(transfers))
+	for _, transfer := range transfers {
+		item, err := s.buildTransferDTO(ctx, transfer)
+		if err != nil {
+			s.logger.Warn("构建所有权转让信息失败", zap.Int64("transferId", transfer.ID), zap.Error(err))
+			continue
+		}
+		result = append(result, *item)
+	}
+
+	return result, nil
+}
+
+// CancelTransfer 所有者取消待处理的转让
+func (s *OwnershipService) CancelTransfer(ctx context.Context, openID, babyID string) error {
+	baby, _, err := s.findOwnedBaby(ctx, openID, babyID)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := s.transferRepo.FindPendingByBabyID(ctx, baby.ID)
+	if err != nil {
+		return err
+	}
+	if transfer == nil {
+		return errors.New(errors.NotFound, "没有待处理的所有权转让")
+	}
+
+	if _, err := s.transferRepo.Respond(ctx, transfer.ID, entity.OwnershipTransferStatusCancelled, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AcceptTransfer 被提名人接受转让,成为宝宝所有者
+func (s *OwnershipService) AcceptTransfer(ctx context.Context, openID, babyID string) (*dto.OwnershipTransferDTO, error) {
+	transfer, baby, user, err := s.findIncomingTransfer(ctx, openID, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+
+	// 提名后所有者已变更(例如另一次转让已完成),提名失效
+	if baby.UserID != transfer.FromUserID {
+		if _, err := s.transferRepo.Respond(ctx, transfer.ID, entity.OwnershipTransferStatusCancelled, now); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(errors.Conflict, "所有权转让已失效")
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, baby.ID, user.ID)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if err := checkOwnerCandidate(collaborator); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.transferRepo.Accept(ctx, transfer, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.Conflict, "所有权转让已失效")
+	}
+
+	s.logger.Info("宝宝所有权已转让",
+		zap.Int64("babyId", baby.ID),
+		zap.Int64("fromUserId", transfer.FromUserID),
+		zap.Int64("toUserId", user.ID))
+
+	transfer.Status = entity.OwnershipTransferStatusAccepted
+	transfer.RespondedAt = &now
+	return s.buildTransferDTO(ctx, transfer)
+}
+
+// DeclineTransfer 被提名人拒绝转让
+func (s *OwnershipService) DeclineTransfer(ctx context.Context, openID, babyID string) (*dto.OwnershipTransferDTO, error) {
+	transfer, _, _, err := s.findIncomingTransfer(ctx, openID, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	ok, err := s.transferRepo.Respond(ctx, transfer.ID, entity.OwnershipTransferStatusDeclined, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.Conflict, "该转让已被处理")
+	}
+
+	transfer.Status = entity.OwnershipTransferStatusDeclined
+	transfer.RespondedAt = &now
+	return s.buildTransferDTO(ctx, transfer)
+}
+
+// CancelPendingFor 成员离开或被移除时,取消涉及该成员的待处理转让
+func (s *OwnershipService) CancelPendingFor(ctx context.Context, babyID, userID int64) error {
+	return s.transferRepo.CancelPendingByBabyAndUser(ctx, babyID, userID)
+}
+
+// findOwnedBaby 查找宝宝并校验当前用户为所有者
+func (s *OwnershipService) findOwnedBaby(ctx context.Context, openID, babyID string) (*entity.Baby, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, nil, err
+	}
+	if baby.UserID != user.ID {
+		return nil, nil, errors.New(errors.PermissionDenied, "只有宝宝所有者可以转让所有权")
+	}
+
+	return baby, user, nil
+}
+
+// findIncomingTransfer 查找宝宝提名给当前用户的待处理转让
+func (s *OwnershipService) findIncomingTransfer(ctx context.Context, openID, babyID string) (*entity.BabyOwnershipTransfer, *entity.Baby, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, nil, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	transfer, err := s.transferRepo.FindPendingByBabyID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if transfer == nil || transfer.ToUserID != user.ID {
+		return nil, nil, nil, errors.New(errors.NotFound, "没有待您处理的所有权转让")
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return transfer, baby, user, nil
+}
+
+// buildTransferDTO 补充宝宝和双方用户信息
+func (s *OwnershipService) buildTransferDTO(ctx context.Context, transfer *entity.BabyOwnershipTransfer) (*dto.OwnershipTransferDTO, error) {
+	baby, err := s.babyRepo.FindByID(ctx, transfer.BabyID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.userRepo.FindByID(ctx, transfer.FromUserID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.userRepo.FindByID(ctx, transfer.ToUserID)
+	if err != nil {
+		return nil, err
+	}
+	return toOwnershipTransferDTO(transfer, baby, from, to), nil
+}
+
+// checkOwnerCandidate 只有有效的长期成员才能成为所有者
+func checkOwnerCandidate(collaborator *entity.BabyCollaborator) error {
+	if collaborator == nil || collaborator.IsExpired() {
+		return errors.New(errors.ParamError, "只能转让给宝宝的亲友团成员")
+	}
+	if collaborator.AccessType == "temporary" {
+		return errors.New(errors.ParamError, "临时成员不能成为宝宝所有者")
+	}
+	return nil
+}
+
+// toOwnershipTransferDTO 转换为所有权转让DTO
+func toOwnershipTransferDTO(transfer *entity.BabyOwnershipTransfer, baby *entity.Baby, from, to *entity.User) *dto.OwnershipTransferDTO {
+	return &dto.OwnershipTransferDTO{
+		TransferID:  strconv.FormatInt(transfer.ID, 10),
+		BabyID:      strconv.FormatInt(baby.ID, 10),
+		BabyName:    baby.Name,
+		FromOpenID:  from.OpenID,
+		FromName:    from.NickName,
+		ToOpenID:    to.OpenID,
+		ToName:      to.NickName,
+		Status:      transfer.Status,
+		CreatedAt:   transfer.CreatedAt,
+		RespondedAt: transfer.RespondedAt,
+	}
+}
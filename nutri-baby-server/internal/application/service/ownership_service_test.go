@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestHasOtherActiveAdmin(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).UnixMilli()
+	collaborators := []*entity.BabyCollaborator{
+		{UserID: 1, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"},
+		{UserID: 2, Role: entity.CollaboratorRoleEditor, AccessType: "permanent"},
+		{UserID: 3, Role: entity.CollaboratorRoleAdmin, AccessType: "temporary", ExpiresAt: &expired},
+	}
+
+	// 唯一有效的管理员不能被移除或降级,过期的临时管理员不算
+	assert.False(t, entity.HasOtherActiveAdmin(collaborators, 1))
+	assert.True(t, entity.HasOtherActiveAdmin(collaborators, 2))
+
+	collaborators = append(collaborators, &entity.BabyCollaborator{UserID: 4, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"})
+	assert.True(t, entity.HasOtherActiveAdmin(collaborators, 1))
+}
+
+func TestCheckOwnerCandidate(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).UnixMilli()
+
+	assert.ErrorContains(t, checkOwnerCandidate(nil), "只能转让给宝宝的亲友团成员")
+	assert.ErrorContains(t, checkOwnerCandidate(&entity.BabyCollaborator{
+		Role: entity.CollaboratorRoleAdmin, AccessType: "temporary", ExpiresAt: &expiresAt,
+	}), "临时成员不能成为宝宝所有者")
+	assert.NoError(t, checkOwnerCandidate(&entity.BabyCollaborator{Role: entity.CollaboratorRoleViewer, AccessType: "permanent"}))
+}
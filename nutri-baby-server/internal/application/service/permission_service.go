@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+var permissionResourceLabels = map[string]string{
+	entity.PermissionResourceFeeding:   "喂养记录",
+	entity.PermissionResourceSleep:     "睡眠记录",
+	entity.PermissionResourceDiaper:    "排泄记录",
+	entity.PermissionResourceGrowth:    "成长记录",
+	entity.PermissionResourceVaccine:   "疫苗接种记录",
+	entity.PermissionResourceMilestone: "发育里程碑",
+}
+
+var permissionActionLabels = map[string]string{
+	entity.PermissionActionRead:      "查看",
+	entity.PermissionActionCreate:    "新增",
+	entity.PermissionActionUpdateOwn: "修改",
+	entity.PermissionActionUpdateAny: "修改",
+	entity.PermissionActionDelete:    "删除",
+}
+
+var collaboratorRoleLabels = map[string]string{
+	entity.CollaboratorRoleAdmin:     "管理员",
+	entity.CollaboratorRoleEditor:    "编辑者",
+	entity.CollaboratorRoleCaregiver: "照护者",
+	entity.CollaboratorRoleViewer:    "查看者",
+}
+
+// PermissionService 协作者权限服务
+// 按 记录类型 × 操作(read/create/update_own/update_any/delete) 的权限矩阵鉴权,
+// 权限 = 角色预设 + 协作者覆盖
+type PermissionService struct {
+	collaboratorRepo repository.BabyCollaboratorRepository
+	userRepo         repository.UserRepository
+	logger           *zap.Logger
+}
+
+// NewPermissionService 创建协作者权限服务
+func NewPermissionService(
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	logger *zap.Logger,
+) *PermissionService {
+	return &PermissionService{
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// ActiveCollaborator 获取用户在该宝宝下的有效协作者身份,非协作者或临时权限已过期时返回无权限错误
+func (s *PermissionService) ActiveCollaborator(ctx context.Context, babyID int64, openID string) (*entity.BabyCollaborator, error) {
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCollaboratorActive(collaborator); err != nil {
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// checkCollaboratorActive 统一拒绝非协作者和临时权限已过期的协作者(过期行在定时任务撤销前仍存在)
+func checkCollaboratorActive(collaborator *entity.BabyCollaborator) error {
+	if collaborator == nil {
+		return errors.New(errors.PermissionDenied, "您没有权限访问该宝宝的记录")
+	}
+	if collaborator.IsExpired() {
+		return errors.New(errors.PermissionDenied, "您的临时访问权限已过期,如需继续请联系宝宝管理员")
+	}
+	return nil
+}
+
+// Authorize 检查用户能否对宝宝的某类记录执行操作
+func (s *PermissionService) Authorize(ctx context.Context, babyID int64, openID, resource, action string) (*entity.BabyCollaborator, error) {
+	collaborator, err := s.ActiveCollaborator(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+	if !collaborator.Can(resource, action) {
+		return nil, permissionDenied(resource, action)
+	}
+	return collaborator, nil
+}
+
+// AuthorizeAll 检查用户能否对多类记录执行同一操作
+func (s *PermissionService) AuthorizeAll(ctx context.Context, babyID int64, openID, action string, resources ...string) (*entity.BabyCollaborator, error) {
+	collaborator, err := s.ActiveCollaborator(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		if !collaborator.Can(resource, action) {
+			return nil, permissionDenied(resource, action)
+		}
+	}
+	return collaborator, nil
+}
+
+// AuthorizeUpdate 检查用户能否修改 ownerID 创建的某类记录
+func (s *PermissionService) AuthorizeUpdate(ctx context.Context, babyID int64, openID, resource string, ownerID int64) (*entity.BabyCollaborator, error) {
+	collaborator, err := s.ActiveCollaborator(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+	if !collaborator.CanUpdate(resource, ownerID) {
+		if collaborator.Can(resource, entity.PermissionActionUpdateOwn) {
+			return nil, errors.New(errors.PermissionDenied, fmt.Sprintf("您只能修改自己创建的%s", permissionResourceLabels[resource]))
+		}
+		return nil, permissionDenied(resource, entity.PermissionActionUpdateAny)
+	}
+	return collaborator, nil
+}
+
+// ListRolePresets 列出角色预设权限
+func (s *PermissionService) ListRolePresets() []dto.RolePermissionPresetDTO {
+	presets := make([]dto.RolePermissionPresetDTO, 0, len(entity.CollaboratorRoles))
+	for _, role := range entity.CollaboratorRoles {
+		presets = append(presets, dto.RolePermissionPresetDTO{
+			Role:        role,
+			Name:        collaboratorRoleLabels[role],
+			Permissions: (&entity.BabyCollaborator{Role: role}).EffectivePermissions(),
+		})
+	}
+	return presets
+}
+
+// GetMyPermissions 获取当前用户对宝宝的实际权限
+func (s *PermissionService) GetMyPermissions(ctx context.Context, babyID, openID string) (*dto.CollaboratorPermissionsDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	collaborator, err := s.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+	return toCollaboratorPermissionsDTO(babyID, openID, collaborator), nil
+}
+
+// GetCollaboratorPermissions 获取协作者的权限矩阵(管理员可查看所有人)
+func (s *PermissionService) GetCollaboratorPermissions(ctx context.Context, babyID, openID, targetOpenID string) (*dto.CollaboratorPermissionsDTO, error) {
+	_, target, err := s.findManagedCollaborator(ctx, babyID, openID, targetOpenID)
+	if err != nil {
+		return nil, err
+	}
+	return toCollaboratorPermissionsDTO(babyID, targetOpenID, target), nil
+}
+
+// UpdateCollaboratorPermissions 设置协作者的权限覆盖(仅管理员)
+func (s *PermissionService) UpdateCollaboratorPermissions(
+	ctx context.Context,
+	babyID, openID, targetOpenID string,
+	req *dto.UpdateCollaboratorPermissionsRequest,
+) (*dto.CollaboratorPermissionsDTO, error) {
+	for resource, actions := range req.Overrides {
+		for action := range actions {
+			if !entity.IsValidPermission(resource, action) {
+				return nil, errors.New(errors.ParamError, fmt.Sprintf("无效的权限项: %s.%s", resource, action))
+			}
+		}
+	}
+
+	babyIDInt64, target, err := s.findManagedCollaborator(ctx, babyID, openID, targetOpenID)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsAdmin() {
+		return nil, errors.New(errors.ParamError, "管理员拥有全部权限,无需单独设置")
+	}
+
+	overrides := compactOverrides(target.Role, req.Overrides)
+	if err := s.collaboratorRepo.UpdatePermissions(ctx, babyIDInt64, target.UserID, overrides); err != nil {
+		return nil, err
+	}
+	target.Permissions = overrides
+
+	s.logger.Info("更新协作者权限",
+		zap.String("babyId", babyID),
+		zap.String("targetOpenId", targetOpenID),
+		zap.Any("overrides", overrides))
+
+	return toCollaboratorPermissionsDTO(babyID, targetOpenID, target), nil
+}
+
+// findManagedCollaborator 校验操作者为管理员并查找目标协作者
+func (s *PermissionService) findManagedCollaborator(ctx context.Context, babyID, openID, targetOpenID string) (int64, *entity.BabyCollaborator, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return 0, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	operator, err := s.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !operator.IsAdmin() {
+		return 0, nil, errors.New(errors.PermissionDenied, "只有管理员可以管理协作者权限")
+	}
+
+	targetUser, err := s.userRepo.FindByOpenID(ctx, targetOpenID)
+	if err != nil {
+		return 0, nil, err
+	}
+	target, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, targetUser.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if target == nil {
+		return 0, nil, errors.New(errors.NotFound, "协作者不存在")
+	}
+	return babyIDInt64, target, nil
+}
+
+// compactOverrides 去掉与角色预设一致的覆盖项,只保存真正偏离预设的部分
+func compactOverrides(role string, overrides map[string]map[string]bool) entity.PermissionOverrides {
+	preset := entity.RolePermissions(role)
+	result := entity.PermissionOverrides{}
+	for resource, actions := range overrides {
+		for action, allowed := range actions {
+			if preset.Allows(resource, action) == allowed {
+				continue
+			}
+			if result[resource] == nil {
+				result[resource] = map[string]bool{}
+			}
+			result[resource][action] = allowed
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// permissionDenied 构造权限不足错误
+func permissionDenied(resource, action string) error {
+	return errors.New(errors.PermissionDenied,
+		fmt.Sprintf("您没有权限%s该宝宝的%s", permissionActionLabels[action], permissionResourceLabels[resource]))
+}
+
+// toCollaboratorPermissionsDTO 转换为权限矩阵DTO
+func toCollaboratorPermissionsDTO(babyID, openID string, collaborator *entity.BabyCollaborator) *dto.CollaboratorPermissionsDTO {
+	return &dto.CollaboratorPermissionsDTO{
+		BabyID:      babyID,
+		OpenID:      openID,
+		Role:        collaborator.Role,
+		Permissions: collaborator.EffectivePermissions(),
+		Overrides:   collaborator.Permissions,
+	}
+}
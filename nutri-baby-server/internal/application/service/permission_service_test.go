@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestCollaboratorRolePresets(t *testing.T) {
+	// 保姆: 可记录喂养/睡眠/排泄,看不到成长和疫苗
+	nanny := &entity.BabyCollaborator{UserID: 1, Role: entity.CollaboratorRoleCaregiver}
+	assert.True(t, nanny.Can(entity.PermissionResourceFeeding, entity.PermissionActionCreate))
+	assert.True(t, nanny.Can(entity.PermissionResourceDiaper, entity.PermissionActionRead))
+	assert.False(t, nanny.Can(entity.PermissionResourceFeeding, entity.PermissionActionDelete))
+	assert.False(t, nanny.Can(entity.PermissionResourceGrowth, entity.PermissionActionRead))
+	assert.False(t, nanny.Can(entity.PermissionResourceVaccine, entity.PermissionActionRead))
+
+	// 祖父母(查看者): 全部可看,不能删除
+	grandparent := &entity.BabyCollaborator{UserID: 2, Role: entity.CollaboratorRoleViewer}
+	assert.True(t, grandparent.Can(entity.PermissionResourceGrowth, entity.PermissionActionRead))
+	assert.False(t, grandparent.Can(entity.PermissionResourceSleep, entity.PermissionActionDelete))
+
+	editor := &entity.BabyCollaborator{UserID: 3, Role: entity.CollaboratorRoleEditor}
+	assert.True(t, editor.Can(entity.PermissionResourceMilestone, entity.PermissionActionDelete))
+
+	unknown := &entity.BabyCollaborator{UserID: 4, Role: "guest"}
+	assert.False(t, unknown.Can(entity.PermissionResourceFeeding, entity.PermissionActionRead))
+}
+
+func TestCollaboratorPermissionOverrides(t *testing.T) {
+	editor := &entity.BabyCollaborator{
+		UserID: 1,
+		Role:   entity.CollaboratorRoleEditor,
+		Permissions: entity.PermissionOverrides{
+			entity.PermissionResourceFeeding: {entity.PermissionActionDelete: false},
+			entity.PermissionResourceGrowth:  {entity.PermissionActionRead: false},
+		},
+	}
+	assert.False(t, editor.Can(entity.PermissionResourceFeeding, entity.PermissionActionDelete))
+	assert.True(t, editor.Can(entity.PermissionResourceFeeding, entity.PermissionActionCreate))
+	assert.False(t, editor.Can(entity.PermissionResourceGrowth, entity.PermissionActionRead))
+
+	nanny := &entity.BabyCollaborator{
+		UserID: 2,
+		Role:   entity.CollaboratorRoleCaregiver,
+		Permissions: entity.PermissionOverrides{
+			entity.PermissionResourceGrowth: {entity.PermissionActionRead: true},
+		},
+	}
+	assert.True(t, nanny.Can(entity.PermissionResourceGrowth, entity.PermissionActionRead))
+	assert.Equal(t, []string{entity.PermissionActionRead}, nanny.EffectivePermissions()[entity.PermissionResourceGrowth])
+
+	// 管理员不受覆盖限制
+	admin := &entity.BabyCollaborator{
+		UserID:      3,
+		Role:        entity.CollaboratorRoleAdmin,
+		Permissions: entity.PermissionOverrides{entity.PermissionResourceFeeding: {entity.PermissionActionRead: false}},
+	}
+	assert.True(t, admin.Can(entity.PermissionResourceFeeding, entity.PermissionActionRead))
+}
+
+func TestCollaboratorCanUpdate(t *testing.T) {
+	nanny := &entity.BabyCollaborator{UserID: 1, Role: entity.CollaboratorRoleCaregiver}
+	assert.True(t, nanny.CanUpdate(entity.PermissionResourceFeeding, 1))
+	assert.False(t, nanny.CanUpdate(entity.PermissionResourceFeeding, 2))
+	assert.False(t, nanny.CanUpdate(entity.PermissionResourceGrowth, 1))
+
+	editor := &entity.BabyCollaborator{UserID: 3, Role: entity.CollaboratorRoleEditor}
+	assert.True(t, editor.CanUpdate(entity.PermissionResourceFeeding, 2))
+}
+
+func TestExpiredCollaboratorHasNoPermissions(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Hour).UnixMilli()
+	expired := &entity.BabyCollaborator{
+		UserID:     1,
+		Role:       entity.CollaboratorRoleAdmin,
+		AccessType: "temporary",
+		ExpiresAt:  &expiresAt,
+	}
+	assert.False(t, expired.Can(entity.PermissionResourceFeeding, entity.PermissionActionRead))
+}
+
+func TestCompactOverrides(t *testing.T) {
+	overrides := compactOverrides(entity.CollaboratorRoleViewer, map[string]map[string]bool{
+		entity.PermissionResourceFeeding: {
+			entity.PermissionActionRead:   true, // 与预设一致,丢弃
+			entity.PermissionActionCreate: true,
+		},
+		entity.PermissionResourceGrowth: {entity.PermissionActionDelete: false},
+	})
+	assert.Equal(t, entity.PermissionOverrides{
+		entity.PermissionResourceFeeding: {entity.PermissionActionCreate: true},
+	}, overrides)
+
+	assert.Nil(t, compactOverrides(entity.CollaboratorRoleEditor, map[string]map[string]bool{
+		entity.PermissionResourceSleep: {entity.PermissionActionDelete: true},
+	}))
+}
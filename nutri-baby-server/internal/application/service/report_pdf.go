@@ -0,0 +1,448 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/pdf"
+)
+
+// 报告版式
+const (
+	reportMargin       = 40.0
+	reportBottom       = pdf.PageHeight - 50
+	reportContentWidth = pdf.PageWidth - 2*reportMargin
+	reportFontSize     = 10.0
+	reportLineHeight   = 16.0
+	reportChartHeight  = 220.0
+)
+
+var (
+	reportThemeColor = pdf.Color{R: 52, G: 120, B: 246}
+	reportAlertColor = pdf.Color{R: 220, G: 53, B: 69}
+	reportCurveColor = pdf.Color{R: 170, G: 170, B: 170}
+	reportGridColor  = pdf.Color{R: 235, G: 235, B: 235}
+)
+
+// reportReferenceCurves 生长曲线中绘制的参考百分位及对应 Z 分数
+var reportReferenceCurves = []struct {
+	label string
+	z     float64
+}{
+	{"P3", -1.881}, {"P15", -1.036}, {"P50", 0}, {"P85", 1.036}, {"P97", 1.881},
+}
+
+// reportLayout 自上而下排版,空间不足时自动换页
+type reportLayout struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+func (l *reportLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.pages = append(l.pages, l.page)
+	l.y = reportMargin
+}
+
+// ensure 剩余空间不足 h 时换页
+func (l *reportLayout) ensure(h float64) {
+	if l.page == nil || l.y+h > reportBottom {
+		l.newPage()
+	}
+}
+
+func (l *reportLayout) heading(text string) {
+	l.ensure(60)
+	l.y += 22
+	l.page.Text(reportMargin, l.y, 13, reportThemeColor, text)
+	l.y += 6
+	l.page.Line(reportMargin, l.y, reportMargin+reportContentWidth, l.y, 0.8, reportThemeColor)
+	l.y += 6
+}
+
+// paragraph 输出自动换行的段落
+func (l *reportLayout) paragraph(text string, color pdf.Color) {
+	for _, line := range wrapText(text, reportFontSize, reportContentWidth) {
+		l.ensure(reportLineHeight)
+		l.y += reportLineHeight
+		l.page.Text(reportMargin, l.y, reportFontSize, color, line)
+	}
+}
+
+// keyValues 两列键值对
+func (l *reportLayout) keyValues(pairs [][2]string) {
+	colWidth := reportContentWidth / 2
+	for i := 0; i < len(pairs); i += 2 {
+		l.ensure(reportLineHeight)
+		l.y += reportLineHeight
+		for j := 0; j < 2 && i+j < len(pairs); j++ {
+			x := reportMargin + float64(j)*colWidth
+			l.page.Text(x, l.y, reportFontSize, pdf.Gray, pairs[i+j][0])
+			l.page.Text(x+90, l.y, reportFontSize, pdf.Black, pdf.Truncate(pairs[i+j][1], reportFontSize, colWidth-95))
+		}
+	}
+}
+
+// table 绘制表格,换页时重复表头
+func (l *reportLayout) table(header []string, widths []float64, rows [][]string) {
+	const rowHeight = 18.0
+	drawHeader := func() {
+		l.page.Rect(reportMargin, l.y, reportContentWidth, rowHeight, &pdf.LightGray, nil)
+		x := reportMargin
+		for i, h := range header {
+			l.page.Text(x+4, l.y+12.5, 9, pdf.Black, h)
+			x += widths[i]
+		}
+		l.y += rowHeight
+	}
+
+	l.ensure(rowHeight * 2)
+	l.y += 6
+	drawHeader()
+	for _, row := range rows {
+		if l.y+rowHeight > reportBottom {
+			l.newPage()
+			drawHeader()
+		}
+		x := reportMargin
+		for i, v := range row {
+			l.page.Text(x+4, l.y+12.5, 9, pdf.Black, pdf.Truncate(v, 9, widths[i]-8))
+			x += widths[i]
+		}
+		l.y += rowHeight
+		l.page.Line(reportMargin, l.y, reportMargin+reportContentWidth, l.y, 0.3, reportGridColor)
+	}
+}
+
+// renderReport 使用指定字体渲染就诊报告 PDF
+func renderReport(data *reportData, font []byte) ([]byte, error) {
+	loc := data.End.Location()
+	babyName := data.Baby.Name
+	if data.Baby.Nickname != "" && data.Baby.Nickname != data.Baby.Name {
+		babyName = fmt.Sprintf("%s(%s)", data.Baby.Name, data.Baby.Nickname)
+	}
+
+	l := &reportLayout{doc: pdf.New()}
+	l.doc.SetFont(font)
+	l.doc.SetTitle(fmt.Sprintf("%s 就诊报告", data.Baby.Name))
+	l.newPage()
+
+	// 标题
+	l.y += 20
+	l.page.Text(reportMargin, l.y, 18, pdf.Black, "宝宝就诊报告")
+	l.page.TextRight(reportMargin+reportContentWidth, l.y, 9, pdf.Gray,
+		"生成时间 "+data.GeneratedAt.In(loc).Format("2006-01-02 15:04"))
+	l.y += 18
+	l.page.Text(reportMargin, l.y, reportFontSize, pdf.Gray, fmt.Sprintf("报告周期: %s 至 %s (共 %d 天)",
+		data.Start.In(loc).Format("2006-01-02"), data.End.In(loc).Format("2006-01-02"), data.Days))
+
+	// 基本信息
+	l.heading("基本信息")
+	age := "-"
+	if data.HasAge {
+		age = formatReportAge(data.AgeMonths)
+	}
+	pairs := [][2]string{
+		{"姓名", babyName},
+		{"性别", reportGenderLabel(data.Baby.Gender)},
+		{"出生日期", data.Baby.BirthDate},
+		{"月龄", age},
+	}
+	if n := len(data.Growths); n > 0 {
+		latest := data.Growths[n-1]
+		pairs = append(pairs,
+			[2]string{"最近测量", time.UnixMilli(latest.Record.Time).In(loc).Format("2006-01-02")},
+			[2]string{"体重/身长", fmt.Sprintf("%s / %s", formatReportFloat(latest.Record.Weight, "kg"), formatReportFloat(latest.Record.Height, "cm"))},
+		)
+	}
+	l.keyValues(pairs)
+
+	// 生长发育
+	l.heading("生长发育")
+	if len(data.Growths) == 0 {
+		l.paragraph("暂无生长记录", pdf.Gray)
+	} else {
+		rows := data.Growths
+		if len(rows) > reportGrowthRows {
+			rows = rows[len(rows)-reportGrowthRows:]
+		}
+		var tableRows [][]string
+		for _, row := range rows {
+			r := row.Record
+			ageText := "-"
+			if row.HasAge {
+				ageText = formatReportAge(row.AgeMonths)
+			}
+			tableRows = append(tableRows, []string{
+				time.UnixMilli(r.Time).In(loc).Format("2006-01-02"),
+				ageText,
+				formatReportFloat(r.Weight, ""), formatReportPercentile(row.WeightPercentile),
+				formatReportFloat(r.Height, ""), formatReportPercentile(row.HeightPercentile),
+				formatReportFloat(r.HeadCircumference, ""), formatReportPercentile(row.HeadPercentile),
+			})
+		}
+		l.table(
+			[]string{"日期", "月龄", "体重(kg)", "百分位", "身长(cm)", "百分位", "头围(cm)", "百分位"},
+			[]float64{75, 80, 60, 60, 60, 60, 60, reportContentWidth - 455},
+			tableRows,
+		)
+		l.paragraph("百分位依据 WHO 儿童生长标准(0-24 月龄)计算。", pdf.Gray)
+		drawGrowthChart(l, data)
+	}
+
+	// 喂养与睡眠
+	days := float64(data.Days)
+	l.heading("喂养与睡眠(日均)")
+	l.keyValues([][2]string{
+		{"喂养次数", fmt.Sprintf("%.1f 次", float64(data.Feeding.Count)/days)},
+		{"母乳", fmt.Sprintf("%.1f 次 / %.0f 分钟", float64(data.Feeding.BreastCount)/days, float64(data.Feeding.BreastSeconds)/60/days)},
+		{"奶瓶", fmt.Sprintf("%.1f 次 / %.0f ml", float64(data.Feeding.BottleCount)/days, float64(data.Feeding.BottleMl)/days)},
+		{"辅食", fmt.Sprintf("%.1f 次", float64(data.Feeding.FoodCount)/days)},
+		{"睡眠时长", formatReportHours(float64(data.Sleep.TotalSeconds) / days)},
+		{"小睡次数", fmt.Sprintf("%.1f 次", float64(data.Sleep.NapCount)/days)},
+		{"最长睡眠", formatReportHours(float64(data.Sleep.LongestSecs))},
+		{"夜间睡眠", fmt.Sprintf("%d 次", data.Sleep.NightCount)},
+	})
+
+	// 尿布
+	l.heading("排泄(日均)")
+	l.keyValues([][2]string{
+		{"换尿布", fmt.Sprintf("%.1f 次", float64(data.Diaper.Count)/days)},
+		{"小便", fmt.Sprintf("%.1f 次", float64(data.Diaper.Pee)/days)},
+		{"大便", fmt.Sprintf("%.1f 次", float64(data.Diaper.Poop)/days)},
+		{"周期合计", fmt.Sprintf("%d 次", data.Diaper.Count)},
+	})
+
+	// 疫苗
+	l.heading("疫苗接种")
+	if len(data.Vaccines) == 0 {
+		l.paragraph("暂无疫苗接种计划", pdf.Gray)
+	} else {
+		var rows [][]string
+		for _, v := range data.Vaccines {
+			vaccineDate, reaction := "-", "-"
+			if v.VaccineDate != nil {
+				vaccineDate = time.UnixMilli(*v.VaccineDate).In(loc).Format("2006-01-02")
+			}
+			if v.Reaction != nil && *v.Reaction != "" {
+				reaction = *v.Reaction
+			}
+			rows = append(rows, []string{
+				v.VaccineName,
+				fmt.Sprintf("第 %d 剂", v.DoseNumber),
+				time.UnixMilli(v.ScheduledDate).In(loc).Format("2006-01-02"),
+				reportVaccineStatusLabel(v, data.End),
+				vaccineDate,
+				reaction,
+			})
+		}
+		l.table(
+			[]string{"疫苗", "剂次", "计划日期", "状态", "接种日期", "接种反应"},
+			[]float64{130, 50, 75, 55, 75, reportContentWidth - 385},
+			rows,
+		)
+	}
+
+	// 需要关注
+	l.heading("需要关注")
+	if len(data.Alerts) == 0 {
+		l.paragraph("报告周期内未发现需要特别关注的情况。", pdf.Gray)
+	}
+	for _, alert := range data.Alerts {
+		l.paragraph("• "+alert, reportAlertColor)
+	}
+
+	l.y += 10
+	l.paragraph("本报告由家长记录的数据自动生成,仅供医生参考,不作为诊断依据。", pdf.Gray)
+
+	// 页脚页码
+	for i, page := range l.pages {
+		page.TextRight(reportMargin+reportContentWidth, pdf.PageHeight-25, 8, pdf.Gray,
+			fmt.Sprintf("第 %d / %d 页", i+1, len(l.pages)))
+	}
+
+	var buf bytes.Buffer
+	if err := l.doc.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawGrowthChart 绘制年龄别体重曲线,0-24 月龄叠加 WHO 参考百分位曲线
+func drawGrowthChart(l *reportLayout, data *reportData) {
+	type point struct{ age, weight float64 }
+	var points []point
+	for _, row := range data.Growths {
+		if row.HasAge && row.Record.Weight != nil {
+			points = append(points, point{row.AgeMonths, *row.Record.Weight})
+		}
+	}
+	if len(points) == 0 {
+		return
+	}
+
+	gender := data.Baby.Gender
+	maxAge := math.Max(6, math.Ceil(points[len(points)-1].age+1))
+	withReference := maxAge <= growthStandardMaxMonths && whoGrowthStandards[growthIndicatorWeight][gender] != nil
+
+	minW, maxW := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		minW, maxW = math.Min(minW, p.weight), math.Max(maxW, p.weight)
+	}
+	if withReference {
+		for age := 0.0; age <= maxAge; age += 0.5 {
+			if v, ok := growthValueAtZ(growthIndicatorWeight, gender, age, reportReferenceCurves[0].z); ok {
+				minW = math.Min(minW, v)
+			}
+			if v, ok := growthValueAtZ(growthIndicatorWeight, gender, age, reportReferenceCurves[len(reportReferenceCurves)-1].z); ok {
+				maxW = math.Max(maxW, v)
+			}
+		}
+	}
+	minW, maxW = math.Floor(minW), math.Ceil(maxW)
+	if maxW-minW < 2 {
+		maxW = minW + 2
+	}
+
+	l.ensure(reportChartHeight + 40)
+	l.y += 22
+	l.page.Text(reportMargin, l.y, 11, pdf.Black, "年龄别体重曲线")
+	l.y += 10
+
+	const axisLeft, axisBottomGap, legendWidth = 34.0, 20.0, 30.0
+	left := reportMargin + axisLeft
+	top := l.y
+	width := reportContentWidth - axisLeft - legendWidth
+	height := reportChartHeight - axisBottomGap
+	x := func(age float64) float64 { return left + age/maxAge*width }
+	y := func(w float64) float64 { return top + (maxW-w)/(maxW-minW)*height }
+
+	// 网格与坐标轴
+	wStep := math.Max(1, math.Ceil((maxW-minW)/8))
+	for w := minW; w <= maxW; w += wStep {
+		l.page.Line(left, y(w), left+width, y(w), 0.3, reportGridColor)
+		l.page.TextRight(left-4, y(w)+3, 8, pdf.Gray, fmt.Sprintf("%.0f", w))
+	}
+	ageStep := math.Max(1, math.Ceil(maxAge/12))
+	for age := 0.0; age <= maxAge; age += ageStep {
+		l.page.Line(x(age), top, x(age), top+height, 0.3, reportGridColor)
+		l.page.Text(x(age)-3, top+height+12, 8, pdf.Gray, fmt.Sprintf("%.0f", age))
+	}
+	l.page.Rect(left, top, width, height, nil, &pdf.Gray)
+	l.page.Text(left+width-40, top+height+12, 8, pdf.Gray, "月龄")
+	l.page.Text(reportMargin, top-2, 8, pdf.Gray, "kg")
+
+	// 参考曲线
+	if withReference {
+		for _, curve := range reportReferenceCurves {
+			var line []pdf.Point
+			for age := 0.0; age <= maxAge+1e-9; age += 0.25 {
+				if v, ok := growthValueAtZ(growthIndicatorWeight, gender, age, curve.z); ok {
+					line = append(line, pdf.Point{X: x(age), Y: y(v)})
+				}
+			}
+			lineWidth := 0.6
+			if curve.z == 0 {
+				lineWidth = 1.2
+			}
+			l.page.Polyline(line, lineWidth, reportCurveColor)
+			if len(line) > 0 {
+				end := line[len(line)-1]
+				l.page.Text(end.X+3, end.Y+3, 7, pdf.Gray, curve.label)
+			}
+		}
+	}
+
+	// 宝宝体重
+	var series []pdf.Point
+	for _, p := range points {
+		series = append(series, pdf.Point{X: x(p.age), Y: y(p.weight)})
+	}
+	l.page.Polyline(series, 1.5, reportThemeColor)
+	for _, p := range series {
+		l.page.Rect(p.X-2, p.Y-2, 4, 4, &reportThemeColor, nil)
+	}
+
+	l.y = top + reportChartHeight
+}
+
+// wrapText 按宽度折行
+func wrapText(text string, size, maxWidth float64) []string {
+	var lines []string
+	var current []rune
+	for _, r := range text {
+		if pdf.TextWidth(string(append(current, r)), size) > maxWidth && len(current) > 0 {
+			lines = append(lines, string(current))
+			current = current[:0]
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+func reportGenderLabel(gender string) string {
+	switch gender {
+	case "male":
+		return "男"
+	case "female":
+		return "女"
+	}
+	return "-"
+}
+
+func reportVaccineStatusLabel(v *entity.BabyVaccineSchedule, end time.Time) string {
+	switch v.VaccinationStatus {
+	case entity.VaccinationStatusCompleted:
+		return "已接种"
+	case entity.VaccinationStatusSkipped:
+		return "已跳过"
+	}
+	if v.ScheduledDate < end.UnixMilli() {
+		return "已逾期"
+	}
+	return "待接种"
+}
+
+// formatReportAge 月龄显示为 "X个月Y天"
+func formatReportAge(months float64) string {
+	whole := int(months)
+	days := int((months - float64(whole)) * daysPerMonth)
+	if whole >= 24 {
+		return fmt.Sprintf("%d岁%d个月", whole/12, whole%12)
+	}
+	return fmt.Sprintf("%d个月%d天", whole, days)
+}
+
+func formatReportFloat(v *float64, unit string) string {
+	if v == nil {
+		return "-"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", *v, unit))
+}
+
+func formatReportPercentile(p *float64) string {
+	switch {
+	case p == nil:
+		return "-"
+	case *p < 1:
+		return "<P1"
+	case *p > 99:
+		return ">P99"
+	}
+	return fmt.Sprintf("P%.0f", *p)
+}
+
+// formatReportHours 秒数显示为 "X小时Y分钟"
+func formatReportHours(seconds float64) string {
+	minutes := int(seconds / 60)
+	return fmt.Sprintf("%d小时%d分钟", minutes/60, minutes%60)
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/pdf"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	reportDefaultDays    = 30   // 默认报告周期(天)
+	reportMaxDays        = 366  // 报告周期上限(天)
+	reportPageSize       = 500  // 分页读取记录的每页条数
+	reportVaccineLimit   = 1000 // 疫苗日程最多读取条数
+	reportGrowthRows     = 12   // 生长记录表格最多显示条数
+	reportLowPercentile  = 3    // 低于该百分位提示关注
+	reportHighPercentile = 97   // 高于该百分位提示关注
+)
+
+// 喂养/尿布次数提示阈值
+const (
+	reportMinDailyFeedings    = 8 // 3 月龄内日均喂养次数下限
+	reportMinDailyWetDiapers  = 6 // 6 月龄内日均小便次数下限
+	reportFeedingCheckMonths  = 3
+	reportDiaperCheckMonths   = 6
+	reportOverdueVaccineGrace = 0 // 超过计划日期即视为逾期(天)
+)
+
+// ReportService 儿科就诊报告服务
+// 汇总指定周期内的生长、喂养、睡眠、尿布及疫苗数据,生成可打印的 PDF
+type ReportService struct {
+	*BaseRecordService
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	font              []byte // PDF 中文字体,未配置或读取失败时为空
+}
+
+// NewReportService 创建就诊报告服务
+func NewReportService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *ReportService {
+	var font []byte
+	if cfg.Report.FontPath == "" {
+		logger.Warn("未配置报告字体(report.font_path),无法生成 PDF 报告")
+	} else if data, err := pdf.LoadFont(cfg.Report.FontPath); err != nil {
+		logger.Error("读取报告字体失败", zap.String("path", cfg.Report.FontPath), zap.Error(err))
+	} else {
+		font = data
+	}
+
+	return &ReportService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		scheduleRepo:      scheduleRepo,
+		font:              font,
+	}
+}
+
+// reportData 报告数据
+type reportData struct {
+	Baby        *entity.Baby
+	Start       time.Time
+	End         time.Time
+	GeneratedAt time.Time
+	Days        int
+	AgeMonths   float64 // 报告截止时的月龄
+	HasAge      bool
+
+	Growths  []reportGrowthRow // 截止时间前的全部生长记录(按时间升序)
+	Feeding  reportFeedingSummary
+	Sleep    reportSleepSummary
+	Diaper   reportDiaperSummary
+	Vaccines []*entity.BabyVaccineSchedule // 按计划日期升序
+	Alerts   []string
+}
+
+// reportGrowthRow 生长记录及对应百分位
+type reportGrowthRow struct {
+	Record           *entity.GrowthRecord
+	AgeMonths        float64
+	HasAge           bool
+	WeightPercentile *float64
+	HeightPercentile *float64
+	HeadPercentile   *float64
+}
+
+type reportFeedingSummary struct {
+	Count         int
+	BreastCount   int
+	BreastSeconds int
+	BottleCount   int
+	BottleMl      int64
+	FoodCount     int
+}
+
+type reportSleepSummary struct {
+	Count        int
+	NapCount     int
+	NightCount   int
+	TotalSeconds int
+	LongestSecs  int
+}
+
+type reportDiaperSummary struct {
+	Count int
+	Pee   int // 含 both
+	Poop  int // 含 both
+}
+
+// GenerateReport 生成就诊报告 PDF,返回文件内容和建议的文件名
+func (s *ReportService) GenerateReport(ctx context.Context, openID, babyID string, req *dto.ReportRequest) ([]byte, string, error) {
+	if err := s.CheckRecordsPermission(ctx, babyID, openID, entity.PermissionActionRead,
+		entity.PermissionResourceFeeding, entity.PermissionResourceSleep, entity.PermissionResourceDiaper,
+		entity.PermissionResourceGrowth, entity.PermissionResourceVaccine); err != nil {
+		return nil, "", err
+	}
+
+	if len(s.font) == 0 {
+		return nil, "", errors.New(errors.InternalError, "服务器未配置报告字体,暂无法生成报告")
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, "", errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	now := time.Now()
+	end := now
+	if req.EndTime > 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+	start := end.AddDate(0, 0, -reportDefaultDays)
+	if req.StartTime > 0 {
+		start = time.UnixMilli(req.StartTime)
+	}
+	if !start.Before(end) {
+		return nil, "", errors.New(errors.ParamError, "开始时间必须早于结束时间")
+	}
+	if end.Sub(start) > reportMaxDays*24*time.Hour {
+		return nil, "", errors.New(errors.ParamError, fmt.Sprintf("报告周期不能超过 %d 天", reportMaxDays))
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := s.collect(ctx, baby, start, end, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := renderReport(data, s.font)
+	if err != nil {
+		s.logger.Error("生成就诊报告失败", zap.String("babyID", babyID), zap.Error(err))
+		return nil, "", errors.Wrap(errors.InternalError, "failed to render report", err)
+	}
+
+	filename := fmt.Sprintf("nutri-baby-report_%d_%s.pdf", baby.ID, end.Format("20060102"))
+	return content, filename, nil
+}
+
+// collect 读取报告所需数据并汇总
+func (s *ReportService) collect(ctx context.Context, baby *entity.Baby, start, end, now time.Time) (*reportData, error) {
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+
+	var feedings []*entity.FeedingRecord
+	for page := 1; ; page++ {
+		records, _, err := s.feedingRecordRepo.FindByBabyID(ctx, baby.ID, startMs, endMs, page, reportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		feedings = append(feedings, records...)
+		if len(records) < reportPageSize {
+			break
+		}
+	}
+
+	var sleeps []*entity.SleepRecord
+	for page := 1; ; page++ {
+		records, _, err := s.sleepRecordRepo.FindByBabyID(ctx, baby.ID, startMs, endMs, page, reportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		sleeps = append(sleeps, records...)
+		if len(records) < reportPageSize {
+			break
+		}
+	}
+
+	var diapers []*entity.DiaperRecord
+	for page := 1; ; page++ {
+		records, _, err := s.diaperRecordRepo.FindByBabyID(ctx, baby.ID, startMs, endMs, page, reportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		diapers = append(diapers, records...)
+		if len(records) < reportPageSize {
+			break
+		}
+	}
+
+	// 生长曲线需要完整的历史记录
+	var growths []*entity.GrowthRecord
+	for page := 1; ; page++ {
+		records, _, err := s.growthRecordRepo.FindByBabyID(ctx, baby.ID, 0, endMs, page, reportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		growths = append(growths, records...)
+		if len(records) < reportPageSize {
+			break
+		}
+	}
+
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, baby.ID, 1, reportVaccineLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildReportData(baby, start, end, now, feedings, sleeps, diapers, growths, schedules), nil
+}
+
+// buildReportData 汇总报告数据并生成关注提示
+func buildReportData(
+	baby *entity.Baby,
+	start, end, now time.Time,
+	feedings []*entity.FeedingRecord,
+	sleeps []*entity.SleepRecord,
+	diapers []*entity.DiaperRecord,
+	growths []*entity.GrowthRecord,
+	schedules []*entity.BabyVaccineSchedule,
+) *reportData {
+	data := &reportData{
+		Baby:        baby,
+		Start:       start,
+		End:         end,
+		GeneratedAt: now,
+		Days:        int(math.Ceil(end.Sub(start).Hours() / 24)),
+	}
+	if data.Days < 1 {
+		data.Days = 1
+	}
+	data.AgeMonths, data.HasAge = fractionalAgeInMonths(baby.BirthDate, end)
+
+	for _, r := range feedings {
+		data.Feeding.Count++
+		switch r.FeedingType {
+		case entity.FeedingTypeBreast:
+			data.Feeding.BreastCount++
+			data.Feeding.BreastSeconds += r.Duration
+		case entity.FeedingTypeBottle:
+			data.Feeding.BottleCount++
+			data.Feeding.BottleMl += r.Amount
+		case entity.FeedingTypeFood:
+			data.Feeding.FoodCount++
+		}
+	}
+
+	for _, r := range sleeps {
+		data.Sleep.Count++
+		if r.Type == entity.SleepTypeNight {
+			data.Sleep.NightCount++
+		} else {
+			data.Sleep.NapCount++
+		}
+		if r.Duration != nil {
+			data.Sleep.TotalSeconds += *r.Duration
+			data.Sleep.LongestSecs = max(data.Sleep.LongestSecs, *r.Duration)
+		}
+	}
+
+	for _, r := range diapers {
+		data.Diaper.Count++
+		if r.Type == entity.DiaperTypePee || r.Type == entity.DiaperTypeBoth {
+			data.Diaper.Pee++
+		}
+		if r.Type == entity.DiaperTypePoop || r.Type == entity.DiaperTypeBoth {
+			data.Diaper.Poop++
+		}
+	}
+
+	sort.Slice(growths, func(i, j int) bool { return growths[i].Time < growths[j].Time })
+	for _, r := range growths {
+		row := reportGrowthRow{Record: r}
+		row.AgeMonths, row.HasAge = fractionalAgeInMonths(baby.BirthDate, time.UnixMilli(r.Time).In(end.Location()))
+		if row.HasAge {
+			row.WeightPercentile = percentileOf(growthIndicatorWeight, baby.Gender, row.AgeMonths, r.Weight)
+			row.HeightPercentile = percentileOf(growthIndicatorLength, baby.Gender, row.AgeMonths, r.Height)
+			row.HeadPercentile = percentileOf(growthIndicatorHead, baby.Gender, row.AgeMonths, r.HeadCircumference)
+		}
+		data.Growths = append(data.Growths, row)
+	}
+
+	data.Vaccines = append(data.Vaccines, schedules...)
+	sort.SliceStable(data.Vaccines, func(i, j int) bool {
+		return data.Vaccines[i].ScheduledDate < data.Vaccines[j].ScheduledDate
+	})
+
+	data.Alerts = reportAlerts(data)
+	return data
+}
+
+func percentileOf(indicator, gender string, ageMonths float64, value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	p, ok := growthPercentile(indicator, gender, ageMonths, *value)
+	if !ok {
+		return nil
+	}
+	return &p
+}
+
+// reportAlerts 生成需要关注的提示
+func reportAlerts(data *reportData) []string {
+	var alerts []string
+
+	// 最新一次测量的百分位
+	if n := len(data.Growths); n > 0 {
+		latest := data.Growths[n-1]
+		date := time.UnixMilli(latest.Record.Time).In(data.End.Location()).Format("2006-01-02")
+		for _, item := range []struct {
+			label      string
+			percentile *float64
+		}{
+			{"体重", latest.WeightPercentile},
+			{"身长", latest.HeightPercentile},
+			{"头围", latest.HeadPercentile},
+		} {
+			if item.percentile == nil {
+				continue
+			}
+			switch {
+			case *item.percentile < reportLowPercentile:
+				alerts = append(alerts, fmt.Sprintf("%s 测量的%s处于第 %.1f 百分位,低于第 %d 百分位", date, item.label, *item.percentile, reportLowPercentile))
+			case *item.percentile > reportHighPercentile:
+				alerts = append(alerts, fmt.Sprintf("%s 测量的%s处于第 %.1f 百分位,高于第 %d 百分位", date, item.label, *item.percentile, reportHighPercentile))
+			}
+		}
+	}
+
+	// 报告周期内的体重下降
+	var prev *entity.GrowthRecord
+	for _, row := range data.Growths {
+		r := row.Record
+		if r.Weight == nil {
+			continue
+		}
+		if prev != nil && r.Time >= data.Start.UnixMilli() && *r.Weight < *prev.Weight {
+			alerts = append(alerts, fmt.Sprintf("%s 体重 %.2fkg,较 %s 下降 %.2fkg",
+				time.UnixMilli(r.Time).In(data.End.Location()).Format("2006-01-02"), *r.Weight,
+				time.UnixMilli(prev.Time).In(data.End.Location()).Format("2006-01-02"), *prev.Weight-*r.Weight))
+		}
+		prev = r
+	}
+
+	days := float64(data.Days)
+	if data.HasAge && data.AgeMonths < reportFeedingCheckMonths && data.Feeding.Count > 0 {
+		if avg := float64(data.Feeding.Count) / days; avg < reportMinDailyFeedings {
+			alerts = append(alerts, fmt.Sprintf("日均喂养 %.1f 次,少于 %d 次", avg, reportMinDailyFeedings))
+		}
+	}
+	if data.HasAge && data.AgeMonths < reportDiaperCheckMonths && data.Diaper.Count > 0 {
+		if avg := float64(data.Diaper.Pee) / days; avg < reportMinDailyWetDiapers {
+			alerts = append(alerts, fmt.Sprintf("日均小便 %.1f 次,少于 %d 次", avg, reportMinDailyWetDiapers))
+		}
+	}
+
+	// 疫苗: 逾期未接种及周期内记录的接种反应
+	startMs, endMs := data.Start.UnixMilli(), data.End.UnixMilli()
+	for _, v := range data.Vaccines {
+		switch v.VaccinationStatus {
+		case entity.VaccinationStatusPending:
+			overdueDays := int((endMs - v.ScheduledDate) / (24 * time.Hour).Milliseconds())
+			if overdueDays > reportOverdueVaccineGrace {
+				alerts = append(alerts, fmt.Sprintf("%s 第 %d 剂已逾期 %d 天未接种", v.VaccineName, v.DoseNumber, overdueDays))
+			}
+		case entity.VaccinationStatusCompleted:
+			if v.Reaction != nil && *v.Reaction != "" && v.VaccineDate != nil &&
+				*v.VaccineDate >= startMs && *v.VaccineDate <= endMs {
+				alerts = append(alerts, fmt.Sprintf("%s 第 %d 剂接种后出现反应: %s", v.VaccineName, v.DoseNumber, *v.Reaction))
+			}
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/pdf/pdftest"
+)
+
+func TestGrowthPercentile(t *testing.T) {
+	p, ok := growthPercentile(growthIndicatorWeight, "male", 0, 3.3464)
+	require.True(t, ok)
+	assert.InDelta(t, 50, p, 0.01)
+
+	// Z=-1.881 对应第 3 百分位
+	v, ok := growthValueAtZ(growthIndicatorLength, "female", 6, -1.881)
+	require.True(t, ok)
+	p, ok = growthPercentile(growthIndicatorLength, "female", 6, v)
+	require.True(t, ok)
+	assert.InDelta(t, 3, p, 0.01)
+
+	_, ok = growthPercentile(growthIndicatorWeight, "male", 30, 12)
+	assert.False(t, ok)
+	_, ok = growthPercentile(growthIndicatorWeight, "", 3, 6)
+	assert.False(t, ok)
+}
+
+func TestBuildReportData(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, loc)
+	start := end.AddDate(0, 0, -10)
+	baby := &entity.Baby{ID: 1, Name: "小明", Gender: "male", BirthDate: "2024-01-01"}
+
+	weight := func(v float64) *float64 { return &v }
+	growths := []*entity.GrowthRecord{
+		{Time: start.Add(48 * time.Hour).UnixMilli(), Weight: weight(4.6)},
+		{Time: end.Add(-24 * time.Hour).UnixMilli(), Weight: weight(3.0)},
+	}
+	feedings := []*entity.FeedingRecord{
+		{FeedingType: entity.FeedingTypeBottle, Amount: 120},
+		{FeedingType: entity.FeedingTypeBreast, Duration: 600},
+	}
+	diapers := []*entity.DiaperRecord{{Type: entity.DiaperTypeBoth}, {Type: entity.DiaperTypePee}}
+	reaction := "发热 38.2℃"
+	vaccineDate := start.Add(72 * time.Hour).UnixMilli()
+	schedules := []*entity.BabyVaccineSchedule{
+		{VaccineName: "乙肝疫苗", DoseNumber: 2, ScheduledDate: end.AddDate(0, 0, -5).UnixMilli(), VaccinationStatus: entity.VaccinationStatusPending},
+		{VaccineName: "卡介苗", DoseNumber: 1, ScheduledDate: start.UnixMilli(), VaccinationStatus: entity.VaccinationStatusCompleted,
+			VaccineDate: &vaccineDate, Reaction: &reaction},
+	}
+
+	data := buildReportData(baby, start, end, end, feedings, nil, diapers, growths, schedules)
+
+	assert.Equal(t, 10, data.Days)
+	assert.Equal(t, 1, data.Feeding.BottleCount)
+	assert.EqualValues(t, 120, data.Feeding.BottleMl)
+	assert.Equal(t, 2, data.Diaper.Pee)
+	assert.Equal(t, 1, data.Diaper.Poop)
+	require.Len(t, data.Growths, 2)
+	require.NotNil(t, data.Growths[1].WeightPercentile)
+	assert.Less(t, *data.Growths[1].WeightPercentile, 1.0)
+	assert.Equal(t, "卡介苗", data.Vaccines[0].VaccineName)
+
+	alerts := data.Alerts
+	assert.Len(t, alerts, 6) // 百分位、体重下降、喂养、小便、接种反应、逾期
+	assert.Contains(t, alerts[0], "低于第 3 百分位")
+	assert.Contains(t, alerts[1], "下降 1.60kg")
+	assert.Contains(t, alerts[4], reaction)
+	assert.Contains(t, alerts[5], "乙肝疫苗 第 2 剂已逾期 5 天")
+
+	t.Run("render", func(t *testing.T) {
+		content, err := renderReport(data, pdftest.Font(t))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte("%PDF-1.")))
+	})
+}
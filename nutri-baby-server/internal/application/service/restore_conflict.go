@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// restoreOverlapLookback 恢复时向前查找可能重叠的记录的时间范围(跨度超过一天的睡眠或喂养极少)
+const restoreOverlapLookback = 24 * time.Hour
+
+// restoreConflictChecker 恢复记录前检查与现有记录的冲突
+// 回收站恢复已删除的记录、恢复到历史版本时共用;现有记录中与待恢复记录ID相同的(即记录本身)不参与比较
+type restoreConflictChecker struct {
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	milestoneRepo     repository.BabyMilestoneRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+}
+
+func newRestoreConflictChecker(
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+) *restoreConflictChecker {
+	return &restoreConflictChecker{
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		milestoneRepo:     milestoneRepo,
+		scheduleRepo:      scheduleRepo,
+	}
+}
+
+// check 检查待恢复的记录与现有记录是否冲突:
+// 睡眠和喂养时间段重叠、已有进行中的睡眠、同一时间的重复排泄或成长记录、同一模板的里程碑、同一疫苗剂次
+func (c *restoreConflictChecker) check(ctx context.Context, record any) error {
+	lookback := restoreOverlapLookback.Milliseconds()
+
+	switch r := record.(type) {
+	case *entity.FeedingRecord:
+		span := feedingSpan(r)
+		existing, _, err := c.feedingRecordRepo.FindByBabyIDAndType(ctx, r.BabyID, r.FeedingType, span.start-lookback, span.end, 1, 1000)
+		if err != nil {
+			return err
+		}
+		spans := make([]recordSpan, 0, len(existing))
+		for _, other := range existing {
+			if other.ID != r.ID {
+				spans = append(spans, feedingSpan(other))
+			}
+		}
+		if overlapsAny(span, spans) {
+			return errors.New(errors.Conflict, "与现有喂养记录时间重叠,无法恢复")
+		}
+
+	case *entity.SleepRecord:
+		now := time.Now().UnixMilli()
+		span := sleepSpan(r, now)
+		existing, _, err := c.sleepRecordRepo.FindByBabyID(ctx, r.BabyID, span.start-lookback, span.end, 1, 1000)
+		if err != nil {
+			return err
+		}
+		spans := make([]recordSpan, 0, len(existing))
+		for _, other := range existing {
+			if other.ID != r.ID {
+				spans = append(spans, sleepSpan(other, now))
+			}
+		}
+		if overlapsAny(span, spans) {
+			return errors.New(errors.Conflict, "与现有睡眠记录时间重叠,无法恢复")
+		}
+		if sleepOngoing(r) {
+			ongoing, err := c.sleepRecordRepo.FindOngoingSleep(ctx, r.BabyID)
+			if err != nil && !errors.Is(err, errors.ErrRecordNotFound) {
+				return err
+			}
+			if ongoing != nil && ongoing.ID != r.ID {
+				return errors.New(errors.Conflict, "宝宝已有进行中的睡眠,无法恢复")
+			}
+		}
+
+	case *entity.DiaperRecord:
+		existing, _, err := c.diaperRecordRepo.FindByBabyID(ctx, r.BabyID, r.Time, r.Time, 1, 1000)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.ID != r.ID {
+				return errors.New(errors.Conflict, "同一时间已有排泄记录,无法恢复")
+			}
+		}
+
+	case *entity.GrowthRecord:
+		existing, _, err := c.growthRecordRepo.FindByBabyID(ctx, r.BabyID, r.Time, r.Time, 1, 1000)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.ID != r.ID {
+				return errors.New(errors.Conflict, "同一时间已有成长记录,无法恢复")
+			}
+		}
+
+	case *entity.BabyMilestone:
+		if r.TemplateID == nil {
+			return nil
+		}
+		existing, err := c.milestoneRepo.FindByBabyID(ctx, r.BabyID, "")
+		if err != nil {
+			return err
+		}
+		if duplicateMilestone(existing, r) {
+			return errors.New(errors.Conflict, "该里程碑已存在,无法恢复")
+		}
+
+	case *entity.BabyVaccineSchedule:
+		existing, err := c.scheduleRepo.FindByBabyID(ctx, r.BabyID, 1, 1000)
+		if err != nil {
+			return err
+		}
+		if duplicateVaccineDose(existing, r) {
+			return errors.New(errors.Conflict, "该疫苗剂次已存在,无法恢复")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// revisionSystemFields 不计入字段变更、恢复时保持当前值的字段(标识、创建和更新信息、提醒发送状态和汇总字段)
+var revisionSystemFields = map[string]bool{
+	"id":               true,
+	"babyId":           true,
+	"createdBy":        true,
+	"createdByName":    true,
+	"createdByAvatar":  true,
+	"createdAt":        true,
+	"updatedAt":        true,
+	"updatedBy":        true,
+	"nextReminderTime": true,
+	"reminderSent":     true,
+	"reminderTime":     true,
+	"reminderSentAt":   true,
+	"reactionSeverity": true,
+	"reactionSummary":  true,
+}
+
+// revisionAssociationFields 快照中不保存的关联对象
+var revisionAssociationFields = []string{"template", "baby"}
+
+// RevisionService 记录修订历史服务
+// 各类记录和疫苗日程的创建、修改、删除都会追加一条修订,可查看历史并恢复到任一历史版本
+type RevisionService struct {
+	*BaseRecordService
+	revisionRepo      repository.RecordRevisionRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	milestoneRepo     repository.BabyMilestoneRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	conflicts         *restoreConflictChecker
+	restoreHooks      map[string]func(ctx context.Context, record any)
+}
+
+// NewRevisionService 创建记录修订历史服务
+func NewRevisionService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	revisionRepo repository.RecordRevisionRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	logger *zap.Logger,
+) *RevisionService {
+	return &RevisionService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		revisionRepo:      revisionRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		milestoneRepo:     milestoneRepo,
+		scheduleRepo:      scheduleRepo,
+		conflicts:         newRestoreConflictChecker(feedingRecordRepo, sleepRecordRepo, diaperRecordRepo, growthRecordRepo, milestoneRepo, scheduleRepo),
+		restoreHooks:      make(map[string]func(ctx context.Context, record any)),
+	}
+}
+
+// OnRestore 注册某类记录恢复到历史版本后的处理(如重新安排喂养提醒),record 为恢复后的记录实体
+// 由各记录服务在创建时注册,避免修订服务反向依赖定时任务等服务
+func (s *RevisionService) OnRestore(recordType string, hook func(ctx context.Context, record any)) {
+	s.restoreHooks[recordType] = hook
+}
+
+// Record 追加一条修订,before 为操作前的快照(创建时为空);创建和修改后重新读取记录作为操作后的快照
+// 没有实际变化的修改不追加;修订保存失败时返回错误,调用方应使操作失败,保证每次变更都有审计记录
+func (s *RevisionService) Record(ctx context.Context, openID, action, recordType string, recordID int64, before entity.RevisionSnapshot) error {
+	var after entity.RevisionSnapshot
+	if action != entity.RevisionActionDelete {
+		record, _, _, err := s.loadRecord(ctx, recordType, recordID)
+		if err != nil {
+			s.logger.Error("读取记录快照失败",
+				zap.String("recordType", recordType),
+				zap.Int64("recordID", recordID),
+				zap.Error(err))
+			return errors.Wrap(errors.InternalError, "保存修订历史失败", err)
+		}
+		after = revisionSnapshotOf(record)
+	}
+	_, err := s.append(ctx, openID, action, recordType, recordID, before, after, 0)
+	return err
+}
+
+// RecordMany 为一次影响多条记录的操作批量追加修订(批量导入、补种重排、切换疫苗计划等)
+// records 为操作后的记录实体(删除时为删除前的实体),befores 以记录ID索引操作前的快照,创建时可为空。
+// 与 Record 不同,操作后的快照直接取自传入的实体,不再逐条重新读取;保存失败时返回错误
+func (s *RevisionService) RecordMany(ctx context.Context, openID, action, recordType string, records []any, befores map[int64]entity.RevisionSnapshot) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		s.logger.Error("获取修订操作人失败", zap.String("openID", openID), zap.Error(err))
+		return errors.Wrap(errors.InternalError, "保存修订历史失败", err)
+	}
+
+	revisions := make([]*entity.RecordRevision, 0, len(records))
+	for _, record := range records {
+		current := revisionSnapshotOf(record)
+		recordID := snapshotInt64(current, "id")
+		before, after := befores[recordID], current
+		if action == entity.RevisionActionDelete {
+			before, after = current, nil
+		}
+		revision := newRecordRevision(action, recordType, recordID, before, after, 0)
+		if revision == nil {
+			continue
+		}
+		revision.ActorID, revision.ActorName = user.ID, user.NickName
+		revisions = append(revisions, revision)
+	}
+
+	if err := s.revisionRepo.BatchCreate(ctx, revisions); err != nil {
+		s.logger.Error("批量保存记录修订失败",
+			zap.String("recordType", recordType),
+			zap.String("action", action),
+			zap.Int("count", len(revisions)),
+			zap.Error(err))
+		return errors.Wrap(errors.InternalError, "保存修订历史失败", err)
+	}
+	return nil
+}
+
+// GetHistory 获取记录的修订历史(按时间倒序),已删除记录的历史同样可以查看
+func (s *RevisionService) GetHistory(ctx context.Context, openID, recordType, recordID string) (*dto.RecordHistoryResponse, error) {
+	recordIDInt64, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+
+	revisions, err := s.revisionRepo.FindByRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	var babyID int64
+	if len(revisions) > 0 {
+		babyID = revisions[0].BabyID
+	} else {
+		// 功能上线前创建且从未修改过的记录没有修订,按记录本身校验权限
+		_, babyID, _, err = s.loadRecord(ctx, recordType, recordIDInt64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(babyID, 10), openID, recordType, entity.PermissionActionRead); err != nil {
+		return nil, err
+	}
+
+	result := &dto.RecordHistoryResponse{
+		RecordType: recordType,
+		RecordID:   recordID,
+		Revisions:  make([]dto.RecordRevisionDTO, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		result.Revisions = append(result.Revisions, toRecordRevisionDTO(revision))
+	}
+	return result, nil
+}
+
+// RestoreRevision 将记录恢复到某个历史版本,需要修改该记录的权限
+// 标识、创建信息和提醒发送状态保持当前值;恢复本身也会追加一条修订
+// 恢复后的记录与编辑一样需要通过冲突检查;疫苗日程的接种状态和接种日期不能通过恢复修改,
+// 以免绕过补种重排和后续剂次的处理;喂养记录按恢复后的时间重新计算并安排提醒
+func (s *RevisionService) RestoreRevision(ctx context.Context, openID, recordType, recordID string, req *dto.RestoreRevisionRequest) (*dto.RecordRevisionDTO, error) {
+	recordIDInt64, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+	revisionID, err := strconv.ParseInt(req.RevisionID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid revision id format")
+	}
+
+	revision, err := s.revisionRepo.FindByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision.RecordType != recordType || revision.RecordID != recordIDInt64 {
+		return nil, errors.New(errors.ParamError, "修订不属于该记录")
+	}
+
+	current, babyID, ownerID, err := s.loadRecord(ctx, recordType, recordIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckRecordUpdatePermission(ctx, strconv.FormatInt(babyID, 10), openID, recordType, ownerID); err != nil {
+		return nil, err
+	}
+
+	before := revisionSnapshotOf(current)
+	if req.ExpectedUpdatedAt > 0 && snapshotInt64(before, "updatedAt") != req.ExpectedUpdatedAt {
+		return nil, errors.New(errors.Conflict, "记录已被他人修改,请刷新后重试")
+	}
+
+	merged := mergeRevisionSnapshot(before, revision.Snapshot)
+	if len(diffRevisionSnapshots(before, merged)) == 0 {
+		return nil, errors.New(errors.Conflict, "记录已是该版本,无需恢复")
+	}
+
+	restored, err := decodeRevisionRecord(recordType, merged)
+	if err != nil {
+		return nil, err
+	}
+	switch r := restored.(type) {
+	case *entity.BabyVaccineSchedule:
+		if vaccinationChanged(current.(*entity.BabyVaccineSchedule), r) {
+			return nil, errors.New(errors.Conflict, "接种状态和接种日期不能通过恢复历史版本修改,请重新记录接种或跳过")
+		}
+	case *entity.FeedingRecord:
+		restoreFeedingReminder(current.(*entity.FeedingRecord), r)
+	}
+	if err := s.conflicts.check(ctx, restored); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.revisionRepo.RestoreRecord(ctx, restored, snapshotInt64(before, "updatedAt"))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(errors.Conflict, "记录已被他人修改,请刷新后重试")
+	}
+	if hook := s.restoreHooks[recordType]; hook != nil {
+		hook(ctx, restored)
+	}
+
+	created, err := s.append(ctx, openID, entity.RevisionActionRestore, recordType, recordIDInt64, before, revisionSnapshotOf(restored), revision.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("记录已恢复到历史版本",
+		zap.String("recordType", recordType),
+		zap.String("recordID", recordID),
+		zap.Int64("revisionId", revision.ID),
+		zap.String("operator", openID))
+
+	revisionDTO := toRecordRevisionDTO(created)
+	return &revisionDTO, nil
+}
+
+// append 追加修订,返回保存的修订;没有实际变化时返回 nil
+func (s *RevisionService) append(ctx context.Context, openID, action, recordType string, recordID int64, before, after entity.RevisionSnapshot, restoredFrom int64) (*entity.RecordRevision, error) {
+	revision := newRecordRevision(action, recordType, recordID, before, after, restoredFrom)
+	if revision == nil {
+		return nil, nil
+	}
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		s.logger.Error("获取修订操作人失败", zap.String("openID", openID), zap.Error(err))
+		return nil, errors.Wrap(errors.InternalError, "保存修订历史失败", err)
+	}
+	revision.ActorID = user.ID
+	revision.ActorName = user.NickName
+
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		s.logger.Error("保存记录修订失败",
+			zap.String("recordType", recordType),
+			zap.Int64("recordID", recordID),
+			zap.String("action", action),
+			zap.Error(err))
+		return nil, errors.Wrap(errors.InternalError, "保存修订历史失败", err)
+	}
+	return revision, nil
+}
+
+// newRecordRevision 构造修订(不含操作人),没有实际变化的修改返回 nil
+func newRecordRevision(action, recordType string, recordID int64, before, after entity.RevisionSnapshot, restoredFrom int64) *entity.RecordRevision {
+	changes := diffRevisionSnapshots(before, after)
+	if action == entity.RevisionActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	snapshot := after
+	if action == entity.RevisionActionDelete {
+		snapshot = before
+	}
+
+	return &entity.RecordRevision{
+		BabyID:          snapshotInt64(snapshot, "babyId"),
+		RecordType:      recordType,
+		RecordID:        recordID,
+		Action:          action,
+		Snapshot:        snapshot,
+		Changes:         changes,
+		RecordUpdatedAt: snapshotInt64(snapshot, "updatedAt"),
+		RestoredFrom:    restoredFrom,
+	}
+}
+
+// loadRecord 读取未删除的记录,返回记录、所属宝宝ID和创建者用户ID
+func (s *RevisionService) loadRecord(ctx context.Context, recordType string, recordID int64) (any, int64, int64, error) {
+	switch recordType {
+	case entity.AttachmentRecordTypeFeeding:
+		record, err := s.feedingRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return record, record.BabyID, record.CreatedBy, nil
+	case entity.AttachmentRecordTypeSleep:
+		record, err := s.sleepRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return record, record.BabyID, record.CreatedBy, nil
+	case entity.AttachmentRecordTypeDiaper:
+		record, err := s.diaperRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return record, record.BabyID, record.CreatedBy, nil
+	case entity.AttachmentRecordTypeGrowth:
+		record, err := s.growthRecordRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return record, record.BabyID, record.CreatedBy, nil
+	case entity.AttachmentRecordTypeMilestone:
+		milestone, err := s.milestoneRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return milestone, milestone.BabyID, milestone.CreatedBy, nil
+	case entity.AttachmentRecordTypeVaccine:
+		schedule, err := s.scheduleRepo.FindByID(ctx, recordID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return schedule, schedule.BabyID, schedule.CreatedBy, nil
+	default:
+		return nil, 0, 0, errors.New(errors.ParamError, "不支持的记录类型")
+	}
+}
+
+// decodeRevisionRecord 将快照还原为对应类型的记录实体
+func decodeRevisionRecord(recordType string, snapshot entity.RevisionSnapshot) (any, error) {
+	var record any
+	switch recordType {
+	case entity.AttachmentRecordTypeFeeding:
+		record = &entity.FeedingRecord{}
+	case entity.AttachmentRecordTypeSleep:
+		record = &entity.SleepRecord{}
+	case entity.AttachmentRecordTypeDiaper:
+		record = &entity.DiaperRecord{}
+	case entity.AttachmentRecordTypeGrowth:
+		record = &entity.GrowthRecord{}
+	case entity.AttachmentRecordTypeMilestone:
+		record = &entity.BabyMilestone{}
+	case entity.AttachmentRecordTypeVaccine:
+		record = &entity.BabyVaccineSchedule{}
+	default:
+		return nil, errors.New(errors.ParamError, "不支持的记录类型")
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Wrap(errors.InternalError, "解析记录快照失败", err)
+	}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, errors.Wrap(errors.InternalError, "解析记录快照失败", err)
+	}
+	return record, nil
+}
+
+// revisionSnapshotOf 将记录实体序列化为快照(按实体的JSON字段,不含关联对象,数字保留原始精度)
+func revisionSnapshotOf(record any) entity.RevisionSnapshot {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil
+	}
+	snapshot, err := entity.DecodeRevisionSnapshot(data)
+	if err != nil {
+		return nil
+	}
+	for _, field := range revisionAssociationFields {
+		delete(snapshot, field)
+	}
+	return snapshot
+}
+
+// revisionSnapshotsByID 批量生成快照,以记录ID索引,用于 RecordMany 的操作前快照
+func revisionSnapshotsByID[T any](records []*T) map[int64]entity.RevisionSnapshot {
+	snapshots := make(map[int64]entity.RevisionSnapshot, len(records))
+	for _, record := range records {
+		snapshot := revisionSnapshotOf(record)
+		snapshots[snapshotInt64(snapshot, "id")] = snapshot
+	}
+	return snapshots
+}
+
+// revisionRecords 将记录实体切片转换为 RecordMany 的参数
+func revisionRecords[T any](records []*T) []any {
+	result := make([]any, 0, len(records))
+	for _, record := range records {
+		result = append(result, record)
+	}
+	return result
+}
+
+// snapshotInt64 读取快照中的整数字段(宝宝ID、更新时间等)
+func snapshotInt64(snapshot entity.RevisionSnapshot, field string) int64 {
+	if value, ok := snapshot[field].(json.Number); ok {
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// mergeRevisionSnapshot 以历史版本覆盖当前快照的业务字段,系统字段保持当前值
+// 历史版本中不存在的业务字段(如 omitempty 的空值)视为清空
+func mergeRevisionSnapshot(current, revision entity.RevisionSnapshot) entity.RevisionSnapshot {
+	merged := make(entity.RevisionSnapshot, len(current))
+	for field, value := range current {
+		if revisionSystemFields[field] {
+			merged[field] = value
+		}
+	}
+	for field, value := range revision {
+		if !revisionSystemFields[field] {
+			merged[field] = value
+		}
+	}
+	return merged
+}
+
+// diffRevisionSnapshots 比较前后快照的业务字段,嵌套对象展开为点号连接的字段名,按字段名排序
+func diffRevisionSnapshots(before, after entity.RevisionSnapshot) entity.RevisionChanges {
+	oldFields := flattenSnapshot("", before)
+	newFields := flattenSnapshot("", after)
+
+	fields := make(map[string]bool, len(oldFields)+len(newFields))
+	for field := range oldFields {
+		fields[field] = true
+	}
+	for field := range newFields {
+		fields[field] = true
+	}
+
+	changes := entity.RevisionChanges{}
+	for field := range fields {
+		oldValue, newValue := oldFields[field], newFields[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, entity.RevisionFieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// flattenSnapshot 展开嵌套对象,跳过系统字段和空值
+func flattenSnapshot(prefix string, snapshot map[string]any) map[string]any {
+	fields := make(map[string]any)
+	for field, value := range snapshot {
+		if prefix == "" && revisionSystemFields[field] {
+			continue
+		}
+		name := field
+		if prefix != "" {
+			name = prefix + "." + field
+		}
+		switch v := value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			for nestedField, nestedValue := range flattenSnapshot(name, v) {
+				fields[nestedField] = nestedValue
+			}
+		default:
+			fields[name] = v
+		}
+	}
+	return fields
+}
+
+// toRecordRevisionDTO 转换为DTO
+func toRecordRevisionDTO(revision *entity.RecordRevision) dto.RecordRevisionDTO {
+	changes := make([]dto.RevisionFieldChangeDTO, 0, len(revision.Changes))
+	for _, change := range revision.Changes {
+		changes = append(changes, dto.RevisionFieldChangeDTO{Field: change.Field, Old: change.Old, New: change.New})
+	}
+
+	revisionDTO := dto.RecordRevisionDTO{
+		RevisionID:      strconv.FormatInt(revision.ID, 10),
+		RecordType:      revision.RecordType,
+		RecordID:        strconv.FormatInt(revision.RecordID, 10),
+		Action:          revision.Action,
+		ActorID:         strconv.FormatInt(revision.ActorID, 10),
+		ActorName:       revision.ActorName,
+		Changes:         changes,
+		Snapshot:        revision.Snapshot,
+		RecordUpdatedAt: revision.RecordUpdatedAt,
+		CreateTime:      revision.CreatedAt,
+	}
+	if revision.RestoredFrom != 0 {
+		revisionDTO.RestoredFrom = strconv.FormatInt(revision.RestoredFrom, 10)
+	}
+	return revisionDTO
+}
+
+// vaccinationChanged 恢复后的疫苗日程接种状态或接种日期是否与当前不同
+func vaccinationChanged(current, restored *entity.BabyVaccineSchedule) bool {
+	if current.VaccinationStatus != restored.VaccinationStatus {
+		return true
+	}
+	if (current.VaccineDate == nil) != (restored.VaccineDate == nil) {
+		return true
+	}
+	return current.VaccineDate != nil && *current.VaccineDate != *restored.VaccineDate
+}
+
+// restoreFeedingReminder 按恢复后的喂养时间和提醒间隔重新计算下次提醒时间(与编辑喂养记录的规则一致)
+// 提醒时间变化时重置提醒发送状态,以便按新的时间重新提醒
+func restoreFeedingReminder(current, restored *entity.FeedingRecord) {
+	var next *int64
+	if restored.ReminderInterval != nil && *restored.ReminderInterval > 0 {
+		baseTime := restored.Time
+		if restored.ActualCompleteTime != nil {
+			baseTime = *restored.ActualCompleteTime
+		}
+		t := baseTime + int64(*restored.ReminderInterval*60*1000)
+		next = &t
+	}
+
+	unchanged := (next == nil && current.NextReminderTime == nil) ||
+		(next != nil && current.NextReminderTime != nil && *next == *current.NextReminderTime)
+	if unchanged {
+		return
+	}
+	restored.NextReminderTime = next
+	restored.ReminderSent = false
+	restored.ReminderTime = nil
+}
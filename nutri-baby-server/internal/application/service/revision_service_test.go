@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestDiffRevisionSnapshots(t *testing.T) {
+	before := revisionSnapshotOf(&entity.FeedingRecord{
+		ID:          1,
+		BabyID:      2,
+		FeedingType: "bottle",
+		Amount:      120,
+		Detail:      entity.FeedingDetail{"type": "bottle", "amount": 120, "unit": "ml"},
+		UpdatedAt:   1000,
+	})
+	after := revisionSnapshotOf(&entity.FeedingRecord{
+		ID:          1,
+		BabyID:      2,
+		FeedingType: "bottle",
+		Amount:      60,
+		Detail:      entity.FeedingDetail{"type": "bottle", "amount": 60, "unit": "ml"},
+		UpdatedAt:   2000,
+	})
+
+	// 嵌套字段展开为点号字段名,更新时间等系统字段不计入变更
+	changes := diffRevisionSnapshots(before, after)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "amount", changes[0].Field)
+	assert.Equal(t, json.Number("120"), changes[0].Old)
+	assert.Equal(t, json.Number("60"), changes[0].New)
+	assert.Equal(t, "detail.amount", changes[1].Field)
+
+	assert.Empty(t, diffRevisionSnapshots(before, before))
+}
+
+func TestDiffRevisionSnapshotsCreateAndDelete(t *testing.T) {
+	snapshot := revisionSnapshotOf(&entity.SleepRecord{ID: 1, BabyID: 2, Type: "nap", StartTime: 1000})
+
+	// 创建时旧值为空,删除时新值为空
+	created := diffRevisionSnapshots(nil, snapshot)
+	require.NotEmpty(t, created)
+	for _, change := range created {
+		assert.Nil(t, change.Old)
+		assert.NotEqual(t, "id", change.Field)
+		assert.NotEqual(t, "babyId", change.Field)
+	}
+
+	deleted := diffRevisionSnapshots(snapshot, nil)
+	require.Len(t, deleted, len(created))
+	for _, change := range deleted {
+		assert.Nil(t, change.New)
+	}
+}
+
+func TestRevisionSnapshotKeepsLargeIDs(t *testing.T) {
+	const babyID = int64(1876543210987654321)
+	snapshot := revisionSnapshotOf(&entity.DiaperRecord{ID: babyID + 1, BabyID: babyID, UpdatedAt: 1700000000123})
+
+	assert.Equal(t, babyID, snapshotInt64(snapshot, "babyId"))
+	assert.Equal(t, babyID+1, snapshotInt64(snapshot, "id"))
+	assert.Equal(t, int64(1700000000123), snapshotInt64(snapshot, "updatedAt"))
+	assert.Zero(t, snapshotInt64(snapshot, "missing"))
+}
+
+func TestMergeRevisionSnapshot(t *testing.T) {
+	current := revisionSnapshotOf(&entity.FeedingRecord{
+		ID:          1,
+		BabyID:      2,
+		FeedingType: "bottle",
+		Amount:      60,
+		Duration:    300,
+		CreatedBy:   3,
+		UpdatedAt:   2000,
+	})
+	revision := revisionSnapshotOf(&entity.FeedingRecord{
+		ID:          1,
+		BabyID:      2,
+		FeedingType: "bottle",
+		Amount:      120,
+		CreatedBy:   3,
+		UpdatedAt:   1000,
+	})
+
+	merged := mergeRevisionSnapshot(current, revision)
+	assert.Equal(t, json.Number("120"), merged["amount"])
+	// 历史版本中不存在的字段视为清空,系统字段保持当前值
+	assert.NotContains(t, merged, "duration")
+	assert.Equal(t, int64(2000), snapshotInt64(merged, "updatedAt"))
+
+	record, err := decodeRevisionRecord(entity.AttachmentRecordTypeFeeding, merged)
+	require.NoError(t, err)
+	feeding, ok := record.(*entity.FeedingRecord)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), feeding.ID)
+	assert.Equal(t, int64(120), feeding.Amount)
+	assert.Zero(t, feeding.Duration)
+	assert.Equal(t, int64(2000), feeding.UpdatedAt)
+}
+
+func TestNewRecordRevisionForBatch(t *testing.T) {
+	schedules := []*entity.BabyVaccineSchedule{
+		{ID: 1, BabyID: 9, VaccineType: "HepB", DoseNumber: 2, ScheduledDate: 1000, UpdatedAt: 10},
+		{ID: 2, BabyID: 9, VaccineType: "HepB", DoseNumber: 3, ScheduledDate: 2000, UpdatedAt: 10},
+	}
+	befores := revisionSnapshotsByID(schedules)
+	require.Len(t, befores, 2)
+
+	// 补种重排只修改了第2条
+	schedules[1].ScheduledDate = 3000
+	schedules[1].UpdatedAt = 20
+	records := revisionRecords(schedules)
+	require.Len(t, records, 2)
+
+	assert.Nil(t, newRecordRevision(entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine, 1,
+		befores[1], revisionSnapshotOf(records[0]), 0))
+
+	revision := newRecordRevision(entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine, 2,
+		befores[2], revisionSnapshotOf(records[1]), 0)
+	require.NotNil(t, revision)
+	assert.Equal(t, int64(9), revision.BabyID)
+	assert.Equal(t, int64(20), revision.RecordUpdatedAt)
+	require.Len(t, revision.Changes, 1)
+	assert.Equal(t, "scheduledDate", revision.Changes[0].Field)
+
+	// 删除时快照为删除前的记录
+	deleted := newRecordRevision(entity.RevisionActionDelete, entity.AttachmentRecordTypeVaccine, 1, befores[1], nil, 0)
+	require.NotNil(t, deleted)
+	assert.Equal(t, befores[1], deleted.Snapshot)
+}
+
+func TestDecodeRevisionRecordUnknownType(t *testing.T) {
+	_, err := decodeRevisionRecord("unknown", entity.RevisionSnapshot{})
+	assert.ErrorContains(t, err, "不支持的记录类型")
+}
+
+func TestToRecordRevisionDTO(t *testing.T) {
+	revision := &entity.RecordRevision{
+		ID:         10,
+		RecordType: entity.AttachmentRecordTypeFeeding,
+		RecordID:   20,
+		Action:     entity.RevisionActionRestore,
+		ActorID:    30,
+		ActorName:  "妈妈",
+		Changes: entity.RevisionChanges{
+			{Field: "amount", Old: json.Number("60"), New: json.Number("120")},
+		},
+		RestoredFrom: 5,
+	}
+
+	revisionDTO := toRecordRevisionDTO(revision)
+	assert.Equal(t, "10", revisionDTO.RevisionID)
+	assert.Equal(t, "20", revisionDTO.RecordID)
+	assert.Equal(t, "5", revisionDTO.RestoredFrom)
+	require.Len(t, revisionDTO.Changes, 1)
+	assert.Equal(t, "amount", revisionDTO.Changes[0].Field)
+
+	revision.RestoredFrom = 0
+	assert.Empty(t, toRecordRevisionDTO(revision).RestoredFrom)
+}
+
+func TestRestoreFeedingReminder(t *testing.T) {
+	interval := 180
+	next := int64(1000 + 180*60*1000)
+	sentAt := int64(5000)
+	current := &entity.FeedingRecord{ID: 1, Time: 1000, ReminderInterval: &interval, NextReminderTime: &next, ReminderSent: true, ReminderTime: &sentAt}
+
+	// 喂养时间未变时保持原有提醒状态
+	same := *current
+	restoreFeedingReminder(current, &same)
+	assert.Equal(t, *current.NextReminderTime, *same.NextReminderTime)
+	assert.True(t, same.ReminderSent)
+
+	// 喂养时间改变后重新计算提醒时间并重置发送状态
+	moved := *current
+	moved.Time = 2000
+	restoreFeedingReminder(current, &moved)
+	require.NotNil(t, moved.NextReminderTime)
+	assert.Equal(t, int64(2000+180*60*1000), *moved.NextReminderTime)
+	assert.False(t, moved.ReminderSent)
+	assert.Nil(t, moved.ReminderTime)
+
+	// 历史版本没有提醒间隔时取消提醒
+	cleared := *current
+	cleared.ReminderInterval = nil
+	restoreFeedingReminder(current, &cleared)
+	assert.Nil(t, cleared.NextReminderTime)
+}
+
+func TestVaccinationChanged(t *testing.T) {
+	date := int64(1000)
+	other := int64(2000)
+	current := &entity.BabyVaccineSchedule{VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &date}
+
+	assert.False(t, vaccinationChanged(current, &entity.BabyVaccineSchedule{VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &date}))
+	assert.True(t, vaccinationChanged(current, &entity.BabyVaccineSchedule{VaccinationStatus: entity.VaccinationStatusPending}))
+	assert.True(t, vaccinationChanged(current, &entity.BabyVaccineSchedule{VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &other}))
+}
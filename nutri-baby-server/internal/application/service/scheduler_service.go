@@ -19,12 +19,18 @@ type SchedulerService struct {
 	scheduler           *gocron.Scheduler
 	vaccineScheduleRepo repository.BabyVaccineScheduleRepository // 新增: 疫苗接种日程仓储
 	feedingRecordRepo   repository.FeedingRecordRepository
+	followUpRepo        repository.VaccineReactionFollowUpRepository // 接种后反应随访仓储
 	userRepo            repository.UserRepository
 	babyRepo            repository.BabyRepository             // 新增: 宝宝仓储
 	collaboratorRepo    repository.BabyCollaboratorRepository // 协作者仓储
 	subscribeService    *SubscribeService
-	aiAnalysisService   AIAnalysisService // 新增: AI分析服务
+	aiAnalysisService   AIAnalysisService    // 新增: AI分析服务
+	exportService       *ExportService       // 数据导出服务
+	expiryService       *AccessExpiryService // 临时成员到期服务
+	shiftService        *ShiftService        // 值班交接服务(提醒只发给值班照护人)
+	trashService        *TrashService        // 回收站服务
 	strategyFactory     *FeedingReminderStrategyFactory
+	followUpTemplateID  string // 接种反应随访订阅消息模板ID
 	logger              *zap.Logger
 }
 
@@ -32,11 +38,16 @@ type SchedulerService struct {
 func NewSchedulerService(
 	vaccineScheduleRepo repository.BabyVaccineScheduleRepository,
 	feedingRecordRepo repository.FeedingRecordRepository,
+	followUpRepo repository.VaccineReactionFollowUpRepository, // 接种后反应随访仓储
 	userRepo repository.UserRepository,
 	babyRepo repository.BabyRepository,
 	collaboratorRepo repository.BabyCollaboratorRepository, // 协作者仓储
 	subscribeService *SubscribeService,
 	aiAnalysisService AIAnalysisService, // 新增: AI分析服务
+	exportService *ExportService, // 数据导出服务
+	expiryService *AccessExpiryService, // 临时成员到期服务
+	shiftService *ShiftService, // 值班交接服务
+	trashService *TrashService, // 回收站服务
 	cfg *config.Config,
 	logger *zap.Logger,
 ) *SchedulerService {
@@ -47,12 +58,18 @@ func NewSchedulerService(
 		scheduler:           scheduler,
 		vaccineScheduleRepo: vaccineScheduleRepo,
 		feedingRecordRepo:   feedingRecordRepo,
+		followUpRepo:        followUpRepo,
 		userRepo:            userRepo,
 		babyRepo:            babyRepo,
 		collaboratorRepo:    collaboratorRepo,
 		subscribeService:    subscribeService,
 		aiAnalysisService:   aiAnalysisService,
+		exportService:       exportService,
+		expiryService:       expiryService,
+		shiftService:        shiftService,
+		trashService:        trashService,
 		strategyFactory:     NewFeedingReminderStrategyFactory(cfg),
+		followUpTemplateID:  cfg.Wechat.SubscribeTemplates[vaccineFollowUpTemplateType],
 		logger:              logger,
 	}
 }
@@ -78,6 +95,46 @@ func (s *SchedulerService) Start() {
 		s.logger.Info("每日建议自动生成任务已启用 (每天 00:00)")
 	}
 
+	// 每小时清理一次下载链接已过期的导出文件
+	_, err = s.scheduler.Every(1).Hour().Do(s.cleanupExpiredExports)
+	if err != nil {
+		s.logger.Error("添加导出文件清理任务失败", zap.Error(err))
+	} else {
+		s.logger.Info("导出文件清理任务已启用 (每小时一次)")
+	}
+
+	// 每5分钟恢复一次导出任务: 重新入队排队超时的任务,中断的任务标记为失败(服务重启后队列会丢失)
+	_, err = s.scheduler.Every(5).Minutes().Do(s.recoverExports)
+	if err != nil {
+		s.logger.Error("添加导出任务恢复任务失败", zap.Error(err))
+	} else {
+		s.logger.Info("导出任务恢复任务已启用 (每5分钟一次)")
+	}
+
+	// 每30分钟补发一次到期未提醒的接种反应随访(服务重启后一次性任务会丢失)
+	_, err = s.scheduler.Every(30).Minutes().Do(s.sendDueVaccineFollowUps)
+	if err != nil {
+		s.logger.Error("添加接种反应随访补发任务失败", zap.Error(err))
+	} else {
+		s.logger.Info("接种反应随访补发任务已启用 (每30分钟一次)")
+	}
+
+	// 每小时处理一次临时成员: 到期前一天提醒管理员,到期后撤销并通知本人
+	_, err = s.scheduler.Every(1).Hour().Do(s.processTemporaryCollaborators)
+	if err != nil {
+		s.logger.Error("添加临时成员到期任务失败", zap.Error(err))
+	} else {
+		s.logger.Info("临时成员到期任务已启用 (每小时一次)")
+	}
+
+	// 每天凌晨 03:00 彻底删除超过保留期限的回收站记录
+	_, err = s.scheduler.Every(1).Day().At("03:00").Do(s.purgeExpiredTrash)
+	if err != nil {
+		s.logger.Error("添加回收站清理任务失败", zap.Error(err))
+	} else {
+		s.logger.Info("回收站清理任务已启用 (每天 03:00)")
+	}
+
 	s.logger.Info("Scheduler service started with auto-processing enabled")
 }
 
@@ -101,6 +158,49 @@ func (s *SchedulerService) processAIAnalysisTasks() {
 	s.logger.Info("自动处理待分析AI任务成功")
 }
 
+// cleanupExpiredExports 清理过期的导出文件（定时任务回调）
+func (s *SchedulerService) cleanupExpiredExports() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.exportService.CleanupExpiredExports(ctx); err != nil {
+		s.logger.Error("清理过期导出文件失败", zap.Error(err))
+	}
+}
+
+// recoverExports 恢复排队和中断的导出任务（定时任务回调）
+func (s *SchedulerService) recoverExports() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := s.exportService.RecoverExports(ctx); err != nil {
+		s.logger.Error("恢复导出任务失败", zap.Error(err))
+	}
+}
+
+// processTemporaryCollaborators 临时成员到期提醒与撤销（定时任务回调）
+func (s *SchedulerService) processTemporaryCollaborators() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.expiryService.RemindUpcomingExpiries(ctx); err != nil {
+		s.logger.Error("发送临时成员到期提醒失败", zap.Error(err))
+	}
+	if err := s.expiryService.RevokeExpired(ctx); err != nil {
+		s.logger.Error("撤销过期临时成员失败", zap.Error(err))
+	}
+}
+
+// purgeExpiredTrash 彻底删除过期的回收站记录（定时任务回调）
+func (s *SchedulerService) purgeExpiredTrash() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if err := s.trashService.PurgeExpired(ctx); err != nil {
+		s.logger.Error("清理过期回收站记录失败", zap.Error(err))
+	}
+}
+
 // generateDailyTipsForActiveBabies 生成活跃用户的每日建议
 func (s *SchedulerService) generateDailyTipsForActiveBabies() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
@@ -211,7 +311,7 @@ func (s *SchedulerService) AddFeedingReminderTask(ctx context.Context, record *e
 	}
 
 	// 创建任务标签用于识别和取消
-	jobTag := fmt.Sprintf("feeding_reminder_%s", strconv.FormatInt(record.ID, 10))
+	jobTag := feedingReminderJobTag(record.ID)
 
 	// 使用 gocron 的一次性任务 API
 	// StartAt() 指定任务开始时间, LimitRunsTo(1) 限制只执行一次
@@ -237,6 +337,11 @@ func (s *SchedulerService) AddFeedingReminderTask(ctx context.Context, record *e
 	return jobTag, nil
 }
 
+// feedingReminderJobTag 喂养提醒任务的标签
+func feedingReminderJobTag(recordID int64) string {
+	return fmt.Sprintf("feeding_reminder_%s", strconv.FormatInt(recordID, 10))
+}
+
 // CancelFeedingReminderTask 取消喂养提醒任务
 //
 // 如果用户编辑了喂养记录或取消了提醒，可调用此方法取消已添加的任务
@@ -252,7 +357,7 @@ func (s *SchedulerService) CancelFeedingReminderTask(jobTag string) {
 }
 
 // executeFeedingReminder 执行喂养提醒逻辑
-// 向宝宝的所有协作者发送喂养提醒消息
+// 向宝宝的所有协作者发送喂养提醒消息,值班照护人开启"提醒只发给我"时只发给值班照护人
 func (s *SchedulerService) executeFeedingReminder(ctx context.Context, record *entity.FeedingRecord) error {
 	s.logger.Info("开始执行喂养提醒",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
@@ -293,15 +398,19 @@ func (s *SchedulerService) executeFeedingReminder(ctx context.Context, record *e
 	hoursSince := time.Since(lastFeedingTime).Hours()
 	messageData := strategy.BuildMessageData(record, lastFeedingTime, hoursSince)
 
-	// 4. 遍历所有协作者，分别发送消息
+	// 4. 遍历接收提醒的协作者，分别发送消息
 	var sentCount, failCount int
-	for _, collaborator := range collaborators {
+	for _, collaborator := range s.shiftService.ReminderRecipients(ctx, record.BabyID, collaborators, entity.PermissionResourceFeeding) {
 		// 跳过已过期的临时协作者
 		if collaborator.IsExpired() {
 			s.logger.Debug("跳过已过期的临时协作者",
 				zap.Int64("userID", collaborator.UserID))
 			continue
 		}
+		// 跳过无权查看喂养记录的协作者
+		if !collaborator.Can(entity.PermissionResourceFeeding, entity.PermissionActionRead) {
+			continue
+		}
 
 		// 获取协作者的用户信息
 		user, err := s.userRepo.FindByID(ctx, collaborator.UserID)
@@ -385,3 +494,147 @@ func (s *SchedulerService) getTemplateType(feedingType string) string {
 		return ""
 	}
 }
+
+// vaccineFollowUpTemplateType 接种反应随访订阅消息模板类型
+const vaccineFollowUpTemplateType = "vaccine_reaction_followup"
+
+// vaccineFollowUpCatchUpWindow 补发任务只处理最近这段时间内到期的随访,更早的不再打扰
+const vaccineFollowUpCatchUpWindow = 12 * time.Hour
+
+// AddVaccineFollowUpTask 添加接种反应随访一次性提醒任务
+//
+// 在接种记录完成、生成随访时间点后调用,到达 DueAt 时提醒照护人记录体温、红肿和哭闹情况
+func (s *SchedulerService) AddVaccineFollowUpTask(followUp *entity.VaccineReactionFollowUp) (string, error) {
+	executeTime := time.UnixMilli(followUp.DueAt)
+	if executeTime.Before(time.Now()) {
+		return "", nil
+	}
+
+	followUpID := followUp.ID
+	followUpJob := func() {
+		taskCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := s.executeVaccineFollowUp(taskCtx, followUpID); err != nil {
+			s.logger.Error("执行接种反应随访提醒失败",
+				zap.Int64("followUpID", followUpID),
+				zap.Error(err))
+		}
+	}
+
+	jobTag := fmt.Sprintf("vaccine_followup_%d", followUpID)
+	_, err := s.scheduler.Every(1).Second().
+		StartAt(executeTime).
+		LimitRunsTo(1).
+		Tag(jobTag).
+		Do(followUpJob)
+	if err != nil {
+		s.logger.Error("添加接种反应随访任务失败",
+			zap.Int64("followUpID", followUpID),
+			zap.Error(err))
+		return "", err
+	}
+
+	s.logger.Info("添加接种反应随访任务成功",
+		zap.Int64("followUpID", followUpID),
+		zap.String("jobTag", jobTag),
+		zap.Time("executeTime", executeTime))
+
+	return jobTag, nil
+}
+
+// sendDueVaccineFollowUps 补发到期但尚未提醒的接种反应随访（定时任务回调）
+func (s *SchedulerService) sendDueVaccineFollowUps() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	followUps, err := s.followUpRepo.FindDuePrompts(ctx, now.Add(-vaccineFollowUpCatchUpWindow).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		s.logger.Error("查询待提醒的接种反应随访失败", zap.Error(err))
+		return
+	}
+
+	for _, followUp := range followUps {
+		if err := s.executeVaccineFollowUp(ctx, followUp.ID); err != nil {
+			s.logger.Error("补发接种反应随访提醒失败",
+				zap.Int64("followUpID", followUp.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// executeVaccineFollowUp 执行接种反应随访提醒
+// 向宝宝的所有未过期协作者(或开启"提醒只发给我"的值班照护人)发送提醒,已提醒或已记录的随访直接跳过
+func (s *SchedulerService) executeVaccineFollowUp(ctx context.Context, followUpID int64) error {
+	followUp, err := s.followUpRepo.FindByID(ctx, followUpID)
+	if err != nil {
+		return err
+	}
+	if followUp.PromptSentAt != nil || followUp.IsRecorded() {
+		return nil
+	}
+
+	schedule, err := s.vaccineScheduleRepo.FindByID(ctx, followUp.ScheduleID)
+	if err != nil {
+		return err
+	}
+
+	collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, followUp.BabyID)
+	if err != nil {
+		return err
+	}
+
+	messageData := map[string]any{
+		"thing1": fmt.Sprintf("%s第%d剂", schedule.VaccineName, schedule.DoseNumber), // 疫苗名称
+		"thing2": fmt.Sprintf("接种后%d小时随访", followUp.CheckpointHours),               // 随访事项
+		"thing3": "请记录宝宝体温、接种部位红肿和哭闹情况",                                            // 温馨提示
+	}
+
+	var sentCount int
+	for _, collaborator := range s.shiftService.ReminderRecipients(ctx, followUp.BabyID, collaborators, entity.PermissionResourceVaccine) {
+		if !collaborator.Can(entity.PermissionResourceVaccine, entity.PermissionActionRead) {
+			continue
+		}
+
+		user, err := s.userRepo.FindByID(ctx, collaborator.UserID)
+		if err != nil {
+			s.logger.Warn("获取协作者用户信息失败",
+				zap.Int64("userID", collaborator.UserID),
+				zap.Error(err))
+			continue
+		}
+
+		hasAuth, err := s.subscribeService.CheckAuthorizationStatus(ctx, user.OpenID, vaccineFollowUpTemplateType)
+		if err != nil || !hasAuth {
+			continue
+		}
+
+		sendReq := &dto.SendMessageRequest{
+			OpenID:     user.OpenID,
+			TemplateID: s.followUpTemplateID,
+			Data:       messageData,
+			Page:       "pages/vaccine/vaccine",
+		}
+		if err := s.subscribeService.SendSubscribeMessage(ctx, sendReq); err != nil {
+			s.logger.Warn("发送接种反应随访提醒失败",
+				zap.String("openID", user.OpenID),
+				zap.Error(err))
+			continue
+		}
+		sentCount++
+	}
+
+	// 无论是否有人接收都标记已提醒,避免补发任务重复处理
+	if err := s.followUpRepo.MarkPromptSent(ctx, followUp.ID, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+
+	s.logger.Info("接种反应随访提醒发送完成",
+		zap.Int64("followUpID", followUp.ID),
+		zap.Int64("scheduleID", followUp.ScheduleID),
+		zap.Int("checkpointHours", followUp.CheckpointHours),
+		zap.Int("sentCount", sentCount))
+
+	return nil
+}
@@ -0,0 +1,602 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	shareDefaultTTL    = 72 * time.Hour // 分享链接默认有效期
+	shareListLimit     = 50             // 分享链接列表最多返回条数
+	shareRecordLimit   = 1000           // 每类记录最多读取条数
+	shareVaccineLimit  = 1000           // 疫苗日程最多读取条数
+	shareOverview      = "overview"     // 访问日志: 查看宝宝概况
+	shareStatusActive  = "active"
+	shareStatusExpired = "expired"
+	shareStatusRevoked = "revoked"
+)
+
+// shareRecordTypes 分享链接可授权的记录类型(按展示顺序)
+var shareRecordTypes = []string{
+	entity.ShareRecordTypeFeeding,
+	entity.ShareRecordTypeSleep,
+	entity.ShareRecordTypeDiaper,
+	entity.ShareRecordTypeGrowth,
+	entity.ShareRecordTypeVaccine,
+}
+
+// ShareService 只读分享链接服务
+// 管理员为宝宝创建带签名的限时链接,医生或临时保姆无需登录即可查看授权范围内的记录,每次访问都会记录审计日志
+type ShareService struct {
+	*BaseRecordService
+	shareRepo         repository.ShareLinkRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	uploadService     *UploadService
+	secret            string
+}
+
+// NewShareService 创建只读分享链接服务
+func NewShareService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	shareRepo repository.ShareLinkRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	uploadService *UploadService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *ShareService {
+	return &ShareService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		shareRepo:         shareRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		scheduleRepo:      scheduleRepo,
+		uploadService:     uploadService,
+		secret:            cfg.JWT.Secret,
+	}
+}
+
+// CreateShareLink 创建只读分享链接(仅管理员)
+func (s *ShareService) CreateShareLink(ctx context.Context, openID, babyID string, req *dto.CreateShareLinkRequest) (*dto.ShareLinkDTO, error) {
+	babyIDInt64, user, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	startTime, endTime := req.StartTime, req.EndTime
+	if req.RecentDays > 0 {
+		startTime = now.AddDate(0, 0, -req.RecentDays).UnixMilli()
+		endTime = 0
+	}
+	if startTime <= 0 {
+		return nil, errors.New(errors.ParamError, "请指定分享的时间范围")
+	}
+	if endTime > 0 && endTime <= startTime {
+		return nil, errors.New(errors.ParamError, "开始时间必须早于结束时间")
+	}
+
+	ttl := shareDefaultTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	link := &entity.ShareLink{
+		BabyID:      babyIDInt64,
+		CreatedBy:   user.ID,
+		Label:       strings.TrimSpace(req.Label),
+		RecordTypes: strings.Join(normalizeShareRecordTypes(req.RecordTypes), ","),
+		StartTime:   startTime,
+		EndTime:     endTime,
+		ExpiresAt:   now.Add(ttl).UnixMilli(),
+	}
+	if err := s.shareRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("创建分享链接",
+		zap.Int64("shareId", link.ID),
+		zap.String("babyId", babyID),
+		zap.String("recordTypes", link.RecordTypes),
+		zap.Int64("expiresAt", link.ExpiresAt))
+
+	return s.toShareLinkDTO(link, now), nil
+}
+
+// ListShareLinks 获取宝宝的分享链接列表(仅管理员)
+func (s *ShareService) ListShareLinks(ctx context.Context, openID, babyID string) ([]dto.ShareLinkDTO, error) {
+	babyIDInt64, _, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.shareRepo.FindByBabyID(ctx, babyIDInt64, shareListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]dto.ShareLinkDTO, 0, len(links))
+	for _, link := range links {
+		result = append(result, *s.toShareLinkDTO(link, now))
+	}
+	return result, nil
+}
+
+// RevokeShareLink 撤销分享链接(仅管理员),撤销后立即失效
+func (s *ShareService) RevokeShareLink(ctx context.Context, openID, babyID, shareID string) error {
+	link, err := s.findBabyShareLink(ctx, openID, babyID, shareID)
+	if err != nil {
+		return err
+	}
+	if link.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.shareRepo.Revoke(ctx, link.ID, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+
+	s.logger.Info("撤销分享链接", zap.Int64("shareId", link.ID), zap.String("babyId", babyID))
+	return nil
+}
+
+// GetAccessLogs 获取分享链接的访问审计日志(仅管理员)
+func (s *ShareService) GetAccessLogs(ctx context.Context, openID, babyID, shareID string, pagination *dto.PaginationRequest) (*dto.ShareAccessLogListResponse, error) {
+	link, err := s.findBabyShareLink(ctx, openID, babyID, shareID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := pagination.GetPageWithDefault()
+	pageSize := pagination.GetPageSizeWithDefault()
+	logs, total, err := s.shareRepo.FindAccessLogs(ctx, link.ID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ShareAccessLogDTO, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, dto.ShareAccessLogDTO{
+			Resource:   log.Resource,
+			Result:     log.Result,
+			IP:         log.IP,
+			UserAgent:  log.UserAgent,
+			AccessedAt: log.AccessedAt,
+		})
+	}
+
+	return &dto.ShareAccessLogListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetSharedBaby 通过分享令牌查看宝宝概况及分享范围(无需登录)
+func (s *ShareService) GetSharedBaby(ctx context.Context, token string, meta *dto.ShareAccessMeta) (*dto.SharedBabyDTO, error) {
+	link, err := s.resolveToken(ctx, token, shareOverview, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, link.BabyID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, end := shareWindow(link, time.Now())
+	return &dto.SharedBabyDTO{
+		Name:        baby.Name,
+		Nickname:    baby.Nickname,
+		Gender:      baby.Gender,
+		BirthDate:   baby.BirthDate,
+		AvatarURL:   s.uploadService.ResolveURL(baby.AvatarURL),
+		Label:       link.Label,
+		RecordTypes: splitShareRecordTypes(link.RecordTypes),
+		StartTime:   link.StartTime,
+		EndTime:     end,
+		ExpiresAt:   link.ExpiresAt,
+	}, nil
+}
+
+// GetSharedRecords 通过分享令牌查看授权范围内的记录(无需登录)
+// 查询的记录类型和时间范围都会被限制在分享范围内,返回格式与时间线一致
+func (s *ShareService) GetSharedRecords(ctx context.Context, token string, query *dto.SharedRecordsQuery, meta *dto.ShareAccessMeta) (*dto.TimelineResponse, error) {
+	resource := query.RecordType
+	if resource == "" {
+		resource = "all"
+	}
+	link, err := s.resolveToken(ctx, token, resource, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := splitShareRecordTypes(link.RecordTypes)
+	types := allowed
+	if query.RecordType != "" {
+		if !containsString(allowed, query.RecordType) {
+			return nil, errors.New(errors.PermissionDenied, "该分享链接未授权查看此类记录")
+		}
+		types = []string{query.RecordType}
+	}
+
+	start, end := shareWindow(link, time.Now())
+	if query.StartTime > start {
+		start = query.StartTime
+	}
+	if query.EndTime > 0 && query.EndTime < end {
+		end = query.EndTime
+	}
+
+	page := query.GetPageWithDefault()
+	pageSize := query.GetPageSizeWithDefault()
+	response := &dto.TimelineResponse{Items: []dto.TimelineItem{}, Page: page, PageSize: pageSize}
+	if start > end {
+		return response, nil
+	}
+
+	var items []dto.TimelineItem
+	for _, recordType := range types {
+		typeItems, err := s.sharedItems(ctx, link.BabyID, recordType, start, end)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, typeItems...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].EventTime > items[j].EventTime })
+
+	response.Total = int64(len(items))
+	offset := (page - 1) * pageSize
+	if offset < len(items) {
+		response.Items = items[offset:min(offset+pageSize, len(items))]
+	}
+	return response, nil
+}
+
+// sharedItems 读取一类记录并转换为时间线条目,不包含创建者信息
+func (s *ShareService) sharedItems(ctx context.Context, babyID int64, recordType string, start, end int64) ([]dto.TimelineItem, error) {
+	babyIDStr := strconv.FormatInt(babyID, 10)
+	var items []dto.TimelineItem
+	add := func(recordID, eventTime, createTime int64, detail any) {
+		items = append(items, dto.TimelineItem{
+			RecordType: recordType,
+			RecordID:   strconv.FormatInt(recordID, 10),
+			BabyID:     babyIDStr,
+			EventTime:  eventTime,
+			Detail:     detail,
+			CreateTime: createTime,
+		})
+	}
+
+	switch recordType {
+	case entity.ShareRecordTypeFeeding:
+		records, _, err := s.feedingRecordRepo.FindByBabyID(ctx, babyID, start, end, 1, shareRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			detail := dto.FeedingDetail{Type: r.FeedingType}
+			if r.Detail != nil {
+				if raw, err := json.Marshal(r.Detail); err == nil {
+					_ = json.Unmarshal(raw, &detail)
+				}
+			}
+			note := ""
+			if detail.Note != nil {
+				note = *detail.Note
+			}
+			add(r.ID, r.Time, r.CreatedAt, dto.FeedingRecordDTO{
+				RecordID:           strconv.FormatInt(r.ID, 10),
+				BabyID:             babyIDStr,
+				FeedingType:        r.FeedingType,
+				Amount:             r.Amount,
+				Duration:           r.Duration,
+				Detail:             detail,
+				Note:               note,
+				FeedingTime:        r.Time,
+				ActualCompleteTime: r.ActualCompleteTime,
+				CreateTime:         r.CreatedAt,
+			})
+		}
+
+	case entity.ShareRecordTypeSleep:
+		records, _, err := s.sleepRecordRepo.FindByBabyID(ctx, babyID, start, end, 1, shareRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			item := dto.SleepRecordDTO{
+				RecordID:   strconv.FormatInt(r.ID, 10),
+				BabyID:     babyIDStr,
+				StartTime:  r.StartTime,
+				SleepType:  r.Type,
+				CreateTime: r.CreatedAt,
+			}
+			if r.EndTime != nil {
+				item.EndTime = *r.EndTime
+			}
+			if r.Duration != nil {
+				item.Duration = *r.Duration
+			}
+			add(r.ID, r.StartTime, r.CreatedAt, item)
+		}
+
+	case entity.ShareRecordTypeDiaper:
+		records, _, err := s.diaperRecordRepo.FindByBabyID(ctx, babyID, start, end, 1, shareRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			add(r.ID, r.Time, r.CreatedAt, dto.DiaperRecordDTO{
+				RecordID:   strconv.FormatInt(r.ID, 10),
+				BabyID:     babyIDStr,
+				DiaperType: r.Type,
+				Note:       stringValue(r.Note),
+				ChangeTime: r.Time,
+				CreateTime: r.CreatedAt,
+			})
+		}
+
+	case entity.ShareRecordTypeGrowth:
+		records, _, err := s.growthRecordRepo.FindByBabyID(ctx, babyID, start, end, 1, shareRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			add(r.ID, r.Time, r.CreatedAt, dto.GrowthRecordDTO{
+				RecordID:          strconv.FormatInt(r.ID, 10),
+				BabyID:            babyIDStr,
+				Height:            r.Height,
+				Weight:            r.Weight,
+				HeadCircumference: r.HeadCircumference,
+				Note:              stringValue(r.Note),
+				MeasureTime:       r.Time,
+				CreateTime:        r.CreatedAt,
+			})
+		}
+
+	case entity.ShareRecordTypeVaccine:
+		// 疫苗仅分享范围内已完成的接种
+		schedules, err := s.scheduleRepo.FindByBabyID(ctx, babyID, 1, shareVaccineLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range schedules {
+			if v.VaccinationStatus != entity.VaccinationStatusCompleted || v.VaccineDate == nil ||
+				*v.VaccineDate < start || *v.VaccineDate > end {
+				continue
+			}
+			add(v.ID, *v.VaccineDate, v.CreatedAt, dto.VaccineScheduleDTO{
+				ScheduleID:        strconv.FormatInt(v.ID, 10),
+				BabyID:            babyIDStr,
+				VaccineType:       v.VaccineType,
+				VaccineName:       v.VaccineName,
+				Description:       v.Description,
+				AgeInMonths:       v.AgeInMonths,
+				DoseNumber:        v.DoseNumber,
+				IsRequired:        v.IsRequired,
+				IsCustom:          v.IsCustom,
+				VaccinationStatus: v.VaccinationStatus,
+				VaccineDate:       v.VaccineDate,
+				Hospital:          v.Hospital,
+				BatchNumber:       v.BatchNumber,
+				Doctor:            v.Doctor,
+				Reaction:          v.Reaction,
+				Note:              v.Note,
+				CreateTime:        v.CreatedAt,
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// resolveToken 校验分享令牌并记录访问日志
+// 签名无效的请求不写入日志(无法确定所属链接),过期或撤销的访问会以对应结果记录
+func (s *ShareService) resolveToken(ctx context.Context, token, resource string, meta *dto.ShareAccessMeta) (*entity.ShareLink, error) {
+	linkID, expiresAt, err := parseShareToken(s.secret, token)
+	if err != nil {
+		return nil, errors.New(errors.InvalidToken, "分享链接无效")
+	}
+
+	link, err := s.shareRepo.FindByID(ctx, linkID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.NotFound {
+			return nil, errors.New(errors.InvalidToken, "分享链接无效")
+		}
+		return nil, err
+	}
+	if link.ExpiresAt != expiresAt {
+		return nil, errors.New(errors.InvalidToken, "分享链接无效")
+	}
+
+	now := time.Now()
+	result := entity.ShareAccessAllowed
+	switch shareLinkStatus(link, now) {
+	case shareStatusRevoked:
+		result = entity.ShareAccessRevoked
+	case shareStatusExpired:
+		result = entity.ShareAccessExpired
+	}
+
+	accessLog := &entity.ShareAccessLog{
+		ShareLinkID: link.ID,
+		BabyID:      link.BabyID,
+		Resource:    resource,
+		Result:      result,
+		AccessedAt:  now.UnixMilli(),
+	}
+	if meta != nil {
+		accessLog.IP = meta.IP
+		accessLog.UserAgent = truncateRunes(meta.UserAgent, 512)
+	}
+	if err := s.shareRepo.RecordAccess(ctx, accessLog); err != nil {
+		// 审计日志写入失败时拒绝访问,保证每次成功访问都有记录
+		s.logger.Error("记录分享链接访问日志失败", zap.Int64("shareId", link.ID), zap.Error(err))
+		return nil, err
+	}
+
+	switch result {
+	case entity.ShareAccessRevoked:
+		return nil, errors.New(errors.PermissionDenied, "分享链接已被撤销")
+	case entity.ShareAccessExpired:
+		return nil, errors.New(errors.TokenExpired, "分享链接已过期")
+	}
+	return link, nil
+}
+
+// checkBabyAdmin 检查用户是否为宝宝管理员
+func (s *ShareService) checkBabyAdmin(ctx context.Context, babyID, openID string) (int64, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return 0, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	isAdmin, err := s.collaboratorRepo.IsAdmin(ctx, babyIDInt64, user.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !isAdmin {
+		return 0, nil, errors.New(errors.PermissionDenied, "只有管理员可以管理分享链接")
+	}
+
+	return babyIDInt64, user, nil
+}
+
+// findBabyShareLink 查找属于该宝宝的分享链接(仅管理员)
+func (s *ShareService) findBabyShareLink(ctx context.Context, openID, babyID, shareID string) (*entity.ShareLink, error) {
+	babyIDInt64, _, err := s.checkBabyAdmin(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareIDInt64, err := strconv.ParseInt(shareID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid share id format")
+	}
+
+	link, err := s.shareRepo.FindByID(ctx, shareIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	if link.BabyID != babyIDInt64 {
+		return nil, errors.ErrNotFound
+	}
+	return link, nil
+}
+
+func (s *ShareService) toShareLinkDTO(link *entity.ShareLink, now time.Time) *dto.ShareLinkDTO {
+	status := shareLinkStatus(link, now)
+	result := &dto.ShareLinkDTO{
+		ShareID:        strconv.FormatInt(link.ID, 10),
+		BabyID:         strconv.FormatInt(link.BabyID, 10),
+		Label:          link.Label,
+		RecordTypes:    splitShareRecordTypes(link.RecordTypes),
+		StartTime:      link.StartTime,
+		EndTime:        link.EndTime,
+		ExpiresAt:      link.ExpiresAt,
+		RevokedAt:      link.RevokedAt,
+		Status:         status,
+		AccessCount:    link.AccessCount,
+		LastAccessedAt: link.LastAccessedAt,
+		CreateBy:       strconv.FormatInt(link.CreatedBy, 10),
+		CreateTime:     link.CreatedAt,
+	}
+	if status == shareStatusActive {
+		result.Token = signShareToken(s.secret, link.ID, link.ExpiresAt)
+	}
+	return result
+}
+
+// shareLinkStatus 计算分享链接状态
+func shareLinkStatus(link *entity.ShareLink, now time.Time) string {
+	switch {
+	case link.RevokedAt != nil:
+		return shareStatusRevoked
+	case now.UnixMilli() >= link.ExpiresAt:
+		return shareStatusExpired
+	}
+	return shareStatusActive
+}
+
+// shareWindow 计算分享链接当前可查看的时间范围
+func shareWindow(link *entity.ShareLink, now time.Time) (start, end int64) {
+	end = link.EndTime
+	if end <= 0 || end > now.UnixMilli() {
+		end = now.UnixMilli()
+	}
+	return link.StartTime, end
+}
+
+// normalizeShareRecordTypes 去重并按固定顺序排列记录类型
+func normalizeShareRecordTypes(types []string) []string {
+	var result []string
+	for _, t := range shareRecordTypes {
+		if containsString(types, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func splitShareRecordTypes(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// shareTokenSignatureLen 令牌签名长度(字节),截取 HMAC-SHA256 前 16 字节
+const shareTokenSignatureLen = 16
+
+// signShareToken 生成分享令牌
+// 格式: base64url("<链接ID>.<过期时间>") + "." + base64url(签名),过期时间写入令牌,篡改后签名失效
+func signShareToken(secret string, linkID, expiresAt int64) string {
+	payload := fmt.Sprintf("%d.%d", linkID, expiresAt)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(shareTokenSignature(secret, payload))
+}
+
+// parseShareToken 校验令牌签名并解析出链接ID与过期时间
+func parseShareToken(secret, token string) (linkID, expiresAt int64, err error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed share token")
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed share token payload")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed share token signature")
+	}
+	payload := string(payloadBytes)
+	if !hmac.Equal(sig, shareTokenSignature(secret, payload)) {
+		return 0, 0, fmt.Errorf("invalid share token signature")
+	}
+
+	idPart, expPart, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed share token payload")
+	}
+	if linkID, err = strconv.ParseInt(idPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("malformed share token id")
+	}
+	if expiresAt, err = strconv.ParseInt(expPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("malformed share token expiry")
+	}
+	return linkID, expiresAt, nil
+}
+
+func shareTokenSignature(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte("share-link:"+secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)[:shareTokenSignatureLen]
+}
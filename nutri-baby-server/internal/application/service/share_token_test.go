@@ -0,0 +1,34 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareToken(t *testing.T) {
+	token := signShareToken("secret", 12345, 1700000000000)
+
+	linkID, expiresAt, err := parseShareToken("secret", token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12345), linkID)
+	assert.Equal(t, int64(1700000000000), expiresAt)
+
+	// 密钥不同
+	_, _, err = parseShareToken("other", token)
+	assert.Error(t, err)
+
+	// 篡改过期时间
+	forged := signShareToken("other", 12345, 1900000000000)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, sig, _ := strings.Cut(token, ".")
+	_, _, err = parseShareToken("secret", payload+"."+sig)
+	assert.Error(t, err)
+
+	for _, bad := range []string{"", "abc", "abc.def", "!!.!!"} {
+		_, _, err = parseShareToken("secret", bad)
+		assert.Error(t, err, bad)
+	}
+}
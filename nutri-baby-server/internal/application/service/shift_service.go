@@ -0,0 +1,814 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// shiftHandoffTemplateType 交接摘要订阅消息模板类型
+const shiftHandoffTemplateType = "shift_handoff_summary"
+
+// 交接摘要的时间范围
+const (
+	handoffDefaultWindow         = 12 * time.Hour      // 没有上一位照护人的值班时,汇总最近这段时间
+	handoffReminderHorizon       = 12 * time.Hour      // 待办提醒: 已到期或在这段时间内到期
+	handoffVaccineOverdueWindow  = 30 * 24 * time.Hour // 逾期更久的疫苗不再出现在交接摘要中
+	handoffFeedingGapNoticeAfter = 4 * time.Hour       // 距上次喂养超过该时长时提醒留意
+	handoffRecordLimit           = 200                 // 每类记录最多汇总条数
+	shiftListLimit               = 30                  // 值班记录列表条数
+)
+
+// ShiftService 照护人值班交接服务
+// 同一宝宝同时最多一位照护人值班,新照护人开始值班即完成交接并获得上一位照护人值班以来的交接摘要
+type ShiftService struct {
+	babyRepo            repository.BabyRepository
+	collaboratorRepo    repository.BabyCollaboratorRepository
+	shiftRepo           repository.CaregiverShiftRepository
+	userRepo            repository.UserRepository
+	feedingRecordRepo   repository.FeedingRecordRepository
+	sleepRecordRepo     repository.SleepRecordRepository
+	diaperRecordRepo    repository.DiaperRecordRepository
+	growthRecordRepo    repository.GrowthRecordRepository
+	milestoneRepo       repository.BabyMilestoneRepository
+	vaccineScheduleRepo repository.BabyVaccineScheduleRepository
+	followUpRepo        repository.VaccineReactionFollowUpRepository
+	subscribeService    *SubscribeService
+	permissions         *PermissionService
+	handoffTemplateID   string
+	logger              *zap.Logger
+}
+
+// NewShiftService 创建照护人值班交接服务
+func NewShiftService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	shiftRepo repository.CaregiverShiftRepository,
+	userRepo repository.UserRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	vaccineScheduleRepo repository.BabyVaccineScheduleRepository,
+	followUpRepo repository.VaccineReactionFollowUpRepository,
+	subscribeService *SubscribeService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *ShiftService {
+	return &ShiftService{
+		babyRepo:            babyRepo,
+		collaboratorRepo:    collaboratorRepo,
+		shiftRepo:           shiftRepo,
+		userRepo:            userRepo,
+		feedingRecordRepo:   feedingRecordRepo,
+		sleepRecordRepo:     sleepRecordRepo,
+		diaperRecordRepo:    diaperRecordRepo,
+		growthRecordRepo:    growthRecordRepo,
+		milestoneRepo:       milestoneRepo,
+		vaccineScheduleRepo: vaccineScheduleRepo,
+		followUpRepo:        followUpRepo,
+		subscribeService:    subscribeService,
+		permissions:         NewPermissionService(collaboratorRepo, userRepo, logger),
+		handoffTemplateID:   cfg.Wechat.SubscribeTemplates[shiftHandoffTemplateType],
+		logger:              logger,
+	}
+}
+
+// StartShift 开始值班,其他照护人未结束的值班自动结束(交接),返回上一位照护人值班以来的交接摘要
+func (s *ShiftService) StartShift(ctx context.Context, openID, babyID string, req *dto.StartShiftRequest) (*dto.StartShiftResponse, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkShiftCaregiver(collaborator); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	onDuty, err := s.shiftRepo.FindOnDuty(ctx, babyIDInt64, onDutySince(now))
+	if err != nil {
+		return nil, err
+	}
+	if onDuty != nil && onDuty.UserID == collaborator.UserID {
+		return nil, errors.New(errors.Conflict, "您已在值班中")
+	}
+
+	if err := s.shiftRepo.EndOpenByBaby(ctx, babyIDInt64, now); err != nil {
+		return nil, err
+	}
+
+	shift := &entity.CaregiverShift{
+		BabyID:         babyIDInt64,
+		UserID:         collaborator.UserID,
+		StartTime:      now,
+		RouteReminders: req.RouteReminders,
+	}
+	if err := s.shiftRepo.Create(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	members, err := s.shiftMembers(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	handoff, err := s.buildHandoffSummary(ctx, babyIDInt64, collaborator.UserID, readableResources(collaborator), members, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("照护人开始值班",
+		zap.Int64("babyID", babyIDInt64),
+		zap.Int64("userID", collaborator.UserID),
+		zap.Bool("routeReminders", req.RouteReminders))
+
+	return &dto.StartShiftResponse{
+		Shift:   s.toShiftDTO(ctx, shift, members, now),
+		Handoff: handoff,
+	}, nil
+}
+
+// EndShift 结束本人的值班,指定接班照护人时向其推送交接摘要
+func (s *ShiftService) EndShift(ctx context.Context, openID, babyID string, req *dto.EndShiftRequest) (*dto.EndShiftResponse, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	shift, err := s.shiftRepo.FindOnDuty(ctx, babyIDInt64, onDutySince(now))
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil || shift.UserID != collaborator.UserID {
+		return nil, errors.New(errors.Conflict, "您当前不在值班中")
+	}
+
+	var incoming *entity.BabyCollaborator
+	var incomingUser *entity.User
+	if req.HandoffToOpenID != "" {
+		incoming, incomingUser, err = s.findIncomingCaregiver(ctx, babyIDInt64, collaborator.UserID, req.HandoffToOpenID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	note := strings.TrimSpace(req.HandoffNote)
+	ended, err := s.shiftRepo.End(ctx, shift.ID, now, note)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, errors.New(errors.Conflict, "值班已结束")
+	}
+	shift.EndTime = &now
+	shift.HandoffNote = note
+
+	members, err := s.shiftMembers(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.EndShiftResponse{Shift: s.toShiftDTO(ctx, shift, members, now)}
+
+	if incoming != nil {
+		// 摘要同时返回给交班人,只包含双方都能查看的记录类型
+		readable := intersectReadable(readableResources(collaborator), readableResources(incoming))
+		handoff, err := s.buildHandoffSummary(ctx, babyIDInt64, incoming.UserID, readable, members, now)
+		if err != nil {
+			return nil, err
+		}
+		resp.Handoff = handoff
+		resp.Pushed = s.pushHandoff(ctx, babyIDInt64, incomingUser, handoff)
+	}
+
+	s.logger.Info("照护人结束值班",
+		zap.Int64("babyID", babyIDInt64),
+		zap.Int64("userID", collaborator.UserID),
+		zap.String("handoffTo", req.HandoffToOpenID),
+		zap.Bool("pushed", resp.Pushed))
+
+	return resp, nil
+}
+
+// GetOnDuty 获取宝宝当前值班的照护人,无人值班时返回 nil
+func (s *ShiftService) GetOnDuty(ctx context.Context, openID, babyID string) (*dto.CaregiverShiftDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	if _, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	shift, err := s.shiftRepo.FindOnDuty(ctx, babyIDInt64, onDutySince(now))
+	if err != nil || shift == nil {
+		return nil, err
+	}
+
+	members, err := s.shiftMembers(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	return s.toShiftDTO(ctx, shift, members, now), nil
+}
+
+// ListShifts 获取宝宝最近的值班记录
+func (s *ShiftService) ListShifts(ctx context.Context, openID, babyID string) ([]dto.CaregiverShiftDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	if _, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID); err != nil {
+		return nil, err
+	}
+
+	shifts, err := s.shiftRepo.FindRecentByBaby(ctx, babyIDInt64, shiftListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.shiftMembers(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	result := make([]dto.CaregiverShiftDTO, 0, len(shifts))
+	for _, shift := range shifts {
+		result = append(result, *s.toShiftDTO(ctx, shift, members, now))
+	}
+	return result, nil
+}
+
+// GetHandoffSummary 获取当前用户的交接摘要(上一位照护人开始值班以来的记录和待办提醒)
+func (s *ShiftService) GetHandoffSummary(ctx context.Context, openID, babyID string) (*dto.HandoffSummaryDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.shiftMembers(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildHandoffSummary(ctx, babyIDInt64, collaborator.UserID, readableResources(collaborator), members, time.Now().UnixMilli())
+}
+
+// ReminderRecipients 筛选提醒接收人
+// 值班照护人开启了"提醒只发给我"且有权查看该类记录时只发给值班照护人,否则发给全部协作者
+func (s *ShiftService) ReminderRecipients(ctx context.Context, babyID int64, collaborators []*entity.BabyCollaborator, resource string) []*entity.BabyCollaborator {
+	onDuty, err := s.shiftRepo.FindOnDuty(ctx, babyID, onDutySince(time.Now().UnixMilli()))
+	if err != nil {
+		s.logger.Warn("查询值班照护人失败,提醒发给全部协作者",
+			zap.Int64("babyID", babyID),
+			zap.Error(err))
+		return collaborators
+	}
+	return routeToOnDuty(collaborators, onDuty, resource)
+}
+
+// findIncomingCaregiver 校验接班照护人: 宝宝的有效非只读成员且不是交班人本人
+func (s *ShiftService) findIncomingCaregiver(ctx context.Context, babyID, outgoingUserID int64, targetOpenID string) (*entity.BabyCollaborator, *entity.User, error) {
+	user, err := s.userRepo.FindByOpenID(ctx, targetOpenID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.ID == outgoingUserID {
+		return nil, nil, errors.New(errors.ParamError, "不能交接给自己")
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyID, user.ID)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, nil, err
+	}
+	if collaborator == nil || collaborator.IsExpired() {
+		return nil, nil, errors.New(errors.ParamError, "接班人不是该宝宝的有效成员")
+	}
+	if err := checkShiftCaregiver(collaborator); err != nil {
+		return nil, nil, err
+	}
+	return collaborator, user, nil
+}
+
+// buildHandoffSummary 为接班照护人生成交接摘要,只加载 readable 中的记录类型
+func (s *ShiftService) buildHandoffSummary(
+	ctx context.Context,
+	babyID, incomingUserID int64,
+	readable map[string]bool,
+	members map[int64]*entity.BabyCollaborator,
+	now int64,
+) (*dto.HandoffSummaryDTO, error) {
+	previous, err := s.shiftRepo.FindLatestByOtherUser(ctx, babyID, incomingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := handoffSince(previous, now)
+	records, err := s.loadHandoffRecords(ctx, babyID, readable, since, now)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeHandoff(babyID, since, now, previous, records)
+	if previous != nil {
+		summary.PreviousShift = s.toShiftDTO(ctx, previous, members, now)
+	}
+	return summary, nil
+}
+
+// loadHandoffRecords 加载交接区间内的记录和待办提醒
+func (s *ShiftService) loadHandoffRecords(ctx context.Context, babyID int64, readable map[string]bool, since, now int64) (*handoffRecords, error) {
+	records := &handoffRecords{followUpVaccines: make(map[int64]*entity.BabyVaccineSchedule)}
+	var err error
+
+	if readable[entity.PermissionResourceFeeding] {
+		records.lastFeeding, err = s.feedingRecordRepo.FindLatestRecord(ctx, babyID)
+		if err != nil && !errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, err
+		}
+		if records.feedings, _, err = s.feedingRecordRepo.FindByBabyID(ctx, babyID, since, now, 1, handoffRecordLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if readable[entity.PermissionResourceSleep] {
+		if records.ongoingSleep, err = s.sleepRecordRepo.FindOngoingSleep(ctx, babyID); err != nil {
+			return nil, err
+		}
+		if records.sleeps, _, err = s.sleepRecordRepo.FindByBabyID(ctx, babyID, since, now, 1, handoffRecordLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if readable[entity.PermissionResourceDiaper] {
+		if records.diapers, _, err = s.diaperRecordRepo.FindByBabyID(ctx, babyID, since, now, 1, handoffRecordLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if readable[entity.PermissionResourceGrowth] {
+		if records.growths, _, err = s.growthRecordRepo.FindByBabyID(ctx, babyID, since, now, 1, handoffRecordLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if readable[entity.PermissionResourceMilestone] {
+		if records.milestones, err = s.milestoneRepo.FindAchievedByBabyID(ctx, babyID, since, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if readable[entity.PermissionResourceVaccine] {
+		horizon := now + handoffReminderHorizon.Milliseconds()
+		_, schedules, err := s.vaccineScheduleRepo.FindByBabyIDAndStatus(ctx, babyID, entity.VaccinationStatusPending, 1, handoffRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, schedule := range schedules {
+			if schedule.ScheduledDate <= horizon && schedule.ScheduledDate >= now-handoffVaccineOverdueWindow.Milliseconds() {
+				records.vaccines = append(records.vaccines, schedule)
+			}
+		}
+
+		if records.followUps, err = s.followUpRepo.FindUnrecordedByBabyID(ctx, babyID, horizon); err != nil {
+			return nil, err
+		}
+		for _, followUp := range records.followUps {
+			if _, ok := records.followUpVaccines[followUp.ScheduleID]; ok {
+				continue
+			}
+			schedule, err := s.vaccineScheduleRepo.FindByID(ctx, followUp.ScheduleID)
+			if err != nil {
+				s.logger.Warn("获取随访对应的疫苗日程失败",
+					zap.Int64("scheduleID", followUp.ScheduleID),
+					zap.Error(err))
+				continue
+			}
+			records.followUpVaccines[followUp.ScheduleID] = schedule
+		}
+	}
+
+	return records, nil
+}
+
+// pushHandoff 向接班照护人推送交接摘要,对方未授权订阅消息时返回 false
+func (s *ShiftService) pushHandoff(ctx context.Context, babyID int64, user *entity.User, summary *dto.HandoffSummaryDTO) bool {
+	hasAuth, err := s.subscribeService.CheckAuthorizationStatus(ctx, user.OpenID, shiftHandoffTemplateType)
+	if err != nil || !hasAuth {
+		return false
+	}
+
+	babyName := ""
+	if baby, err := s.babyRepo.FindByID(ctx, babyID); err == nil {
+		babyName = baby.Name
+	}
+
+	sendReq := &dto.SendMessageRequest{
+		OpenID:     user.OpenID,
+		TemplateID: s.handoffTemplateID,
+		Data:       handoffMessageData(babyName, summary),
+		Page:       fmt.Sprintf("pages/shift/handoff?babyId=%d", babyID),
+	}
+	if err := s.subscribeService.SendSubscribeMessage(ctx, sendReq); err != nil {
+		s.logger.Warn("推送交接摘要失败",
+			zap.String("openID", user.OpenID),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// shiftMembers 宝宝的协作者(按用户ID索引),用于填充值班人的昵称和关系
+func (s *ShiftService) shiftMembers(ctx context.Context, babyID int64) (map[int64]*entity.BabyCollaborator, error) {
+	collaborators, err := s.collaboratorRepo.FindByBabyID(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int64]*entity.BabyCollaborator, len(collaborators))
+	for _, collaborator := range collaborators {
+		members[collaborator.UserID] = collaborator
+	}
+	return members, nil
+}
+
+// toShiftDTO 转换值班DTO,已离开宝宝的成员从用户表补充昵称
+func (s *ShiftService) toShiftDTO(ctx context.Context, shift *entity.CaregiverShift, members map[int64]*entity.BabyCollaborator, now int64) *dto.CaregiverShiftDTO {
+	result := &dto.CaregiverShiftDTO{
+		ShiftID:        strconv.FormatInt(shift.ID, 10),
+		BabyID:         strconv.FormatInt(shift.BabyID, 10),
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		OnDuty:         shift.IsOnDuty(now),
+		RouteReminders: shift.RouteReminders,
+		HandoffNote:    shift.HandoffNote,
+	}
+
+	var user *entity.User
+	if member, ok := members[shift.UserID]; ok {
+		result.Relationship = member.Relationship
+		user = member.User
+	}
+	if user == nil {
+		found, err := s.userRepo.FindByID(ctx, shift.UserID)
+		if err != nil {
+			s.logger.Warn("获取值班照护人信息失败", zap.Int64("userID", shift.UserID), zap.Error(err))
+			return result
+		}
+		user = found
+	}
+
+	result.OpenID = user.OpenID
+	result.NickName = user.NickName
+	result.AvatarURL = user.AvatarURL
+	return result
+}
+
+// handoffRecords 生成交接摘要所需的记录
+type handoffRecords struct {
+	lastFeeding      *entity.FeedingRecord
+	ongoingSleep     *entity.SleepRecord
+	feedings         []*entity.FeedingRecord
+	sleeps           []*entity.SleepRecord
+	diapers          []*entity.DiaperRecord
+	growths          []*entity.GrowthRecord
+	milestones       []*entity.BabyMilestone
+	vaccines         []*entity.BabyVaccineSchedule
+	followUps        []*entity.VaccineReactionFollowUp
+	followUpVaccines map[int64]*entity.BabyVaccineSchedule // 随访对应的疫苗日程(按日程ID索引)
+}
+
+// summarizeHandoff 汇总交接摘要: 记录统计、待办提醒和需要留意的情况
+func summarizeHandoff(babyID, since, now int64, previous *entity.CaregiverShift, records *handoffRecords) *dto.HandoffSummaryDTO {
+	summary := &dto.HandoffSummaryDTO{
+		BabyID:           strconv.FormatInt(babyID, 10),
+		Since:            since,
+		GeneratedAt:      now,
+		Records:          []dto.HandoffRecordDTO{},
+		PendingReminders: []dto.HandoffReminderDTO{},
+		Notices:          []string{},
+	}
+
+	if previous != nil && previous.HandoffNote != "" {
+		summary.Notices = append(summary.Notices, "交班备注: "+previous.HandoffNote)
+	}
+
+	if records.lastFeeding != nil {
+		item := feedingHandoffRecord(records.lastFeeding)
+		summary.LastFeeding = &item
+
+		if gap := time.Duration(now-records.lastFeeding.Time) * time.Millisecond; gap > handoffFeedingGapNoticeAfter {
+			summary.Notices = append(summary.Notices, fmt.Sprintf("距上次喂养已超过 %d 小时", int(gap.Hours())))
+		}
+		if next := records.lastFeeding.NextReminderTime; next != nil && !records.lastFeeding.ReminderSent {
+			summary.PendingReminders = append(summary.PendingReminders, dto.HandoffReminderDTO{
+				ReminderType: entity.PermissionResourceFeeding,
+				Title:        "下次喂养提醒",
+				DueAt:        *next,
+				Overdue:      *next < now,
+			})
+		}
+	}
+
+	if records.ongoingSleep != nil {
+		item := sleepHandoffRecord(records.ongoingSleep)
+		summary.CurrentSleep = &item
+	}
+
+	for _, record := range records.feedings {
+		summary.Counts.Feedings++
+		if record.FeedingType == entity.FeedingTypeBottle {
+			summary.Counts.BottleAmount += record.Amount
+		}
+		summary.Records = append(summary.Records, feedingHandoffRecord(record))
+	}
+
+	for _, record := range records.sleeps {
+		summary.Counts.Sleeps++
+		if !sleepOngoing(record) && record.Duration != nil {
+			summary.Counts.SleepMinutes += *record.Duration / 60
+		}
+		summary.Records = append(summary.Records, sleepHandoffRecord(record))
+	}
+
+	for _, record := range records.diapers {
+		summary.Counts.Diapers++
+		if record.Type == entity.DiaperTypePee || record.Type == entity.DiaperTypeBoth {
+			summary.Counts.WetDiapers++
+		}
+		if record.Type == entity.DiaperTypePoop || record.Type == entity.DiaperTypeBoth {
+			summary.Counts.DirtyDiapers++
+		}
+		summary.Records = append(summary.Records, diaperHandoffRecord(record))
+	}
+
+	for _, record := range records.growths {
+		summary.Records = append(summary.Records, growthHandoffRecord(record))
+	}
+
+	for _, milestone := range records.milestones {
+		if milestone.AchievedDate == nil {
+			continue
+		}
+		summary.Records = append(summary.Records, dto.HandoffRecordDTO{
+			RecordType: entity.PermissionResourceMilestone,
+			RecordID:   strconv.FormatInt(milestone.ID, 10),
+			EventTime:  *milestone.AchievedDate,
+			Summary:    "达成里程碑: " + milestone.Title,
+			Note:       stringValue(milestone.Note),
+		})
+	}
+
+	sort.SliceStable(summary.Records, func(i, j int) bool {
+		return summary.Records[i].EventTime > summary.Records[j].EventTime
+	})
+	for _, record := range summary.Records {
+		if record.Note != "" {
+			summary.Notices = append(summary.Notices, fmt.Sprintf("%s %s: %s",
+				time.UnixMilli(record.EventTime).Format("15:04"), record.Summary, record.Note))
+		}
+	}
+
+	for _, schedule := range records.vaccines {
+		summary.PendingReminders = append(summary.PendingReminders, dto.HandoffReminderDTO{
+			ReminderType: entity.PermissionResourceVaccine,
+			Title:        fmt.Sprintf("%s第%d剂", schedule.VaccineName, schedule.DoseNumber),
+			DueAt:        schedule.ScheduledDate,
+			Overdue:      schedule.ScheduledDate < now,
+		})
+	}
+
+	for _, followUp := range records.followUps {
+		title := fmt.Sprintf("接种后%d小时随访", followUp.CheckpointHours)
+		if schedule, ok := records.followUpVaccines[followUp.ScheduleID]; ok {
+			title = fmt.Sprintf("%s第%d剂 %s", schedule.VaccineName, schedule.DoseNumber, title)
+		}
+		summary.PendingReminders = append(summary.PendingReminders, dto.HandoffReminderDTO{
+			ReminderType: "vaccine_followup",
+			Title:        title,
+			DueAt:        followUp.DueAt,
+			Overdue:      followUp.DueAt < now,
+		})
+	}
+
+	sort.SliceStable(summary.PendingReminders, func(i, j int) bool {
+		return summary.PendingReminders[i].DueAt < summary.PendingReminders[j].DueAt
+	})
+
+	return summary
+}
+
+// feedingHandoffRecord 喂养记录摘要,如 "奶瓶 120ml"
+func feedingHandoffRecord(record *entity.FeedingRecord) dto.HandoffRecordDTO {
+	var text string
+	switch record.FeedingType {
+	case entity.FeedingTypeBreast:
+		text = fmt.Sprintf("母乳 %d分钟", record.Duration/60)
+	case entity.FeedingTypeBottle:
+		text = fmt.Sprintf("奶瓶 %dml", record.Amount)
+	case entity.FeedingTypeFood:
+		text = "辅食"
+		if name, ok := record.Detail["foodName"].(string); ok && name != "" {
+			text += " " + name
+		}
+	default:
+		text = "喂养"
+	}
+
+	note, _ := record.Detail["note"].(string)
+	return dto.HandoffRecordDTO{
+		RecordType:    entity.PermissionResourceFeeding,
+		RecordID:      strconv.FormatInt(record.ID, 10),
+		EventTime:     record.Time,
+		Summary:       text,
+		Note:          note,
+		CreatedByName: record.CreatedByName,
+	}
+}
+
+// sleepHandoffRecord 睡眠记录摘要,如 "小睡 45分钟"
+func sleepHandoffRecord(record *entity.SleepRecord) dto.HandoffRecordDTO {
+	text := "小睡"
+	if record.Type == entity.SleepTypeNight {
+		text = "夜间睡眠"
+	}
+
+	item := dto.HandoffRecordDTO{
+		RecordType:    entity.PermissionResourceSleep,
+		RecordID:      strconv.FormatInt(record.ID, 10),
+		EventTime:     record.StartTime,
+		CreatedByName: record.CreatedByName,
+	}
+	if sleepOngoing(record) {
+		item.Summary = text + " 进行中"
+		return item
+	}
+
+	item.EndTime = record.EndTime
+	if record.Duration != nil {
+		text = fmt.Sprintf("%s %d分钟", text, *record.Duration/60)
+	}
+	item.Summary = text
+	return item
+}
+
+// sleepOngoing 睡眠是否仍在进行(进行中的记录 end_time 为 0)
+func sleepOngoing(record *entity.SleepRecord) bool {
+	return record.EndTime == nil || *record.EndTime == 0
+}
+
+// diaperHandoffRecord 换尿布记录摘要
+func diaperHandoffRecord(record *entity.DiaperRecord) dto.HandoffRecordDTO {
+	text := "换尿布"
+	switch record.Type {
+	case entity.DiaperTypePee:
+		text = "换尿布(小便)"
+	case entity.DiaperTypePoop:
+		text = "换尿布(大便)"
+	case entity.DiaperTypeBoth:
+		text = "换尿布(大小便)"
+	}
+
+	return dto.HandoffRecordDTO{
+		RecordType:    entity.PermissionResourceDiaper,
+		RecordID:      strconv.FormatInt(record.ID, 10),
+		EventTime:     record.Time,
+		Summary:       text,
+		Note:          stringValue(record.Note),
+		CreatedByName: record.CreatedByName,
+	}
+}
+
+// growthHandoffRecord 成长记录摘要,如 "体重 6.2kg 身高 62.0cm"
+func growthHandoffRecord(record *entity.GrowthRecord) dto.HandoffRecordDTO {
+	var parts []string
+	if record.Weight != nil {
+		parts = append(parts, fmt.Sprintf("体重 %.1fkg", *record.Weight))
+	}
+	if record.Height != nil {
+		parts = append(parts, fmt.Sprintf("身高 %.1fcm", *record.Height))
+	}
+	if record.HeadCircumference != nil {
+		parts = append(parts, fmt.Sprintf("头围 %.1fcm", *record.HeadCircumference))
+	}
+	text := "成长记录"
+	if len(parts) > 0 {
+		text = strings.Join(parts, " ")
+	}
+
+	return dto.HandoffRecordDTO{
+		RecordType:    entity.PermissionResourceGrowth,
+		RecordID:      strconv.FormatInt(record.ID, 10),
+		EventTime:     record.Time,
+		Summary:       text,
+		Note:          stringValue(record.Note),
+		CreatedByName: record.CreatedByName,
+	}
+}
+
+// handoffMessageData 交接摘要订阅消息内容
+func handoffMessageData(babyName string, summary *dto.HandoffSummaryDTO) map[string]any {
+	lastFeeding := "暂无喂养记录"
+	if summary.LastFeeding != nil {
+		lastFeeding = time.UnixMilli(summary.LastFeeding.EventTime).Format("15:04") + " " + summary.LastFeeding.Summary
+	}
+
+	status := fmt.Sprintf("尿布%d次 喂养%d次", summary.Counts.Diapers, summary.Counts.Feedings)
+	if summary.CurrentSleep != nil {
+		status += " 睡眠中"
+	}
+
+	return map[string]any{
+		"thing1": truncateRunes(babyName, 20),                                              // 宝宝
+		"thing2": truncateRunes(lastFeeding, 20),                                           // 上次喂养
+		"thing3": truncateRunes(status, 20),                                                // 交接情况
+		"thing4": truncateRunes(fmt.Sprintf("待办提醒%d项", len(summary.PendingReminders)), 20), // 待办
+		"time5":  formatMessageTime(summary.GeneratedAt),                                   // 交接时间
+	}
+}
+
+// handoffSince 交接摘要的起点: 上一位照护人开始值班的时间,最多回溯到忘记交班的最长值班时长
+func handoffSince(previous *entity.CaregiverShift, now int64) int64 {
+	if previous == nil {
+		return now - handoffDefaultWindow.Milliseconds()
+	}
+	return max(previous.StartTime, now-entity.CaregiverShiftMaxDuration.Milliseconds())
+}
+
+// onDutySince 开始时间早于该时间的未结束班次视为忘记交班
+func onDutySince(now int64) int64 {
+	return now - entity.CaregiverShiftMaxDuration.Milliseconds()
+}
+
+// routeToOnDuty 值班照护人开启"提醒只发给我"且能查看该类记录时只保留值班照护人
+func routeToOnDuty(collaborators []*entity.BabyCollaborator, onDuty *entity.CaregiverShift, resource string) []*entity.BabyCollaborator {
+	if onDuty == nil || !onDuty.RouteReminders {
+		return collaborators
+	}
+	for _, collaborator := range collaborators {
+		if collaborator.UserID == onDuty.UserID && !collaborator.IsExpired() &&
+			collaborator.Can(resource, entity.PermissionActionRead) {
+			return []*entity.BabyCollaborator{collaborator}
+		}
+	}
+	return collaborators
+}
+
+// checkShiftCaregiver 只读成员不参与值班
+func checkShiftCaregiver(collaborator *entity.BabyCollaborator) error {
+	if collaborator.Role == entity.CollaboratorRoleViewer {
+		return errors.New(errors.PermissionDenied, "只读成员不能值班")
+	}
+	return nil
+}
+
+// readableResources 协作者可查看的记录类型
+func readableResources(collaborator *entity.BabyCollaborator) map[string]bool {
+	readable := make(map[string]bool, len(entity.PermissionResources))
+	for _, resource := range entity.PermissionResources {
+		if collaborator.Can(resource, entity.PermissionActionRead) {
+			readable[resource] = true
+		}
+	}
+	return readable
+}
+
+// intersectReadable 两位成员都能查看的记录类型
+func intersectReadable(a, b map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(a))
+	for resource := range a {
+		if b[resource] {
+			result[resource] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestRouteToOnDuty(t *testing.T) {
+	collaborators := []*entity.BabyCollaborator{
+		{UserID: 1, Role: entity.CollaboratorRoleAdmin, AccessType: "permanent"},
+		{UserID: 2, Role: entity.CollaboratorRoleCaregiver, AccessType: "permanent"},
+	}
+
+	// 无人值班或值班人未开启"提醒只发给我"时发给全部协作者
+	assert.Len(t, routeToOnDuty(collaborators, nil, entity.PermissionResourceFeeding), 2)
+	assert.Len(t, routeToOnDuty(collaborators, &entity.CaregiverShift{UserID: 2}, entity.PermissionResourceFeeding), 2)
+
+	routed := routeToOnDuty(collaborators, &entity.CaregiverShift{UserID: 2, RouteReminders: true}, entity.PermissionResourceFeeding)
+	require.Len(t, routed, 1)
+	assert.Equal(t, int64(2), routed[0].UserID)
+
+	// 值班人无权查看该类记录时退回发给全部协作者,避免提醒无人接收
+	collaborators[1].Permissions = entity.PermissionOverrides{
+		entity.PermissionResourceVaccine: {entity.PermissionActionRead: false},
+	}
+	assert.Len(t, routeToOnDuty(collaborators, &entity.CaregiverShift{UserID: 2, RouteReminders: true}, entity.PermissionResourceVaccine), 2)
+
+	// 值班人已不是协作者时同样发给全部协作者
+	assert.Len(t, routeToOnDuty(collaborators, &entity.CaregiverShift{UserID: 3, RouteReminders: true}, entity.PermissionResourceFeeding), 2)
+}
+
+func TestHandoffSince(t *testing.T) {
+	now := time.Now().UnixMilli()
+
+	assert.Equal(t, now-handoffDefaultWindow.Milliseconds(), handoffSince(nil, now))
+
+	start := now - 3*time.Hour.Milliseconds()
+	assert.Equal(t, start, handoffSince(&entity.CaregiverShift{StartTime: start}, now))
+
+	// 忘记交班的旧班次最多回溯一个最长值班时长
+	stale := now - 3*24*time.Hour.Milliseconds()
+	assert.Equal(t, now-entity.CaregiverShiftMaxDuration.Milliseconds(), handoffSince(&entity.CaregiverShift{StartTime: stale}, now))
+}
+
+func TestCaregiverShiftIsOnDuty(t *testing.T) {
+	now := time.Now().UnixMilli()
+	ended := now - time.Hour.Milliseconds()
+
+	assert.True(t, (&entity.CaregiverShift{StartTime: now - time.Hour.Milliseconds()}).IsOnDuty(now))
+	assert.False(t, (&entity.CaregiverShift{StartTime: now - 2*time.Hour.Milliseconds(), EndTime: &ended}).IsOnDuty(now))
+	assert.False(t, (&entity.CaregiverShift{StartTime: now - 25*time.Hour.Milliseconds()}).IsOnDuty(now))
+}
+
+func TestSummarizeHandoff(t *testing.T) {
+	now := time.Now().UnixMilli()
+	since := now - 6*time.Hour.Milliseconds()
+	hoursAgo := func(h float64) int64 { return now - int64(h*float64(time.Hour.Milliseconds())) }
+
+	nextReminder := now + 30*time.Minute.Milliseconds()
+	lastFeeding := &entity.FeedingRecord{
+		ID: 11, FeedingType: entity.FeedingTypeBottle, Amount: 120, Time: hoursAgo(5),
+		NextReminderTime: &nextReminder,
+		Detail:           entity.FeedingDetail{"note": "吐奶一次"},
+	}
+	sleepEnd := hoursAgo(1)
+	sleepDuration := 3600
+	ongoingEnd := int64(0)
+	diaperNote := "有点红屁股"
+
+	records := &handoffRecords{
+		lastFeeding:  lastFeeding,
+		ongoingSleep: &entity.SleepRecord{ID: 30, Type: entity.SleepTypeNight, StartTime: hoursAgo(0.5), EndTime: &ongoingEnd},
+		feedings: []*entity.FeedingRecord{
+			lastFeeding,
+			{ID: 12, FeedingType: entity.FeedingTypeBreast, Duration: 900, Time: hoursAgo(5.5)},
+		},
+		sleeps: []*entity.SleepRecord{
+			{ID: 30, Type: entity.SleepTypeNight, StartTime: hoursAgo(0.5), EndTime: &ongoingEnd},
+			{ID: 31, Type: entity.SleepTypeNap, StartTime: hoursAgo(2), EndTime: &sleepEnd, Duration: &sleepDuration},
+		},
+		diapers: []*entity.DiaperRecord{
+			{ID: 21, Type: entity.DiaperTypeBoth, Time: hoursAgo(3), Note: &diaperNote},
+			{ID: 22, Type: entity.DiaperTypePee, Time: hoursAgo(4)},
+		},
+		vaccines: []*entity.BabyVaccineSchedule{
+			{ID: 41, VaccineName: "乙肝疫苗", DoseNumber: 2, ScheduledDate: hoursAgo(24)},
+		},
+		followUps: []*entity.VaccineReactionFollowUp{
+			{ID: 51, ScheduleID: 40, CheckpointHours: 24, DueAt: now + time.Hour.Milliseconds()},
+		},
+		followUpVaccines: map[int64]*entity.BabyVaccineSchedule{
+			40: {ID: 40, VaccineName: "百白破", DoseNumber: 1},
+		},
+	}
+	previous := &entity.CaregiverShift{UserID: 1, StartTime: since, HandoffNote: "晚上有点咳嗽"}
+
+	summary := summarizeHandoff(100, since, now, previous, records)
+
+	assert.Equal(t, "100", summary.BabyID)
+	assert.Equal(t, since, summary.Since)
+	require.NotNil(t, summary.LastFeeding)
+	assert.Equal(t, "奶瓶 120ml", summary.LastFeeding.Summary)
+	require.NotNil(t, summary.CurrentSleep)
+	assert.Equal(t, "夜间睡眠 进行中", summary.CurrentSleep.Summary)
+
+	assert.Equal(t, 2, summary.Counts.Feedings)
+	assert.Equal(t, int64(120), summary.Counts.BottleAmount)
+	assert.Equal(t, 2, summary.Counts.Sleeps)
+	assert.Equal(t, 60, summary.Counts.SleepMinutes)
+	assert.Equal(t, 2, summary.Counts.Diapers)
+	assert.Equal(t, 2, summary.Counts.WetDiapers)
+	assert.Equal(t, 1, summary.Counts.DirtyDiapers)
+
+	// 记录按时间倒序
+	require.Len(t, summary.Records, 6)
+	assert.Equal(t, "30", summary.Records[0].RecordID)
+	assert.Equal(t, "12", summary.Records[5].RecordID)
+	assert.Equal(t, "母乳 15分钟", summary.Records[5].Summary)
+
+	// 待办提醒按到期时间排序: 逾期疫苗、下次喂养、接种随访
+	require.Len(t, summary.PendingReminders, 3)
+	assert.Equal(t, "乙肝疫苗第2剂", summary.PendingReminders[0].Title)
+	assert.True(t, summary.PendingReminders[0].Overdue)
+	assert.Equal(t, "下次喂养提醒", summary.PendingReminders[1].Title)
+	assert.Equal(t, "百白破第1剂 接种后24小时随访", summary.PendingReminders[2].Title)
+
+	// 交班备注、记录备注和长时间未喂养都需要留意
+	require.Len(t, summary.Notices, 4)
+	assert.Equal(t, "交班备注: 晚上有点咳嗽", summary.Notices[0])
+	assert.Equal(t, "距上次喂养已超过 5 小时", summary.Notices[1])
+	assert.Contains(t, summary.Notices[2], "换尿布(大小便): 有点红屁股")
+	assert.Contains(t, summary.Notices[3], "奶瓶 120ml: 吐奶一次")
+}
+
+func TestSummarizeHandoffWithoutRecords(t *testing.T) {
+	now := time.Now().UnixMilli()
+	summary := summarizeHandoff(100, now-handoffDefaultWindow.Milliseconds(), now, nil, &handoffRecords{})
+
+	assert.Nil(t, summary.LastFeeding)
+	assert.Nil(t, summary.CurrentSleep)
+	assert.Empty(t, summary.Records)
+	assert.Empty(t, summary.PendingReminders)
+	assert.Empty(t, summary.Notices)
+
+	data := handoffMessageData("小宝", summary)
+	assert.Equal(t, "暂无喂养记录", data["thing2"])
+	assert.Equal(t, "尿布0次 喂养0次", data["thing3"])
+}
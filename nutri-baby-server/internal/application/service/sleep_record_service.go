@@ -14,6 +14,9 @@ import (
 type SleepRecordService struct {
 	*BaseRecordService
 	sleepRecordRepo repository.SleepRecordRepository
+	attachmentRepo  repository.AttachmentRepository
+	commentRepo     repository.RecordCommentRepository
+	revisionService *RevisionService
 }
 
 // NewSleepRecordService 创建睡眠记录服务
@@ -22,17 +25,23 @@ func NewSleepRecordService(
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	userRepo repository.UserRepository,
 	sleepRecordRepo repository.SleepRecordRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
+	revisionService *RevisionService,
 	logger *zap.Logger,
 ) *SleepRecordService {
 	return &SleepRecordService{
 		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
 		sleepRecordRepo:   sleepRecordRepo,
+		attachmentRepo:    attachmentRepo,
+		commentRepo:       commentRepo,
+		revisionService:   revisionService,
 	}
 }
 
 // CreateSleepRecord 创建睡眠记录
 func (s *SleepRecordService) CreateSleepRecord(ctx context.Context, openID string, req *dto.CreateSleepRecordRequest) (*dto.SleepRecordDTO, error) {
-	if err := s.CheckBabyAccess(ctx, req.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, req.BabyID, openID, entity.PermissionResourceSleep, entity.PermissionActionCreate); err != nil {
 		return nil, err
 	}
 
@@ -76,6 +85,10 @@ func (s *SleepRecordService) CreateSleepRecord(ctx context.Context, openID strin
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeSleep, record.ID, nil); err != nil {
+		return nil, err
+	}
+
 	resultEndTime := int64(0)
 	if record.EndTime != nil {
 		resultEndTime = *record.EndTime
@@ -101,7 +114,7 @@ func (s *SleepRecordService) CreateSleepRecord(ctx context.Context, openID strin
 
 // GetSleepRecords 获取睡眠记录列表
 func (s *SleepRecordService) GetSleepRecords(ctx context.Context, openID string, query *dto.RecordListQuery) ([]dto.SleepRecordDTO, int64, error) {
-	if err := s.CheckBabyAccess(ctx, query.BabyID, openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, query.BabyID, openID, entity.PermissionResourceSleep, entity.PermissionActionRead); err != nil {
 		return nil, 0, err
 	}
 
@@ -169,7 +182,7 @@ func (s *SleepRecordService) GetSleepRecordById(ctx context.Context, openID, rec
 	}
 
 	// 验证用户是否有权限访问该宝宝的记录
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceSleep, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
@@ -213,8 +226,10 @@ func (s *SleepRecordService) UpdateSleepRecord(ctx context.Context, openID, reco
 		return nil, err
 	}
 
+	before := revisionSnapshotOf(record)
+
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordUpdatePermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceSleep, record.CreatedBy); err != nil {
 		return nil, err
 	}
 
@@ -263,6 +278,10 @@ func (s *SleepRecordService) UpdateSleepRecord(ctx context.Context, openID, reco
 		return nil, err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeSleep, record.ID, before); err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("睡眠记录更新成功",
 		zap.String("recordID", strconv.FormatInt(record.ID, 10)),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
@@ -289,7 +308,7 @@ func (s *SleepRecordService) DeleteSleepRecord(ctx context.Context, openID, reco
 	}
 
 	// 验证权限
-	if err := s.CheckBabyAccess(ctx, strconv.FormatInt(record.BabyID, 10), openID); err != nil {
+	if err := s.CheckRecordPermission(ctx, strconv.FormatInt(record.BabyID, 10), openID, entity.PermissionResourceSleep, entity.PermissionActionDelete); err != nil {
 		return err
 	}
 
@@ -301,6 +320,24 @@ func (s *SleepRecordService) DeleteSleepRecord(ctx context.Context, openID, reco
 		return err
 	}
 
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeSleep, record.ID, revisionSnapshotOf(record)); err != nil {
+		return err
+	}
+
+	// 同步软删除记录的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeSleep, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录附件失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
+	// 同步软删除记录的评论和表情回应
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeSleep, recordIDInt64); err != nil {
+		s.logger.Warn("删除记录评论失败",
+			zap.String("recordID", recordID),
+			zap.Error(err))
+	}
+
 	s.logger.Info("睡眠记录删除成功",
 		zap.String("recordID", recordID),
 		zap.String("babyID", strconv.FormatInt(record.BabyID, 10)))
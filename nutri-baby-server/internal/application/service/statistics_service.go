@@ -21,6 +21,8 @@ type StatisticsService struct {
 	diaperRecordRepo  repository.DiaperRecordRepository
 	growthRecordRepo  repository.GrowthRecordRepository
 	userRepo          repository.UserRepository
+	targetService     *TargetService
+	permissions       *PermissionService // 按记录类型裁剪统计内容
 	logger            *zap.Logger
 }
 
@@ -33,6 +35,7 @@ func NewStatisticsService(
 	diaperRecordRepo repository.DiaperRecordRepository,
 	growthRecordRepo repository.GrowthRecordRepository,
 	userRepo repository.UserRepository,
+	targetService *TargetService,
 	logger *zap.Logger,
 ) *StatisticsService {
 	return &StatisticsService{
@@ -43,12 +46,14 @@ func NewStatisticsService(
 		diaperRecordRepo:  diaperRecordRepo,
 		growthRecordRepo:  growthRecordRepo,
 		userRepo:          userRepo,
+		targetService:     targetService,
+		permissions:       NewPermissionService(collaboratorRepo, userRepo, logger),
 		logger:            logger,
 	}
 }
 
 // GetBabyStatistics 获取宝宝统计数据
-func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openID string) (*dto.BabyStatisticsResponse, error) {
+func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openID string, query *dto.StatisticsQuery) (*dto.BabyStatisticsResponse, error) {
 	// 1. 将字符串 ID 转换为 int64
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
@@ -61,13 +66,14 @@ func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openI
 		return nil, errors.Wrap(errors.DatabaseError, "查询宝宝信息失败", err)
 	}
 
-	// 检查权限
-	hasAccess, err := s.checkBabyAccess(ctx, babyIDInt64, openID)
+	// 检查权限: 无权查看的记录类型不参与统计,对应字段保持零值
+	collaborator, err := s.permissions.ActiveCollaborator(ctx, babyIDInt64, openID)
 	if err != nil {
 		return nil, err
 	}
-	if !hasAccess {
-		return nil, errors.New(errors.PermissionDenied, "没有权限访问该宝宝信息")
+	readable := make(map[string]bool, len(entity.PermissionResources))
+	for _, resource := range entity.PermissionResources {
+		readable[resource] = collaborator.Can(resource, entity.PermissionActionRead)
 	}
 
 	// 3. 获取今日统计
@@ -75,7 +81,7 @@ func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openI
 	todayStart := getTodayStart(now)
 	todayEnd := getTodayEnd(now)
 
-	todayStats, err := s.getTodayStatistics(ctx, babyIDInt64, todayStart.Unix()*1000, todayEnd.Unix()*1000)
+	todayStats, err := s.getTodayStatistics(ctx, babyIDInt64, readable, todayStart.Unix()*1000, todayEnd.Unix()*1000)
 	if err != nil {
 		s.logger.Error("获取今日统计失败", zap.String("babyId", babyID), zap.Error(err))
 		return nil, err
@@ -87,7 +93,7 @@ func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openI
 	prevWeekStart := weekStart.AddDate(0, 0, -7)
 	prevWeekEnd := weekStart.AddDate(0, 0, -1)
 
-	weeklyStats, err := s.getWeeklyStatistics(ctx, babyIDInt64,
+	weeklyStats, err := s.getWeeklyStatistics(ctx, babyIDInt64, readable,
 		weekStart.Unix()*1000, weekEnd.Unix()*1000,
 		prevWeekStart.Unix()*1000, prevWeekEnd.Unix()*1000)
 	if err != nil {
@@ -95,14 +101,53 @@ func (s *StatisticsService) GetBabyStatistics(ctx context.Context, babyID, openI
 		return nil, err
 	}
 
+	// 本周照护人分工(可选)
+	if query.IncludeCaregivers {
+		weeklyStats.Caregivers, err = s.getCaregiverStatistics(ctx, babyIDInt64, readable, weekStart.UnixMilli(), weekEnd.UnixMilli(), time.Local)
+		if err != nil {
+			s.logger.Error("获取本周照护人分工失败", zap.String("babyId", babyID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// 5. 今日目标进度（目标计算失败不影响统计结果,无权查看喂养或睡眠时不计算）
+	var targets *dto.TodayTargetProgress
+	if s.targetService != nil && readable[entity.PermissionResourceFeeding] && readable[entity.PermissionResourceSleep] {
+		targets, err = s.getTodayTargetProgress(ctx, babyIDInt64, todayStats)
+		if err != nil {
+			s.logger.Warn("获取今日目标进度失败", zap.String("babyId", babyID), zap.Error(err))
+		}
+	}
+
 	return &dto.BabyStatisticsResponse{
-		Today:  *todayStats,
-		Weekly: *weeklyStats,
+		Today:   *todayStats,
+		Weekly:  *weeklyStats,
+		Targets: targets,
+	}, nil
+}
+
+// getTodayTargetProgress 计算今日统计相对每日目标的进度
+func (s *StatisticsService) getTodayTargetProgress(ctx context.Context, babyID int64, today *dto.TodayStatistics) (*dto.TodayTargetProgress, error) {
+	targets, err := s.targetService.ResolveTargets(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 喂养次数只统计奶类喂养(母乳+奶瓶),辅食不计入
+	milkFeedCount := today.Feeding.BreastCount + today.Feeding.BottleCount
+	// 亲喂没有奶量,按估算值计入奶量进度,避免母乳喂养的宝宝奶量进度始终接近零
+	breastMl := estimateBreastMilkMl(today.Feeding.BreastCount, targets.MilkMl, targets.FeedCount)
+
+	return &dto.TodayTargetProgress{
+		MilkMl:           newTargetProgress(float64(today.Feeding.BottleMl)+breastMl, targets.MilkMl),
+		BreastMlEstimate: breastMl,
+		FeedCount:        newTargetProgress(float64(milkFeedCount), targets.FeedCount),
+		SleepMinute:      newTargetProgress(float64(today.Sleep.TotalMinutes), targets.SleepMinute),
 	}, nil
 }
 
 // getTodayStatistics 获取今日统计
-func (s *StatisticsService) getTodayStatistics(ctx context.Context, babyID int64, startTime, endTime int64) (*dto.TodayStatistics, error) {
+func (s *StatisticsService) getTodayStatistics(ctx context.Context, babyID int64, readable map[string]bool, startTime, endTime int64) (*dto.TodayStatistics, error) {
 	stats := &dto.TodayStatistics{
 		Feeding: dto.TodayFeedingStats{},
 		Sleep:   dto.TodaySleepStats{},
@@ -111,32 +156,40 @@ func (s *StatisticsService) getTodayStatistics(ctx context.Context, babyID int64
 	}
 
 	// 1. 喂养统计
-	feedingStats, err := s.getTodayFeedingStats(ctx, babyID, startTime, endTime)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceFeeding] {
+		feedingStats, err := s.getTodayFeedingStats(ctx, babyID, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		stats.Feeding = *feedingStats
 	}
-	stats.Feeding = *feedingStats
 
 	// 2. 睡眠统计
-	sleepStats, err := s.getTodaySleepStats(ctx, babyID, startTime, endTime)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceSleep] {
+		sleepStats, err := s.getTodaySleepStats(ctx, babyID, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		stats.Sleep = *sleepStats
 	}
-	stats.Sleep = *sleepStats
 
 	// 3. 换尿布统计
-	diaperStats, err := s.getTodayDiaperStats(ctx, babyID, startTime, endTime)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceDiaper] {
+		diaperStats, err := s.getTodayDiaperStats(ctx, babyID, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		stats.Diaper = *diaperStats
 	}
-	stats.Diaper = *diaperStats
 
 	// 4. 成长统计（最新的成长记录）
-	growthStats, err := s.getTodayGrowthStats(ctx, babyID)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceGrowth] {
+		growthStats, err := s.getTodayGrowthStats(ctx, babyID)
+		if err != nil {
+			return nil, err
+		}
+		stats.Growth = *growthStats
 	}
-	stats.Growth = *growthStats
 
 	return stats, nil
 }
@@ -249,7 +302,7 @@ func (s *StatisticsService) getTodayGrowthStats(ctx context.Context, babyID int6
 }
 
 // getWeeklyStatistics 获取本周统计
-func (s *StatisticsService) getWeeklyStatistics(ctx context.Context, babyID int64, weekStart, weekEnd, prevWeekStart, prevWeekEnd int64) (*dto.WeeklyStatistics, error) {
+func (s *StatisticsService) getWeeklyStatistics(ctx context.Context, babyID int64, readable map[string]bool, weekStart, weekEnd, prevWeekStart, prevWeekEnd int64) (*dto.WeeklyStatistics, error) {
 	stats := &dto.WeeklyStatistics{
 		Feeding: dto.WeeklyFeedingStats{},
 		Sleep:   dto.WeeklySleepStats{},
@@ -257,25 +310,31 @@ func (s *StatisticsService) getWeeklyStatistics(ctx context.Context, babyID int6
 	}
 
 	// 1. 本周喂养统计和趋势
-	feedingStats, err := s.getWeeklyFeedingStats(ctx, babyID, weekStart, weekEnd, prevWeekStart, prevWeekEnd)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceFeeding] {
+		feedingStats, err := s.getWeeklyFeedingStats(ctx, babyID, weekStart, weekEnd, prevWeekStart, prevWeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		stats.Feeding = *feedingStats
 	}
-	stats.Feeding = *feedingStats
 
 	// 2. 本周睡眠统计和趋势
-	sleepStats, err := s.getWeeklySleepStats(ctx, babyID, weekStart, weekEnd, prevWeekStart, prevWeekEnd)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceSleep] {
+		sleepStats, err := s.getWeeklySleepStats(ctx, babyID, weekStart, weekEnd, prevWeekStart, prevWeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		stats.Sleep = *sleepStats
 	}
-	stats.Sleep = *sleepStats
 
 	// 3. 本周成长统计
-	growthStats, err := s.getWeeklyGrowthStats(ctx, babyID, weekStart, weekEnd)
-	if err != nil {
-		return nil, err
+	if readable[entity.PermissionResourceGrowth] {
+		growthStats, err := s.getWeeklyGrowthStats(ctx, babyID, weekStart, weekEnd)
+		if err != nil {
+			return nil, err
+		}
+		stats.Growth = *growthStats
 	}
-	stats.Growth = *growthStats
 
 	return stats, nil
 }
@@ -395,48 +454,6 @@ func (s *StatisticsService) getWeeklyGrowthStats(ctx context.Context, babyID int
 	return stats, nil
 }
 
-// checkBabyAccess 检查用户是否有权访问宝宝
-func (s *StatisticsService) checkBabyAccess(ctx context.Context, babyID int64, openID string) (bool, error) {
-	baby, err := s.babyRepo.FindByID(ctx, babyID)
-	if err != nil {
-		return false, err
-	}
-
-	// 获取创建者用户信息
-	user, err := s.userRepo.FindByID(ctx, baby.UserID)
-	if err != nil {
-		return false, err
-	}
-
-	// 检查是否是创建者
-	if user.OpenID == openID {
-		return true, nil
-	}
-
-	// 获取用户ID
-	targetUser, err := s.userRepo.FindByOpenID(ctx, openID)
-	if err != nil {
-		return false, nil
-	}
-
-	// 检查是否是协作者
-	collaborator, err := s.collaboratorRepo.CheckPermission(ctx, babyID, targetUser.ID)
-	if err != nil {
-		return false, nil
-	}
-
-	if collaborator == nil {
-		return false, nil
-	}
-
-	// 检查权限是否未过期
-	if collaborator.ExpiresAt != nil && time.UnixMilli(*collaborator.ExpiresAt).Before(time.Now()) {
-		return false, nil
-	}
-
-	return true, nil
-}
-
 // Helper functions
 
 // getTodayStart 获取今天的开始时间 (00:00:00)
@@ -95,6 +95,7 @@ func TestGetTodayFeedingStats_Accumulation(t *testing.T) {
 		nil, // diaperRecordRepo
 		nil, // growthRecordRepo
 		nil, // userRepo
+		nil, // targetService
 		logger,
 	)
 
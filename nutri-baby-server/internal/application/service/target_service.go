@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// TargetService 每日目标服务
+// 根据最新体重和月龄推荐每日奶量、喂养次数和睡眠时长,并支持看护人按宝宝覆盖
+type TargetService struct {
+	*BaseRecordService
+	targetRepo       repository.BabyTargetRepository
+	growthRecordRepo repository.GrowthRecordRepository
+}
+
+// NewTargetService 创建每日目标服务
+func NewTargetService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	targetRepo repository.BabyTargetRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	logger *zap.Logger,
+) *TargetService {
+	return &TargetService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		targetRepo:        targetRepo,
+		growthRecordRepo:  growthRecordRepo,
+	}
+}
+
+// GetTargets 获取宝宝每日目标
+func (s *TargetService) GetTargets(ctx context.Context, babyID, openID string) (*dto.BabyTargetsResponse, error) {
+	if err := s.CheckBabyAccess(ctx, babyID, openID); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "无效的宝宝ID格式")
+	}
+
+	return s.ResolveTargets(ctx, babyIDInt64)
+}
+
+// UpdateTargets 设置宝宝每日目标覆盖
+func (s *TargetService) UpdateTargets(ctx context.Context, babyID, openID string, req *dto.UpdateBabyTargetsRequest) (*dto.BabyTargetsResponse, error) {
+	if err := s.CheckBabyEditAccess(ctx, babyID, openID); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "无效的宝宝ID格式")
+	}
+
+	if req.MilkMinMl != nil && req.MilkMaxMl != nil && *req.MilkMinMl > *req.MilkMaxMl {
+		return nil, errors.New(errors.ParamError, "奶量下限不能大于上限")
+	}
+	if req.FeedCountMin != nil && req.FeedCountMax != nil && *req.FeedCountMin > *req.FeedCountMax {
+		return nil, errors.New(errors.ParamError, "喂养次数下限不能大于上限")
+	}
+	if req.SleepMinMinutes != nil && req.SleepMaxMinutes != nil && *req.SleepMinMinutes > *req.SleepMaxMinutes {
+		return nil, errors.New(errors.ParamError, "睡眠时长下限不能大于上限")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.targetRepo.FindByBabyID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		target = &entity.BabyTarget{BabyID: babyIDInt64}
+	}
+
+	target.MilkMinMl = req.MilkMinMl
+	target.MilkMaxMl = req.MilkMaxMl
+	target.FeedCountMin = req.FeedCountMin
+	target.FeedCountMax = req.FeedCountMax
+	target.SleepMinMinutes = req.SleepMinMinutes
+	target.SleepMaxMinutes = req.SleepMaxMinutes
+	target.UpdatedBy = user.ID
+
+	if err := s.targetRepo.Save(ctx, target); err != nil {
+		s.logger.Error("保存每日目标失败", zap.String("babyId", babyID), zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("每日目标已更新", zap.String("babyId", babyID), zap.String("openid", openID))
+
+	return s.ResolveTargets(ctx, babyIDInt64)
+}
+
+// ResetTargets 清除自定义目标,恢复系统推荐值
+func (s *TargetService) ResetTargets(ctx context.Context, babyID, openID string) (*dto.BabyTargetsResponse, error) {
+	if err := s.CheckBabyEditAccess(ctx, babyID, openID); err != nil {
+		return nil, err
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "无效的宝宝ID格式")
+	}
+
+	if err := s.targetRepo.DeleteByBabyID(ctx, babyIDInt64); err != nil {
+		return nil, err
+	}
+
+	return s.ResolveTargets(ctx, babyIDInt64)
+}
+
+// ResolveTargets 计算宝宝当前生效的每日目标(推荐值 + 自定义覆盖)
+// 不做权限校验,调用方需自行保证
+func (s *TargetService) ResolveTargets(ctx context.Context, babyID int64) (*dto.BabyTargetsResponse, error) {
+	baby, err := s.babyRepo.FindByID(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ageInDays := 0
+	if birthTime, err := time.Parse("2006-01-02", baby.BirthDate); err == nil {
+		ageInDays = int(time.Since(birthTime).Hours() / 24)
+		if ageInDays < 0 {
+			ageInDays = 0
+		}
+	}
+
+	weight, err := s.getLatestWeight(ctx, baby)
+	if err != nil {
+		return nil, err
+	}
+
+	milk, feedCount, sleep := recommendDailyTargets(ageInDays, weight)
+
+	resp := &dto.BabyTargetsResponse{
+		BabyID:      strconv.FormatInt(babyID, 10),
+		AgeInDays:   ageInDays,
+		WeightKg:    weight,
+		MilkMl:      milk,
+		FeedCount:   feedCount,
+		SleepMinute: sleep,
+	}
+
+	override, err := s.targetRepo.FindByBabyID(ctx, babyID)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		resp.HasOverride = true
+		resp.UpdatedAt = override.UpdatedAt
+		applyInt64Override(&resp.MilkMl, override.MilkMinMl, override.MilkMaxMl)
+		applyIntOverride(&resp.FeedCount, override.FeedCountMin, override.FeedCountMax)
+		applyIntOverride(&resp.SleepMinute, override.SleepMinMinutes, override.SleepMaxMinutes)
+	}
+
+	return resp, nil
+}
+
+// getLatestWeight 获取最新体重(kg),优先使用成长记录,其次使用宝宝档案
+func (s *TargetService) getLatestWeight(ctx context.Context, baby *entity.Baby) (*float64, error) {
+	records, _, err := s.growthRecordRepo.FindByBabyID(ctx, baby.ID, 0, 9999999999999, 1, 10)
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询成长记录失败", err)
+	}
+
+	// 按 time DESC 排序,第一条有体重的记录即为最新体重
+	for _, record := range records {
+		if record.Weight != nil && *record.Weight > 0 {
+			return record.Weight, nil
+		}
+	}
+
+	if baby.Weight > 0 {
+		weight := baby.Weight
+		return &weight, nil
+	}
+
+	return nil, nil
+}
+
+// recommendDailyTargets 根据出生天数和体重推荐每日目标
+//
+// 奶量: 6月龄内按 120-160 ml/kg/天 估算(单日上限 1000ml),无体重时按月龄经验值
+// 喂养次数: 按月龄段经验值
+// 睡眠: 参考 AASM 各年龄段推荐睡眠时长(含小睡)
+func recommendDailyTargets(ageInDays int, weightKg *float64) (milk, feedCount, sleep dto.TargetRange) {
+	milk = dto.TargetRange{Unit: "ml", Source: dto.TargetSourceRecommended}
+	feedCount = dto.TargetRange{Unit: "次", Source: dto.TargetSourceRecommended}
+	sleep = dto.TargetRange{Unit: "min", Source: dto.TargetSourceRecommended}
+
+	// 奶量
+	switch {
+	case ageInDays < 183 && weightKg != nil && *weightKg > 0:
+		milk.Min = math.Min(math.Round(*weightKg*120), 1000)
+		milk.Max = math.Min(math.Round(*weightKg*160), 1000)
+	case ageInDays < 30:
+		milk.Min, milk.Max = 400, 700
+	case ageInDays < 183:
+		milk.Min, milk.Max = 600, 900
+	case ageInDays < 365:
+		milk.Min, milk.Max = 600, 800 // 已添加辅食
+	case ageInDays < 1095:
+		milk.Min, milk.Max = 400, 600
+	default:
+		milk.Min, milk.Max = 300, 500
+	}
+
+	// 喂养次数
+	switch {
+	case ageInDays < 30:
+		feedCount.Min, feedCount.Max = 8, 12
+	case ageInDays < 90:
+		feedCount.Min, feedCount.Max = 6, 8
+	case ageInDays < 183:
+		feedCount.Min, feedCount.Max = 5, 7
+	case ageInDays < 365:
+		feedCount.Min, feedCount.Max = 4, 6
+	default:
+		feedCount.Min, feedCount.Max = 3, 5
+	}
+
+	// 睡眠时长
+	switch {
+	case ageInDays < 120:
+		sleep.Min, sleep.Max = 14*60, 17*60
+	case ageInDays < 365:
+		sleep.Min, sleep.Max = 12*60, 16*60
+	case ageInDays < 1095:
+		sleep.Min, sleep.Max = 11*60, 14*60
+	default:
+		sleep.Min, sleep.Max = 10*60, 13*60
+	}
+
+	return milk, feedCount, sleep
+}
+
+// applyInt64Override 应用 int64 类型的自定义区间
+func applyInt64Override(target *dto.TargetRange, minValue, maxValue *int64) {
+	if minValue != nil {
+		target.Min = float64(*minValue)
+		target.Source = dto.TargetSourceCustom
+	}
+	if maxValue != nil {
+		target.Max = float64(*maxValue)
+		target.Source = dto.TargetSourceCustom
+	}
+}
+
+// applyIntOverride 应用 int 类型的自定义区间
+func applyIntOverride(target *dto.TargetRange, minValue, maxValue *int) {
+	if minValue != nil {
+		target.Min = float64(*minValue)
+		target.Source = dto.TargetSourceCustom
+	}
+	if maxValue != nil {
+		target.Max = float64(*maxValue)
+		target.Source = dto.TargetSourceCustom
+	}
+}
+
+// estimateBreastMilkMl 估算亲喂奶量
+// 亲喂无法计量,每次按目标奶量中值除以目标喂养次数中值得到的单次奶量折算
+func estimateBreastMilkMl(breastCount int, milk, feedCount dto.TargetRange) float64 {
+	feeds := (feedCount.Min + feedCount.Max) / 2
+	if breastCount <= 0 || feeds <= 0 {
+		return 0
+	}
+	perFeed := (milk.Min + milk.Max) / 2 / feeds
+	return math.Round(perFeed * float64(breastCount))
+}
+
+// newTargetProgress 计算单项目标进度
+func newTargetProgress(current float64, target dto.TargetRange) dto.TargetProgress {
+	progress := dto.TargetProgress{
+		Current: current,
+		Target:  target,
+		Status:  dto.TargetStatusWithin,
+	}
+
+	if target.Min > 0 {
+		progress.Percent = roundToOneDecimal(current / target.Min * 100)
+	} else {
+		progress.Percent = 100
+	}
+
+	switch {
+	case current < target.Min:
+		progress.Status = dto.TargetStatusBelow
+	case target.Max > 0 && current > target.Max:
+		progress.Status = dto.TargetStatusAbove
+	}
+
+	return progress
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+)
+
+func TestRecommendDailyTargets(t *testing.T) {
+	weight := 5.0
+
+	// 2月龄、5kg: 奶量按体重估算 600-800ml
+	milk, feedCount, sleep := recommendDailyTargets(60, &weight)
+	assert.Equal(t, 600.0, milk.Min)
+	assert.Equal(t, 800.0, milk.Max)
+	assert.Equal(t, 6.0, feedCount.Min)
+	assert.Equal(t, 8.0, feedCount.Max)
+	assert.Equal(t, 14*60.0, sleep.Min)
+	assert.Equal(t, dto.TargetSourceRecommended, milk.Source)
+
+	// 无体重时按月龄经验值
+	milk, _, _ = recommendDailyTargets(10, nil)
+	assert.Equal(t, 400.0, milk.Min)
+	assert.Equal(t, 700.0, milk.Max)
+
+	// 6月龄以上不再按体重估算
+	milk, _, sleep = recommendDailyTargets(200, &weight)
+	assert.Equal(t, 600.0, milk.Min)
+	assert.Equal(t, 12*60.0, sleep.Min)
+}
+
+func TestNewTargetProgress(t *testing.T) {
+	target := dto.TargetRange{Min: 600, Max: 800, Unit: "ml"}
+
+	assert.Equal(t, dto.TargetStatusBelow, newTargetProgress(300, target).Status)
+	assert.Equal(t, 50.0, newTargetProgress(300, target).Percent)
+	assert.Equal(t, dto.TargetStatusWithin, newTargetProgress(700, target).Status)
+	assert.Equal(t, dto.TargetStatusAbove, newTargetProgress(900, target).Status)
+}
+
+func TestEstimateBreastMilkMl(t *testing.T) {
+	milk := dto.TargetRange{Min: 600, Max: 900}
+	feedCount := dto.TargetRange{Min: 5, Max: 7}
+
+	// 单次按 750ml / 6次 = 125ml 折算
+	assert.Equal(t, 500.0, estimateBreastMilkMl(4, milk, feedCount))
+	assert.Zero(t, estimateBreastMilkMl(0, milk, feedCount))
+	assert.Zero(t, estimateBreastMilkMl(4, milk, dto.TargetRange{}))
+}
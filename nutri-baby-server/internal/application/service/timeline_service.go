@@ -9,16 +9,19 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
 	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
 )
 
 // TimelineService 时间线服务
 type TimelineService struct {
 	*BaseRecordService
-	feedingService *FeedingRecordService
-	sleepService   *SleepRecordService
-	diaperService  *DiaperRecordService
-	growthService  *GrowthRecordService
+	feedingService   *FeedingRecordService
+	sleepService     *SleepRecordService
+	diaperService    *DiaperRecordService
+	growthService    *GrowthRecordService
+	milestoneService *MilestoneService
+	commentService   *CommentService
 }
 
 // NewTimelineService 创建时间线服务
@@ -30,6 +33,8 @@ func NewTimelineService(
 	sleepService *SleepRecordService,
 	diaperService *DiaperRecordService,
 	growthService *GrowthRecordService,
+	milestoneService *MilestoneService,
+	commentService *CommentService,
 	logger *zap.Logger,
 ) *TimelineService {
 	return &TimelineService{
@@ -38,15 +43,21 @@ func NewTimelineService(
 		sleepService:      sleepService,
 		diaperService:     diaperService,
 		growthService:     growthService,
+		milestoneService:  milestoneService,
+		commentService:    commentService,
 	}
 }
 
 // GetTimeline 获取时间线记录
 func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query *dto.TimelineQuery) (*dto.TimelineResponse, error) {
-	// 检查权限
-	if err := s.CheckBabyAccess(ctx, query.BabyID, openID); err != nil {
+	// 检查权限: 只查询当前用户有查看权限的记录类型
+	readable, err := s.ReadableResources(ctx, query.BabyID, openID)
+	if err != nil {
 		return nil, err
 	}
+	if entity.IsValidPermission(query.RecordType, entity.PermissionActionRead) && !readable[query.RecordType] {
+		return nil, permissionDenied(query.RecordType, entity.PermissionActionRead)
+	}
 
 	// 设置分页参数（使用统一的默认值：pageSize 默认 10，最大 100）
 	page := query.GetPageWithDefault()
@@ -66,10 +77,11 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 
 	// 根据 recordType 决定查询哪些类型
 	recordType := query.RecordType
-	queryFeeding := recordType == "" || recordType == "feeding"
-	querySleep := recordType == "" || recordType == "sleep"
-	queryDiaper := recordType == "" || recordType == "diaper"
-	queryGrowth := recordType == "" || recordType == "growth"
+	queryFeeding := (recordType == "" || recordType == "feeding") && readable[entity.PermissionResourceFeeding]
+	querySleep := (recordType == "" || recordType == "sleep") && readable[entity.PermissionResourceSleep]
+	queryDiaper := (recordType == "" || recordType == "diaper") && readable[entity.PermissionResourceDiaper]
+	queryGrowth := (recordType == "" || recordType == "growth") && readable[entity.PermissionResourceGrowth]
+	queryMilestone := (recordType == "" || recordType == "milestone") && readable[entity.PermissionResourceMilestone]
 
 	// 计算需要查询的类型数量
 	queryCount := 0
@@ -85,6 +97,9 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 	if queryGrowth {
 		queryCount++
 	}
+	if queryMilestone {
+		queryCount++
+	}
 
 	// 并发查询所需类型的记录
 	var (
@@ -92,6 +107,7 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 		sleepRecords   []dto.SleepRecordDTO
 		diaperRecords  []dto.DiaperRecordDTO
 		growthRecords  []dto.GrowthRecordDTO
+		milestones     []dto.MilestoneDTO
 		wg             sync.WaitGroup
 		mu             sync.Mutex
 		errs           []error
@@ -163,10 +179,26 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 		}()
 	}
 
+	// 查询已达成的发育里程碑
+	if queryMilestone {
+		go func() {
+			defer wg.Done()
+			records, err := s.milestoneService.GetAchievedMilestones(ctx, openID, query.BabyID, query.StartTime, query.EndTime)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				s.logger.Warn("获取里程碑失败", zap.Error(err))
+				return
+			}
+			milestones = records
+		}()
+	}
+
 	wg.Wait()
 
 	// 如果所有查询都失败,返回错误
-	if len(errs) == queryCount {
+	if queryCount > 0 && len(errs) == queryCount {
 		return nil, errs[0]
 	}
 
@@ -233,6 +265,21 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 		items = append(items, item)
 	}
 
+	// 转换发育里程碑
+	for _, milestone := range milestones {
+		item := dto.TimelineItem{
+			RecordType: "milestone",
+			RecordID:   milestone.MilestoneID,
+			BabyID:     milestone.BabyID,
+			EventTime:  *milestone.AchievedDate,
+			Detail:     milestone,
+			CreateBy:   milestone.CreateBy,
+			CreateTime: milestone.CreateTime,
+		}
+		s.enrichTimelineItem(ctx, &item)
+		items = append(items, item)
+	}
+
 	// 按 eventTime 倒序排序 (最新的在前面)
 	sort.Slice(items, func(i, j int) bool {
 		return items[i].EventTime > items[j].EventTime
@@ -254,6 +301,11 @@ func (s *TimelineService) GetTimeline(ctx context.Context, openID string, query
 		items = items[start:end]
 	}
 
+	// 附上当前页记录的评论和表情回应,失败时不影响时间线本身
+	if err := s.commentService.EnrichTimelineItems(ctx, openID, query.BabyID, items); err != nil {
+		s.logger.Warn("获取时间线评论失败", zap.Error(err))
+	}
+
 	return &dto.TimelineResponse{
 		Items:    items,
 		Total:    total,
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	trashPurgeBatchSize       = 500
+)
+
+// trashRecordTypes 回收站支持的记录类型
+var trashRecordTypes = []string{
+	entity.AttachmentRecordTypeFeeding,
+	entity.AttachmentRecordTypeSleep,
+	entity.AttachmentRecordTypeDiaper,
+	entity.AttachmentRecordTypeGrowth,
+	entity.AttachmentRecordTypeMilestone,
+	entity.AttachmentRecordTypeVaccine,
+}
+
+// TrashService 回收站服务
+// 已删除的记录在保留期内可以查看和恢复,到期后由定时任务彻底删除
+type TrashService struct {
+	*BaseRecordService
+	trashRepo         repository.RecordTrashRepository
+	revisionRepo      repository.RecordRevisionRepository
+	feedingRecordRepo repository.FeedingRecordRepository
+	sleepRecordRepo   repository.SleepRecordRepository
+	diaperRecordRepo  repository.DiaperRecordRepository
+	growthRecordRepo  repository.GrowthRecordRepository
+	milestoneRepo     repository.BabyMilestoneRepository
+	scheduleRepo      repository.BabyVaccineScheduleRepository
+	revisionService   *RevisionService
+	uploadService     *UploadService
+	conflicts         *restoreConflictChecker
+	retentionDays     int
+}
+
+// NewTrashService 创建回收站服务
+func NewTrashService(
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	trashRepo repository.RecordTrashRepository,
+	revisionRepo repository.RecordRevisionRepository,
+	feedingRecordRepo repository.FeedingRecordRepository,
+	sleepRecordRepo repository.SleepRecordRepository,
+	diaperRecordRepo repository.DiaperRecordRepository,
+	growthRecordRepo repository.GrowthRecordRepository,
+	milestoneRepo repository.BabyMilestoneRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	revisionService *RevisionService,
+	uploadService *UploadService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *TrashService {
+	retentionDays := cfg.Trash.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = defaultTrashRetentionDays
+	}
+
+	return &TrashService{
+		BaseRecordService: NewBaseRecordService(babyRepo, collaboratorRepo, userRepo, logger),
+		trashRepo:         trashRepo,
+		revisionRepo:      revisionRepo,
+		feedingRecordRepo: feedingRecordRepo,
+		sleepRecordRepo:   sleepRecordRepo,
+		diaperRecordRepo:  diaperRecordRepo,
+		growthRecordRepo:  growthRecordRepo,
+		milestoneRepo:     milestoneRepo,
+		scheduleRepo:      scheduleRepo,
+		revisionService:   revisionService,
+		uploadService:     uploadService,
+		conflicts:         newRestoreConflictChecker(feedingRecordRepo, sleepRecordRepo, diaperRecordRepo, growthRecordRepo, milestoneRepo, scheduleRepo),
+		retentionDays:     retentionDays,
+	}
+}
+
+// trashEntry 已删除记录的通用视图
+type trashEntry struct {
+	recordType string
+	record     any
+	recordID   int64
+	babyID     int64
+	createdBy  int64
+	eventTime  int64
+	deletedAt  int64
+}
+
+// recordSpan 记录占用的时间段(毫秒),单点记录的开始和结束相同
+type recordSpan struct {
+	start int64
+	end   int64
+}
+
+// GetTrash 获取宝宝回收站中保留期内的已删除记录(按删除时间倒序),只包含当前用户有查看权限的记录类型
+func (s *TrashService) GetTrash(ctx context.Context, openID, babyID string, query *dto.TrashQuery) (*dto.TrashResponse, error) {
+	readable, err := s.ReadableResources(ctx, babyID, openID)
+	if err != nil {
+		return nil, err
+	}
+	if query.RecordType != "" && !readable[query.RecordType] {
+		return nil, permissionDenied(query.RecordType, entity.PermissionActionRead)
+	}
+
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	since := s.retentionCutoff(time.Now())
+
+	var entries []trashEntry
+	for _, recordType := range trashRecordTypes {
+		if (query.RecordType != "" && query.RecordType != recordType) || !readable[recordType] {
+			continue
+		}
+		found, err := s.findDeleted(ctx, recordType, babyIDInt64, since)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, found...)
+	}
+	sortTrashEntries(entries)
+
+	page := query.GetPageWithDefault()
+	pageSize := query.GetPageSizeWithDefault()
+	total := int64(len(entries))
+
+	start := (page - 1) * pageSize
+	if start >= len(entries) {
+		entries = nil
+	} else {
+		end := start + pageSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		entries = entries[start:end]
+	}
+
+	// 删除人来自修订历史,查询失败时不影响回收站本身
+	deleters := make(map[string]*entity.RecordRevision)
+	if len(entries) > 0 {
+		revisions, err := s.revisionRepo.FindByBabyAndAction(ctx, babyIDInt64, entity.RevisionActionDelete, since)
+		if err != nil {
+			s.logger.Warn("查询记录删除人失败", zap.String("babyId", babyID), zap.Error(err))
+		}
+		for _, revision := range revisions {
+			key := recordKey(revision.RecordType, revision.RecordID)
+			if _, ok := deleters[key]; !ok {
+				deleters[key] = revision
+			}
+		}
+	}
+
+	creatorNames := make(map[int64]string)
+	items := make([]dto.TrashItemDTO, 0, len(entries))
+	for _, entry := range entries {
+		if _, ok := creatorNames[entry.createdBy]; !ok && entry.createdBy != 0 {
+			if user, err := s.userRepo.FindByID(ctx, entry.createdBy); err == nil {
+				creatorNames[entry.createdBy] = user.NickName
+			} else {
+				creatorNames[entry.createdBy] = ""
+			}
+		}
+		items = append(items, s.toTrashItemDTO(entry, creatorNames[entry.createdBy], deleters[recordKey(entry.recordType, entry.recordID)]))
+	}
+
+	return &dto.TrashResponse{
+		Items:         items,
+		Total:         total,
+		Page:          page,
+		PageSize:      pageSize,
+		RetentionDays: s.retentionDays,
+	}, nil
+}
+
+// RestoreRecord 从回收站恢复记录
+// 按当前权限重新校验(删除后成员的角色或权限可能已变化),并检查与现有记录是否重叠或重复;
+// 随记录一同删除的附件、评论和表情回应一并恢复,恢复操作记入修订历史
+func (s *TrashService) RestoreRecord(ctx context.Context, openID string, params *dto.TrashRecordParams) (*dto.RestoredRecordDTO, error) {
+	babyIDInt64, err := strconv.ParseInt(params.BabyID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+	recordID, err := strconv.ParseInt(params.RecordID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid record id format")
+	}
+
+	record, err := newTrashRecord(params.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.trashRepo.FindDeletedByID(ctx, record, recordID); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, errors.New(errors.RecordNotFound, "回收站中没有该记录")
+		}
+		return nil, err
+	}
+
+	entry, ok := newTrashEntry(params.RecordType, record)
+	if !ok || entry.babyID != babyIDInt64 {
+		return nil, errors.New(errors.RecordNotFound, "回收站中没有该记录")
+	}
+
+	if err := s.CheckRecordPermission(ctx, params.BabyID, openID, params.RecordType, entity.PermissionActionDelete); err != nil {
+		return nil, err
+	}
+
+	if entry.deletedAt < s.retentionCutoff(time.Now()) {
+		return nil, errors.New(errors.Conflict, "记录已超过保留期限,无法恢复")
+	}
+
+	if err := s.conflicts.check(ctx, record); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.trashRepo.Restore(ctx, record, params.RecordType, recordID, entry.deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, errors.New(errors.Conflict, "记录已被恢复")
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionRestore, params.RecordType, recordID, nil); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("已从回收站恢复记录",
+		zap.String("babyId", params.BabyID),
+		zap.String("recordType", params.RecordType),
+		zap.String("recordId", params.RecordID),
+		zap.String("operator", openID))
+
+	return &dto.RestoredRecordDTO{
+		RecordType: params.RecordType,
+		RecordID:   params.RecordID,
+		EventTime:  entry.eventTime,
+		Detail:     revisionSnapshotOf(record),
+	}, nil
+}
+
+// PurgeExpired 彻底删除超过保留期限的已删除记录及其附件文件(定时任务调用)
+func (s *TrashService) PurgeExpired(ctx context.Context) error {
+	cutoff := s.retentionCutoff(time.Now())
+
+	purged := 0
+	for _, recordType := range trashRecordTypes {
+		model, err := newTrashRecord(recordType)
+		if err != nil {
+			return err
+		}
+
+		for {
+			ids, err := s.trashRepo.FindPurgeable(ctx, model, cutoff, trashPurgeBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+
+			paths, err := s.trashRepo.Purge(ctx, model, recordType, ids)
+			if err != nil {
+				return err
+			}
+			// 记录级联删除的附件只做了软删除,彻底删除时清理文件
+			for _, path := range paths {
+				if err := s.uploadService.DeleteFile(ctx, path); err != nil {
+					s.logger.Warn("删除附件文件失败", zap.String("path", path), zap.Error(err))
+				}
+			}
+
+			purged += len(ids)
+			if len(ids) < trashPurgeBatchSize {
+				break
+			}
+		}
+	}
+
+	if purged > 0 {
+		s.logger.Info("已彻底删除过期的回收站记录",
+			zap.Int("count", purged),
+			zap.Int("retentionDays", s.retentionDays))
+	}
+	return nil
+}
+
+// findDeleted 查询某类记录中宝宝在 since 之后删除的记录
+func (s *TrashService) findDeleted(ctx context.Context, recordType string, babyID, since int64) ([]trashEntry, error) {
+	records, err := newTrashRecords(recordType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.trashRepo.FindDeleted(ctx, records, babyID, since); err != nil {
+		return nil, err
+	}
+
+	list := reflect.ValueOf(records).Elem()
+	entries := make([]trashEntry, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		if entry, ok := newTrashEntry(recordType, list.Index(i).Interface()); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// retentionCutoff 保留期的起点,早于该时间删除的记录不再显示并将被彻底删除
+func (s *TrashService) retentionCutoff(now time.Time) int64 {
+	return now.AddDate(0, 0, -s.retentionDays).UnixMilli()
+}
+
+// toTrashItemDTO 转换为DTO
+func (s *TrashService) toTrashItemDTO(entry trashEntry, creatorName string, deleter *entity.RecordRevision) dto.TrashItemDTO {
+	item := dto.TrashItemDTO{
+		RecordType: entry.recordType,
+		RecordID:   strconv.FormatInt(entry.recordID, 10),
+		EventTime:  entry.eventTime,
+		Detail:     revisionSnapshotOf(entry.record),
+		CreateBy:   strconv.FormatInt(entry.createdBy, 10),
+		CreateName: creatorName,
+		DeletedAt:  entry.deletedAt,
+		PurgeAt:    trashPurgeAt(entry.deletedAt, s.retentionDays),
+	}
+	if deleter != nil {
+		item.DeletedBy = strconv.FormatInt(deleter.ActorID, 10)
+		item.DeletedByName = deleter.ActorName
+	}
+	return item
+}
+
+// newTrashRecord 返回记录类型对应的实体指针
+func newTrashRecord(recordType string) (any, error) {
+	switch recordType {
+	case entity.AttachmentRecordTypeFeeding:
+		return &entity.FeedingRecord{}, nil
+	case entity.AttachmentRecordTypeSleep:
+		return &entity.SleepRecord{}, nil
+	case entity.AttachmentRecordTypeDiaper:
+		return &entity.DiaperRecord{}, nil
+	case entity.AttachmentRecordTypeGrowth:
+		return &entity.GrowthRecord{}, nil
+	case entity.AttachmentRecordTypeMilestone:
+		return &entity.BabyMilestone{}, nil
+	case entity.AttachmentRecordTypeVaccine:
+		return &entity.BabyVaccineSchedule{}, nil
+	default:
+		return nil, errors.New(errors.ParamError, "不支持的记录类型")
+	}
+}
+
+// newTrashRecords 返回记录类型对应的实体切片指针
+func newTrashRecords(recordType string) (any, error) {
+	switch recordType {
+	case entity.AttachmentRecordTypeFeeding:
+		return &[]*entity.FeedingRecord{}, nil
+	case entity.AttachmentRecordTypeSleep:
+		return &[]*entity.SleepRecord{}, nil
+	case entity.AttachmentRecordTypeDiaper:
+		return &[]*entity.DiaperRecord{}, nil
+	case entity.AttachmentRecordTypeGrowth:
+		return &[]*entity.GrowthRecord{}, nil
+	case entity.AttachmentRecordTypeMilestone:
+		return &[]*entity.BabyMilestone{}, nil
+	case entity.AttachmentRecordTypeVaccine:
+		return &[]*entity.BabyVaccineSchedule{}, nil
+	default:
+		return nil, errors.New(errors.ParamError, "不支持的记录类型")
+	}
+}
+
+// newTrashEntry 提取已删除记录的通用信息;里程碑以达成日期、疫苗日程以接种日期(未接种时为计划日期)作为记录时间
+func newTrashEntry(recordType string, record any) (trashEntry, bool) {
+	entry := trashEntry{recordType: recordType, record: record}
+
+	switch r := record.(type) {
+	case *entity.FeedingRecord:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.Time, int64(r.DeletedAt)
+	case *entity.SleepRecord:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.StartTime, int64(r.DeletedAt)
+	case *entity.DiaperRecord:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.Time, int64(r.DeletedAt)
+	case *entity.GrowthRecord:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.Time, int64(r.DeletedAt)
+	case *entity.BabyMilestone:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.CreatedAt, int64(r.DeletedAt)
+		if r.AchievedDate != nil {
+			entry.eventTime = *r.AchievedDate
+		}
+	case *entity.BabyVaccineSchedule:
+		entry.recordID, entry.babyID, entry.createdBy = r.ID, r.BabyID, r.CreatedBy
+		entry.eventTime, entry.deletedAt = r.ScheduledDate, int64(r.DeletedAt)
+		if r.VaccineDate != nil {
+			entry.eventTime = *r.VaccineDate
+		}
+	default:
+		return entry, false
+	}
+
+	return entry, true
+}
+
+// sortTrashEntries 按删除时间倒序排列,同时删除的按记录时间倒序
+func sortTrashEntries(entries []trashEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].deletedAt != entries[j].deletedAt {
+			return entries[i].deletedAt > entries[j].deletedAt
+		}
+		return entries[i].eventTime > entries[j].eventTime
+	})
+}
+
+// trashPurgeAt 记录到期彻底删除的时间
+func trashPurgeAt(deletedAt int64, retentionDays int) int64 {
+	return time.UnixMilli(deletedAt).AddDate(0, 0, retentionDays).UnixMilli()
+}
+
+// feedingSpan 喂养占用的时间段(母乳等带时长的喂养为开始时间加时长)
+func feedingSpan(record *entity.FeedingRecord) recordSpan {
+	return recordSpan{
+		start: record.Time,
+		end:   record.Time + int64(record.Duration)*1000,
+	}
+}
+
+// sleepSpan 睡眠占用的时间段,进行中的睡眠截止到当前时间
+func sleepSpan(record *entity.SleepRecord, now int64) recordSpan {
+	if !sleepOngoing(record) {
+		return recordSpan{start: record.StartTime, end: *record.EndTime}
+	}
+	if record.StartTime > now {
+		return recordSpan{start: record.StartTime, end: record.StartTime}
+	}
+	return recordSpan{start: record.StartTime, end: now}
+}
+
+// spansOverlap 两个时间段是否重叠;首尾相接不算重叠,开始时间相同视为重叠(包括两条单点记录)
+func spansOverlap(a, b recordSpan) bool {
+	if a.start == b.start {
+		return true
+	}
+	return a.start < b.end && b.start < a.end
+}
+
+// overlapsAny 时间段是否与任一现有时间段重叠
+func overlapsAny(span recordSpan, existing []recordSpan) bool {
+	for _, other := range existing {
+		if spansOverlap(span, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateMilestone 是否已有来自同一模板的里程碑
+func duplicateMilestone(existing []*entity.BabyMilestone, milestone *entity.BabyMilestone) bool {
+	if milestone.TemplateID == nil {
+		return false
+	}
+	for _, other := range existing {
+		if other.ID != milestone.ID && other.TemplateID != nil && *other.TemplateID == *milestone.TemplateID {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateVaccineDose 是否已有同一疫苗的同一剂次
+func duplicateVaccineDose(existing []*entity.BabyVaccineSchedule, schedule *entity.BabyVaccineSchedule) bool {
+	for _, other := range existing {
+		if other.ID != schedule.ID && other.VaccineType == schedule.VaccineType && other.DoseNumber == schedule.DoseNumber {
+			return true
+		}
+	}
+	return false
+}
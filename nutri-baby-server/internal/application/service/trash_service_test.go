@@ -0,0 +1,137 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/plugin/soft_delete"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+)
+
+func TestSpansOverlap(t *testing.T) {
+	nap := recordSpan{start: 1000, end: 5000}
+
+	assert.True(t, spansOverlap(nap, recordSpan{start: 4000, end: 8000}))
+	assert.True(t, spansOverlap(nap, recordSpan{start: 2000, end: 3000}))
+	// 首尾相接不算重叠
+	assert.False(t, spansOverlap(nap, recordSpan{start: 5000, end: 8000}))
+	assert.False(t, spansOverlap(recordSpan{start: 0, end: 1000}, nap))
+	// 同一时间的单点记录视为重复
+	assert.True(t, spansOverlap(recordSpan{start: 3000, end: 3000}, recordSpan{start: 3000, end: 3000}))
+	assert.False(t, spansOverlap(recordSpan{start: 3000, end: 3000}, recordSpan{start: 3001, end: 3001}))
+
+	assert.True(t, overlapsAny(nap, []recordSpan{{start: 9000, end: 9500}, {start: 4500, end: 6000}}))
+	assert.False(t, overlapsAny(nap, nil))
+}
+
+func TestSleepSpan(t *testing.T) {
+	end := int64(5000)
+	assert.Equal(t, recordSpan{start: 1000, end: 5000}, sleepSpan(&entity.SleepRecord{StartTime: 1000, EndTime: &end}, 9000))
+
+	// 进行中的睡眠(结束时间为空或0)截止到当前时间
+	zero := int64(0)
+	assert.Equal(t, recordSpan{start: 1000, end: 9000}, sleepSpan(&entity.SleepRecord{StartTime: 1000, EndTime: &zero}, 9000))
+	assert.Equal(t, recordSpan{start: 1000, end: 9000}, sleepSpan(&entity.SleepRecord{StartTime: 1000}, 9000))
+	assert.Equal(t, recordSpan{start: 9500, end: 9500}, sleepSpan(&entity.SleepRecord{StartTime: 9500}, 9000))
+}
+
+func TestFeedingSpan(t *testing.T) {
+	breast := &entity.FeedingRecord{FeedingType: "breast", Time: 1000, Duration: 600}
+	assert.Equal(t, recordSpan{start: 1000, end: 601000}, feedingSpan(breast))
+
+	bottle := &entity.FeedingRecord{FeedingType: "bottle", Time: 1000, Amount: 120}
+	assert.Equal(t, recordSpan{start: 1000, end: 1000}, feedingSpan(bottle))
+}
+
+func TestNewTrashEntry(t *testing.T) {
+	entry, ok := newTrashEntry(entity.AttachmentRecordTypeDiaper, &entity.DiaperRecord{
+		ID: 1, BabyID: 2, CreatedBy: 3, Time: 1000, DeletedAt: soft_delete.DeletedAt(2000),
+	})
+	require.True(t, ok)
+	assert.Equal(t, int64(1), entry.recordID)
+	assert.Equal(t, int64(2), entry.babyID)
+	assert.Equal(t, int64(3), entry.createdBy)
+	assert.Equal(t, int64(1000), entry.eventTime)
+	assert.Equal(t, int64(2000), entry.deletedAt)
+
+	// 里程碑以达成日期为记录时间,未达成时为创建时间
+	achieved := int64(3000)
+	entry, ok = newTrashEntry(entity.AttachmentRecordTypeMilestone, &entity.BabyMilestone{CreatedAt: 1000, AchievedDate: &achieved})
+	require.True(t, ok)
+	assert.Equal(t, int64(3000), entry.eventTime)
+	entry, _ = newTrashEntry(entity.AttachmentRecordTypeMilestone, &entity.BabyMilestone{CreatedAt: 1000})
+	assert.Equal(t, int64(1000), entry.eventTime)
+
+	// 疫苗日程以接种日期为记录时间,未接种时为计划日期
+	entry, ok = newTrashEntry(entity.AttachmentRecordTypeVaccine, &entity.BabyVaccineSchedule{ScheduledDate: 4000})
+	require.True(t, ok)
+	assert.Equal(t, int64(4000), entry.eventTime)
+
+	_, ok = newTrashEntry("unknown", &entity.Attachment{})
+	assert.False(t, ok)
+}
+
+func TestNewTrashRecord(t *testing.T) {
+	for _, recordType := range trashRecordTypes {
+		record, err := newTrashRecord(recordType)
+		require.NoError(t, err)
+		_, ok := newTrashEntry(recordType, record)
+		assert.True(t, ok, recordType)
+
+		_, err = newTrashRecords(recordType)
+		assert.NoError(t, err)
+	}
+
+	_, err := newTrashRecord("unknown")
+	assert.ErrorContains(t, err, "不支持的记录类型")
+}
+
+func TestSortTrashEntries(t *testing.T) {
+	entries := []trashEntry{
+		{recordID: 1, deletedAt: 1000, eventTime: 100},
+		{recordID: 2, deletedAt: 3000, eventTime: 100},
+		{recordID: 3, deletedAt: 1000, eventTime: 500},
+	}
+	sortTrashEntries(entries)
+
+	assert.Equal(t, int64(2), entries[0].recordID)
+	assert.Equal(t, int64(3), entries[1].recordID)
+	assert.Equal(t, int64(1), entries[2].recordID)
+}
+
+func TestTrashRetention(t *testing.T) {
+	// 未配置时使用默认保留天数
+	s := NewTrashService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, nil)
+	assert.Equal(t, defaultTrashRetentionDays, s.retentionDays)
+
+	s = NewTrashService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Trash: config.TrashConfig{RetentionDays: 7}}, nil)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC).UnixMilli(), s.retentionCutoff(now))
+
+	deletedAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, deletedAt.AddDate(0, 0, 7).UnixMilli(), trashPurgeAt(deletedAt.UnixMilli(), 7))
+}
+
+func TestDuplicateMilestone(t *testing.T) {
+	templateID, otherTemplateID := int64(10), int64(11)
+	milestone := &entity.BabyMilestone{ID: 1, TemplateID: &templateID}
+
+	assert.True(t, duplicateMilestone([]*entity.BabyMilestone{{ID: 2, TemplateID: &templateID}}, milestone))
+	assert.False(t, duplicateMilestone([]*entity.BabyMilestone{{ID: 2, TemplateID: &otherTemplateID}, {ID: 3}}, milestone))
+	// 自定义里程碑没有模板,不判重
+	assert.False(t, duplicateMilestone([]*entity.BabyMilestone{{ID: 2}}, &entity.BabyMilestone{ID: 1}))
+}
+
+func TestDuplicateVaccineDose(t *testing.T) {
+	schedule := &entity.BabyVaccineSchedule{ID: 1, VaccineType: "HepB", DoseNumber: 2}
+
+	assert.True(t, duplicateVaccineDose([]*entity.BabyVaccineSchedule{{ID: 2, VaccineType: "HepB", DoseNumber: 2}}, schedule))
+	assert.False(t, duplicateVaccineDose([]*entity.BabyVaccineSchedule{
+		{ID: 2, VaccineType: "HepB", DoseNumber: 1},
+		{ID: 3, VaccineType: "BCG", DoseNumber: 2},
+	}, schedule))
+}
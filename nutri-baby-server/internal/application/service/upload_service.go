@@ -3,19 +3,23 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
-	"path/filepath"
+	pathpkg "path"
 	"strings"
 	"time"
 
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/imageproc"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
 	"github.com/wxlbd/nutri-baby-server/pkg/errors"
 )
 
 // UploadService 文件上传服务
 type UploadService struct {
-	cfg *config.Config
+	cfg     *config.Config
+	storage storage.Storage
 }
 
 // UploadType 上传类型
@@ -24,62 +28,60 @@ type UploadType string
 const (
 	UploadTypeUserAvatar UploadType = "user_avatar"
 	UploadTypeBabyAvatar UploadType = "baby_avatar"
+	UploadTypeAttachment UploadType = "attachment" // 记录照片附件
 )
 
 // UploadResult 上传结果
 type UploadResult struct {
-	URL      string `json:"url"`      // 完整访问URL
-	Path     string `json:"path"`     // 相对路径
-	Filename string `json:"filename"` // 文件名
-	Size     int64  `json:"size"`     // 文件大小
+	URL      string                    `json:"url"`      // 完整访问URL(原图)
+	Path     string                    `json:"path"`     // 相对路径(原图)
+	Filename string                    `json:"filename"` // 文件名(原图)
+	Size     int64                     `json:"size"`     // 文件大小(原图)
+	MimeType string                    `json:"mimeType"` // 按文件内容识别的类型
+	Width    int                       `json:"width"`    // 原图宽度(已校正方向)
+	Height   int                       `json:"height"`   // 原图高度(已校正方向)
+	Variants map[string]*UploadVariant `json:"variants"` // 所有尺寸变体: original, display, thumbnail
 }
 
+// UploadVariant 图片变体
+type UploadVariant struct {
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
+}
+
+// 图片变体文件名后缀(原图不带后缀)
+var variantSuffixes = map[string]string{
+	imageproc.VariantDisplay:   "_display",
+	imageproc.VariantThumbnail: "_thumb",
+}
+
+// 私密文件的 key 前缀(宝宝照片、数据导出),只能通过签名URL访问
+var privateKeyPrefixes = []string{
+	"images/attachments/",
+	"exports/",
+}
+
+// 默认签名URL有效期
+const defaultSignedURLExpire = time.Hour
+
 // NewUploadService 创建上传服务
-func NewUploadService(cfg *config.Config) *UploadService {
+func NewUploadService(cfg *config.Config, store storage.Storage) *UploadService {
 	return &UploadService{
-		cfg: cfg,
+		cfg:     cfg,
+		storage: store,
 	}
 }
 
 // UploadFile 上传文件
+// 按文件内容(魔数)校验类型,校正方向并去除 EXIF 后保存原图、展示图和缩略图
 func (s *UploadService) UploadFile(ctx context.Context, fileHeader *multipart.FileHeader, uploadType UploadType, relatedID string) (*UploadResult, error) {
 	if fileHeader == nil {
 		return nil, errors.New(errors.ParamError, "File cannot be empty")
 	}
 
-	// MIME type to extension mapping
-	mimeToExt := map[string]string{
-		"image/jpeg": ".jpg",
-		"image/jpg":  ".jpg",
-		"image/png":  ".png",
-		"image/gif":  ".gif",
-	}
-
-	// Validate file type
-	allowedTypes := s.cfg.Upload.AllowedTypes
-	fileExt := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	isAllowed := false
-	allowedExts := []string{}
-
-	// Collect all allowed extensions
-	for _, mimeType := range allowedTypes {
-		if ext, ok := mimeToExt[strings.ToLower(mimeType)]; ok {
-			allowedExts = append(allowedExts, ext)
-		}
-	}
-
-	// Validate file extension
-	for _, ext := range allowedExts {
-		if fileExt == strings.ToLower(ext) {
-			isAllowed = true
-			break
-		}
-	}
-
-	if !isAllowed {
-		return nil, errors.New(errors.ParamError, "Unsupported file type. Allowed types: "+strings.Join(allowedExts, ", "))
-	}
-
 	// Validate file size
 	if fileHeader.Size > s.cfg.Upload.MaxSize {
 		return nil, errors.New(errors.ParamError, "File size exceeds limit")
@@ -91,32 +93,102 @@ func (s *UploadService) UploadFile(ctx context.Context, fileHeader *multipart.Fi
 		return nil, errors.New(errors.ParamError, "Unsupported upload type")
 	}
 
+	data, err := s.readFile(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate file type by content, not by the client supplied extension
+	mimeType := imageproc.DetectMimeType(data)
+	if mimeType == "" || !s.isAllowedType(mimeType) {
+		return nil, errors.New(errors.ParamError, "Unsupported file type. Allowed types: "+strings.Join(s.cfg.Upload.AllowedTypes, ", "))
+	}
+
+	processed, err := imageproc.Process(data, s.processOptions())
+	if errors.Is(err, imageproc.ErrImageTooLarge) {
+		return nil, errors.New(errors.ParamError, "Image dimensions too large")
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ParamError, "Invalid image file", err)
+	}
+
 	// Generate filename
-	filename := s.generateFilename(fileHeader.Filename, uploadType, relatedID)
+	baseName := s.generateBaseName(uploadType, relatedID)
 
-	// Create save path
-	storagePath := s.cfg.Upload.StoragePath
-	dirPath := filepath.Join(storagePath, "images", subDir)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return nil, errors.Wrap(errors.InternalError, "Failed to create directory", err)
+	result := &UploadResult{
+		MimeType: processed.MimeType,
+		Variants: make(map[string]*UploadVariant, len(processed.Variants)),
 	}
+	var saved []string
+	for _, variant := range processed.Variants {
+		filename := baseName + variantSuffixes[variant.Name] + variant.Ext
+		key := "images/" + subDir + "/" + filename
+		if err := s.storage.Put(ctx, key, variant.Data, variant.MimeType); err != nil {
+			for _, savedKey := range saved {
+				_ = s.storage.Delete(ctx, savedKey)
+			}
+			return nil, errors.Wrap(errors.InternalError, "Failed to save file", err)
+		}
+		saved = append(saved, key)
 
-	// Save file
-	filePath := filepath.Join(dirPath, filename)
-	if err := s.saveFile(fileHeader, filePath); err != nil {
-		return nil, err
+		// Generate access URL
+		path := "/uploads/" + key
+		result.Variants[variant.Name] = &UploadVariant{
+			URL:    s.FileURL(path),
+			Path:   path,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   int64(len(variant.Data)),
+		}
+
+		if variant.Name == imageproc.VariantOriginal {
+			result.URL = result.Variants[variant.Name].URL
+			result.Path = path
+			result.Filename = filename
+			result.Size = int64(len(variant.Data))
+			result.Width = variant.Width
+			result.Height = variant.Height
+		}
 	}
 
-	// Generate access URL
-	relPath := filepath.Join("uploads", "images", subDir, filename)
-	url := s.cfg.Server.BaseURL + "/" + filepath.ToSlash(relPath)
+	return result, nil
+}
 
-	return &UploadResult{
-		URL:      url,
-		Path:     "/" + relPath,
-		Filename: filename,
-		Size:     fileHeader.Size,
-	}, nil
+// VariantURL 返回指定变体的访问URL,不存在时回退到原图
+func (r *UploadResult) VariantURL(name string) string {
+	if variant, ok := r.Variants[name]; ok {
+		return variant.URL
+	}
+	return r.URL
+}
+
+// isAllowedType 检查识别出的类型是否在允许列表中
+func (s *UploadService) isAllowedType(mimeType string) bool {
+	for _, allowed := range s.cfg.Upload.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == "image/jpg" {
+			allowed = imageproc.MimeJPEG
+		}
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// processOptions 图片处理参数,未配置的项使用默认值
+func (s *UploadService) processOptions() imageproc.Options {
+	opts := imageproc.DefaultOptions()
+	if s.cfg.Upload.DisplayMaxSize > 0 {
+		opts.DisplayMaxSize = s.cfg.Upload.DisplayMaxSize
+	}
+	if s.cfg.Upload.ThumbnailMaxSize > 0 {
+		opts.ThumbnailMaxSize = s.cfg.Upload.ThumbnailMaxSize
+	}
+	if s.cfg.Upload.JPEGQuality > 0 && s.cfg.Upload.JPEGQuality <= 100 {
+		opts.JPEGQuality = s.cfg.Upload.JPEGQuality
+	}
+	return opts
 }
 
 // getSubDir Get subdirectory by upload type
@@ -126,19 +198,15 @@ func (s *UploadService) getSubDir(uploadType UploadType) string {
 		return "users"
 	case UploadTypeBabyAvatar:
 		return "babies"
+	case UploadTypeAttachment:
+		return "attachments"
 	default:
 		return ""
 	}
 }
 
-// generateFilename Generate unique filename
-func (s *UploadService) generateFilename(originalFilename string, uploadType UploadType, relatedID string) string {
-	// Get file extension
-	ext := filepath.Ext(originalFilename)
-	if ext == "" {
-		ext = ".jpg" // Default extension
-	}
-
+// generateBaseName Generate unique filename without extension
+func (s *UploadService) generateBaseName(uploadType UploadType, relatedID string) string {
 	// Build prefix
 	prefix := string(uploadType)
 	if relatedID != "" {
@@ -148,28 +216,156 @@ func (s *UploadService) generateFilename(originalFilename string, uploadType Upl
 	// Add timestamp
 	timestamp := time.Now().Format("20060102_150405")
 
-	// Generate unique filename: {prefix}_{timestamp}{ext}
-	return fmt.Sprintf("%s_%s%s", prefix, timestamp, ext)
+	// Generate unique filename: {prefix}_{timestamp}
+	return fmt.Sprintf("%s_%s", prefix, timestamp)
 }
 
-// saveFile Save file to disk
-func (s *UploadService) saveFile(fileHeader *multipart.FileHeader, filePath string) error {
+// readFile Read uploaded file content
+func (s *UploadService) readFile(fileHeader *multipart.FileHeader) ([]byte, error) {
 	srcFile, err := fileHeader.Open()
 	if err != nil {
-		return errors.Wrap(errors.InternalError, "Failed to open file", err)
+		return nil, errors.Wrap(errors.InternalError, "Failed to open file", err)
 	}
 	defer srcFile.Close()
 
-	dstFile, err := os.Create(filePath)
+	data, err := io.ReadAll(io.LimitReader(srcFile, s.cfg.Upload.MaxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(errors.InternalError, "Failed to read file", err)
+	}
+	if int64(len(data)) > s.cfg.Upload.MaxSize {
+		return nil, errors.New(errors.ParamError, "File size exceeds limit")
+	}
+
+	return data, nil
+}
+
+// DeleteFile 删除已上传的文件及其尺寸变体
+// path 为 UploadResult.Path 返回的相对路径(/uploads/...)
+func (s *UploadService) DeleteFile(ctx context.Context, path string) error {
+	key, err := keyFromPath(path)
 	if err != nil {
-		return errors.Wrap(errors.InternalError, "Failed to create file", err)
+		return errors.New(errors.ParamError, "Invalid file path")
 	}
-	defer dstFile.Close()
 
-	// Copy file content
-	if _, err := dstFile.ReadFrom(srcFile); err != nil {
-		return errors.Wrap(errors.InternalError, "Failed to save file", err)
+	keys := []string{key}
+	for name := range variantSuffixes {
+		keys = append(keys, variantKey(key, name))
+	}
+
+	for _, k := range keys {
+		if err := s.storage.Delete(ctx, k); err != nil {
+			return errors.Wrap(errors.InternalError, "Failed to delete file", err)
+		}
 	}
 
 	return nil
 }
+
+// FileURL 返回文件的访问URL
+// 宝宝照片等私密文件返回限时签名URL,头像等公开文件返回固定URL
+func (s *UploadService) FileURL(path string) string {
+	key, err := keyFromPath(path)
+	if err != nil {
+		return ""
+	}
+
+	if !isPrivateKey(key) {
+		return s.storage.URL(key)
+	}
+
+	signedURL, err := s.storage.SignedURL(key, s.signedURLExpire())
+	if err != nil {
+		return ""
+	}
+	return signedURL
+}
+
+// ResolveURL 将保存的文件地址解析为当前可访问的URL
+// 本服务上传的文件(/uploads/... 相对路径或本服务生成的完整地址)按当前存储重新生成,私密文件返回签名URL;
+// 外部地址(如微信头像)原样返回
+func (s *UploadService) ResolveURL(raw string) string {
+	path := raw
+	if base := strings.TrimRight(s.cfg.Server.BaseURL, "/"); base != "" {
+		path = strings.TrimPrefix(path, base)
+	}
+	if !strings.HasPrefix(path, "/uploads/") {
+		return raw
+	}
+	if url := s.FileURL(path); url != "" {
+		return url
+	}
+	return raw
+}
+
+// VariantPath 返回文件指定尺寸变体的相对路径
+func (s *UploadService) VariantPath(path, variant string) string {
+	if _, ok := variantSuffixes[variant]; !ok {
+		return path
+	}
+	key, err := keyFromPath(path)
+	if err != nil {
+		return path
+	}
+	return "/uploads/" + variantKey(key, variant)
+}
+
+// ResolveFile 解析 /uploads 下的文件访问请求
+// 本地存储返回文件路径,对象存储返回重定向地址;私密文件必须携带有效签名
+func (s *UploadService) ResolveFile(ctx context.Context, key, expires, signature string) (localPath string, redirectURL string, err error) {
+	key, err = storage.CleanKey(key)
+	if err != nil {
+		return "", "", errors.New(errors.NotFound, "File not found")
+	}
+
+	local, isLocal := s.storage.(*storage.LocalStorage)
+	if isPrivateKey(key) {
+		// 对象存储的私密文件由预签名URL直接访问,不经过本服务
+		if !isLocal || !local.VerifySignature(key, expires, signature) {
+			return "", "", errors.New(errors.PermissionDenied, "Invalid or expired file signature")
+		}
+	}
+
+	if !isLocal {
+		return "", s.storage.URL(key), nil
+	}
+
+	localPath, err = local.FilePath(key)
+	if err != nil {
+		return "", "", errors.New(errors.NotFound, "File not found")
+	}
+	// 不暴露目录列表
+	if info, statErr := os.Stat(localPath); statErr != nil || info.IsDir() {
+		return "", "", errors.New(errors.NotFound, "File not found")
+	}
+	return localPath, "", nil
+}
+
+// signedURLExpire 签名URL有效期
+func (s *UploadService) signedURLExpire() time.Duration {
+	if s.cfg.Storage.SignedURLExpire > 0 {
+		return time.Duration(s.cfg.Storage.SignedURLExpire) * time.Second
+	}
+	return defaultSignedURLExpire
+}
+
+// keyFromPath 将 /uploads/... 相对路径转换为存储 key
+func keyFromPath(path string) (string, error) {
+	relPath := strings.TrimPrefix(strings.ReplaceAll(path, "\\", "/"), "/")
+	relPath = strings.TrimPrefix(relPath, "uploads/")
+	return storage.CleanKey(relPath)
+}
+
+// variantKey 尺寸变体的存储 key(变体统一为 JPEG)
+func variantKey(key, variant string) string {
+	return strings.TrimSuffix(key, pathpkg.Ext(key)) + variantSuffixes[variant] + ".jpg"
+}
+
+// isPrivateKey 是否为私密文件
+func isPrivateKey(key string) bool {
+	for _, prefix := range privateKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
+)
+
+func TestResolveURL(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{BaseURL: "https://api.example.com"}}
+	s := NewUploadService(cfg, storage.NewLocalStorage(t.TempDir(), "https://cdn.example.com", "secret"))
+
+	// 相对路径和本服务生成的地址按当前存储重新生成
+	assert.Equal(t, "https://cdn.example.com/uploads/images/babies/a.jpg", s.ResolveURL("/uploads/images/babies/a.jpg"))
+	assert.Equal(t, "https://cdn.example.com/uploads/images/babies/a.jpg", s.ResolveURL("https://api.example.com/uploads/images/babies/a.jpg"))
+
+	// 私密文件返回签名URL
+	assert.True(t, strings.HasPrefix(s.ResolveURL("/uploads/images/attachments/b.jpg"), "https://cdn.example.com/uploads/images/attachments/b.jpg?"))
+
+	// 外部地址原样返回
+	assert.Equal(t, "https://thirdwx.qlogo.cn/avatar/0", s.ResolveURL("https://thirdwx.qlogo.cn/avatar/0"))
+	assert.Empty(t, s.ResolveURL(""))
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+const dayMillis = int64(24 * time.Hour / time.Millisecond)
+
+// catchUpSchedules 按最小接种日龄和最小间隔重新计算各疫苗系列中待接种剂次的计划日期
+// 计划日期取以下三者的最晚值: 出生日期+推荐月龄、出生日期+最小日龄、上一剂(已接种取实际日期,待接种取计划日期)+最小间隔;
+// 已跳过的剂次不参与间隔计算,用户自定义的待接种日程保持原日期。返回计划日期有变化的日程(已重置提醒状态)
+func catchUpSchedules(birth time.Time, schedules []*entity.BabyVaccineSchedule) []*entity.BabyVaccineSchedule {
+	var changed []*entity.BabyVaccineSchedule
+	for _, series := range vaccineSeries(schedules) {
+		var prev int64
+		for _, schedule := range series {
+			switch {
+			case schedule.IsSkipped():
+				continue
+			case schedule.IsCompleted():
+				prev = doseDate(schedule)
+				continue
+			case schedule.IsCustom:
+				prev = schedule.ScheduledDate
+				continue
+			}
+
+			target := birth.AddDate(0, schedule.AgeInMonths, 0).UnixMilli()
+			if schedule.MinAgeDays > 0 {
+				target = max(target, birth.UnixMilli()+int64(schedule.MinAgeDays)*dayMillis)
+			}
+			if prev > 0 && schedule.MinIntervalDays > 0 {
+				target = max(target, prev+int64(schedule.MinIntervalDays)*dayMillis)
+			}
+			if target != schedule.ScheduledDate {
+				schedule.ScheduledDate = target
+				schedule.ReminderSent = false
+				schedule.ReminderSentAt = nil
+				changed = append(changed, schedule)
+			}
+			prev = target
+		}
+	}
+	return changed
+}
+
+// doseTimingWarnings 检查在 vaccineDate 接种 schedule 这一剂是否早于最小日龄或最小间隔
+func doseTimingWarnings(birth time.Time, schedules []*entity.BabyVaccineSchedule, schedule *entity.BabyVaccineSchedule, vaccineDate int64) []string {
+	var warnings []string
+
+	if schedule.MinAgeDays > 0 {
+		ageDays := floorDays(vaccineDate - birth.UnixMilli())
+		if ageDays < schedule.MinAgeDays {
+			warnings = append(warnings, fmt.Sprintf("%s第%d剂最小接种日龄为%d天,接种时宝宝仅%d天",
+				schedule.VaccineName, schedule.DoseNumber, schedule.MinAgeDays, ageDays))
+		}
+	}
+
+	// 同系列中剂次更小且未跳过的最后一剂(尚未保存的日程 ID 为 0,按指针判断是否为同一条)
+	var prev *entity.BabyVaccineSchedule
+	for _, s := range schedules {
+		if s == schedule || (s.ID != 0 && s.ID == schedule.ID) || s.VaccineType != schedule.VaccineType || s.IsSkipped() || s.DoseNumber >= schedule.DoseNumber {
+			continue
+		}
+		if prev == nil || s.DoseNumber > prev.DoseNumber {
+			prev = s
+		}
+	}
+	switch {
+	case prev == nil:
+	case !prev.IsCompleted():
+		warnings = append(warnings, fmt.Sprintf("%s第%d剂尚未记录接种", prev.VaccineName, prev.DoseNumber))
+	case schedule.MinIntervalDays > 0:
+		interval := floorDays(vaccineDate - doseDate(prev))
+		if interval < schedule.MinIntervalDays {
+			warnings = append(warnings, fmt.Sprintf("%s第%d剂与第%d剂至少间隔%d天,当前仅间隔%d天",
+				schedule.VaccineName, schedule.DoseNumber, prev.DoseNumber, schedule.MinIntervalDays, interval))
+		}
+	}
+
+	return warnings
+}
+
+// vaccineSeries 按疫苗类型分组,组内按剂次排序
+func vaccineSeries(schedules []*entity.BabyVaccineSchedule) [][]*entity.BabyVaccineSchedule {
+	index := make(map[string]int)
+	var groups [][]*entity.BabyVaccineSchedule
+	for _, schedule := range schedules {
+		i, ok := index[schedule.VaccineType]
+		if !ok {
+			i = len(groups)
+			index[schedule.VaccineType] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], schedule)
+	}
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].DoseNumber < group[j].DoseNumber })
+	}
+	return groups
+}
+
+// doseDate 已接种剂次的接种日期,缺失时使用计划日期
+func doseDate(schedule *entity.BabyVaccineSchedule) int64 {
+	if schedule.VaccineDate != nil {
+		return *schedule.VaccineDate
+	}
+	return schedule.ScheduledDate
+}
+
+// floorDays 毫秒时长折算为整天数
+func floorDays(millis int64) int {
+	if millis < 0 {
+		return -int((-millis + dayMillis - 1) / dayMillis)
+	}
+	return int(millis / dayMillis)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestCatchUpSchedules(t *testing.T) {
+	birth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dateAt := func(months int) int64 { return birth.AddDate(0, months, 0).UnixMilli() }
+	daysAfter := func(base int64, days int) int64 { return base + int64(days)*dayMillis }
+
+	// 第1剂推迟到4月龄才接种
+	late := dateAt(4)
+	dose1 := &entity.BabyVaccineSchedule{ID: 1, VaccineType: "DTaP", DoseNumber: 1, AgeInMonths: 3, MinAgeDays: 42,
+		VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &late, ScheduledDate: dateAt(3)}
+	// 第2剂原计划4月龄,需顺延到第1剂后28天
+	dose2 := &entity.BabyVaccineSchedule{ID: 2, VaccineType: "DTaP", DoseNumber: 2, AgeInMonths: 4, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(4), ReminderSent: true}
+	// 第3剂原计划5月龄,需顺延到第2剂新日期后28天
+	dose3 := &entity.BabyVaccineSchedule{ID: 3, VaccineType: "DTaP", DoseNumber: 3, AgeInMonths: 5, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(5)}
+	// 第4剂18月龄,间隔已足够,不变
+	dose4 := &entity.BabyVaccineSchedule{ID: 4, VaccineType: "DTaP", DoseNumber: 4, AgeInMonths: 18, MinIntervalDays: 180,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(18)}
+	// 其他系列不受影响
+	hepB := &entity.BabyVaccineSchedule{ID: 5, VaccineType: "HepB", DoseNumber: 2, AgeInMonths: 1, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(1)}
+
+	changed := catchUpSchedules(birth, []*entity.BabyVaccineSchedule{dose4, dose3, hepB, dose2, dose1})
+
+	require.Len(t, changed, 2)
+	assert.Equal(t, daysAfter(late, 28), dose2.ScheduledDate)
+	assert.False(t, dose2.ReminderSent)
+	assert.Equal(t, daysAfter(late, 56), dose3.ScheduledDate)
+	assert.Equal(t, dateAt(18), dose4.ScheduledDate)
+	assert.Equal(t, dateAt(1), hepB.ScheduledDate)
+
+	// 再次计算没有变化
+	assert.Empty(t, catchUpSchedules(birth, []*entity.BabyVaccineSchedule{dose1, dose2, dose3, dose4, hepB}))
+}
+
+func TestCatchUpSchedulesSkipsAndCustom(t *testing.T) {
+	birth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dateAt := func(months int) int64 { return birth.AddDate(0, months, 0).UnixMilli() }
+
+	// 最小日龄晚于推荐月龄时按最小日龄
+	ipv := &entity.BabyVaccineSchedule{ID: 1, VaccineType: "IPV", DoseNumber: 1, AgeInMonths: 1, MinAgeDays: 42,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(1)}
+	// 跳过的剂次不参与间隔计算
+	skipped := &entity.BabyVaccineSchedule{ID: 2, VaccineType: "HepA", DoseNumber: 1, AgeInMonths: 18,
+		VaccinationStatus: entity.VaccinationStatusSkipped, ScheduledDate: dateAt(18)}
+	hepA2 := &entity.BabyVaccineSchedule{ID: 3, VaccineType: "HepA", DoseNumber: 2, AgeInMonths: 24, MinIntervalDays: 180,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(24)}
+	// 自定义日程保持原日期,但作为后续剂次的间隔起点
+	custom := &entity.BabyVaccineSchedule{ID: 4, VaccineType: "Flu", DoseNumber: 1, IsCustom: true,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(7)}
+	flu2 := &entity.BabyVaccineSchedule{ID: 5, VaccineType: "Flu", DoseNumber: 2, AgeInMonths: 6, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(6)}
+
+	changed := catchUpSchedules(birth, []*entity.BabyVaccineSchedule{ipv, skipped, hepA2, custom, flu2})
+
+	require.Len(t, changed, 2)
+	assert.Equal(t, birth.AddDate(0, 0, 42).UnixMilli(), ipv.ScheduledDate)
+	assert.Equal(t, dateAt(24), hepA2.ScheduledDate)
+	assert.Equal(t, dateAt(7), custom.ScheduledDate)
+	assert.Equal(t, dateAt(7)+28*dayMillis, flu2.ScheduledDate)
+}
+
+func TestDoseTimingWarnings(t *testing.T) {
+	birth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	day := func(days int) int64 { return birth.AddDate(0, 0, days).UnixMilli() }
+
+	given := day(60)
+	dose1 := &entity.BabyVaccineSchedule{ID: 1, VaccineType: "DTaP", VaccineName: "百白破疫苗", DoseNumber: 1, MinAgeDays: 42,
+		VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &given}
+	dose2 := &entity.BabyVaccineSchedule{ID: 2, VaccineType: "DTaP", VaccineName: "百白破疫苗", DoseNumber: 2, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending}
+	dose3 := &entity.BabyVaccineSchedule{ID: 3, VaccineType: "DTaP", VaccineName: "百白破疫苗", DoseNumber: 3, MinIntervalDays: 28,
+		VaccinationStatus: entity.VaccinationStatusPending}
+	series := []*entity.BabyVaccineSchedule{dose1, dose2, dose3}
+
+	// 第1剂早于最小日龄
+	warnings := doseTimingWarnings(birth, series, &entity.BabyVaccineSchedule{ID: 1, VaccineType: "DTaP", VaccineName: "百白破疫苗",
+		DoseNumber: 1, MinAgeDays: 42}, day(30))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "42天")
+
+	// 第2剂距第1剂不足28天
+	warnings = doseTimingWarnings(birth, series, dose2, day(70))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "仅间隔10天")
+
+	// 间隔足够
+	assert.Empty(t, doseTimingWarnings(birth, series, dose2, day(88)))
+
+	// 第3剂在第2剂之前接种
+	warnings = doseTimingWarnings(birth, series, dose3, day(120))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "第2剂尚未记录接种")
+
+	// 尚未保存的日程(ID 为 0)之间同样校验间隔
+	unsaved1 := &entity.BabyVaccineSchedule{VaccineType: "Flu", VaccineName: "流感疫苗", DoseNumber: 1,
+		VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &given}
+	unsaved2 := &entity.BabyVaccineSchedule{VaccineType: "Flu", VaccineName: "流感疫苗", DoseNumber: 2, MinIntervalDays: 28}
+	warnings = doseTimingWarnings(birth, []*entity.BabyVaccineSchedule{unsaved1, unsaved2}, unsaved2, day(70))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "仅间隔10天")
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// VaccinePlanService 疫苗计划服务
+// 管理多套疫苗计划(国家免疫规划、含自费疫苗的计划、海外计划等)及宝宝的计划切换
+type VaccinePlanService struct {
+	planRepo         repository.VaccinePlanRepository
+	templateRepo     repository.VaccinePlanTemplateRepository
+	scheduleRepo     repository.BabyVaccineScheduleRepository
+	babyRepo         repository.BabyRepository
+	collaboratorRepo repository.BabyCollaboratorRepository
+	userRepo         repository.UserRepository
+	revisionService  *RevisionService // 修订历史
+	logger           *zap.Logger
+}
+
+// NewVaccinePlanService 创建疫苗计划服务
+func NewVaccinePlanService(
+	planRepo repository.VaccinePlanRepository,
+	templateRepo repository.VaccinePlanTemplateRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	babyRepo repository.BabyRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	revisionService *RevisionService,
+	logger *zap.Logger,
+) *VaccinePlanService {
+	return &VaccinePlanService{
+		planRepo:         planRepo,
+		templateRepo:     templateRepo,
+		scheduleRepo:     scheduleRepo,
+		babyRepo:         babyRepo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+		revisionService:  revisionService,
+		logger:           logger,
+	}
+}
+
+// ListPlans 获取可选的疫苗计划(每个计划的最新版本)
+func (s *VaccinePlanService) ListPlans(ctx context.Context, req *dto.ListVaccinePlansRequest) ([]dto.VaccinePlanDTO, error) {
+	plans, err := s.planRepo.FindLatest(ctx, req.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.VaccinePlanDTO, 0, len(plans))
+	for _, plan := range plans {
+		result = append(result, *toVaccinePlanDTO(plan))
+	}
+	return result, nil
+}
+
+// GetPlan 获取疫苗计划详情(含各剂次)
+func (s *VaccinePlanService) GetPlan(ctx context.Context, planID string) (*dto.VaccinePlanDetailDTO, error) {
+	planIDInt64, err := strconv.ParseInt(planID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid plan id format")
+	}
+
+	plan, err := s.planRepo.FindByID(ctx, planIDInt64)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.New(errors.NotFound, "疫苗计划不存在")
+		}
+		return nil, err
+	}
+
+	templates, err := s.templateRepo.FindByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "获取疫苗计划模板失败", err)
+	}
+
+	result := &dto.VaccinePlanDetailDTO{
+		VaccinePlanDTO: *toVaccinePlanDTO(plan),
+		Items:          make([]dto.VaccinePlanItemDTO, 0, len(templates)),
+	}
+	for _, t := range templates {
+		result.Items = append(result.Items, dto.VaccinePlanItemDTO{
+			TemplateID:      strconv.FormatInt(t.ID, 10),
+			VaccineType:     t.VaccineType,
+			VaccineName:     t.VaccineName,
+			Description:     t.Description,
+			AgeInMonths:     t.AgeInMonths,
+			DoseNumber:      t.DoseNumber,
+			IsRequired:      t.IsRequired,
+			ReminderDays:    t.ReminderDays,
+			MinAgeDays:      t.MinAgeDays,
+			MinIntervalDays: t.MinIntervalDays,
+		})
+	}
+	return result, nil
+}
+
+// GetBabyPlan 获取宝宝当前使用的疫苗计划,未选择时返回默认计划
+func (s *VaccinePlanService) GetBabyPlan(ctx context.Context, openID, babyID string) (*dto.VaccinePlanDTO, error) {
+	baby, _, err := s.checkBabyAccess(ctx, babyID, openID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.babyPlan(ctx, baby)
+	if err != nil {
+		return nil, err
+	}
+	return toVaccinePlanDTO(plan), nil
+}
+
+// SwitchBabyPlan 为宝宝切换疫苗计划(仅管理员)
+// 已接种、已跳过及自定义的日程保持不变;其余待接种日程按新计划新增、调整或删除
+func (s *VaccinePlanService) SwitchBabyPlan(ctx context.Context, openID, babyID string, req *dto.SwitchVaccinePlanRequest) (*dto.VaccinePlanReconcileDTO, error) {
+	baby, user, err := s.checkBabyAccess(ctx, babyID, openID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	planIDInt64, err := strconv.ParseInt(req.PlanID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid plan id format")
+	}
+	plan, err := s.planRepo.FindByID(ctx, planIDInt64)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.New(errors.NotFound, "疫苗计划不存在")
+		}
+		return nil, err
+	}
+
+	result, err := s.reconcile(ctx, baby, plan, user.ID, openID, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		s.logger.Info("切换疫苗计划",
+			zap.Int64("babyId", baby.ID),
+			zap.Int64("fromPlanId", baby.VaccinePlanID),
+			zap.Int64("toPlanId", plan.ID),
+			zap.Int("added", len(result.Added)),
+			zap.Int("updated", len(result.Updated)),
+			zap.Int("removed", len(result.Removed)))
+	}
+	return result, nil
+}
+
+// RebaseBabySchedules 宝宝出生日期变化后重新计算待接种日程的计划日期
+// baby 为已修改出生日期(尚未保存亦可)的宝宝。只按新出生日期及补种规则调整来自计划的待接种日程,
+// 不新增或删除日程,不按模板修改其他字段,也不改变宝宝使用的计划;已接种、已跳过及自定义的日程不变
+func (s *VaccinePlanService) RebaseBabySchedules(ctx context.Context, baby *entity.Baby, openID string) (*dto.VaccinePlanReconcileDTO, error) {
+	birth, err := time.Parse("2006-01-02", baby.BirthDate)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "宝宝出生日期格式错误")
+	}
+
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, baby.ID, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	befores := revisionSnapshotsByID(schedules)
+	rescheduled := catchUpSchedules(birth, schedules)
+	if err := s.scheduleRepo.UpdateScheduledDates(ctx, rescheduled); err != nil {
+		return nil, err
+	}
+	err = s.revisionService.RecordMany(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine,
+		revisionRecords(rescheduled), befores)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("出生日期变化,重新计算疫苗日程",
+		zap.Int64("babyId", baby.ID),
+		zap.String("birthDate", baby.BirthDate),
+		zap.String("operator", openID),
+		zap.Int("updated", len(rescheduled)))
+
+	return &dto.VaccinePlanReconcileDTO{
+		Added:   []dto.VaccinePlanChangeDTO{},
+		Updated: toPlanChangeDTOs(rescheduled),
+		Removed: []dto.VaccinePlanChangeDTO{},
+		Kept:    []dto.VaccinePlanChangeDTO{},
+	}, nil
+}
+
+// reconcile 按计划调整宝宝的待接种日程,dryRun 时只计算不保存;保存后以 openID 为操作人记录各日程的修订
+func (s *VaccinePlanService) reconcile(ctx context.Context, baby *entity.Baby, plan *entity.VaccinePlan, userID int64, openID string, dryRun bool) (*dto.VaccinePlanReconcileDTO, error) {
+	birth, err := time.Parse("2006-01-02", baby.BirthDate)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "宝宝出生日期格式错误")
+	}
+
+	templates, err := s.templateRepo.FindByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "获取疫苗计划模板失败", err)
+	}
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, baby.ID, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	befores := revisionSnapshotsByID(schedules)
+	changes := reconcileVaccineSchedules(baby.ID, birth, schedules, templates, userID)
+	if !dryRun {
+		deleteIDs := make([]int64, 0, len(changes.removed))
+		for _, schedule := range changes.removed {
+			deleteIDs = append(deleteIDs, schedule.ID)
+		}
+		if err := s.scheduleRepo.ApplyPlan(ctx, baby.ID, plan.ID, changes.added, changes.updated, deleteIDs); err != nil {
+			return nil, err
+		}
+
+		recordType := entity.AttachmentRecordTypeVaccine
+		if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionCreate, recordType, revisionRecords(changes.added), nil); err != nil {
+			return nil, err
+		}
+		if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionUpdate, recordType, revisionRecords(changes.updated), befores); err != nil {
+			return nil, err
+		}
+		if err := s.revisionService.RecordMany(ctx, openID, entity.RevisionActionDelete, recordType, revisionRecords(changes.removed), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.VaccinePlanReconcileDTO{
+		Plan:    toVaccinePlanDTO(plan),
+		DryRun:  dryRun,
+		Added:   toPlanChangeDTOs(changes.added),
+		Updated: toPlanChangeDTOs(changes.updated),
+		Removed: toPlanChangeDTOs(changes.removed),
+		Kept:    toPlanChangeDTOs(changes.kept),
+	}, nil
+}
+
+// babyPlan 宝宝当前使用的计划
+func (s *VaccinePlanService) babyPlan(ctx context.Context, baby *entity.Baby) (*entity.VaccinePlan, error) {
+	var (
+		plan *entity.VaccinePlan
+		err  error
+	)
+	if baby.VaccinePlanID > 0 {
+		plan, err = s.planRepo.FindByID(ctx, baby.VaccinePlanID)
+	} else {
+		plan, err = s.planRepo.FindDefault(ctx)
+	}
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, errors.New(errors.NotFound, "未找到疫苗计划")
+	}
+	return plan, err
+}
+
+// checkBabyAccess 检查宝宝访问权限,requireAdmin 时要求管理员
+func (s *VaccinePlanService) checkBabyAccess(ctx context.Context, babyID, openID string, requireAdmin bool) (*entity.Baby, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if requireAdmin {
+		isAdmin, err := s.collaboratorRepo.IsAdmin(ctx, babyIDInt64, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isAdmin {
+			return nil, nil, errors.New(errors.PermissionDenied, "只有管理员可以切换疫苗计划")
+		}
+	} else {
+		collaborator, err := s.collaboratorRepo.CheckPermission(ctx, babyIDInt64, user.ID)
+		if err != nil || collaborator == nil || !collaborator.Can(entity.PermissionResourceVaccine, entity.PermissionActionRead) {
+			return nil, nil, errors.New(errors.PermissionDenied, "无权访问该宝宝的疫苗信息")
+		}
+	}
+
+	baby, err := s.babyRepo.FindByID(ctx, babyIDInt64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return baby, user, nil
+}
+
+// vaccinePlanChanges 日程调整结果
+type vaccinePlanChanges struct {
+	added   []*entity.BabyVaccineSchedule
+	updated []*entity.BabyVaccineSchedule
+	removed []*entity.BabyVaccineSchedule
+	kept    []*entity.BabyVaccineSchedule
+}
+
+// vaccineDoseKey 以疫苗类型+剂次标识同一剂疫苗
+type vaccineDoseKey struct {
+	vaccineType string
+	doseNumber  int
+}
+
+// reconcileVaccineSchedules 计算将宝宝日程调整为指定计划所需的变更
+// 已接种、已跳过、自定义的日程一律保留,并视为已覆盖计划中的同一剂;
+// 来自模板的待接种日程按模板更新,计划中没有的删除;计划中未被覆盖的剂次新增为待接种日程。
+// 计划日期按出生日期重新计算,并按最小日龄和最小间隔顺延(见 catchUpSchedules)
+func reconcileVaccineSchedules(
+	babyID int64,
+	birth time.Time,
+	schedules []*entity.BabyVaccineSchedule,
+	templates []*entity.VaccinePlanTemplate,
+	createdBy int64,
+) *vaccinePlanChanges {
+	changes := &vaccinePlanChanges{}
+
+	covered := make(map[vaccineDoseKey]bool)
+	pending := make(map[vaccineDoseKey][]*entity.BabyVaccineSchedule)
+	for _, schedule := range schedules {
+		key := vaccineDoseKey{schedule.VaccineType, schedule.DoseNumber}
+		if schedule.IsPending() && !schedule.IsCustom {
+			pending[key] = append(pending[key], schedule)
+			continue
+		}
+		covered[key] = true
+		changes.kept = append(changes.kept, schedule)
+	}
+
+	// 匹配到模板的待接种日程在修改前的快照,用于判断最终是否有变化
+	originals := make(map[int64]entity.BabyVaccineSchedule)
+	var matched []*entity.BabyVaccineSchedule
+	for _, template := range templates {
+		key := vaccineDoseKey{template.VaccineType, template.DoseNumber}
+		if covered[key] {
+			continue
+		}
+		covered[key] = true
+
+		scheduledDate := birth.AddDate(0, template.AgeInMonths, 0).UnixMilli()
+		if candidates := pending[key]; len(candidates) > 0 {
+			schedule := candidates[0]
+			originals[schedule.ID] = *schedule
+			applyTemplate(schedule, template, scheduledDate)
+			matched = append(matched, schedule)
+			continue
+		}
+
+		templateID := template.ID
+		changes.added = append(changes.added, &entity.BabyVaccineSchedule{
+			BabyID:            babyID,
+			TemplateID:        &templateID,
+			VaccineType:       template.VaccineType,
+			VaccineName:       template.VaccineName,
+			Description:       template.Description,
+			AgeInMonths:       template.AgeInMonths,
+			DoseNumber:        template.DoseNumber,
+			IsRequired:        template.IsRequired,
+			ReminderDays:      template.ReminderDays,
+			MinAgeDays:        template.MinAgeDays,
+			MinIntervalDays:   template.MinIntervalDays,
+			VaccinationStatus: entity.VaccinationStatusPending,
+			ScheduledDate:     scheduledDate,
+			CreatedBy:         createdBy,
+		})
+	}
+
+	series := make([]*entity.BabyVaccineSchedule, 0, len(changes.kept)+len(matched)+len(changes.added))
+	series = append(append(append(series, changes.kept...), matched...), changes.added...)
+	catchUpSchedules(birth, series)
+
+	for _, schedule := range schedules {
+		if !schedule.IsPending() || schedule.IsCustom {
+			continue
+		}
+		original, ok := originals[schedule.ID]
+		if !ok {
+			changes.removed = append(changes.removed, schedule)
+			continue
+		}
+		if schedule.ScheduledDate == original.ScheduledDate {
+			schedule.ReminderSent = original.ReminderSent
+			schedule.ReminderSentAt = original.ReminderSentAt
+		} else {
+			schedule.ReminderSent = false
+			schedule.ReminderSentAt = nil
+		}
+		if scheduleChanged(&original, schedule) {
+			changes.updated = append(changes.updated, schedule)
+		}
+	}
+	return changes
+}
+
+// applyTemplate 按模板更新待接种日程的模板字段和计划日期,提醒状态由调用方根据最终日期处理
+func applyTemplate(schedule *entity.BabyVaccineSchedule, template *entity.VaccinePlanTemplate, scheduledDate int64) {
+	templateID := template.ID
+	schedule.TemplateID = &templateID
+	schedule.VaccineName = template.VaccineName
+	schedule.Description = template.Description
+	schedule.AgeInMonths = template.AgeInMonths
+	schedule.IsRequired = template.IsRequired
+	schedule.ReminderDays = template.ReminderDays
+	schedule.MinAgeDays = template.MinAgeDays
+	schedule.MinIntervalDays = template.MinIntervalDays
+	schedule.ScheduledDate = scheduledDate
+}
+
+// scheduleChanged 比较日程中由计划决定的字段是否变化
+func scheduleChanged(before, after *entity.BabyVaccineSchedule) bool {
+	return before.TemplateID == nil || after.TemplateID == nil || *before.TemplateID != *after.TemplateID ||
+		before.VaccineName != after.VaccineName ||
+		before.Description != after.Description ||
+		before.AgeInMonths != after.AgeInMonths ||
+		before.IsRequired != after.IsRequired ||
+		before.ReminderDays != after.ReminderDays ||
+		before.MinAgeDays != after.MinAgeDays ||
+		before.MinIntervalDays != after.MinIntervalDays ||
+		before.ScheduledDate != after.ScheduledDate
+}
+
+func toVaccinePlanDTO(plan *entity.VaccinePlan) *dto.VaccinePlanDTO {
+	return &dto.VaccinePlanDTO{
+		PlanID:      strconv.FormatInt(plan.ID, 10),
+		Code:        plan.Code,
+		Version:     plan.Version,
+		Name:        plan.Name,
+		Region:      plan.Region,
+		Description: plan.Description,
+		IsDefault:   plan.IsDefault,
+		CreateTime:  plan.CreatedAt,
+	}
+}
+
+func toPlanChangeDTOs(schedules []*entity.BabyVaccineSchedule) []dto.VaccinePlanChangeDTO {
+	result := make([]dto.VaccinePlanChangeDTO, 0, len(schedules))
+	for _, schedule := range schedules {
+		item := dto.VaccinePlanChangeDTO{
+			VaccineType:       schedule.VaccineType,
+			VaccineName:       schedule.VaccineName,
+			DoseNumber:        schedule.DoseNumber,
+			VaccinationStatus: schedule.VaccinationStatus,
+			ScheduledDate:     schedule.ScheduledDate,
+		}
+		if schedule.ID != 0 {
+			item.ScheduleID = strconv.FormatInt(schedule.ID, 10)
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestReconcileVaccineSchedules(t *testing.T) {
+	birth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dateAt := func(months int) int64 { return birth.AddDate(0, months, 0).UnixMilli() }
+	vaccineDate := dateAt(0)
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	schedules := []*entity.BabyVaccineSchedule{
+		// 已接种: 保留,新计划中的同一剂不再新增
+		{ID: 1, TemplateID: int64Ptr(101), VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 1,
+			VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &vaccineDate, ScheduledDate: dateAt(0)},
+		// 待接种且新计划中月龄变化: 更新并重置提醒
+		{ID: 2, TemplateID: int64Ptr(102), VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2, AgeInMonths: 1,
+			IsRequired: true, ReminderDays: 7, VaccinationStatus: entity.VaccinationStatusPending,
+			ScheduledDate: dateAt(1), ReminderSent: true},
+		// 待接种但新计划中没有: 删除
+		{ID: 3, TemplateID: int64Ptr(103), VaccineType: "JE", VaccineName: "乙脑疫苗", DoseNumber: 1,
+			VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(8)},
+		// 自定义: 保留
+		{ID: 4, VaccineType: "Influenza", VaccineName: "流感疫苗", DoseNumber: 1, IsCustom: true,
+			VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(6)},
+		// 已跳过: 保留
+		{ID: 5, TemplateID: int64Ptr(105), VaccineType: "BCG", VaccineName: "卡介苗", DoseNumber: 1,
+			VaccinationStatus: entity.VaccinationStatusSkipped, ScheduledDate: dateAt(0)},
+	}
+	templates := []*entity.VaccinePlanTemplate{
+		{ID: 201, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 1, AgeInMonths: 0, IsRequired: true, ReminderDays: 7},
+		{ID: 202, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2, AgeInMonths: 2, IsRequired: true, ReminderDays: 7},
+		{ID: 203, VaccineType: "BCG", VaccineName: "卡介苗", DoseNumber: 1, AgeInMonths: 0, IsRequired: true, ReminderDays: 7},
+		{ID: 204, VaccineType: "Pneumococcal", VaccineName: "13价肺炎球菌疫苗", DoseNumber: 1, AgeInMonths: 2, ReminderDays: 14},
+		{ID: 205, VaccineType: "Influenza", VaccineName: "流感疫苗", DoseNumber: 1, AgeInMonths: 6, ReminderDays: 7},
+	}
+
+	changes := reconcileVaccineSchedules(9, birth, schedules, templates, 42)
+
+	require.Len(t, changes.updated, 1)
+	updated := changes.updated[0]
+	assert.Equal(t, int64(2), updated.ID)
+	assert.Equal(t, int64(202), *updated.TemplateID)
+	assert.Equal(t, 2, updated.AgeInMonths)
+	assert.Equal(t, dateAt(2), updated.ScheduledDate)
+	assert.False(t, updated.ReminderSent)
+
+	require.Len(t, changes.removed, 1)
+	assert.Equal(t, int64(3), changes.removed[0].ID)
+
+	require.Len(t, changes.added, 1)
+	added := changes.added[0]
+	assert.Equal(t, "Pneumococcal", added.VaccineType)
+	assert.Equal(t, int64(204), *added.TemplateID)
+	assert.Equal(t, int64(9), added.BabyID)
+	assert.Equal(t, int64(42), added.CreatedBy)
+	assert.Equal(t, dateAt(2), added.ScheduledDate)
+	assert.Equal(t, 14, added.ReminderDays)
+	assert.True(t, added.IsPending())
+
+	keptIDs := make([]int64, 0, len(changes.kept))
+	for _, s := range changes.kept {
+		keptIDs = append(keptIDs, s.ID)
+	}
+	assert.ElementsMatch(t, []int64{1, 4, 5}, keptIDs)
+
+	// 再次应用同一计划没有任何变化
+	schedules = append(schedules[:2], schedules[3:]...)
+	added.ID = 6
+	schedules = append(schedules, added)
+	changes = reconcileVaccineSchedules(9, birth, schedules, templates, 42)
+	assert.Empty(t, changes.added)
+	assert.Empty(t, changes.updated)
+	assert.Empty(t, changes.removed)
+}
+
+func TestReconcileVaccineSchedulesAppliesCatchUp(t *testing.T) {
+	birth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dateAt := func(months int) int64 { return birth.AddDate(0, months, 0).UnixMilli() }
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	// 第1剂在2月龄才接种,第2剂原计划1月龄
+	late := dateAt(2)
+	schedules := []*entity.BabyVaccineSchedule{
+		{ID: 1, TemplateID: int64Ptr(101), VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 1,
+			VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &late, ScheduledDate: dateAt(0)},
+		{ID: 2, TemplateID: int64Ptr(102), VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2, AgeInMonths: 1,
+			ReminderDays: 7, VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: dateAt(1)},
+	}
+	templates := []*entity.VaccinePlanTemplate{
+		{ID: 101, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 1, ReminderDays: 7},
+		{ID: 102, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2, AgeInMonths: 1, ReminderDays: 7, MinIntervalDays: 28},
+		{ID: 103, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 3, AgeInMonths: 6, ReminderDays: 7, MinIntervalDays: 60},
+	}
+
+	changes := reconcileVaccineSchedules(9, birth, schedules, templates, 42)
+
+	require.Len(t, changes.updated, 1)
+	assert.Equal(t, 28, changes.updated[0].MinIntervalDays)
+	assert.Equal(t, late+28*dayMillis, changes.updated[0].ScheduledDate)
+	require.Len(t, changes.added, 1)
+	assert.Equal(t, dateAt(6), changes.added[0].ScheduledDate)
+	assert.Equal(t, 60, changes.added[0].MinIntervalDays)
+}
+
+func TestVaccinePlanYAMLRoundTrip(t *testing.T) {
+	data := []byte(`
+code: cn-nip-plus
+name: 国家免疫规划 + 自费疫苗
+region: cn
+default: true
+vaccines:
+  - type: HepB
+    name: 乙肝疫苗
+    dose: 1
+    ageInMonths: 0
+    required: true
+  - type: EV71
+    name: EV71 肠道病毒疫苗
+    description: 自费,预防手足口病重症
+    dose: 1
+    ageInMonths: 6
+    required: false
+    reminderDays: 14
+  - type: EV71
+    name: EV71 肠道病毒疫苗
+    dose: 2
+    ageInMonths: 7
+    minIntervalDays: 28
+`)
+
+	plan, templates, err := parseVaccinePlanYAML(data)
+	require.NoError(t, err)
+	assert.Equal(t, "cn-nip-plus", plan.Code)
+	assert.Equal(t, "CN", plan.Region)
+	assert.Equal(t, 0, plan.Version)
+	assert.True(t, plan.IsDefault)
+	require.Len(t, templates, 3)
+	assert.Equal(t, defaultPlanReminderDays, templates[0].ReminderDays)
+	assert.Equal(t, 14, templates[1].ReminderDays)
+	assert.Equal(t, 2, templates[1].SortOrder)
+	assert.False(t, templates[1].IsRequired)
+	assert.Equal(t, 28, templates[2].MinIntervalDays)
+
+	plan.Version = 3
+	out, err := marshalVaccinePlanYAML(plan, templates)
+	require.NoError(t, err)
+
+	again, againTemplates, err := parseVaccinePlanYAML(out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, again.Version)
+	assert.Equal(t, plan.Name, again.Name)
+	assert.Equal(t, templates, againTemplates)
+}
+
+func TestParseVaccinePlanYAMLRejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"bad code":      "code: CN NIP\nname: x\nvaccines: [{type: HepB, name: 乙肝, dose: 1}]",
+		"no vaccines":   "code: cn-nip\nname: x\nvaccines: []",
+		"zero dose":     "code: cn-nip\nname: x\nvaccines: [{type: HepB, name: 乙肝, dose: 0}]",
+		"duplicate":     "code: cn-nip\nname: x\nvaccines: [{type: HepB, name: 乙肝, dose: 1}, {type: HepB, name: 乙肝, dose: 1}]",
+		"unknown field": "code: cn-nip\nname: x\nvaccines: [{type: HepB, name: 乙肝, dose: 1, age: 2}]",
+	}
+	for name, data := range cases {
+		_, _, err := parseVaccinePlanYAML([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRebaseBirthDateOnlyReschedulesPending(t *testing.T) {
+	oldBirth := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	newBirth := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	given := oldBirth.UnixMilli()
+	completed := &entity.BabyVaccineSchedule{ID: 1, TemplateID: int64Ptr(101), VaccineType: "BCG", VaccineName: "卡介苗", DoseNumber: 1,
+		VaccinationStatus: entity.VaccinationStatusCompleted, VaccineDate: &given, ScheduledDate: oldBirth.UnixMilli()}
+	pending := &entity.BabyVaccineSchedule{ID: 2, TemplateID: int64Ptr(102), VaccineType: "HepA", VaccineName: "甲肝疫苗", DoseNumber: 1,
+		AgeInMonths: 18, VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: oldBirth.AddDate(0, 18, 0).UnixMilli(), ReminderSent: true}
+	custom := &entity.BabyVaccineSchedule{ID: 3, VaccineType: "Influenza", VaccineName: "流感疫苗", DoseNumber: 1, AgeInMonths: 6, IsCustom: true,
+		VaccinationStatus: entity.VaccinationStatusPending, ScheduledDate: oldBirth.AddDate(0, 6, 0).UnixMilli()}
+
+	rescheduled := catchUpSchedules(newBirth, []*entity.BabyVaccineSchedule{completed, pending, custom})
+
+	require.Len(t, rescheduled, 1)
+	assert.Same(t, pending, rescheduled[0])
+	assert.Equal(t, newBirth.AddDate(0, 18, 0).UnixMilli(), pending.ScheduledDate)
+	assert.False(t, pending.ReminderSent)
+	assert.Equal(t, "甲肝疫苗", pending.VaccineName)
+	assert.Equal(t, int64(102), *pending.TemplateID)
+	assert.Equal(t, oldBirth.UnixMilli(), completed.ScheduledDate)
+	assert.Equal(t, oldBirth.AddDate(0, 6, 0).UnixMilli(), custom.ScheduledDate)
+
+	// 出生日期不变时不产生调整
+	assert.Empty(t, catchUpSchedules(newBirth, []*entity.BabyVaccineSchedule{completed, pending, custom}))
+}
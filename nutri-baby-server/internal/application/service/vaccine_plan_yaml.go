@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+const (
+	defaultPlanReminderDays = 7
+	maxPlanAgeInMonths      = 216  // 18岁
+	maxPlanIntervalDays     = 6570 // 18年
+)
+
+var planCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,63}$`)
+
+// vaccinePlanFile 疫苗计划 YAML 文件格式
+//
+//	code: cn-nip-plus
+//	name: 国家免疫规划 + 常见自费疫苗
+//	region: CN
+//	default: false
+//	vaccines:
+//	  - type: Pneumococcal
+//	    name: 13价肺炎球菌疫苗
+//	    dose: 1
+//	    ageInMonths: 2
+//	    required: false
+//	  - type: Pneumococcal
+//	    name: 13价肺炎球菌疫苗
+//	    dose: 2
+//	    ageInMonths: 4
+//	    minIntervalDays: 28
+type vaccinePlanFile struct {
+	Code        string            `yaml:"code"`
+	Name        string            `yaml:"name"`
+	Region      string            `yaml:"region,omitempty"`
+	Version     int               `yaml:"version,omitempty"` // 为空时自动取下一个版本号
+	Description string            `yaml:"description,omitempty"`
+	Default     bool              `yaml:"default,omitempty"`
+	Vaccines    []vaccinePlanDose `yaml:"vaccines"`
+}
+
+// vaccinePlanDose 计划中的一剂疫苗
+type vaccinePlanDose struct {
+	Type            string `yaml:"type"`
+	Name            string `yaml:"name"`
+	Description     string `yaml:"description,omitempty"`
+	Dose            int    `yaml:"dose"`
+	AgeInMonths     int    `yaml:"ageInMonths"`
+	Required        bool   `yaml:"required"`
+	ReminderDays    *int   `yaml:"reminderDays,omitempty"`    // 为空时默认提前7天
+	MinAgeDays      int    `yaml:"minAgeDays,omitempty"`      // 最小接种日龄(天)
+	MinIntervalDays int    `yaml:"minIntervalDays,omitempty"` // 与上一剂最小间隔(天)
+}
+
+// ImportPlanYAML 从 YAML 导入疫苗计划,每次导入生成一个新版本
+// 返回创建的计划及剂次数量
+func (s *VaccinePlanService) ImportPlanYAML(ctx context.Context, data []byte) (*entity.VaccinePlan, int, error) {
+	plan, templates, err := parseVaccinePlanYAML(data)
+	if err != nil {
+		return nil, 0, errors.New(errors.ParamError, err.Error())
+	}
+
+	maxVersion, err := s.planRepo.MaxVersion(ctx, plan.Code)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case plan.Version == 0:
+		plan.Version = maxVersion + 1
+	case plan.Version <= maxVersion:
+		return nil, 0, errors.New(errors.Conflict,
+			fmt.Sprintf("计划 %s 已存在版本 %d,新版本号必须大于 %d", plan.Code, maxVersion, maxVersion))
+	}
+
+	if err := s.planRepo.CreateWithTemplates(ctx, plan, templates); err != nil {
+		return nil, 0, err
+	}
+
+	s.logger.Sugar().Infof("导入疫苗计划 %s v%d, 共 %d 剂", plan.Code, plan.Version, len(templates))
+	return plan, len(templates), nil
+}
+
+// ExportPlanYAML 导出疫苗计划为 YAML,version 为 0 时导出最新版本
+func (s *VaccinePlanService) ExportPlanYAML(ctx context.Context, code string, version int) ([]byte, error) {
+	plan, err := s.planRepo.FindByCode(ctx, code, version)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.New(errors.NotFound, "疫苗计划不存在")
+		}
+		return nil, err
+	}
+
+	templates, err := s.templateRepo.FindByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "获取疫苗计划模板失败", err)
+	}
+	return marshalVaccinePlanYAML(plan, templates)
+}
+
+// parseVaccinePlanYAML 解析并校验疫苗计划 YAML
+func parseVaccinePlanYAML(data []byte) (*entity.VaccinePlan, []*entity.VaccinePlanTemplate, error) {
+	var file vaccinePlanFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, nil, fmt.Errorf("YAML 格式错误: %w", err)
+	}
+
+	file.Code = strings.TrimSpace(file.Code)
+	file.Name = strings.TrimSpace(file.Name)
+	if !planCodePattern.MatchString(file.Code) {
+		return nil, nil, fmt.Errorf("code %q 无效,只能包含小写字母、数字和连字符", file.Code)
+	}
+	if file.Name == "" {
+		return nil, nil, fmt.Errorf("name 不能为空")
+	}
+	if file.Version < 0 {
+		return nil, nil, fmt.Errorf("version 不能为负数")
+	}
+	if len(file.Vaccines) == 0 {
+		return nil, nil, fmt.Errorf("vaccines 不能为空")
+	}
+
+	plan := &entity.VaccinePlan{
+		Code:        file.Code,
+		Version:     file.Version,
+		Name:        file.Name,
+		Region:      strings.ToUpper(strings.TrimSpace(file.Region)),
+		Description: strings.TrimSpace(file.Description),
+		IsDefault:   file.Default,
+	}
+
+	seen := make(map[vaccineDoseKey]bool)
+	templates := make([]*entity.VaccinePlanTemplate, 0, len(file.Vaccines))
+	for i, v := range file.Vaccines {
+		v.Type = strings.TrimSpace(v.Type)
+		v.Name = strings.TrimSpace(v.Name)
+		switch {
+		case v.Type == "":
+			return nil, nil, fmt.Errorf("vaccines[%d].type 不能为空", i)
+		case v.Name == "":
+			return nil, nil, fmt.Errorf("vaccines[%d].name 不能为空", i)
+		case v.Dose < 1:
+			return nil, nil, fmt.Errorf("vaccines[%d].dose 必须大于0", i)
+		case v.AgeInMonths < 0 || v.AgeInMonths > maxPlanAgeInMonths:
+			return nil, nil, fmt.Errorf("vaccines[%d].ageInMonths 必须在 0-%d 之间", i, maxPlanAgeInMonths)
+		case v.ReminderDays != nil && (*v.ReminderDays < 0 || *v.ReminderDays > 60):
+			return nil, nil, fmt.Errorf("vaccines[%d].reminderDays 必须在 0-60 之间", i)
+		case v.MinAgeDays < 0 || v.MinAgeDays > maxPlanIntervalDays:
+			return nil, nil, fmt.Errorf("vaccines[%d].minAgeDays 必须在 0-%d 之间", i, maxPlanIntervalDays)
+		case v.MinIntervalDays < 0 || v.MinIntervalDays > maxPlanIntervalDays:
+			return nil, nil, fmt.Errorf("vaccines[%d].minIntervalDays 必须在 0-%d 之间", i, maxPlanIntervalDays)
+		}
+
+		key := vaccineDoseKey{v.Type, v.Dose}
+		if seen[key] {
+			return nil, nil, fmt.Errorf("vaccines[%d]: %s 第%d剂重复", i, v.Type, v.Dose)
+		}
+		seen[key] = true
+
+		reminderDays := defaultPlanReminderDays
+		if v.ReminderDays != nil {
+			reminderDays = *v.ReminderDays
+		}
+		templates = append(templates, &entity.VaccinePlanTemplate{
+			VaccineType:     v.Type,
+			VaccineName:     v.Name,
+			Description:     strings.TrimSpace(v.Description),
+			AgeInMonths:     v.AgeInMonths,
+			DoseNumber:      v.Dose,
+			IsRequired:      v.Required,
+			ReminderDays:    reminderDays,
+			MinAgeDays:      v.MinAgeDays,
+			MinIntervalDays: v.MinIntervalDays,
+			SortOrder:       i + 1,
+		})
+	}
+
+	return plan, templates, nil
+}
+
+// marshalVaccinePlanYAML 输出疫苗计划 YAML,可直接再次导入
+func marshalVaccinePlanYAML(plan *entity.VaccinePlan, templates []*entity.VaccinePlanTemplate) ([]byte, error) {
+	file := vaccinePlanFile{
+		Code:        plan.Code,
+		Name:        plan.Name,
+		Region:      plan.Region,
+		Version:     plan.Version,
+		Description: plan.Description,
+		Default:     plan.IsDefault,
+		Vaccines:    make([]vaccinePlanDose, 0, len(templates)),
+	}
+	for _, t := range templates {
+		reminderDays := t.ReminderDays
+		file.Vaccines = append(file.Vaccines, vaccinePlanDose{
+			Type:            t.VaccineType,
+			Name:            t.VaccineName,
+			Description:     t.Description,
+			Dose:            t.DoseNumber,
+			AgeInMonths:     t.AgeInMonths,
+			Required:        t.IsRequired,
+			ReminderDays:    &reminderDays,
+			MinAgeDays:      t.MinAgeDays,
+			MinIntervalDays: t.MinIntervalDays,
+		})
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&file); err != nil {
+		return nil, errors.Wrap(errors.InternalError, "failed to encode vaccine plan", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrap(errors.InternalError, "failed to encode vaccine plan", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// reactionCheckpointHours 接种后随访时间点(小时),覆盖常见反应出现的 48-72 小时观察期
+var reactionCheckpointHours = []int{6, 24, 48, 72}
+
+// reactionLateEntryWindow 接种日期距今不足该时长时,以记录时间作为随访起点(接种当天补录的常见情况)
+const reactionLateEntryWindow = 24 * time.Hour
+
+var reactionSeverityRank = map[string]int{
+	entity.ReactionSeverityNone:     0,
+	entity.ReactionSeverityMild:     1,
+	entity.ReactionSeverityModerate: 2,
+	entity.ReactionSeveritySevere:   3,
+}
+
+var reactionSeverityLabels = map[string]string{
+	entity.ReactionSeverityNone:     "无",
+	entity.ReactionSeverityMild:     "轻度",
+	entity.ReactionSeverityModerate: "中度",
+	entity.ReactionSeveritySevere:   "重度",
+}
+
+// VaccineReactionService 接种后反应随访服务
+type VaccineReactionService struct {
+	followUpRepo     repository.VaccineReactionFollowUpRepository
+	scheduleRepo     repository.BabyVaccineScheduleRepository
+	collaboratorRepo repository.BabyCollaboratorRepository
+	userRepo         repository.UserRepository
+	schedulerService *SchedulerService
+	permissions      *PermissionService
+	logger           *zap.Logger
+}
+
+// NewVaccineReactionService 创建接种后反应随访服务
+func NewVaccineReactionService(
+	followUpRepo repository.VaccineReactionFollowUpRepository,
+	scheduleRepo repository.BabyVaccineScheduleRepository,
+	collaboratorRepo repository.BabyCollaboratorRepository,
+	userRepo repository.UserRepository,
+	schedulerService *SchedulerService,
+	logger *zap.Logger,
+) *VaccineReactionService {
+	return &VaccineReactionService{
+		followUpRepo:     followUpRepo,
+		scheduleRepo:     scheduleRepo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+		schedulerService: schedulerService,
+		permissions:      NewPermissionService(collaboratorRepo, userRepo, logger),
+		logger:           logger,
+	}
+}
+
+// StartFollowUp 为刚记录接种的日程生成随访时间点,并为未到期的时间点添加提醒任务
+// 接种日期已超过观察期(72小时)的补录不生成随访
+func (s *VaccineReactionService) StartFollowUp(ctx context.Context, schedule *entity.BabyVaccineSchedule) error {
+	if schedule.VaccineDate == nil {
+		return nil
+	}
+
+	existing, err := s.followUpRepo.FindByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	followUps := make([]*entity.VaccineReactionFollowUp, 0, len(reactionCheckpointHours))
+	for _, checkpoint := range reactionFollowUpCheckpoints(time.UnixMilli(*schedule.VaccineDate), now) {
+		followUps = append(followUps, &entity.VaccineReactionFollowUp{
+			ScheduleID:      schedule.ID,
+			BabyID:          schedule.BabyID,
+			CheckpointHours: checkpoint.hours,
+			DueAt:           checkpoint.dueAt.UnixMilli(),
+		})
+	}
+	if len(followUps) == 0 {
+		return nil
+	}
+
+	if err := s.followUpRepo.BatchCreate(ctx, followUps); err != nil {
+		return err
+	}
+
+	for _, followUp := range followUps {
+		if _, err := s.schedulerService.AddVaccineFollowUpTask(followUp); err != nil {
+			s.logger.Warn("添加接种反应随访提醒失败",
+				zap.Int64("followUpID", followUp.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// ListFollowUps 获取日程的全部随访及反应汇总
+func (s *VaccineReactionService) ListFollowUps(ctx context.Context, babyID, scheduleID, openID string) (*dto.VaccineReactionFollowUpListDTO, error) {
+	schedule, _, err := s.loadSchedule(ctx, babyID, scheduleID, openID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	followUps, err := s.followUpRepo.FindByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.VaccineReactionFollowUpListDTO{
+		ScheduleID:       strconv.FormatInt(schedule.ID, 10),
+		ReactionSeverity: schedule.ReactionSeverity,
+		ReactionSummary:  schedule.ReactionSummary,
+		FollowUps:        make([]dto.VaccineReactionFollowUpDTO, 0, len(followUps)),
+	}
+	for _, followUp := range followUps {
+		result.FollowUps = append(result.FollowUps, toFollowUpDTO(followUp))
+	}
+	return result, nil
+}
+
+// RecordFollowUp 记录某个随访时间点的体温、红肿和哭闹情况,并重新汇总到接种日程
+func (s *VaccineReactionService) RecordFollowUp(
+	ctx context.Context,
+	babyID, scheduleID, followUpID, openID string,
+	req *dto.RecordVaccineReactionRequest,
+) (*dto.VaccineReactionFollowUpListDTO, error) {
+	if req.TemperatureC != nil && (*req.TemperatureC < 34 || *req.TemperatureC > 43) {
+		return nil, errors.New(errors.ParamError, "体温必须在 34-43℃ 之间")
+	}
+	if _, ok := reactionSeverityRank[req.Swelling]; !ok {
+		return nil, errors.New(errors.ParamError, "无效的红肿程度")
+	}
+	if _, ok := reactionSeverityRank[req.Fussiness]; !ok {
+		return nil, errors.New(errors.ParamError, "无效的哭闹程度")
+	}
+
+	followUpIDInt64, err := strconv.ParseInt(followUpID, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ParamError, "invalid follow-up id format")
+	}
+
+	schedule, user, err := s.loadSchedule(ctx, babyID, scheduleID, openID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	followUp, err := s.followUpRepo.FindByID(ctx, followUpIDInt64)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.New(errors.NotFound, "随访记录不存在")
+		}
+		return nil, err
+	}
+	if followUp.ScheduleID != schedule.ID {
+		return nil, errors.New(errors.PermissionDenied, "无权操作该随访记录")
+	}
+
+	now := time.Now().UnixMilli()
+	followUp.TemperatureC = req.TemperatureC
+	followUp.Swelling = req.Swelling
+	followUp.Fussiness = req.Fussiness
+	followUp.Note = req.Note
+	followUp.RecordedBy = &user.ID
+	followUp.RecordedAt = &now
+	if err := s.followUpRepo.Update(ctx, followUp); err != nil {
+		return nil, err
+	}
+
+	followUps, err := s.followUpRepo.FindByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	severity, summary := summarizeReactions(followUps)
+	if err := s.scheduleRepo.UpdateReactionSummary(ctx, schedule.ID, severity, summary); err != nil {
+		return nil, err
+	}
+	schedule.ReactionSeverity = severity
+	schedule.ReactionSummary = &summary
+
+	if severity == entity.ReactionSeveritySevere {
+		s.logger.Warn("接种后出现重度反应",
+			zap.Int64("scheduleID", schedule.ID),
+			zap.String("vaccineType", schedule.VaccineType),
+			zap.String("summary", summary))
+	}
+
+	return s.ListFollowUps(ctx, babyID, scheduleID, openID)
+}
+
+// loadSchedule 校验协作者权限并加载属于该宝宝的接种日程,requireEdit 为 true 时要求编辑权限
+func (s *VaccineReactionService) loadSchedule(
+	ctx context.Context,
+	babyID, scheduleID, openID string,
+	requireEdit bool,
+) (*entity.BabyVaccineSchedule, *entity.User, error) {
+	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
+	if err != nil {
+		return nil, nil, errors.New(errors.ParamError, "invalid baby id format")
+	}
+	scheduleIDInt64, err := strconv.ParseInt(scheduleID, 10, 64)
+	if err != nil {
+		return nil, nil, errors.New(errors.ParamError, "invalid schedule id format")
+	}
+
+	// 记录接种反应视为新增疫苗信息
+	action := entity.PermissionActionRead
+	if requireEdit {
+		action = entity.PermissionActionCreate
+	}
+	if _, err := s.permissions.Authorize(ctx, babyIDInt64, openID, entity.PermissionResourceVaccine, action); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.FindByOpenID(ctx, openID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleIDInt64)
+	if err != nil {
+		return nil, nil, err
+	}
+	if schedule.BabyID != babyIDInt64 {
+		return nil, nil, errors.New(errors.PermissionDenied, "无权操作该疫苗接种日程")
+	}
+	return schedule, user, nil
+}
+
+// toFollowUpDTO 将随访实体转换为DTO
+func toFollowUpDTO(followUp *entity.VaccineReactionFollowUp) dto.VaccineReactionFollowUpDTO {
+	var recordedBy *string
+	if followUp.RecordedBy != nil {
+		id := strconv.FormatInt(*followUp.RecordedBy, 10)
+		recordedBy = &id
+	}
+	return dto.VaccineReactionFollowUpDTO{
+		FollowUpID:      strconv.FormatInt(followUp.ID, 10),
+		ScheduleID:      strconv.FormatInt(followUp.ScheduleID, 10),
+		CheckpointHours: followUp.CheckpointHours,
+		DueAt:           followUp.DueAt,
+		PromptSentAt:    followUp.PromptSentAt,
+		TemperatureC:    followUp.TemperatureC,
+		Swelling:        followUp.Swelling,
+		Fussiness:       followUp.Fussiness,
+		Note:            followUp.Note,
+		RecordedBy:      recordedBy,
+		RecordedAt:      followUp.RecordedAt,
+	}
+}
+
+// reactionCheckpoint 随访时间点
+type reactionCheckpoint struct {
+	hours int
+	dueAt time.Time
+}
+
+// reactionFollowUpCheckpoints 计算随访时间点
+// 接种日期距今不足24小时(通常只记录了日期)时以当前时间为起点,否则以接种日期为起点;
+// 起点已超过观察期的不生成随访,已过去的时间点仍生成,便于照护人补录
+func reactionFollowUpCheckpoints(vaccineDate, now time.Time) []reactionCheckpoint {
+	base := vaccineDate
+	if now.Sub(vaccineDate) < reactionLateEntryWindow {
+		base = now
+	}
+	last := reactionCheckpointHours[len(reactionCheckpointHours)-1]
+	if now.Sub(base) > time.Duration(last)*time.Hour {
+		return nil
+	}
+
+	checkpoints := make([]reactionCheckpoint, 0, len(reactionCheckpointHours))
+	for _, hours := range reactionCheckpointHours {
+		checkpoints = append(checkpoints, reactionCheckpoint{
+			hours: hours,
+			dueAt: base.Add(time.Duration(hours) * time.Hour),
+		})
+	}
+	return checkpoints
+}
+
+// temperatureSeverity 按体温划分发热程度
+func temperatureSeverity(temperature float64) string {
+	switch {
+	case temperature < 37.5:
+		return entity.ReactionSeverityNone
+	case temperature < 38.5:
+		return entity.ReactionSeverityMild
+	case temperature < 39.5:
+		return entity.ReactionSeverityModerate
+	default:
+		return entity.ReactionSeveritySevere
+	}
+}
+
+// summarizeReactions 汇总已记录的随访,返回最重反应程度和描述
+func summarizeReactions(followUps []*entity.VaccineReactionFollowUp) (string, string) {
+	severity := entity.ReactionSeverityNone
+	raise := func(level string) {
+		if reactionSeverityRank[level] > reactionSeverityRank[severity] {
+			severity = level
+		}
+	}
+
+	var maxTemp *entity.VaccineReactionFollowUp
+	swelling, fussiness := entity.ReactionSeverityNone, entity.ReactionSeverityNone
+	recorded := 0
+	for _, followUp := range followUps {
+		if !followUp.IsRecorded() {
+			continue
+		}
+		recorded++
+		if followUp.TemperatureC != nil {
+			raise(temperatureSeverity(*followUp.TemperatureC))
+			if maxTemp == nil || *followUp.TemperatureC > *maxTemp.TemperatureC {
+				maxTemp = followUp
+			}
+		}
+		if reactionSeverityRank[followUp.Swelling] > reactionSeverityRank[swelling] {
+			swelling = followUp.Swelling
+		}
+		if reactionSeverityRank[followUp.Fussiness] > reactionSeverityRank[fussiness] {
+			fussiness = followUp.Fussiness
+		}
+	}
+	if recorded == 0 {
+		return "", ""
+	}
+	raise(swelling)
+	raise(fussiness)
+
+	parts := make([]string, 0, 3)
+	if maxTemp != nil {
+		parts = append(parts, fmt.Sprintf("最高体温%.1f℃(接种后%d小时)", *maxTemp.TemperatureC, maxTemp.CheckpointHours))
+	}
+	parts = append(parts,
+		"局部红肿: "+reactionSeverityLabels[swelling],
+		"哭闹烦躁: "+reactionSeverityLabels[fussiness],
+	)
+	return severity, strings.Join(parts, "; ")
+}
+
+// reactionAlert 同类疫苗此前剂次出现重度反应时,返回下一剂接种前需与医生沟通的提示
+func reactionAlert(schedules []*entity.BabyVaccineSchedule, schedule *entity.BabyVaccineSchedule) string {
+	if schedule.IsCompleted() {
+		return ""
+	}
+	var doses []string
+	for _, s := range schedules {
+		if s.VaccineType == schedule.VaccineType && s.DoseNumber < schedule.DoseNumber &&
+			s.IsCompleted() && s.HasSevereReaction() {
+			doses = append(doses, fmt.Sprintf("第%d剂", s.DoseNumber))
+		}
+	}
+	if len(doses) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s%s接种后出现重度反应,接种前请告知医生", schedule.VaccineName, strings.Join(doses, "、"))
+}
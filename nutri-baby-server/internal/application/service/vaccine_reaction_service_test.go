@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+func TestReactionFollowUpCheckpoints(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	// 当天记录(只有日期): 以记录时间为起点
+	checkpoints := reactionFollowUpCheckpoints(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), now)
+	require.Len(t, checkpoints, 4)
+	assert.Equal(t, 6, checkpoints[0].hours)
+	assert.Equal(t, now.Add(6*time.Hour), checkpoints[0].dueAt)
+	assert.Equal(t, now.Add(72*time.Hour), checkpoints[3].dueAt)
+
+	// 两天前接种的补录: 以接种时间为起点,已过去的时间点也生成
+	vaccinated := now.Add(-50 * time.Hour)
+	checkpoints = reactionFollowUpCheckpoints(vaccinated, now)
+	require.Len(t, checkpoints, 4)
+	assert.Equal(t, vaccinated.Add(24*time.Hour), checkpoints[1].dueAt)
+
+	// 超过观察期不再随访
+	assert.Empty(t, reactionFollowUpCheckpoints(now.Add(-80*time.Hour), now))
+}
+
+func TestSummarizeReactions(t *testing.T) {
+	temp := func(v float64) *float64 { return &v }
+	recordedAt := int64(1)
+
+	severity, summary := summarizeReactions([]*entity.VaccineReactionFollowUp{
+		{CheckpointHours: 6},
+	})
+	assert.Empty(t, severity)
+	assert.Empty(t, summary)
+
+	severity, summary = summarizeReactions([]*entity.VaccineReactionFollowUp{
+		{CheckpointHours: 6, TemperatureC: temp(37.8), Swelling: entity.ReactionSeverityMild,
+			Fussiness: entity.ReactionSeverityModerate, RecordedAt: &recordedAt},
+		{CheckpointHours: 24, TemperatureC: temp(38.6), Swelling: entity.ReactionSeverityNone,
+			Fussiness: entity.ReactionSeverityMild, RecordedAt: &recordedAt},
+		{CheckpointHours: 48, TemperatureC: temp(40.2)}, // 未记录
+	})
+	assert.Equal(t, entity.ReactionSeverityModerate, severity)
+	assert.Equal(t, "最高体温38.6℃(接种后24小时); 局部红肿: 轻度; 哭闹烦躁: 中度", summary)
+
+	severity, _ = summarizeReactions([]*entity.VaccineReactionFollowUp{
+		{CheckpointHours: 24, TemperatureC: temp(39.8), Swelling: entity.ReactionSeverityNone,
+			Fussiness: entity.ReactionSeverityNone, RecordedAt: &recordedAt},
+	})
+	assert.Equal(t, entity.ReactionSeveritySevere, severity)
+}
+
+func TestReactionAlert(t *testing.T) {
+	dose1 := &entity.BabyVaccineSchedule{ID: 1, VaccineType: "DTaP", VaccineName: "百白破疫苗", DoseNumber: 1,
+		VaccinationStatus: entity.VaccinationStatusCompleted, ReactionSeverity: entity.ReactionSeveritySevere}
+	dose2 := &entity.BabyVaccineSchedule{ID: 2, VaccineType: "DTaP", VaccineName: "百白破疫苗", DoseNumber: 2,
+		VaccinationStatus: entity.VaccinationStatusPending}
+	other := &entity.BabyVaccineSchedule{ID: 3, VaccineType: "HepB", VaccineName: "乙肝疫苗", DoseNumber: 2,
+		VaccinationStatus: entity.VaccinationStatusPending}
+	schedules := []*entity.BabyVaccineSchedule{dose1, dose2, other}
+
+	assert.Equal(t, "百白破疫苗第1剂接种后出现重度反应,接种前请告知医生", reactionAlert(schedules, dose2))
+	assert.Empty(t, reactionAlert(schedules, other))
+	assert.Empty(t, reactionAlert(schedules, dose1))
+
+	dose1.ReactionSeverity = entity.ReactionSeverityModerate
+	assert.Empty(t, reactionAlert(schedules, dose2))
+}
@@ -17,25 +17,39 @@ import (
 // VaccineScheduleService 疫苗接种日程服务(新)
 type VaccineScheduleService struct {
 	scheduleRepo     repository.BabyVaccineScheduleRepository
+	attachmentRepo   repository.AttachmentRepository
+	commentRepo      repository.RecordCommentRepository
 	babyRepo         repository.BabyRepository
 	collaboratorRepo repository.BabyCollaboratorRepository
 	userRepository   repository.UserRepository
+	reactionService  *VaccineReactionService // 接种后反应随访
+	revisionService  *RevisionService        // 修订历史
+	permissions      *PermissionService
 	logger           *zap.Logger
 }
 
 // NewVaccineScheduleService 创建疫苗接种日程服务实例
 func NewVaccineScheduleService(
 	scheduleRepo repository.BabyVaccineScheduleRepository,
+	attachmentRepo repository.AttachmentRepository,
+	commentRepo repository.RecordCommentRepository,
 	babyRepo repository.BabyRepository,
 	collaboratorRepo repository.BabyCollaboratorRepository,
 	userRepository repository.UserRepository,
+	reactionService *VaccineReactionService,
+	revisionService *RevisionService,
 	logger *zap.Logger,
 ) *VaccineScheduleService {
 	return &VaccineScheduleService{
 		scheduleRepo:     scheduleRepo,
+		attachmentRepo:   attachmentRepo,
+		commentRepo:      commentRepo,
 		babyRepo:         babyRepo,
 		collaboratorRepo: collaboratorRepo,
 		userRepository:   userRepository,
+		reactionService:  reactionService,
+		revisionService:  revisionService,
+		permissions:      NewPermissionService(collaboratorRepo, userRepository, logger),
 		logger:           logger,
 	}
 }
@@ -44,7 +58,7 @@ func NewVaccineScheduleService(
 // 职责：仅返回日程列表，不包含统计信息
 func (s *VaccineScheduleService) GetVaccineSchedules(ctx context.Context, request *dto.GetVaccineScheduleListRequest) (int64, []dto.VaccineScheduleDTO, error) {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, request.BabyID, request.OpenID); err != nil {
+	if err := s.checkPermission(ctx, request.BabyID, request.OpenID, entity.PermissionActionRead); err != nil {
 		return 0, nil, err
 	}
 
@@ -69,77 +83,103 @@ func (s *VaccineScheduleService) GetVaccineSchedules(ctx context.Context, reques
 		return 0, nil, err
 	}
 
+	// 同类疫苗前一剂出现重度反应的待接种日程需要提示
+	all, err := s.scheduleRepo.FindByBabyID(ctx, babyIDInt64, 1, 1000)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	// 转换为DTO
 	scheduleDTOs := make([]dto.VaccineScheduleDTO, 0, len(schedules))
 	for _, schedule := range schedules {
-		scheduleDTOs = append(scheduleDTOs, s.toScheduleDTO(schedule))
+		scheduleDTO := s.toScheduleDTO(schedule)
+		scheduleDTO.ReactionAlert = reactionAlert(all, schedule)
+		scheduleDTOs = append(scheduleDTOs, scheduleDTO)
 	}
 	return total, scheduleDTOs, nil
 }
 
 // UpdateVaccineSchedule 更新疫苗接种日程(记录接种或跳过)
+// 接种日期早于最小日龄或最小间隔时仍会记录,但在结果中返回提示;
+// 记录后按补种规则重新计算同系列剩余剂次的计划日期
 func (s *VaccineScheduleService) UpdateVaccineSchedule(
 	ctx context.Context,
 	babyID, scheduleID, openID string,
 	req *dto.UpdateVaccineScheduleRequest,
-) error {
+) (*dto.UpdateVaccineScheduleResultDTO, error) {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
-		return err
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionUpdateAny); err != nil {
+		return nil, err
 	}
 
 	// 转换 IDs from string to int64
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
-		return errors.New(errors.ParamError, "invalid baby id format")
+		return nil, errors.New(errors.ParamError, "invalid baby id format")
 	}
 
 	scheduleIDInt64, err := strconv.ParseInt(scheduleID, 10, 64)
 	if err != nil {
-		return errors.New(errors.ParamError, "invalid schedule id format")
+		return nil, errors.New(errors.ParamError, "invalid schedule id format")
 	}
 
 	// 2. 查询日程是否存在
 	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleIDInt64)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 3. 验证日程是否属于该宝宝
 	if schedule.BabyID != babyIDInt64 {
-		return errors.New(errors.PermissionDenied, "无权操作该疫苗接种日程")
+		return nil, errors.New(errors.PermissionDenied, "无权操作该疫苗接种日程")
 	}
 
 	// 4. 检查是否已完成
 	if schedule.IsCompleted() {
-		return errors.New(errors.Conflict, "该疫苗接种日程已完成,无法重复记录")
+		return nil, errors.New(errors.Conflict, "该疫苗接种日程已完成,无法重复记录")
 	}
 
+	before := revisionSnapshotOf(schedule)
+
 	// 获取用户信息
 	user, err := s.getUserInfo(ctx, openID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// 同系列日程,用于接种时间校验和后续剂次重排
+	birth, series, err := s.loadVaccineSeries(ctx, babyIDInt64, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.UpdateVaccineScheduleResultDTO{
+		Warnings:    []string{},
+		Rescheduled: []dto.VaccinePlanChangeDTO{},
 	}
 
 	// 5. 根据请求的状态处理
-	if req.VaccinationStatus == entity.VaccinationStatusSkipped {
+	switch req.VaccinationStatus {
+	case entity.VaccinationStatusSkipped:
 		// 5a. 标记为跳过
-		err = s.scheduleRepo.MarkAsSkipped(ctx, scheduleIDInt64)
-		if err != nil {
-			return err
+		if err := s.scheduleRepo.MarkAsSkipped(ctx, scheduleIDInt64); err != nil {
+			return nil, err
 		}
+		schedule.VaccinationStatus = entity.VaccinationStatusSkipped
 
-		return nil
-	}
-
-	// 5b. 标记为已完成 (completed)
-	if req.VaccinationStatus == entity.VaccinationStatusCompleted {
+	case entity.VaccinationStatusCompleted:
+		// 5b. 标记为已完成 (completed)
 		// 验证必填字段
 		if req.VaccineDate == 0 {
-			return errors.New(errors.ParamError, "接种日期不能为空")
+			return nil, errors.New(errors.ParamError, "接种日期不能为空")
 		}
 		if req.Hospital == "" {
-			return errors.New(errors.ParamError, "接种医院不能为空")
+			return nil, errors.New(errors.ParamError, "接种医院不能为空")
+		}
+
+		result.Warnings = append(result.Warnings, doseTimingWarnings(birth, series, schedule, req.VaccineDate)...)
+		if alert := reactionAlert(series, schedule); alert != "" {
+			result.Warnings = append(result.Warnings, alert)
 		}
 
 		err = s.scheduleRepo.MarkAsCompleted(
@@ -156,14 +196,40 @@ func (s *VaccineScheduleService) UpdateVaccineSchedule(
 			user.AvatarURL,
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		schedule.VaccinationStatus = entity.VaccinationStatusCompleted
+		schedule.VaccineDate = &req.VaccineDate
 
-		return nil
+		// 开始接种后反应随访(接种已记录,失败只记日志)
+		if err := s.reactionService.StartFollowUp(ctx, schedule); err != nil {
+			s.logger.Warn("创建接种反应随访失败", zap.String("scheduleId", scheduleID), zap.Error(err))
+		}
+
+	default:
+		// 6. 无效的状态
+		return nil, errors.New(errors.ParamError, "无效的接种状态")
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine, schedule.ID, before); err != nil {
+		return nil, err
 	}
 
-	// 6. 无效的状态
-	return errors.New(errors.ParamError, "无效的接种状态")
+	// 7. 按补种规则重新计算同系列剩余剂次(接种已记录,重新计算失败只记日志)
+	befores := revisionSnapshotsByID(series)
+	rescheduled := catchUpSchedules(birth, series)
+	if err := s.scheduleRepo.UpdateScheduledDates(ctx, rescheduled); err != nil {
+		s.logger.Warn("重新计算疫苗后续剂次失败", zap.String("scheduleId", scheduleID), zap.Error(err))
+	} else {
+		result.Rescheduled = append(result.Rescheduled, toPlanChangeDTOs(rescheduled)...)
+		err = s.revisionService.RecordMany(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine,
+			revisionRecords(rescheduled), befores)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
 }
 
 // CreateCustomSchedule 创建自定义疫苗接种日程
@@ -173,7 +239,7 @@ func (s *VaccineScheduleService) CreateCustomSchedule(
 	req *dto.CreateVaccineScheduleRequest,
 ) error {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionCreate); err != nil {
 		return err
 	}
 
@@ -236,7 +302,11 @@ func (s *VaccineScheduleService) CreateCustomSchedule(
 	}
 
 	// 5. 保存日程
-	return s.scheduleRepo.Create(ctx, schedule)
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return err
+	}
+
+	return s.revisionService.Record(ctx, openID, entity.RevisionActionCreate, entity.AttachmentRecordTypeVaccine, schedule.ID, nil)
 }
 
 // UpdateScheduleInfo 更新疫苗接种日程基本信息(仅限未完成的日程)
@@ -246,7 +316,7 @@ func (s *VaccineScheduleService) UpdateScheduleInfo(
 	req *dto.UpdateScheduleInfoRequest,
 ) error {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionUpdateAny); err != nil {
 		return err
 	}
 
@@ -278,6 +348,7 @@ func (s *VaccineScheduleService) UpdateScheduleInfo(
 	}
 
 	// 5. 更新字段（只更新非nil的字段）
+	before := revisionSnapshotOf(schedule)
 	needRecalculateDate := false
 
 	if req.VaccineType != nil {
@@ -319,13 +390,17 @@ func (s *VaccineScheduleService) UpdateScheduleInfo(
 	}
 
 	// 7. 更新到数据库
-	return s.scheduleRepo.Update(ctx, schedule)
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return err
+	}
+
+	return s.revisionService.Record(ctx, openID, entity.RevisionActionUpdate, entity.AttachmentRecordTypeVaccine, schedule.ID, before)
 }
 
 // DeleteSchedule 删除疫苗接种日程(仅限自定义日程)
 func (s *VaccineScheduleService) DeleteSchedule(ctx context.Context, babyID, scheduleID, openID string) error {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionDelete); err != nil {
 		return err
 	}
 
@@ -362,13 +437,29 @@ func (s *VaccineScheduleService) DeleteSchedule(ctx context.Context, babyID, sch
 	}
 
 	// 6. 删除日程
-	return s.scheduleRepo.Delete(ctx, scheduleIDInt64)
+	if err := s.scheduleRepo.Delete(ctx, scheduleIDInt64); err != nil {
+		return err
+	}
+
+	if err := s.revisionService.Record(ctx, openID, entity.RevisionActionDelete, entity.AttachmentRecordTypeVaccine, schedule.ID, revisionSnapshotOf(schedule)); err != nil {
+		return err
+	}
+
+	// 7. 同步软删除日程的照片附件
+	if err := s.attachmentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeVaccine, scheduleIDInt64); err != nil {
+		s.logger.Warn("删除疫苗日程附件失败", zap.String("scheduleId", scheduleID), zap.Error(err))
+	}
+	if err := s.commentRepo.DeleteByRecord(ctx, entity.AttachmentRecordTypeVaccine, scheduleIDInt64); err != nil {
+		s.logger.Warn("删除疫苗日程评论失败", zap.String("scheduleId", scheduleID), zap.Error(err))
+	}
+
+	return nil
 }
 
 // GetStatistics 获取疫苗接种统计
 func (s *VaccineScheduleService) GetStatistics(ctx context.Context, babyID, openID string) (*dto.VaccineScheduleStatisticsDTO, error) {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
@@ -404,29 +495,45 @@ func (s *VaccineScheduleService) GetStatistics(ctx context.Context, babyID, open
 // 辅助方法
 // ===================================================================
 
-// checkPermission 检查用户是否有权限访问该宝宝的疫苗信息
-func (s *VaccineScheduleService) checkPermission(ctx context.Context, babyID, openID string) error {
-	// 转换 babyID from string to int64
+// checkPermission 按权限矩阵检查用户对该宝宝疫苗信息的操作权限
+// 接种日程多由系统按计划生成,修改统一按 update_any 判断
+func (s *VaccineScheduleService) checkPermission(ctx context.Context, babyID, openID, action string) error {
 	babyIDInt64, err := strconv.ParseInt(babyID, 10, 64)
 	if err != nil {
 		return errors.New(errors.ParamError, "invalid baby id format")
 	}
 
-	// 获取用户信息以获取用户ID
-	user, err := s.getUserInfo(ctx, openID)
+	_, err = s.permissions.Authorize(ctx, babyIDInt64, openID, entity.PermissionResourceVaccine, action)
+	return err
+}
+
+// loadVaccineSeries 获取宝宝出生日期及与 schedule 同一疫苗类型的全部日程(以传入的 schedule 代替库中的同一条)
+func (s *VaccineScheduleService) loadVaccineSeries(
+	ctx context.Context,
+	babyID int64,
+	schedule *entity.BabyVaccineSchedule,
+) (time.Time, []*entity.BabyVaccineSchedule, error) {
+	baby, err := s.babyRepo.FindByID(ctx, babyID)
 	if err != nil {
-		return err
+		return time.Time{}, nil, errors.Wrap(errors.DatabaseError, "查询宝宝信息失败", err)
 	}
-
-	collaborator, err := s.collaboratorRepo.FindByBabyAndUser(ctx, babyIDInt64, user.ID)
+	birth, err := time.Parse("2006-01-02", baby.BirthDate)
 	if err != nil {
-		return err
+		return time.Time{}, nil, errors.Wrap(errors.ParamError, "解析出生日期失败", err)
 	}
-	if collaborator == nil {
-		return errors.New(errors.PermissionDenied, "无权访问该宝宝的疫苗信息")
+
+	schedules, err := s.scheduleRepo.FindByBabyID(ctx, babyID, 1, 1000)
+	if err != nil {
+		return time.Time{}, nil, err
 	}
 
-	return nil
+	series := []*entity.BabyVaccineSchedule{schedule}
+	for _, item := range schedules {
+		if item.VaccineType == schedule.VaccineType && item.ID != schedule.ID {
+			series = append(series, item)
+		}
+	}
+	return birth, series, nil
 }
 
 // getUserInfo 获取用户信息
@@ -465,6 +572,8 @@ func (s *VaccineScheduleService) toScheduleDTO(schedule *entity.BabyVaccineSched
 		DoseNumber:        schedule.DoseNumber,
 		IsRequired:        schedule.IsRequired,
 		ReminderDays:      schedule.ReminderDays,
+		MinAgeDays:        schedule.MinAgeDays,
+		MinIntervalDays:   schedule.MinIntervalDays,
 		IsCustom:          schedule.IsCustom,
 		VaccinationStatus: schedule.VaccinationStatus,
 		VaccineDate:       schedule.VaccineDate,
@@ -477,6 +586,8 @@ func (s *VaccineScheduleService) toScheduleDTO(schedule *entity.BabyVaccineSched
 		CompletedByName:   schedule.CompletedByName,
 		CompletedByAvatar: schedule.CompletedByAvatar,
 		CompletedTime:     schedule.CompletedTime,
+		ReactionSeverity:  schedule.ReactionSeverity,
+		ReactionSummary:   schedule.ReactionSummary,
 		CreateBy:          scheduleID, // 创建者是创建这条日程的人
 		CreateTime:        schedule.CreatedAt,
 	}
@@ -542,7 +653,7 @@ func (s *VaccineScheduleService) GetVaccineReminders(
 	babyID, openID string,
 ) ([]*dto.VaccineReminderDTO, error) {
 	// 1. 验证权限
-	if err := s.checkPermission(ctx, babyID, openID); err != nil {
+	if err := s.checkPermission(ctx, babyID, openID, entity.PermissionActionRead); err != nil {
 		return nil, err
 	}
 
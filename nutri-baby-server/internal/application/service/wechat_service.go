@@ -3,12 +3,11 @@ package service
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/silenceper/wechat/v2/miniprogram/qrcode"
 	"github.com/silenceper/wechat/v2/miniprogram/subscribe"
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/wechat"
 	"github.com/wxlbd/nutri-baby-server/pkg/errors"
 	"go.uber.org/zap"
@@ -18,14 +17,16 @@ import (
 type WechatService struct {
 	wechatClient *wechat.Client
 	config       *config.Config
+	storage      storage.Storage
 	logger       *zap.Logger
 }
 
 // NewWechatService 创建微信服务实例
-func NewWechatService(wechatClient *wechat.Client, cfg *config.Config, logger *zap.Logger) *WechatService {
+func NewWechatService(wechatClient *wechat.Client, cfg *config.Config, store storage.Storage, logger *zap.Logger) *WechatService {
 	return &WechatService{
 		wechatClient: wechatClient,
 		config:       cfg,
+		storage:      store,
 		logger:       logger,
 	}
 }
@@ -84,49 +85,18 @@ func (s *WechatService) GenerateQRCode(ctx context.Context, scene, page string)
 	// 生成文件名 (使用 scene 作为文件名的一部分)
 	filename := fmt.Sprintf("qrcode_%s.png", scene)
 
-	// 构建存储路径 (使用配置中的 upload.storage_path)
-	storagePath := s.config.Upload.StoragePath
-	if storagePath == "" {
-		storagePath = "uploads/"
-	}
-
-	// 创建二维码子目录
-	qrcodePath := filepath.Join(storagePath, "qrcodes")
-	if err := os.MkdirAll(qrcodePath, 0755); err != nil {
-		s.logger.Error("❌ [WechatService.GenerateQRCode] 创建目录失败",
-			zap.Error(err),
-			zap.String("path", qrcodePath),
-		)
-		return "", errors.Wrap(errors.InternalError, "创建存储目录失败", err)
-	}
-
-	// 完整文件路径
-	filePath := filepath.Join(qrcodePath, filename)
-
-	// 保存图片到文件
-	if err := os.WriteFile(filePath, imageBytes, 0644); err != nil {
+	// 保存到文件存储 (本地或对象存储)
+	key := "qrcodes/" + filename
+	if err := s.storage.Put(ctx, key, imageBytes, "image/png"); err != nil {
 		s.logger.Error("❌ [WechatService.GenerateQRCode] 保存图片失败",
 			zap.Error(err),
-			zap.String("filePath", filePath),
+			zap.String("key", key),
 		)
 		return "", errors.Wrap(errors.InternalError, "保存小程序码图片失败", err)
 	}
 
-	s.logger.Info("✅ [WechatService.GenerateQRCode] 小程序码保存成功",
-		zap.String("filePath", filePath),
-	)
-
-	// 拼接完整的URL访问地址
-	relativePath := fmt.Sprintf("/uploads/qrcodes/%s", filename)
-
-	// 从配置中获取服务器基础URL
-	baseURL := s.config.Server.BaseURL
-	if baseURL == "" {
-		baseURL = fmt.Sprintf("http://localhost:%d", s.config.Server.Port)
-	}
-
-	// 返回完整的URL路径
-	imageURL := fmt.Sprintf("%s%s", baseURL, relativePath)
+	// 小程序码为公开文件,返回固定访问地址
+	imageURL := s.storage.URL(key)
 
 	s.logger.Info("🎉 [WechatService.GenerateQRCode] 小程序码生成完成",
 		zap.String("imageURL", imageURL),
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"gorm.io/plugin/soft_delete"
+)
+
+// 附件关联的记录类型常量
+const (
+	AttachmentRecordTypeFeeding   = "feeding"   // 喂养记录
+	AttachmentRecordTypeSleep     = "sleep"     // 睡眠记录
+	AttachmentRecordTypeDiaper    = "diaper"    // 换尿布记录
+	AttachmentRecordTypeGrowth    = "growth"    // 成长记录
+	AttachmentRecordTypeMilestone = "milestone" // 发育里程碑
+	AttachmentRecordTypeVaccine   = "vaccine"   // 疫苗接种日程
+)
+
+// Attachment 照片附件(可关联任意类型的记录)
+type Attachment struct {
+	ID           int64                 `gorm:"primaryKey;column:id" json:"id"`                                                    // 雪花ID主键
+	BabyID       int64                 `gorm:"column:baby_id;index:idx_attachment_baby_taken" json:"babyId"`                      // 宝宝ID (引用Baby.ID)
+	RecordType   string                `gorm:"column:record_type;type:varchar(16);index:idx_attachment_record" json:"recordType"` // 记录类型: feeding, sleep, diaper, growth, milestone, vaccine
+	RecordID     int64                 `gorm:"column:record_id;index:idx_attachment_record" json:"recordId"`                      // 记录ID
+	URL          string                `gorm:"column:url;type:varchar(512)" json:"url"`                                           // 访问URL(原图)
+	DisplayURL   string                `gorm:"column:display_url;type:varchar(512)" json:"displayUrl"`                            // 展示图URL
+	ThumbnailURL string                `gorm:"column:thumbnail_url;type:varchar(512)" json:"thumbnailUrl"`                        // 缩略图URL
+	Path         string                `gorm:"column:path;type:varchar(512)" json:"path"`                                         // 存储相对路径
+	Filename     string                `gorm:"column:filename;type:varchar(255)" json:"filename"`                                 // 文件名
+	Size         int64                 `gorm:"column:size" json:"size"`                                                           // 文件大小(字节)
+	Width        int                   `gorm:"column:width" json:"width"`                                                         // 宽度(px,已校正方向)
+	Height       int                   `gorm:"column:height" json:"height"`                                                       // 高度(px,已校正方向)
+	TakenAt      int64                 `gorm:"column:taken_at;index:idx_attachment_baby_taken" json:"takenAt"`                    // 归档时间(毫秒时间戳,默认取关联记录的时间),用于相册按月分页
+	UploadedBy   int64                 `gorm:"column:uploaded_by" json:"uploadedBy"`                                              // 上传者用户ID (引用User.ID)
+	CreatedAt    int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`                           // 创建时间(毫秒时间戳)
+	UpdatedAt    int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`                           // 更新时间(毫秒时间戳)
+	DeletedAt    soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`                       // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (Attachment) TableName() string {
+	return "attachments"
+}
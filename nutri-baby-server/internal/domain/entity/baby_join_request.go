@@ -0,0 +1,35 @@
+package entity
+
+import "gorm.io/plugin/soft_delete"
+
+// 加入申请状态
+const (
+	JoinRequestStatusPending   = "pending"   // 待审批
+	JoinRequestStatusApproved  = "approved"  // 已通过
+	JoinRequestStatusRejected  = "rejected"  // 已拒绝
+	JoinRequestStatusCancelled = "cancelled" // 邀请撤销后自动取消
+)
+
+// BabyJoinRequest 加入申请(邀请开启审批时,扫码/点击分享后先生成申请,管理员通过后才创建协作者)
+// 角色、关系和协作权限在申请时从邀请复制,审批时不受邀请后续变化影响
+type BabyJoinRequest struct {
+	ID           int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	BabyID       int64                 `gorm:"column:baby_id;index" json:"babyId"`                          // 宝宝ID (引用Baby.ID)
+	InvitationID int64                 `gorm:"column:invitation_id;index" json:"invitationId"`              // 邀请ID (引用BabyInvitation.ID)
+	UserID       int64                 `gorm:"column:user_id;index" json:"userId"`                          // 申请人用户ID (引用User.ID)
+	Role         string                `gorm:"column:role;type:varchar(16)" json:"role"`                    // 通过后授予的角色
+	Relationship string                `gorm:"column:relationship;type:varchar(32)" json:"relationship"`    // 与宝宝的关系
+	AccessType   string                `gorm:"column:access_type;type:varchar(16)" json:"accessType"`       // permanent, temporary
+	ExpiresAt    *int64                `gorm:"column:expires_at" json:"expiresAt"`                          // 协作权限过期时间(毫秒)
+	Status       string                `gorm:"column:status;type:varchar(16);index" json:"status"`          // pending, approved, rejected, cancelled
+	ReviewedBy   *int64                `gorm:"column:reviewed_by" json:"reviewedBy,omitempty"`              // 审批人用户ID
+	ReviewedAt   *int64                `gorm:"column:reviewed_at" json:"reviewedAt,omitempty"`              // 审批时间(毫秒时间戳)
+	CreatedAt    int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt    int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt    soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (BabyJoinRequest) TableName() string {
+	return "baby_join_requests"
+}
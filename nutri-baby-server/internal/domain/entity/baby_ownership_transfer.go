@@ -0,0 +1,30 @@
+package entity
+
+import "gorm.io/plugin/soft_delete"
+
+// 所有权转让状态
+const (
+	OwnershipTransferStatusPending   = "pending"   // 等待被提名人接受
+	OwnershipTransferStatusAccepted  = "accepted"  // 已接受
+	OwnershipTransferStatusDeclined  = "declined"  // 被提名人已拒绝
+	OwnershipTransferStatusCancelled = "cancelled" // 所有者取消或被提名人已离开
+)
+
+// BabyOwnershipTransfer 宝宝所有权转让 (所有者提名另一位成员,对方接受后 Baby.UserID 才变更)
+// 同一宝宝同时只有一条待处理的转让
+type BabyOwnershipTransfer struct {
+	ID          int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	BabyID      int64                 `gorm:"column:baby_id;index" json:"babyId"`                          // 宝宝ID (引用Baby.ID)
+	FromUserID  int64                 `gorm:"column:from_user_id" json:"fromUserId"`                       // 当前所有者用户ID
+	ToUserID    int64                 `gorm:"column:to_user_id;index" json:"toUserId"`                     // 被提名人用户ID
+	Status      string                `gorm:"column:status;type:varchar(16);index" json:"status"`          // pending, accepted, declined, cancelled
+	RespondedAt *int64                `gorm:"column:responded_at" json:"respondedAt,omitempty"`            // 处理时间(毫秒时间戳)
+	CreatedAt   int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt   int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt   soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (BabyOwnershipTransfer) TableName() string {
+	return "baby_ownership_transfers"
+}
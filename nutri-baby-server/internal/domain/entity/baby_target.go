@@ -0,0 +1,22 @@
+package entity
+
+// BabyTarget 宝宝每日目标覆盖配置
+// 字段为 nil 表示使用系统根据体重/月龄推荐的目标值
+type BabyTarget struct {
+	ID              int64  `gorm:"primaryKey;column:id" json:"id"`                                // 雪花ID主键
+	BabyID          int64  `gorm:"column:baby_id;uniqueIndex:idx_baby_target_baby" json:"babyId"` // 宝宝ID (引用Baby.ID)
+	MilkMinMl       *int64 `gorm:"column:milk_min_ml" json:"milkMinMl,omitempty"`                 // 每日奶量下限(ml)
+	MilkMaxMl       *int64 `gorm:"column:milk_max_ml" json:"milkMaxMl,omitempty"`                 // 每日奶量上限(ml)
+	FeedCountMin    *int   `gorm:"column:feed_count_min" json:"feedCountMin,omitempty"`           // 每日喂养次数下限
+	FeedCountMax    *int   `gorm:"column:feed_count_max" json:"feedCountMax,omitempty"`           // 每日喂养次数上限
+	SleepMinMinutes *int   `gorm:"column:sleep_min_minutes" json:"sleepMinMinutes,omitempty"`     // 每日睡眠下限(分钟)
+	SleepMaxMinutes *int   `gorm:"column:sleep_max_minutes" json:"sleepMaxMinutes,omitempty"`     // 每日睡眠上限(分钟)
+	UpdatedBy       int64  `gorm:"column:updated_by" json:"updatedBy"`                            // 最后修改人用户ID
+	CreatedAt       int64  `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`       // 创建时间(毫秒时间戳)
+	UpdatedAt       int64  `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`       // 更新时间(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (BabyTarget) TableName() string {
+	return "baby_targets"
+}
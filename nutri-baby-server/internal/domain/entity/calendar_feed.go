@@ -0,0 +1,17 @@
+package entity
+
+// CalendarFeed 用户的日历订阅(ICS)
+// 每个用户一条记录,重新生成时直接替换 Token,旧的订阅地址随即失效
+type CalendarFeed struct {
+	ID             int64  `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	UserID         int64  `gorm:"column:user_id;uniqueIndex;not null" json:"userId"`           // 用户ID (引用User.ID)
+	Token          string `gorm:"column:token;type:varchar(64);uniqueIndex;not null" json:"-"` // 订阅密钥
+	LastAccessedAt *int64 `gorm:"column:last_accessed_at" json:"lastAccessedAt,omitempty"`     // 最近一次被日历拉取的时间(毫秒时间戳)
+	CreatedAt      int64  `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt      int64  `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳,即 Token 生成时间)
+}
+
+// TableName 指定表名
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
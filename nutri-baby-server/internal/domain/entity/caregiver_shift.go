@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/plugin/soft_delete"
+)
+
+// CaregiverShiftMaxDuration 值班最长时长,超过后视为忘记交班,不再算作值班中
+const CaregiverShiftMaxDuration = 24 * time.Hour
+
+// CaregiverShift 照护人值班 (同一宝宝同时最多一位照护人值班,新照护人开始值班即完成交接)
+type CaregiverShift struct {
+	ID             int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	BabyID         int64                 `gorm:"column:baby_id;index" json:"babyId"`                          // 宝宝ID (引用Baby.ID)
+	UserID         int64                 `gorm:"column:user_id;index" json:"userId"`                          // 值班照护人用户ID (引用User.ID)
+	StartTime      int64                 `gorm:"column:start_time;index" json:"startTime"`                    // 开始值班时间(毫秒时间戳)
+	EndTime        *int64                `gorm:"column:end_time" json:"endTime,omitempty"`                    // 结束值班时间(毫秒时间戳),为空表示值班中
+	RouteReminders bool                  `gorm:"column:route_reminders;default:false" json:"routeReminders"`  // 值班期间提醒是否只发给值班照护人
+	HandoffNote    string                `gorm:"column:handoff_note;type:text" json:"handoffNote"`            // 交班备注(需要下一位照护人留意的情况)
+	CreatedAt      int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt      int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt      soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (CaregiverShift) TableName() string {
+	return "caregiver_shifts"
+}
+
+// IsOnDuty 是否值班中(未结束且未超过最长值班时长)
+func (s *CaregiverShift) IsOnDuty(now int64) bool {
+	return s.EndTime == nil && now-s.StartTime < CaregiverShiftMaxDuration.Milliseconds()
+}
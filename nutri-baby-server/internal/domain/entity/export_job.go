@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"gorm.io/plugin/soft_delete"
+)
+
+// 导出任务状态常量
+const (
+	ExportStatusPending    = "pending"    // 排队中
+	ExportStatusProcessing = "processing" // 生成中
+	ExportStatusCompleted  = "completed"  // 已完成,可下载
+	ExportStatusFailed     = "failed"     // 生成失败
+	ExportStatusExpired    = "expired"    // 下载链接已过期,文件已清理
+)
+
+// ExportJob 宝宝数据导出任务
+type ExportJob struct {
+	ID           int64                 `gorm:"primaryKey;column:id" json:"id"`                                        // 雪花ID主键
+	BabyID       int64                 `gorm:"column:baby_id;index:idx_export_job_baby_user" json:"babyId"`           // 宝宝ID (引用Baby.ID)
+	RequestedBy  int64                 `gorm:"column:requested_by;index:idx_export_job_baby_user" json:"requestedBy"` // 发起导出的用户ID (引用User.ID)
+	StartTime    int64                 `gorm:"column:start_time" json:"startTime"`                                    // 导出范围开始时间(毫秒时间戳,0表示不限)
+	EndTime      int64                 `gorm:"column:end_time" json:"endTime"`                                        // 导出范围结束时间(毫秒时间戳)
+	IncludeXLSX  bool                  `gorm:"column:include_xlsx;default:false" json:"includeXlsx"`                  // 是否包含 XLSX 工作簿
+	Status       string                `gorm:"column:status;type:varchar(16);default:'pending';index" json:"status"`  // 状态: pending, processing, completed, failed, expired
+	FileKey      string                `gorm:"column:file_key;type:varchar(512)" json:"fileKey"`                      // 导出文件存储key
+	FileSize     int64                 `gorm:"column:file_size" json:"fileSize"`                                      // 导出文件大小(字节)
+	RecordCount  int                   `gorm:"column:record_count" json:"recordCount"`                                // 导出记录总数
+	ErrorMessage *string               `gorm:"column:error_message;type:text" json:"errorMessage,omitempty"`          // 失败原因
+	CompletedAt  *int64                `gorm:"column:completed_at" json:"completedAt,omitempty"`                      // 完成时间(毫秒时间戳)
+	ExpiresAt    *int64                `gorm:"column:expires_at;index" json:"expiresAt,omitempty"`                    // 下载链接过期时间(毫秒时间戳)
+	CreatedAt    int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`               // 创建时间(毫秒时间戳)
+	UpdatedAt    int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`               // 更新时间(毫秒时间戳)
+	DeletedAt    soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`           // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"gorm.io/plugin/soft_delete"
+)
+
+// 发育里程碑分类常量
+const (
+	MilestoneCategoryMotor     = "motor"     // 大运动/精细动作
+	MilestoneCategoryLanguage  = "language"  // 语言
+	MilestoneCategorySocial    = "social"    // 社交情感
+	MilestoneCategoryCognitive = "cognitive" // 认知
+)
+
+// 发育里程碑状态常量
+const (
+	MilestoneStatusUpcoming          = "upcoming"            // 即将到来/尚未达成
+	MilestoneStatusAchieved          = "achieved"            // 已达成
+	MilestoneStatusDiscussWithDoctor = "discuss_with_doctor" // 需与医生沟通
+)
+
+// MilestoneTemplate 发育里程碑模板(参考儿童发育评估标准)
+type MilestoneTemplate struct {
+	ID             int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	Category       string                `gorm:"column:category;type:varchar(16);index" json:"category"`      // 分类: motor, language, social, cognitive
+	Title          string                `gorm:"column:title;type:varchar(64)" json:"title"`                  // 里程碑名称
+	Description    string                `gorm:"column:description;type:text" json:"description"`             // 描述
+	AgeInMonths    int                   `gorm:"column:age_in_months" json:"ageInMonths"`                     // 通常达成月龄
+	MaxAgeInMonths int                   `gorm:"column:max_age_in_months" json:"maxAgeInMonths"`              // 最晚达成月龄(超过建议咨询医生)
+	SortOrder      int                   `gorm:"column:sort_order;default:0" json:"sortOrder"`                // 排序
+	CreatedAt      int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt      int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt      soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (MilestoneTemplate) TableName() string {
+	return "milestone_templates"
+}
+
+// BabyMilestone 宝宝发育里程碑记录
+type BabyMilestone struct {
+	ID             int64                 `gorm:"primaryKey;column:id" json:"id"`                                        // 雪花ID主键
+	BabyID         int64                 `gorm:"column:baby_id;index;not null" json:"babyId"`                           // 宝宝ID (引用Baby.ID)
+	TemplateID     *int64                `gorm:"column:template_id" json:"templateId,omitempty"`                        // 来源模板ID (引用MilestoneTemplate.ID)
+	Category       string                `gorm:"column:category;type:varchar(16)" json:"category"`                      // 分类
+	Title          string                `gorm:"column:title;type:varchar(64);not null" json:"title"`                   // 里程碑名称
+	Description    string                `gorm:"column:description;type:text" json:"description"`                       // 描述
+	AgeInMonths    int                   `gorm:"column:age_in_months" json:"ageInMonths"`                               // 通常达成月龄
+	MaxAgeInMonths int                   `gorm:"column:max_age_in_months" json:"maxAgeInMonths"`                        // 最晚达成月龄
+	Status         string                `gorm:"column:status;type:varchar(32);default:'upcoming';index" json:"status"` // 状态: upcoming, achieved, discuss_with_doctor
+	AchievedDate   *int64                `gorm:"column:achieved_date;index" json:"achievedDate,omitempty"`              // 达成日期(毫秒时间戳)
+	Photos         MilestonePhotos       `gorm:"column:photos;type:jsonb" json:"photos"`                                // 照片URL列表
+	Note           *string               `gorm:"column:note;type:text" json:"note,omitempty"`                           // 备注
+	UpdatedBy      *int64                `gorm:"column:updated_by" json:"updatedBy,omitempty"`                          // 最后标记状态的用户ID (引用User.ID)
+	CreatedBy      int64                 `gorm:"column:created_by;not null" json:"createdBy"`                           // 创建者用户ID (引用User.ID)
+	CreatedAt      int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`               // 创建时间(毫秒时间戳)
+	UpdatedAt      int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`               // 更新时间(毫秒时间戳)
+	DeletedAt      soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`           // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (BabyMilestone) TableName() string {
+	return "baby_milestones"
+}
+
+// IsAchieved 判断是否已达成
+func (m *BabyMilestone) IsAchieved() bool {
+	return m.Status == MilestoneStatusAchieved
+}
+
+// MilestonePhotos 里程碑照片URL列表
+type MilestonePhotos []string
+
+// Scan 实现sql.Scanner接口
+func (p *MilestonePhotos) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value 实现driver.Valuer接口
+func (p MilestonePhotos) Value() (driver.Value, error) {
+	if p == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal(p)
+}
@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// 协作者角色
+const (
+	CollaboratorRoleAdmin     = "admin"     // 管理员: 全部权限,可管理亲友团
+	CollaboratorRoleEditor    = "editor"    // 编辑者: 全部记录的增删改查
+	CollaboratorRoleCaregiver = "caregiver" // 照护者(保姆/育儿嫂): 仅喂养、睡眠、排泄的查看和记录
+	CollaboratorRoleViewer    = "viewer"    // 查看者: 全部记录只读
+)
+
+// 权限矩阵中的记录类型
+const (
+	PermissionResourceFeeding   = "feeding"   // 喂养记录
+	PermissionResourceSleep     = "sleep"     // 睡眠记录
+	PermissionResourceDiaper    = "diaper"    // 排泄记录
+	PermissionResourceGrowth    = "growth"    // 成长记录
+	PermissionResourceVaccine   = "vaccine"   // 疫苗接种
+	PermissionResourceMilestone = "milestone" // 发育里程碑
+)
+
+// 权限矩阵中的操作
+const (
+	PermissionActionRead      = "read"       // 查看
+	PermissionActionCreate    = "create"     // 新增
+	PermissionActionUpdateOwn = "update_own" // 修改自己创建的记录
+	PermissionActionUpdateAny = "update_any" // 修改任何人创建的记录
+	PermissionActionDelete    = "delete"     // 删除
+)
+
+// PermissionResources 全部记录类型(按展示顺序)
+var PermissionResources = []string{
+	PermissionResourceFeeding,
+	PermissionResourceSleep,
+	PermissionResourceDiaper,
+	PermissionResourceGrowth,
+	PermissionResourceVaccine,
+	PermissionResourceMilestone,
+}
+
+// PermissionActions 全部操作(按展示顺序)
+var PermissionActions = []string{
+	PermissionActionRead,
+	PermissionActionCreate,
+	PermissionActionUpdateOwn,
+	PermissionActionUpdateAny,
+	PermissionActionDelete,
+}
+
+// CollaboratorRoles 全部角色
+var CollaboratorRoles = []string{
+	CollaboratorRoleAdmin,
+	CollaboratorRoleEditor,
+	CollaboratorRoleCaregiver,
+	CollaboratorRoleViewer,
+}
+
+// PermissionSet 权限集合: 记录类型 -> 允许的操作
+type PermissionSet map[string][]string
+
+// Allows 是否允许对记录类型执行操作
+func (p PermissionSet) Allows(resource, action string) bool {
+	for _, a := range p[resource] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionOverrides 协作者权限覆盖: 记录类型 -> 操作 -> 是否允许
+// 未覆盖的操作沿用角色预设
+type PermissionOverrides map[string]map[string]bool
+
+// Scan 实现sql.Scanner接口
+func (p *PermissionOverrides) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value 实现driver.Valuer接口
+func (p PermissionOverrides) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// RolePermissions 角色预设权限,未知角色没有任何权限
+func RolePermissions(role string) PermissionSet {
+	all := []string{PermissionActionRead, PermissionActionCreate, PermissionActionUpdateOwn, PermissionActionUpdateAny, PermissionActionDelete}
+	readOnly := []string{PermissionActionRead}
+	logging := []string{PermissionActionRead, PermissionActionCreate, PermissionActionUpdateOwn}
+
+	set := make(PermissionSet, len(PermissionResources))
+	switch role {
+	case CollaboratorRoleAdmin, CollaboratorRoleEditor:
+		for _, resource := range PermissionResources {
+			set[resource] = all
+		}
+	case CollaboratorRoleViewer:
+		for _, resource := range PermissionResources {
+			set[resource] = readOnly
+		}
+	case CollaboratorRoleCaregiver:
+		set[PermissionResourceFeeding] = logging
+		set[PermissionResourceSleep] = logging
+		set[PermissionResourceDiaper] = logging
+	}
+	return set
+}
+
+// IsValidCollaboratorRole 是否为有效的协作者角色
+func IsValidCollaboratorRole(role string) bool {
+	for _, r := range CollaboratorRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPermission 记录类型和操作是否在权限矩阵中
+func IsValidPermission(resource, action string) bool {
+	validResource, validAction := false, false
+	for _, r := range PermissionResources {
+		validResource = validResource || r == resource
+	}
+	for _, a := range PermissionActions {
+		validAction = validAction || a == action
+	}
+	return validResource && validAction
+}
+
+// Can 检查协作者能否对某类记录执行操作
+// 临时权限过期后没有任何权限;管理员不受覆盖限制,避免把自己锁在外面
+func (bc *BabyCollaborator) Can(resource, action string) bool {
+	if bc.IsExpired() {
+		return false
+	}
+	if bc.IsAdmin() {
+		return true
+	}
+	if allowed, ok := bc.Permissions[resource][action]; ok {
+		return allowed
+	}
+	return RolePermissions(bc.Role).Allows(resource, action)
+}
+
+// CanUpdate 检查协作者能否修改 ownerID 创建的记录
+func (bc *BabyCollaborator) CanUpdate(resource string, ownerID int64) bool {
+	if bc.Can(resource, PermissionActionUpdateAny) {
+		return true
+	}
+	return ownerID == bc.UserID && bc.Can(resource, PermissionActionUpdateOwn)
+}
+
+// EffectivePermissions 合并角色预设和覆盖后的实际权限
+func (bc *BabyCollaborator) EffectivePermissions() PermissionSet {
+	set := make(PermissionSet, len(PermissionResources))
+	for _, resource := range PermissionResources {
+		actions := []string{}
+		for _, action := range PermissionActions {
+			if bc.Can(resource, action) {
+				actions = append(actions, action)
+			}
+		}
+		set[resource] = actions
+	}
+	return set
+}
@@ -14,6 +14,19 @@ const (
 	FeedingTypeFood   = "food"   // 辅食
 )
 
+// 睡眠类型常量
+const (
+	SleepTypeNap   = "nap"   // 小睡
+	SleepTypeNight = "night" // 夜间睡眠
+)
+
+// 尿布类型常量
+const (
+	DiaperTypePee  = "pee"  // 小便
+	DiaperTypePoop = "poop" // 大便
+	DiaperTypeBoth = "both" // 大小便
+)
+
 // FeedingRecord 喂养记录实体
 type FeedingRecord struct {
 	ID              int64         `gorm:"primaryKey;column:id" json:"id"`                                    // 雪花ID主键
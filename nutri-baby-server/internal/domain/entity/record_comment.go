@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"gorm.io/plugin/soft_delete"
+)
+
+// 评论与表情回应限制
+const (
+	RecordCommentMaxLength = 500 // 评论内容最大字数
+	RecordReactionMaxRunes = 8   // 表情回应最大字符数(含组合表情的连接符)
+)
+
+// RecordComment 记录评论(可关联任意类型的记录,支持一层回复)
+// 记录类型与附件相同: feeding, sleep, diaper, growth, milestone, vaccine
+type RecordComment struct {
+	ID               int64                 `gorm:"primaryKey;column:id" json:"id"`                                                 // 雪花ID主键
+	BabyID           int64                 `gorm:"column:baby_id;index" json:"babyId"`                                             // 宝宝ID (引用Baby.ID)
+	RecordType       string                `gorm:"column:record_type;type:varchar(16);index:idx_comment_record" json:"recordType"` // 记录类型
+	RecordID         int64                 `gorm:"column:record_id;index:idx_comment_record" json:"recordId"`                      // 记录ID
+	ParentID         int64                 `gorm:"column:parent_id;default:0" json:"parentId"`                                     // 所属顶层评论ID,0 表示顶层评论
+	ReplyToUserID    int64                 `gorm:"column:reply_to_user_id;default:0" json:"replyToUserId"`                         // 被回复的用户ID (引用User.ID),0 表示未回复具体用户
+	UserID           int64                 `gorm:"column:user_id" json:"userId"`                                                   // 评论者用户ID (引用User.ID)
+	Content          string                `gorm:"column:content;type:text" json:"content"`                                        // 评论内容
+	MentionedUserIDs string                `gorm:"column:mentioned_user_ids;type:varchar(512)" json:"mentionedUserIds"`            // @提及的用户ID,逗号分隔
+	CreatedAt        int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`                        // 创建时间(毫秒时间戳)
+	UpdatedAt        int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`                        // 更新时间(毫秒时间戳)
+	DeletedAt        soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`                    // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (RecordComment) TableName() string {
+	return "record_comments"
+}
+
+// RecordReaction 记录表情回应(每人对同一条记录的同一个表情只记一次)
+type RecordReaction struct {
+	ID         int64                 `gorm:"primaryKey;column:id" json:"id"`                                                                         // 雪花ID主键
+	BabyID     int64                 `gorm:"column:baby_id;index" json:"babyId"`                                                                     // 宝宝ID (引用Baby.ID)
+	RecordType string                `gorm:"column:record_type;type:varchar(16);uniqueIndex:idx_reaction_record_user_emoji" json:"recordType"`       // 记录类型
+	RecordID   int64                 `gorm:"column:record_id;uniqueIndex:idx_reaction_record_user_emoji" json:"recordId"`                            // 记录ID
+	UserID     int64                 `gorm:"column:user_id;uniqueIndex:idx_reaction_record_user_emoji" json:"userId"`                                // 回应者用户ID (引用User.ID)
+	Emoji      string                `gorm:"column:emoji;type:varchar(32);uniqueIndex:idx_reaction_record_user_emoji" json:"emoji"`                  // 表情
+	CreatedAt  int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`                                                // 创建时间(毫秒时间戳)
+	DeletedAt  soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;uniqueIndex:idx_reaction_record_user_emoji;default:0" json:"-"` // 软删除(毫秒时间戳),参与唯一索引以便取消后可再次回应
+}
+
+// TableName 指定表名
+func (RecordReaction) TableName() string {
+	return "record_reactions"
+}
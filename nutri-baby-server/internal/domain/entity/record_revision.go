@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// 修订操作类型
+const (
+	RevisionActionCreate  = "create"  // 创建
+	RevisionActionUpdate  = "update"  // 修改
+	RevisionActionDelete  = "delete"  // 删除(软删除)
+	RevisionActionRestore = "restore" // 恢复到历史版本
+)
+
+// RecordRevision 记录修订历史(只追加,不修改不删除)
+// 记录类型与附件相同: feeding, sleep, diaper, growth, milestone, vaccine
+type RecordRevision struct {
+	ID              int64            `gorm:"primaryKey;column:id" json:"id"`                                                    // 雪花ID主键
+	BabyID          int64            `gorm:"column:baby_id;index" json:"babyId"`                                                // 宝宝ID (引用Baby.ID)
+	RecordType      string           `gorm:"column:record_type;type:varchar(16);index:idx_revision_record" json:"recordType"`   // 记录类型
+	RecordID        int64            `gorm:"column:record_id;index:idx_revision_record" json:"recordId"`                        // 记录ID
+	Action          string           `gorm:"column:action;type:varchar(16)" json:"action"`                                      // 操作: create, update, delete, restore
+	ActorID         int64            `gorm:"column:actor_id" json:"actorId"`                                                    // 操作人用户ID (引用User.ID)
+	ActorName       string           `gorm:"column:actor_name;type:varchar(64)" json:"actorName"`                               // 冗余:操作人昵称
+	Snapshot        RevisionSnapshot `gorm:"column:snapshot;type:jsonb" json:"snapshot"`                                        // 操作后的记录快照,删除时为删除前的快照
+	Changes         RevisionChanges  `gorm:"column:changes;type:jsonb" json:"changes"`                                          // 字段级变更
+	RecordUpdatedAt int64            `gorm:"column:record_updated_at" json:"recordUpdatedAt"`                                   // 快照对应的记录更新时间(毫秒时间戳)
+	RestoredFrom    int64            `gorm:"column:restored_from;default:0" json:"restoredFrom"`                                // 恢复来源的修订ID,仅 restore 操作有值
+	CreatedAt       int64            `gorm:"column:created_at;autoCreateTime:milli;index:idx_revision_record" json:"createdAt"` // 操作时间(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (RecordRevision) TableName() string {
+	return "record_revisions"
+}
+
+// RevisionSnapshot 记录快照(记录实体的JSON字段)
+// 数字解析为 json.Number,避免雪花ID等大整数丢失精度
+type RevisionSnapshot map[string]any
+
+// DecodeRevisionSnapshot 解析快照JSON,数字保留原始精度
+func DecodeRevisionSnapshot(data []byte) (RevisionSnapshot, error) {
+	var snapshot RevisionSnapshot
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Scan 实现sql.Scanner接口
+func (s *RevisionSnapshot) Scan(value any) error {
+	data, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	snapshot, err := DecodeRevisionSnapshot(data)
+	if err != nil {
+		return err
+	}
+	*s = snapshot
+	return nil
+}
+
+// Value 实现driver.Valuer接口
+func (s RevisionSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// RevisionFieldChange 单个字段的变更,嵌套字段用点号连接(如 detail.amount)
+type RevisionFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// RevisionChanges 字段级变更列表
+type RevisionChanges []RevisionFieldChange
+
+// Scan 实现sql.Scanner接口
+func (c *RevisionChanges) Scan(value any) error {
+	data, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(c)
+}
+
+// Value 实现driver.Valuer接口
+func (c RevisionChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
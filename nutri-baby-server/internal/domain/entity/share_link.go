@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"gorm.io/plugin/soft_delete"
+)
+
+// 分享链接可授权的记录类型
+const (
+	ShareRecordTypeFeeding = "feeding" // 喂养记录
+	ShareRecordTypeSleep   = "sleep"   // 睡眠记录
+	ShareRecordTypeDiaper  = "diaper"  // 换尿布记录
+	ShareRecordTypeGrowth  = "growth"  // 成长记录
+	ShareRecordTypeVaccine = "vaccine" // 疫苗接种记录
+)
+
+// 分享链接访问结果
+const (
+	ShareAccessAllowed = "allowed" // 访问成功
+	ShareAccessExpired = "expired" // 链接已过期
+	ShareAccessRevoked = "revoked" // 链接已撤销
+)
+
+// ShareLink 只读分享链接(供医生、临时保姆等非协作者查看指定时间范围内的记录)
+type ShareLink struct {
+	ID             int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	BabyID         int64                 `gorm:"column:baby_id;index" json:"babyId"`                          // 宝宝ID (引用Baby.ID)
+	CreatedBy      int64                 `gorm:"column:created_by" json:"createdBy"`                          // 创建者用户ID (引用User.ID,须为管理员)
+	Label          string                `gorm:"column:label;type:varchar(64)" json:"label"`                  // 备注名称,如 "儿科王医生"
+	RecordTypes    string                `gorm:"column:record_types;type:varchar(128)" json:"recordTypes"`    // 授权的记录类型,逗号分隔
+	StartTime      int64                 `gorm:"column:start_time" json:"startTime"`                          // 可查看记录的开始时间(毫秒时间戳)
+	EndTime        int64                 `gorm:"column:end_time" json:"endTime"`                              // 可查看记录的结束时间(毫秒时间戳,0表示截至访问时)
+	ExpiresAt      int64                 `gorm:"column:expires_at;index" json:"expiresAt"`                    // 链接过期时间(毫秒时间戳)
+	RevokedAt      *int64                `gorm:"column:revoked_at" json:"revokedAt,omitempty"`                // 撤销时间(毫秒时间戳)
+	AccessCount    int                   `gorm:"column:access_count;default:0" json:"accessCount"`            // 成功访问次数
+	LastAccessedAt *int64                `gorm:"column:last_accessed_at" json:"lastAccessedAt,omitempty"`     // 最近访问时间(毫秒时间戳)
+	CreatedAt      int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt      int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt      soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (ShareLink) TableName() string {
+	return "share_links"
+}
+
+// ShareAccessLog 分享链接访问审计日志
+type ShareAccessLog struct {
+	ID          int64  `gorm:"primaryKey;column:id" json:"id"`                                           // 雪花ID主键
+	ShareLinkID int64  `gorm:"column:share_link_id;index:idx_share_access_link_time" json:"shareLinkId"` // 分享链接ID (引用ShareLink.ID)
+	BabyID      int64  `gorm:"column:baby_id;index" json:"babyId"`                                       // 宝宝ID (引用Baby.ID)
+	Resource    string `gorm:"column:resource;type:varchar(32)" json:"resource"`                         // 访问内容: overview 或记录类型
+	Result      string `gorm:"column:result;type:varchar(16)" json:"result"`                             // 访问结果: allowed, expired, revoked
+	IP          string `gorm:"column:ip;type:varchar(64)" json:"ip"`                                     // 访问者IP
+	UserAgent   string `gorm:"column:user_agent;type:varchar(512)" json:"userAgent"`                     // 访问者 User-Agent
+	AccessedAt  int64  `gorm:"column:accessed_at;index:idx_share_access_link_time" json:"accessedAt"`    // 访问时间(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (ShareAccessLog) TableName() string {
+	return "share_access_logs"
+}
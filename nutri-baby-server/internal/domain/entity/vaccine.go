@@ -21,20 +21,43 @@ const (
 	VaccinationStatusSkipped   = "skipped"   // 跳过/不接种
 )
 
-// VaccinePlanTemplate 疫苗计划模板(国家免疫规划标准)
+// VaccinePlan 疫苗计划(一组疫苗计划模板)
+// 同一 Code 可以有多个版本,宝宝固定使用选择时的版本,新版本发布后需手动切换
+type VaccinePlan struct {
+	ID          int64                 `gorm:"primaryKey;column:id" json:"id"`                                             // 雪花ID主键
+	Code        string                `gorm:"column:code;type:varchar(64);uniqueIndex:idx_plan_code_version" json:"code"` // 计划编码,如 cn-nip
+	Version     int                   `gorm:"column:version;uniqueIndex:idx_plan_code_version" json:"version"`            // 版本号,从1开始递增
+	Name        string                `gorm:"column:name;type:varchar(64);not null" json:"name"`                          // 计划名称
+	Region      string                `gorm:"column:region;type:varchar(16);index" json:"region"`                         // 适用地区,如 CN、US
+	Description string                `gorm:"column:description;type:text" json:"description"`                            // 描述
+	IsDefault   bool                  `gorm:"column:is_default;default:false" json:"isDefault"`                           // 是否为新宝宝的默认计划(全局唯一)
+	CreatedAt   int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`                    // 创建时间(毫秒时间戳)
+	UpdatedAt   int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`                    // 更新时间(毫秒时间戳)
+	DeletedAt   soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`                // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (VaccinePlan) TableName() string {
+	return "vaccine_plans"
+}
+
+// VaccinePlanTemplate 疫苗计划模板(计划中的一剂疫苗)
 type VaccinePlanTemplate struct {
-	ID           int64                 `gorm:"primaryKey;column:id" json:"id"`                                // 雪花ID主键
-	VaccineType  string                `gorm:"column:vaccine_type;type:varchar(32);index" json:"vaccineType"` // 疫苗类型
-	VaccineName  string                `gorm:"column:vaccine_name;type:varchar(64)" json:"vaccineName"`       // 疫苗名称
-	Description  string                `gorm:"column:description;type:text" json:"description"`               // 描述
-	AgeInMonths  int                   `gorm:"column:age_in_months" json:"ageInMonths"`                       // 接种年龄(月)
-	DoseNumber   int                   `gorm:"column:dose_number" json:"doseNumber"`                          // 接种次数
-	IsRequired   bool                  `gorm:"column:is_required" json:"isRequired"`                          // 是否必接种
-	ReminderDays int                   `gorm:"column:reminder_days;default:7" json:"reminderDays"`            // 提前多少天提醒
-	SortOrder    int                   `gorm:"column:sort_order;default:0" json:"sortOrder"`                  // 排序
-	CreatedAt    int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`       // 创建时间(毫秒时间戳)
-	UpdatedAt    int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`       // 更新时间(毫秒时间戳)
-	DeletedAt    soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`   // 软删除(毫秒时间戳)
+	ID              int64                 `gorm:"primaryKey;column:id" json:"id"`                                // 雪花ID主键
+	PlanID          int64                 `gorm:"column:plan_id;index;default:0" json:"planId"`                  // 所属疫苗计划ID (引用VaccinePlan.ID)
+	VaccineType     string                `gorm:"column:vaccine_type;type:varchar(32);index" json:"vaccineType"` // 疫苗类型
+	VaccineName     string                `gorm:"column:vaccine_name;type:varchar(64)" json:"vaccineName"`       // 疫苗名称
+	Description     string                `gorm:"column:description;type:text" json:"description"`               // 描述
+	AgeInMonths     int                   `gorm:"column:age_in_months" json:"ageInMonths"`                       // 接种年龄(月)
+	DoseNumber      int                   `gorm:"column:dose_number" json:"doseNumber"`                          // 接种次数
+	IsRequired      bool                  `gorm:"column:is_required" json:"isRequired"`                          // 是否必接种
+	ReminderDays    int                   `gorm:"column:reminder_days;default:7" json:"reminderDays"`            // 提前多少天提醒
+	MinAgeDays      int                   `gorm:"column:min_age_days;default:0" json:"minAgeDays"`               // 最小接种日龄(天),0 表示不限
+	MinIntervalDays int                   `gorm:"column:min_interval_days;default:0" json:"minIntervalDays"`     // 与同系列上一剂的最小间隔(天),0 表示不限
+	SortOrder       int                   `gorm:"column:sort_order;default:0" json:"sortOrder"`                  // 排序
+	CreatedAt       int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`       // 创建时间(毫秒时间戳)
+	UpdatedAt       int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`       // 更新时间(毫秒时间戳)
+	DeletedAt       soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"`   // 软删除(毫秒时间戳)
 }
 
 // TableName 指定表名
@@ -61,6 +84,10 @@ type BabyVaccineSchedule struct {
 	ReminderDays int    `gorm:"column:reminder_days;default:7" json:"reminderDays"`               // 提前多少天提醒
 	IsCustom     bool   `gorm:"column:is_custom;default:false" json:"isCustom"`                   // 是否用户自定义
 
+	// 补种规则 (来自模板,用于推迟后续剂次和提前接种提醒)
+	MinAgeDays      int `gorm:"column:min_age_days;default:0" json:"minAgeDays"`           // 最小接种日龄(天),0 表示不限
+	MinIntervalDays int `gorm:"column:min_interval_days;default:0" json:"minIntervalDays"` // 与同系列上一剂的最小间隔(天),0 表示不限
+
 	// 接种状态 (关键字段)
 	VaccinationStatus string `gorm:"column:vaccination_status;type:varchar(16);not null;default:'pending';index" json:"vaccinationStatus"`
 	// 状态值: pending(未接种), completed(已完成), skipped(跳过/不接种)
@@ -77,6 +104,10 @@ type BabyVaccineSchedule struct {
 	CompletedByAvatar *string `gorm:"column:completed_by_avatar;type:varchar(512)" json:"completedByAvatar,omitempty"` // 记录者头像
 	CompletedTime     *int64  `gorm:"column:completed_time" json:"completedTime,omitempty"`                            // 接种记录创建时间(毫秒时间戳)
 
+	// 接种后反应随访汇总 (由 VaccineReactionFollowUp 汇总生成)
+	ReactionSeverity string  `gorm:"column:reaction_severity;type:varchar(16)" json:"reactionSeverity,omitempty"` // 最重反应程度: none/mild/moderate/severe
+	ReactionSummary  *string `gorm:"column:reaction_summary;type:text" json:"reactionSummary,omitempty"`          // 反应汇总描述
+
 	// 提醒相关字段 (合并自 VaccineReminder)
 	ScheduledDate  int64  `gorm:"column:scheduled_date;index" json:"scheduledDate"`        // 计划接种日期(毫秒时间戳)
 	ReminderSent   bool   `gorm:"column:reminder_sent;default:false" json:"reminderSent"`  // 是否已发送提醒
@@ -151,6 +182,11 @@ func (s *BabyVaccineSchedule) DaysUntilDue() int {
 	return int(diff / (24 * 60 * 60 * 1000))
 }
 
+// HasSevereReaction 接种后是否出现重度反应
+func (s *BabyVaccineSchedule) HasSevereReaction() bool {
+	return s.ReactionSeverity == ReactionSeveritySevere
+}
+
 // MarkReminderSent 标记提醒已发送
 func (s *BabyVaccineSchedule) MarkReminderSent() {
 	s.ReminderSent = true
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"gorm.io/plugin/soft_delete"
+)
+
+// 接种反应程度
+const (
+	ReactionSeverityNone     = "none"     // 无
+	ReactionSeverityMild     = "mild"     // 轻度
+	ReactionSeverityModerate = "moderate" // 中度
+	ReactionSeveritySevere   = "severe"   // 重度(下一剂接种前需告知医生)
+)
+
+// VaccineReactionFollowUp 接种后反应随访(每个随访时间点一条)
+// 接种记录完成后按固定时间点生成,提醒照护人记录体温、局部红肿和哭闹烦躁情况
+type VaccineReactionFollowUp struct {
+	ID              int64                 `gorm:"primaryKey;column:id" json:"id"`                              // 雪花ID主键
+	ScheduleID      int64                 `gorm:"column:schedule_id;index" json:"scheduleId"`                  // 疫苗日程ID (引用BabyVaccineSchedule.ID)
+	BabyID          int64                 `gorm:"column:baby_id;index" json:"babyId"`                          // 宝宝ID (引用Baby.ID)
+	CheckpointHours int                   `gorm:"column:checkpoint_hours" json:"checkpointHours"`              // 接种后第几小时
+	DueAt           int64                 `gorm:"column:due_at;index" json:"dueAt"`                            // 随访时间(毫秒时间戳)
+	PromptSentAt    *int64                `gorm:"column:prompt_sent_at" json:"promptSentAt,omitempty"`         // 提醒发送时间(毫秒时间戳)
+	TemperatureC    *float64              `gorm:"column:temperature_c" json:"temperatureC,omitempty"`          // 体温(℃)
+	Swelling        string                `gorm:"column:swelling;type:varchar(16)" json:"swelling"`            // 接种部位红肿: none/mild/moderate/severe
+	Fussiness       string                `gorm:"column:fussiness;type:varchar(16)" json:"fussiness"`          // 哭闹烦躁: none/mild/moderate/severe
+	Note            *string               `gorm:"column:note;type:text" json:"note,omitempty"`                 // 备注
+	RecordedBy      *int64                `gorm:"column:recorded_by" json:"recordedBy,omitempty"`              // 记录人用户ID (引用User.ID)
+	RecordedAt      *int64                `gorm:"column:recorded_at" json:"recordedAt,omitempty"`              // 记录时间(毫秒时间戳)
+	CreatedAt       int64                 `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`     // 创建时间(毫秒时间戳)
+	UpdatedAt       int64                 `gorm:"column:updated_at;autoUpdateTime:milli" json:"updatedAt"`     // 更新时间(毫秒时间戳)
+	DeletedAt       soft_delete.DeletedAt `gorm:"column:deleted_at;softDelete:milli;index;default:0" json:"-"` // 软删除(毫秒时间戳)
+}
+
+// TableName 指定表名
+func (VaccineReactionFollowUp) TableName() string {
+	return "vaccine_reaction_followups"
+}
+
+// IsRecorded 是否已记录
+func (f *VaccineReactionFollowUp) IsRecorded() bool {
+	return f.RecordedAt != nil
+}
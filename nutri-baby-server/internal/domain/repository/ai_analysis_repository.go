@@ -56,6 +56,9 @@ type DailyTipsRepository interface {
 
 	// 删除过期的每日建议
 	DeleteExpired(ctx context.Context, before time.Time) error
+
+	// 删除宝宝的全部每日建议(出生日期变化等导致建议失效时)
+	DeleteByBabyID(ctx context.Context, babyID int64) error
 }
 
 // AnalysisStats 分析统计信息
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// AttachmentRepository 照片附件仓储接口
+type AttachmentRepository interface {
+	// Create 创建附件
+	Create(ctx context.Context, attachment *entity.Attachment) error
+
+	// FindByID 根据ID查找附件
+	FindByID(ctx context.Context, attachmentID int64) (*entity.Attachment, error)
+
+	// FindByRecord 查找某条记录的所有附件
+	FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.Attachment, error)
+
+	// FindByBabyIDInRange 查找宝宝在时间范围内的附件(按归档时间倒序)
+	FindByBabyIDInRange(ctx context.Context, babyID int64, startTime, endTime int64) ([]*entity.Attachment, error)
+
+	// FindLatestBefore 查找归档时间早于指定时间的最新一条附件,不存在时返回 nil
+	FindLatestBefore(ctx context.Context, babyID int64, before int64) (*entity.Attachment, error)
+
+	// FindEarliestAfter 查找归档时间晚于指定时间的最早一条附件,不存在时返回 nil
+	FindEarliestAfter(ctx context.Context, babyID int64, after int64) (*entity.Attachment, error)
+
+	// Delete 删除附件(软删除)
+	Delete(ctx context.Context, attachmentID int64) error
+
+	// DeleteByRecord 删除某条记录的所有附件(软删除)
+	DeleteByRecord(ctx context.Context, recordType string, recordID int64) error
+}
@@ -27,14 +27,37 @@ type BabyCollaboratorRepository interface {
 	// Update 更新协作者信息
 	Update(ctx context.Context, collaborator *entity.BabyCollaborator) error
 
+	// UpdatePermissions 更新协作者的权限覆盖(传 nil 清除覆盖,恢复角色预设)
+	UpdatePermissions(ctx context.Context, babyID int64, userID int64, overrides entity.PermissionOverrides) error
+
 	// Delete 移除协作者(软删除)
 	Delete(ctx context.Context, babyID int64, userID int64) error
 
+	// UpdateKeepingAdmin 更新协作者信息,降级后宝宝将没有有效管理员时不做修改并返回 false
+	// 检查和更新在同一事务中进行,并锁定宝宝的协作者行
+	UpdateKeepingAdmin(ctx context.Context, collaborator *entity.BabyCollaborator) (bool, error)
+
+	// DeleteKeepingAdmin 移除协作者(软删除),移除后宝宝将没有有效管理员时不做修改并返回 false
+	// 检查和删除在同一事务中进行,并锁定宝宝的协作者行
+	DeleteKeepingAdmin(ctx context.Context, babyID int64, userID int64) (bool, error)
+
 	// BatchCreate 批量创建协作者(用于复制协作者列表)
 	BatchCreate(ctx context.Context, collaborators []*entity.BabyCollaborator) error
 
-	// CleanExpired 清理过期的临时协作者
-	CleanExpired(ctx context.Context) error
+	// FindExpiringBetween 查找在 (from, to] 内到期、尚未提醒过的临时协作者
+	FindExpiringBetween(ctx context.Context, from, to int64) ([]*entity.BabyCollaborator, error)
+
+	// FindExpired 查找在 now 之前已到期的临时协作者
+	FindExpired(ctx context.Context, now int64) ([]*entity.BabyCollaborator, error)
+
+	// DeleteExpired 移除在 now 之前已到期的临时协作者(软删除),已续期或已被移除时返回 false
+	DeleteExpired(ctx context.Context, babyID, userID int64, now int64) (bool, error)
+
+	// MarkReminded 记录到期前提醒已发送
+	MarkReminded(ctx context.Context, collaboratorID int64, remindedAt int64) error
+
+	// UpdateExpiry 延长临时协作者的过期时间,并清空到期提醒记录
+	UpdateExpiry(ctx context.Context, babyID int64, userID int64, expiresAt int64) error
 
 	// IsCollaborator 检查是否是协作者
 	IsCollaborator(ctx context.Context, babyID int64, userID int64) (bool, error)
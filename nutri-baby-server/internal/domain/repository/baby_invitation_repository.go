@@ -11,6 +11,9 @@ type BabyInvitationRepository interface {
 	// Create 创建邀请
 	Create(ctx context.Context, invitation *entity.BabyInvitation) error
 
+	// FindByID 根据ID查找邀请
+	FindByID(ctx context.Context, invitationID int64) (*entity.BabyInvitation, error)
+
 	// FindByToken 根据token查找邀请
 	FindByToken(ctx context.Context, token string) (*entity.BabyInvitation, error)
 
@@ -20,13 +23,19 @@ type BabyInvitationRepository interface {
 	// FindByBabyID 查找宝宝的所有邀请记录
 	FindByBabyID(ctx context.Context, babyID int64) ([]*entity.BabyInvitation, error)
 
-	// FindByBabyAndInviter 根据宝宝ID和邀请人查找未使用的邀请记录
-	// 用于实现邀请码唯一性:同一用户对同一宝宝只有一个有效邀请
+	// FindByBabyAndInviter 根据宝宝ID和邀请人查找最近一条未撤销的邀请记录
+	// 用于复用邀请码:同一用户对同一宝宝以相同设置重复邀请时返回已有邀请
 	FindByBabyAndInviter(ctx context.Context, babyID int64, inviterID int64) (*entity.BabyInvitation, error)
 
+	// IncrementUsedCount 占用一次使用次数,已达上限时返回 false
+	IncrementUsedCount(ctx context.Context, invitationID int64) (bool, error)
+
+	// Revoke 撤销邀请
+	Revoke(ctx context.Context, invitationID int64, revokedAt int64) error
+
 	// Delete 删除邀请(软删除)
 	Delete(ctx context.Context, invitationID int64) error
 
-	// CleanExpired 清理过期的邀请
+	// CleanExpired 清理链接已过期的邀请
 	CleanExpired(ctx context.Context) error
 }
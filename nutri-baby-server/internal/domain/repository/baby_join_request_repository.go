@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// BabyJoinRequestRepository 加入申请仓储接口
+type BabyJoinRequestRepository interface {
+	// Create 创建加入申请
+	Create(ctx context.Context, request *entity.BabyJoinRequest) error
+
+	// FindByID 根据ID查找加入申请
+	FindByID(ctx context.Context, requestID int64) (*entity.BabyJoinRequest, error)
+
+	// FindPendingByBabyID 获取宝宝待审批的加入申请(按申请时间倒序)
+	FindPendingByBabyID(ctx context.Context, babyID int64) ([]*entity.BabyJoinRequest, error)
+
+	// FindPendingByBabyAndUser 查找用户对宝宝待审批的加入申请,不存在时返回 nil
+	FindPendingByBabyAndUser(ctx context.Context, babyID, userID int64) (*entity.BabyJoinRequest, error)
+
+	// Review 审批待处理的申请,申请已被处理时返回 false
+	Review(ctx context.Context, requestID int64, status string, reviewerID int64, reviewedAt int64) (bool, error)
+
+	// CancelPendingByInvitation 取消邀请下全部待审批的申请(邀请撤销时)
+	CancelPendingByInvitation(ctx context.Context, invitationID int64) error
+}
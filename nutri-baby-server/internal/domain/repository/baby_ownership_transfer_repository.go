@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// BabyOwnershipTransferRepository 所有权转让仓储接口
+type BabyOwnershipTransferRepository interface {
+	// Create 创建转让提名
+	Create(ctx context.Context, transfer *entity.BabyOwnershipTransfer) error
+
+	// FindPendingByBabyID 查找宝宝待处理的转让,不存在时返回 nil
+	FindPendingByBabyID(ctx context.Context, babyID int64) (*entity.BabyOwnershipTransfer, error)
+
+	// FindPendingByToUser 获取提名给用户、待处理的转让
+	FindPendingByToUser(ctx context.Context, userID int64) ([]*entity.BabyOwnershipTransfer, error)
+
+	// Respond 处理待处理的转让,已被处理时返回 false
+	Respond(ctx context.Context, transferID int64, status string, respondedAt int64) (bool, error)
+
+	// Accept 在同一事务中接受转让、将被提名人设为管理员并变更宝宝所有者
+	// 转让已被处理、所有者已变更或被提名人已不在亲友团时不做任何修改并返回 false
+	Accept(ctx context.Context, transfer *entity.BabyOwnershipTransfer, respondedAt int64) (bool, error)
+
+	// CancelPendingByBaby 取消宝宝待处理的转让
+	CancelPendingByBaby(ctx context.Context, babyID int64) error
+
+	// CancelPendingByBabyAndUser 取消宝宝中涉及该用户(提名人或被提名人)的待处理转让
+	CancelPendingByBabyAndUser(ctx context.Context, babyID, userID int64) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// BabyTargetRepository 宝宝每日目标覆盖仓储接口
+type BabyTargetRepository interface {
+	// FindByBabyID 获取宝宝的目标覆盖配置,不存在时返回 nil
+	FindByBabyID(ctx context.Context, babyID int64) (*entity.BabyTarget, error)
+
+	// Save 保存目标覆盖配置(不存在则创建,存在则更新)
+	Save(ctx context.Context, target *entity.BabyTarget) error
+
+	// DeleteByBabyID 删除宝宝的目标覆盖配置(恢复系统推荐值)
+	DeleteByBabyID(ctx context.Context, babyID int64) error
+}
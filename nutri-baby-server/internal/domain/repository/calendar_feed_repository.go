@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// CalendarFeedRepository 日历订阅仓储接口
+type CalendarFeedRepository interface {
+	// FindByUserID 查找用户的日历订阅
+	FindByUserID(ctx context.Context, userID int64) (*entity.CalendarFeed, error)
+	// FindByToken 根据订阅密钥查找
+	FindByToken(ctx context.Context, token string) (*entity.CalendarFeed, error)
+	// Create 创建日历订阅
+	Create(ctx context.Context, feed *entity.CalendarFeed) error
+	// UpdateToken 替换订阅密钥
+	UpdateToken(ctx context.Context, feedID int64, token string) error
+	// TouchAccess 更新最近访问时间
+	TouchAccess(ctx context.Context, feedID int64, accessedAt int64) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// CaregiverShiftRepository 照护人值班仓储接口
+type CaregiverShiftRepository interface {
+	// Create 创建值班
+	Create(ctx context.Context, shift *entity.CaregiverShift) error
+
+	// FindOnDuty 查找宝宝值班中的照护人(未结束且开始时间不早于 startedAfter),不存在时返回 nil
+	FindOnDuty(ctx context.Context, babyID int64, startedAfter int64) (*entity.CaregiverShift, error)
+
+	// FindLatestByOtherUser 查找除指定用户外最近开始的一次值班(上一位照护人),不存在时返回 nil
+	FindLatestByOtherUser(ctx context.Context, babyID, userID int64) (*entity.CaregiverShift, error)
+
+	// FindRecentByBaby 获取宝宝最近的值班(按开始时间倒序)
+	FindRecentByBaby(ctx context.Context, babyID int64, limit int) ([]*entity.CaregiverShift, error)
+
+	// End 结束值班中的班次,班次已结束时返回 false
+	End(ctx context.Context, shiftID int64, endTime int64, handoffNote string) (bool, error)
+
+	// EndOpenByBaby 结束宝宝全部未结束的班次(开始新值班时交接,包括忘记交班的旧班次)
+	EndOpenByBaby(ctx context.Context, babyID int64, endTime int64) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// ExportJobRepository 数据导出任务仓储接口
+type ExportJobRepository interface {
+	// Create 创建导出任务
+	Create(ctx context.Context, job *entity.ExportJob) error
+
+	// FindByID 根据ID查找导出任务
+	FindByID(ctx context.Context, jobID int64) (*entity.ExportJob, error)
+
+	// FindByBabyAndUser 查找用户为某个宝宝发起的导出任务(按创建时间倒序)
+	FindByBabyAndUser(ctx context.Context, babyID, userID int64, limit int) ([]*entity.ExportJob, error)
+
+	// FindExpired 查找下载链接已过期但尚未清理的已完成任务
+	FindExpired(ctx context.Context, now int64) ([]*entity.ExportJob, error)
+
+	// FindPendingBefore 查找创建时间早于 before 仍在排队的任务(按创建时间正序)
+	FindPendingBefore(ctx context.Context, before int64, limit int) ([]*entity.ExportJob, error)
+
+	// Claim 将排队中的任务标记为生成中,任务已被其他 worker 领取或不再排队时返回 false
+	Claim(ctx context.Context, jobID int64) (bool, error)
+
+	// FailStaleProcessing 将更新时间早于 before 仍在生成中的任务标记为失败,返回处理的任务数
+	FailStaleProcessing(ctx context.Context, before int64, message string) (int64, error)
+
+	// Update 更新导出任务
+	Update(ctx context.Context, job *entity.ExportJob) error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// MilestoneTemplateRepository 发育里程碑模板仓储接口
+type MilestoneTemplateRepository interface {
+	// FindAll 查找所有模板
+	FindAll(ctx context.Context) ([]*entity.MilestoneTemplate, error)
+	// BatchCreate 批量创建模板
+	BatchCreate(ctx context.Context, templates []*entity.MilestoneTemplate) error
+}
+
+// BabyMilestoneRepository 宝宝发育里程碑仓储接口
+type BabyMilestoneRepository interface {
+	// Create 创建里程碑记录(自定义里程碑)
+	Create(ctx context.Context, milestone *entity.BabyMilestone) error
+
+	// FindByID 根据ID查找里程碑
+	FindByID(ctx context.Context, milestoneID int64) (*entity.BabyMilestone, error)
+
+	// FindByBabyID 查找宝宝的里程碑(可按状态过滤,status为空表示全部)
+	FindByBabyID(ctx context.Context, babyID int64, status string) ([]*entity.BabyMilestone, error)
+
+	// FindAchievedByBabyID 查找时间范围内已达成的里程碑(用于时间线)
+	FindAchievedByBabyID(ctx context.Context, babyID int64, startTime, endTime int64) ([]*entity.BabyMilestone, error)
+
+	// Update 更新里程碑
+	Update(ctx context.Context, milestone *entity.BabyMilestone) error
+
+	// Delete 删除里程碑(软删除)
+	Delete(ctx context.Context, milestoneID int64) error
+
+	// CountByBabyID 统计宝宝的里程碑数量
+	CountByBabyID(ctx context.Context, babyID int64) (int64, error)
+
+	// InitializeFromTemplates 从模板为宝宝初始化里程碑
+	InitializeFromTemplates(ctx context.Context, babyID, createdBy int64) error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// RecordCommentRepository 记录评论与表情回应仓储接口
+type RecordCommentRepository interface {
+	// Create 创建评论
+	Create(ctx context.Context, comment *entity.RecordComment) error
+
+	// FindByID 根据ID查找评论
+	FindByID(ctx context.Context, commentID int64) (*entity.RecordComment, error)
+
+	// FindByRecord 查找某条记录的所有评论(按创建时间正序)
+	FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.RecordComment, error)
+
+	// FindByRecords 批量查找同一类型多条记录的评论(按创建时间正序)
+	FindByRecords(ctx context.Context, recordType string, recordIDs []int64) ([]*entity.RecordComment, error)
+
+	// Delete 删除评论及其回复(软删除)
+	Delete(ctx context.Context, commentID int64) error
+
+	// AddReaction 添加表情回应,已存在时返回 false
+	AddReaction(ctx context.Context, reaction *entity.RecordReaction) (bool, error)
+
+	// RemoveReaction 取消表情回应,不存在时返回 false
+	RemoveReaction(ctx context.Context, recordType string, recordID, userID int64, emoji string) (bool, error)
+
+	// FindReactionsByRecords 批量查找同一类型多条记录的表情回应(按创建时间正序)
+	FindReactionsByRecords(ctx context.Context, recordType string, recordIDs []int64) ([]*entity.RecordReaction, error)
+
+	// DeleteByRecord 删除某条记录的所有评论和表情回应(软删除)
+	DeleteByRecord(ctx context.Context, recordType string, recordID int64) error
+}
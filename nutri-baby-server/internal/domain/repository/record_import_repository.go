@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// RecordBatch 一次导入的记录集合
+type RecordBatch struct {
+	Feedings []*entity.FeedingRecord
+	Sleeps   []*entity.SleepRecord
+	Diapers  []*entity.DiaperRecord
+	Growths  []*entity.GrowthRecord
+}
+
+// Len 记录总数
+func (b *RecordBatch) Len() int {
+	return len(b.Feedings) + len(b.Sleeps) + len(b.Diapers) + len(b.Growths)
+}
+
+// RecordImportRepository 记录批量导入仓储接口
+type RecordImportRepository interface {
+	// ImportRecords 在同一事务中写入全部记录,任一失败则整体回滚
+	ImportRecords(ctx context.Context, batch *RecordBatch) error
+}
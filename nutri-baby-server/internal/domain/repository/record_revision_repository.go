@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// RecordRevisionRepository 记录修订历史仓储接口(只追加)
+type RecordRevisionRepository interface {
+	// Create 追加一条修订
+	Create(ctx context.Context, revision *entity.RecordRevision) error
+
+	// BatchCreate 批量追加修订(批量导入、补种重排等一次影响多条记录的操作)
+	BatchCreate(ctx context.Context, revisions []*entity.RecordRevision) error
+
+	// FindByID 根据ID查找修订
+	FindByID(ctx context.Context, revisionID int64) (*entity.RecordRevision, error)
+
+	// FindByRecord 查找某条记录的修订历史(按时间倒序)
+	FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.RecordRevision, error)
+
+	// FindByBabyAndAction 查找宝宝在 since 之后某种操作的修订(按时间倒序),如回收站查询删除人
+	FindByBabyAndAction(ctx context.Context, babyID int64, action string, since int64) ([]*entity.RecordRevision, error)
+
+	// RestoreRecord 用历史版本整体覆盖未删除的记录(包括零值字段,ID、宝宝、创建者和创建时间不变)并重新读取
+	// expectedUpdatedAt 大于0时只在记录未被他人修改时覆盖;记录已删除或已被修改时返回 false
+	RestoreRecord(ctx context.Context, record any, expectedUpdatedAt int64) (bool, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+)
+
+// RecordTrashRepository 回收站仓储接口: 查询、恢复和彻底删除已软删除的记录
+// record、records、model 为喂养、睡眠、排泄、成长、里程碑或疫苗日程的实体指针(查询列表时为实体切片指针)
+type RecordTrashRepository interface {
+	// FindDeleted 查询宝宝在 deletedSince 之后删除的记录(按删除时间倒序)
+	FindDeleted(ctx context.Context, records any, babyID, deletedSince int64) error
+
+	// FindDeletedByID 查找已删除的记录,记录不存在或未删除时返回 ErrRecordNotFound
+	FindDeletedByID(ctx context.Context, record any, recordID int64) error
+
+	// Restore 撤销记录的软删除,同时恢复随记录一同删除(删除时间不早于记录)的附件、评论和表情回应
+	// 记录已被恢复或已彻底删除时返回 false
+	Restore(ctx context.Context, record any, recordType string, recordID, deletedAt int64) (bool, error)
+
+	// FindPurgeable 查找 deletedBefore 之前删除的记录ID
+	FindPurgeable(ctx context.Context, model any, deletedBefore int64, limit int) ([]int64, error)
+
+	// Purge 彻底删除记录及其附件、评论、表情回应(疫苗日程还包括接种反应随访),修订历史作为审计记录保留
+	// 返回被删除附件的文件路径,由调用方清理文件
+	Purge(ctx context.Context, model any, recordType string, recordIDs []int64) ([]string, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+)
+
+// ShareLinkRepository 只读分享链接仓储接口
+type ShareLinkRepository interface {
+	// Create 创建分享链接
+	Create(ctx context.Context, link *entity.ShareLink) error
+
+	// FindByID 根据ID查找分享链接
+	FindByID(ctx context.Context, linkID int64) (*entity.ShareLink, error)
+
+	// FindByBabyID 查找宝宝的分享链接(按创建时间倒序)
+	FindByBabyID(ctx context.Context, babyID int64, limit int) ([]*entity.ShareLink, error)
+
+	// Revoke 撤销分享链接,只写入撤销时间,已撤销的链接保持原撤销时间
+	Revoke(ctx context.Context, linkID int64, revokedAt int64) error
+
+	// RecordAccess 写入访问日志,访问成功时同时累加访问次数
+	RecordAccess(ctx context.Context, log *entity.ShareAccessLog) error
+
+	// FindAccessLogs 分页查询分享链接的访问日志(按访问时间倒序)
+	FindAccessLogs(ctx context.Context, linkID int64, page, pageSize int) ([]*entity.ShareAccessLog, int64, error)
+}
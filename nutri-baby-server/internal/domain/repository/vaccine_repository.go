@@ -6,10 +6,28 @@ import (
 	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
 )
 
-// VaccinePlanTemplateRepository 疫苗计划模板仓储接口(国家免疫规划标准)
+// VaccinePlanRepository 疫苗计划仓储接口
+type VaccinePlanRepository interface {
+	// FindByID 根据ID查找计划
+	FindByID(ctx context.Context, planID int64) (*entity.VaccinePlan, error)
+	// FindDefault 查找默认计划
+	FindDefault(ctx context.Context) (*entity.VaccinePlan, error)
+	// FindByCode 查找指定编码的计划,version 为 0 时返回最新版本
+	FindByCode(ctx context.Context, code string, version int) (*entity.VaccinePlan, error)
+	// FindLatest 查找每个编码的最新版本,region 为空时不按地区过滤
+	FindLatest(ctx context.Context, region string) ([]*entity.VaccinePlan, error)
+	// MaxVersion 查询编码的最大版本号,不存在时返回 0
+	MaxVersion(ctx context.Context, code string) (int, error)
+	// CreateWithTemplates 在事务中创建计划及其模板;计划为默认时取消其他计划的默认标记
+	CreateWithTemplates(ctx context.Context, plan *entity.VaccinePlan, templates []*entity.VaccinePlanTemplate) error
+}
+
+// VaccinePlanTemplateRepository 疫苗计划模板仓储接口
 type VaccinePlanTemplateRepository interface {
 	// FindAll 查找所有模板
 	FindAll(ctx context.Context) ([]*entity.VaccinePlanTemplate, error)
+	// FindByPlanID 查找计划的所有模板,按排序、月龄、剂次排列
+	FindByPlanID(ctx context.Context, planID int64) ([]*entity.VaccinePlanTemplate, error)
 	// FindByID 根据ID查找模板
 	FindByID(ctx context.Context, templateID int64) (*entity.VaccinePlanTemplate, error)
 	// Create 创建模板(系统初始化)
@@ -42,8 +60,21 @@ type BabyVaccineScheduleRepository interface {
 	BatchCreate(ctx context.Context, schedules []*entity.BabyVaccineSchedule) error
 
 	// InitializeFromTemplates 从模板为宝宝初始化疫苗接种日程
+	// 使用宝宝选择的疫苗计划,未选择时使用默认计划并记录到宝宝上
 	InitializeFromTemplates(ctx context.Context, babyID int64, createdBy int64) error
 
+	// ApplyPlan 在事务中应用计划调整: 新增、更新、删除日程,并记录宝宝使用的计划
+	ApplyPlan(ctx context.Context, babyID, planID int64, creates, updates []*entity.BabyVaccineSchedule, deleteIDs []int64) error
+
+	// ImportCompleted 在事务中导入历史接种: 更新匹配日程的接种信息、创建已接种的自定义日程,并保存补种重排后的计划日期
+	ImportCompleted(ctx context.Context, completes, creates, rescheduled []*entity.BabyVaccineSchedule) error
+
+	// UpdateScheduledDates 批量更新计划接种日期及提醒状态(补种重排)
+	UpdateScheduledDates(ctx context.Context, schedules []*entity.BabyVaccineSchedule) error
+
+	// UpdateReactionSummary 更新接种后反应汇总
+	UpdateReactionSummary(ctx context.Context, scheduleID int64, severity, summary string) error
+
 	// CountByBabyID 统计宝宝的疫苗接种日程总数
 	CountByBabyID(ctx context.Context, babyID int64) (int64, error)
 
@@ -60,3 +91,21 @@ type BabyVaccineScheduleRepository interface {
 	// GetStatistics 获取宝宝疫苗接种统计
 	GetStatistics(ctx context.Context, babyID int64) (total, completed, pending, skipped int64, err error)
 }
+
+// VaccineReactionFollowUpRepository 接种后反应随访仓储接口
+type VaccineReactionFollowUpRepository interface {
+	// BatchCreate 批量创建随访
+	BatchCreate(ctx context.Context, followUps []*entity.VaccineReactionFollowUp) error
+	// FindByID 根据ID查找随访
+	FindByID(ctx context.Context, followUpID int64) (*entity.VaccineReactionFollowUp, error)
+	// FindByScheduleID 查找日程的全部随访,按时间点排序
+	FindByScheduleID(ctx context.Context, scheduleID int64) ([]*entity.VaccineReactionFollowUp, error)
+	// FindDuePrompts 查找随访时间在 [from, to] 内、尚未提醒且未记录的随访
+	FindDuePrompts(ctx context.Context, from, to int64) ([]*entity.VaccineReactionFollowUp, error)
+	// FindUnrecordedByBabyID 查找宝宝随访时间不晚于 dueBefore 且尚未记录的随访
+	FindUnrecordedByBabyID(ctx context.Context, babyID, dueBefore int64) ([]*entity.VaccineReactionFollowUp, error)
+	// MarkPromptSent 标记已发送提醒
+	MarkPromptSent(ctx context.Context, followUpID, sentAt int64) error
+	// Update 保存随访记录
+	Update(ctx context.Context, followUp *entity.VaccineReactionFollowUp) error
+}
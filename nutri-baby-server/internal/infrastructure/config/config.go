@@ -14,8 +14,11 @@ type Config struct {
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Log      LogConfig      `mapstructure:"log"`
 	Upload   UploadConfig   `mapstructure:"upload"`
+	Storage  StorageConfig  `mapstructure:"storage"`
 	Wechat   WechatConfig   `mapstructure:"wechat"`
-	AI       AIConfig       `mapstructure:"ai"` // AI配置
+	Trash    TrashConfig    `mapstructure:"trash"`  // 回收站配置
+	Report   ReportConfig   `mapstructure:"report"` // 就诊报告配置
+	AI       AIConfig       `mapstructure:"ai"`     // AI配置
 }
 
 // ServerConfig 服务器配置
@@ -87,9 +90,31 @@ type LogConfig struct {
 
 // UploadConfig 上传配置
 type UploadConfig struct {
-	MaxSize      int64    `mapstructure:"max_size"`
-	AllowedTypes []string `mapstructure:"allowed_types"`
-	StoragePath  string   `mapstructure:"storage_path"`
+	MaxSize          int64    `mapstructure:"max_size"`
+	AllowedTypes     []string `mapstructure:"allowed_types"`
+	StoragePath      string   `mapstructure:"storage_path"`
+	DisplayMaxSize   int      `mapstructure:"display_max_size"`   // 展示图最长边(px)
+	ThumbnailMaxSize int      `mapstructure:"thumbnail_max_size"` // 缩略图最长边(px)
+	JPEGQuality      int      `mapstructure:"jpeg_quality"`       // JPEG 编码质量(1-100)
+}
+
+// StorageConfig 文件存储配置
+type StorageConfig struct {
+	Driver          string   `mapstructure:"driver"`            // 存储驱动: local(默认,使用 upload.storage_path) 或 s3
+	SignedURLExpire int      `mapstructure:"signed_url_expire"` // 私密文件签名URL有效期(秒)
+	SigningSecret   string   `mapstructure:"signing_secret"`    // 本地存储签名密钥,为空时使用 jwt.secret
+	S3              S3Config `mapstructure:"s3"`
+}
+
+// S3Config S3 兼容对象存储配置(AWS S3、MinIO 等)
+type S3Config struct {
+	Endpoint        string `mapstructure:"endpoint"`          // 如 http://minio:9000
+	Region          string `mapstructure:"region"`            // 默认 us-east-1
+	Bucket          string `mapstructure:"bucket"`            // 存储桶
+	AccessKeyID     string `mapstructure:"access_key_id"`     // 访问密钥ID
+	SecretAccessKey string `mapstructure:"secret_access_key"` // 访问密钥
+	ForcePathStyle  bool   `mapstructure:"force_path_style"`  // 使用路径风格地址(MinIO 需开启)
+	PublicBaseURL   string `mapstructure:"public_base_url"`   // 公开文件访问地址(CDN),为空时直接使用对象存储地址
 }
 
 // WechatConfig 微信配置
@@ -99,6 +124,16 @@ type WechatConfig struct {
 	SubscribeTemplates map[string]string `mapstructure:"subscribe_templates"` // 订阅消息模板映射: templateType -> templateID
 }
 
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // 已删除记录的保留天数,到期后彻底删除
+}
+
+// ReportConfig 就诊报告配置
+type ReportConfig struct {
+	FontPath string `mapstructure:"font_path"` // PDF 中文字体文件路径(TrueType,如 Noto Sans SC 的 TTF 子集),未配置时无法生成 PDF 报告
+}
+
 // AIConfig AI配置
 type AIConfig struct {
 	Provider string         `mapstructure:"provider"`
@@ -223,15 +258,25 @@ func GetDefaultConfig() *Config {
 			Compress:   true,
 		},
 		Upload: UploadConfig{
-			MaxSize:      10 * 1024 * 1024, // 10MB
-			AllowedTypes: []string{"image/jpeg", "image/png", "image/gif"},
-			StoragePath:  "uploads/",
+			MaxSize:          10 * 1024 * 1024, // 10MB
+			AllowedTypes:     []string{"image/jpeg", "image/png", "image/gif"},
+			StoragePath:      "uploads/",
+			DisplayMaxSize:   1600,
+			ThumbnailMaxSize: 320,
+			JPEGQuality:      85,
+		},
+		Storage: StorageConfig{
+			Driver:          "local",
+			SignedURLExpire: 3600,
 		},
 		Wechat: WechatConfig{
 			AppID:              "",
 			AppSecret:          "",
 			SubscribeTemplates: map[string]string{},
 		},
+		Trash: TrashConfig{
+			RetentionDays: 30,
+		},
 		AI: GetDefaultAIConfig(),
 	}
 }
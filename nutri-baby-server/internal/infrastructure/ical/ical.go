@@ -0,0 +1,195 @@
+// Package ical 最小化的 iCalendar(RFC 5545)写入器
+//
+// 仅支持 VEVENT(全天或定时)及 DISPLAY 类型的 VALARM,满足日历订阅需要,不依赖第三方库。
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 内容行最大长度(字节),超出部分需折行
+const maxLineOctets = 75
+
+// Calendar 日历
+type Calendar struct {
+	ProdID string   // 产品标识,如 -//nutri-baby//vaccine//CN
+	Name   string   // 日历名称(X-WR-CALNAME)
+	TZID   string   // 日历默认时区(X-WR-TIMEZONE),可为空
+	Events []*Event // 事件列表
+}
+
+// Event 日历事件
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	AllDay       bool          // 全天事件只输出日期
+	Duration     time.Duration // 定时事件的时长,全天事件忽略
+	Status       string        // TENTATIVE、CONFIRMED、CANCELLED,可为空
+	Categories   []string
+	LastModified time.Time
+	Alarms       []Alarm
+}
+
+// Alarm 事件提醒
+type Alarm struct {
+	// Trigger 相对事件开始的提醒时间,负数表示提前
+	Trigger     time.Duration
+	Description string
+}
+
+// Marshal 输出 iCalendar 文本(CRLF 换行,长行按 75 字节折行)
+// stamp 为 DTSTAMP,各事件的 LastModified 为零值时也使用 stamp
+func (c *Calendar) Marshal(stamp time.Time) []byte {
+	w := &writer{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.TZID != "" {
+		w.line("X-WR-TIMEZONE", c.TZID)
+	}
+
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", e.UID)
+		w.line("DTSTAMP", formatUTC(stamp))
+		if e.AllDay {
+			w.line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			w.line("DTEND;VALUE=DATE", e.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			w.line("DTSTART", formatUTC(e.Start))
+			duration := e.Duration
+			if duration <= 0 {
+				duration = time.Hour
+			}
+			w.line("DTEND", formatUTC(e.Start.Add(duration)))
+		}
+		w.line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION", escapeText(e.Location))
+		}
+		if e.Status != "" {
+			w.line("STATUS", e.Status)
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, category := range e.Categories {
+				categories[i] = escapeText(category)
+			}
+			w.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		modified := e.LastModified
+		if modified.IsZero() {
+			modified = stamp
+		}
+		w.line("LAST-MODIFIED", formatUTC(modified))
+		for _, a := range e.Alarms {
+			w.line("BEGIN", "VALARM")
+			w.line("ACTION", "DISPLAY")
+			w.line("TRIGGER", FormatDuration(a.Trigger))
+			description := a.Description
+			if description == "" {
+				description = e.Summary
+			}
+			w.line("DESCRIPTION", escapeText(description))
+			w.line("END", "VALARM")
+		}
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return []byte(w.b.String())
+}
+
+// FormatDuration 按 RFC 5545 格式化时长,如 -P1DT15H、PT9H、PT0S
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 {
+		b.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+	return b.String()
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText 转义 TEXT 类型的值
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+type writer struct {
+	b strings.Builder
+}
+
+// line 写入一个内容行,超过 75 字节时折行,不在 UTF-8 字符中间断开
+func (w *writer) line(name, value string) {
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		w.b.WriteString(content[:cut])
+		w.b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1 // 续行以空格开头
+	}
+	w.b.WriteString(content)
+	w.b.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
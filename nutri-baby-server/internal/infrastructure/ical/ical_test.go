@@ -0,0 +1,72 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarMarshal(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	stamp := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		ProdID: "-//nutri-baby//test//CN",
+		Name:   "宝宝疫苗",
+		Events: []*Event{
+			{
+				UID:         "vaccine-1@nutri-baby",
+				Summary:     "小明 乙肝疫苗, 第2剂",
+				Description: "第一行\n第二行; 含分号",
+				Start:       time.Date(2024, 3, 15, 0, 0, 0, 0, loc),
+				AllDay:      true,
+				Alarms:      []Alarm{{Trigger: -(6*24*time.Hour + 15*time.Hour)}},
+			},
+			{
+				UID:      "timed-1@nutri-baby",
+				Summary:  strings.Repeat("长", 40),
+				Start:    time.Date(2024, 3, 16, 9, 30, 0, 0, loc),
+				Duration: 30 * time.Minute,
+				Status:   "CONFIRMED",
+			},
+		},
+	}
+
+	out := string(cal.Marshal(stamp))
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n")
+
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240315\r\n")
+	assert.Contains(t, out, "DTEND;VALUE=DATE:20240316\r\n")
+	assert.Contains(t, out, "SUMMARY:小明 乙肝疫苗\\, 第2剂\r\n")
+	assert.Contains(t, out, "DESCRIPTION:第一行\\n第二行\\; 含分号\r\n")
+	assert.Contains(t, out, "BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER:-P6DT15H\r\n")
+	assert.Contains(t, out, "DTSTART:20240316T013000Z\r\nDTEND:20240316T020000Z\r\n")
+	assert.Contains(t, out, "DTSTAMP:20240301T080000Z\r\n")
+
+	// 长行折行: 每行不超过 75 字节,续行以空格开头,且不截断多字节字符
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+		assert.True(t, utf8.ValidString(line), line)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("长", 40)+"\r\n")
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                                "PT0S",
+		9 * time.Hour:                    "PT9H",
+		-15 * time.Hour:                  "-PT15H",
+		-(24 * time.Hour):                "-P1D",
+		-(6*24*time.Hour + 15*time.Hour): "-P6DT15H",
+		90*time.Minute + 5*time.Second:   "PT1H30M5S",
+		2*24*time.Hour + 30*time.Minute:  "P2DT30M",
+	}
+	for d, want := range cases {
+		assert.Equal(t, want, FormatDuration(d), d.String())
+	}
+}
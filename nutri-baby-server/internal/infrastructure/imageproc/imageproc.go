@@ -0,0 +1,220 @@
+// Package imageproc 上传图片处理流水线
+//
+// 负责魔数识别图片类型、解码前按文件头检查尺寸、解码 JPEG/PNG/GIF(变体取首帧)、
+// 按 EXIF Orientation 自动旋转、重新编码以去除 EXIF 等元数据,并生成展示图和缩略图两个尺寸的变体。
+// 仅依赖标准库,避免引入 cgo 图像库。
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// 支持的图片 MIME 类型
+const (
+	MimeJPEG = "image/jpeg"
+	MimePNG  = "image/png"
+	MimeGIF  = "image/gif"
+)
+
+// 变体名称
+const (
+	VariantOriginal  = "original"  // 原图(已去除元数据并校正方向)
+	VariantDisplay   = "display"   // 展示图
+	VariantThumbnail = "thumbnail" // 缩略图
+)
+
+// MaxPixels 允许处理的最大像素数(宽×高)
+// 很小的压缩文件也可能声明极大的尺寸,解码前按文件头拒绝,避免解码占满内存
+const MaxPixels = 40_000_000
+
+var (
+	// ErrUnsupportedFormat 不支持的图片格式
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrImageTooLarge 图片尺寸超过 MaxPixels
+	ErrImageTooLarge = errors.New("image dimensions too large")
+)
+
+// Options 处理参数
+type Options struct {
+	DisplayMaxSize   int // 展示图最长边(px)
+	ThumbnailMaxSize int // 缩略图最长边(px)
+	JPEGQuality      int // JPEG 编码质量(1-100)
+}
+
+// DefaultOptions 默认处理参数
+func DefaultOptions() Options {
+	return Options{
+		DisplayMaxSize:   1600,
+		ThumbnailMaxSize: 320,
+		JPEGQuality:      85,
+	}
+}
+
+// Variant 处理后的单个图片变体
+type Variant struct {
+	Name     string // original, display, thumbnail
+	MimeType string // 编码后的 MIME 类型
+	Ext      string // 文件扩展名(含点)
+	Width    int
+	Height   int
+	Data     []byte
+}
+
+// Result 处理结果
+type Result struct {
+	MimeType string     // 原图识别出的 MIME 类型
+	Variants []*Variant // 按 original, display, thumbnail 顺序
+}
+
+// DetectMimeType 根据文件头魔数识别图片类型,无法识别时返回空字符串
+func DetectMimeType(data []byte) string {
+	switch {
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return MimeJPEG
+	case len(data) >= 8 && bytes.Equal(data[:8], []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}):
+		return MimePNG
+	case len(data) >= 6 && (bytes.Equal(data[:6], []byte("GIF87a")) || bytes.Equal(data[:6], []byte("GIF89a"))):
+		return MimeGIF
+	default:
+		return ""
+	}
+}
+
+// ExtensionFor 返回 MIME 类型对应的文件扩展名
+func ExtensionFor(mimeType string) string {
+	switch mimeType {
+	case MimeJPEG:
+		return ".jpg"
+	case MimePNG:
+		return ".png"
+	case MimeGIF:
+		return ".gif"
+	default:
+		return ""
+	}
+}
+
+// Process 处理上传的图片数据
+func Process(data []byte, opts Options) (*Result, error) {
+	mimeType := DetectMimeType(data)
+	if mimeType == "" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	var (
+		img  image.Image
+		anim *gif.GIF
+	)
+	switch mimeType {
+	case MimeJPEG:
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			img = applyOrientation(img, readJPEGOrientation(data))
+		}
+	case MimePNG:
+		img, err = png.Decode(bytes.NewReader(data))
+	case MimeGIF:
+		anim, err = gif.DecodeAll(bytes.NewReader(data))
+		if err == nil {
+			img = anim.Image[0]
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{MimeType: mimeType}
+
+	// 原图: 重新编码以去除 EXIF/文本块等元数据; GIF 重新编码全部帧,保留动画但丢弃注释和应用扩展
+	original := &Variant{
+		Name:     VariantOriginal,
+		MimeType: mimeType,
+		Ext:      ExtensionFor(mimeType),
+		Width:    img.Bounds().Dx(),
+		Height:   img.Bounds().Dy(),
+	}
+	switch mimeType {
+	case MimeJPEG:
+		original.Data, err = encodeJPEG(img, opts.JPEGQuality)
+	case MimePNG:
+		original.Data, err = encodePNG(img)
+	case MimeGIF:
+		original.Data, err = encodeGIF(anim)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Variants = append(result.Variants, original)
+
+	// 展示图和缩略图统一输出为 JPEG
+	for _, spec := range []struct {
+		name    string
+		maxSize int
+	}{
+		{VariantDisplay, opts.DisplayMaxSize},
+		{VariantThumbnail, opts.ThumbnailMaxSize},
+	} {
+		resized := fit(img, spec.maxSize)
+		encoded, err := encodeJPEG(resized, opts.JPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, &Variant{
+			Name:     spec.name,
+			MimeType: MimeJPEG,
+			Ext:      ".jpg",
+			Width:    resized.Bounds().Dx(),
+			Height:   resized.Bounds().Dy(),
+			Data:     encoded,
+		})
+	}
+
+	return result, nil
+}
+
+// encodeJPEG 编码为 JPEG,透明区域以白色填充
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	canvas := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeGIF 重新编码 GIF,只写出帧、延时、处置方式和循环次数
+func encodeGIF(anim *gif.GIF) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodePNG 编码为 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
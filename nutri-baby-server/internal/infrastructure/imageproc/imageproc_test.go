@@ -0,0 +1,187 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestImage 左上角为红色像素,其余为蓝色
+func newTestImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{B: 255, A: 255})
+		}
+	}
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	return img
+}
+
+// withOrientation 在 JPEG 的 SOI 之后插入带 Orientation 标签的 EXIF 段
+func withOrientation(t *testing.T, jpegData []byte, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(42))
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(8))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(1)) // entry count
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(0x0112))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(3))
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(1))
+	_ = binary.Write(&tiff, binary.BigEndian, orientation)
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(0))
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(0)) // next IFD
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+func TestDetectMimeType(t *testing.T) {
+	var pngBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, newTestImage(2, 2)))
+
+	assert.Equal(t, MimeJPEG, DetectMimeType(encodeTestJPEG(t, newTestImage(2, 2))))
+	assert.Equal(t, MimePNG, DetectMimeType(pngBuf.Bytes()))
+	assert.Equal(t, MimeGIF, DetectMimeType([]byte("GIF89a....")))
+	assert.Equal(t, "", DetectMimeType([]byte("<?php echo 1; ?>")))
+	assert.Equal(t, "", DetectMimeType(nil))
+}
+
+func TestReadJPEGOrientation(t *testing.T) {
+	data := encodeTestJPEG(t, newTestImage(4, 2))
+	assert.Equal(t, 1, readJPEGOrientation(data))
+
+	for _, orientation := range []uint16{3, 6, 8} {
+		assert.Equal(t, int(orientation), readJPEGOrientation(withOrientation(t, data, orientation)))
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	src := newTestImage(4, 2)
+
+	tests := []struct {
+		orientation int
+		wantW       int
+		wantH       int
+		redX, redY  int // 原左上角红色像素变换后的位置
+	}{
+		{1, 4, 2, 0, 0},
+		{2, 4, 2, 3, 0},
+		{3, 4, 2, 3, 1},
+		{4, 4, 2, 0, 1},
+		{5, 2, 4, 0, 0},
+		{6, 2, 4, 1, 0},
+		{7, 2, 4, 1, 3},
+		{8, 2, 4, 0, 3},
+	}
+
+	for _, tt := range tests {
+		out := applyOrientation(src, tt.orientation)
+		assert.Equal(t, tt.wantW, out.Bounds().Dx(), "orientation %d width", tt.orientation)
+		assert.Equal(t, tt.wantH, out.Bounds().Dy(), "orientation %d height", tt.orientation)
+		r, _, _, _ := out.At(tt.redX, tt.redY).RGBA()
+		assert.Equal(t, uint32(0xFFFF), r, "orientation %d red pixel", tt.orientation)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	data := withOrientation(t, encodeTestJPEG(t, newTestImage(800, 400)), 6)
+
+	result, err := Process(data, Options{DisplayMaxSize: 200, ThumbnailMaxSize: 50, JPEGQuality: 80})
+	require.NoError(t, err)
+	require.Len(t, result.Variants, 3)
+	assert.Equal(t, MimeJPEG, result.MimeType)
+
+	original, display, thumbnail := result.Variants[0], result.Variants[1], result.Variants[2]
+
+	// 旋转90°后宽高互换
+	assert.Equal(t, VariantOriginal, original.Name)
+	assert.Equal(t, 400, original.Width)
+	assert.Equal(t, 800, original.Height)
+	// 重新编码后不再包含 EXIF
+	assert.False(t, bytes.Contains(original.Data, []byte("Exif\x00\x00")))
+	assert.Equal(t, 1, readJPEGOrientation(original.Data))
+
+	assert.Equal(t, VariantDisplay, display.Name)
+	assert.Equal(t, 100, display.Width)
+	assert.Equal(t, 200, display.Height)
+
+	assert.Equal(t, VariantThumbnail, thumbnail.Name)
+	assert.Equal(t, 25, thumbnail.Width)
+	assert.Equal(t, 50, thumbnail.Height)
+}
+
+func TestProcessRejectsUnknownFormat(t *testing.T) {
+	_, err := Process([]byte("not an image"), DefaultOptions())
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestProcessRejectsOversizedImage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, newTestImage(2, 2)))
+	data := buf.Bytes()
+
+	// 改写 IHDR 中的宽高为 100000×100000 并重新计算 CRC,文件本身仍然很小
+	ihdr := data[12:29]
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	binary.BigEndian.PutUint32(ihdr[8:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(ihdr))
+
+	_, err := Process(data, DefaultOptions())
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
+
+func TestProcessStripsGIFExtensions(t *testing.T) {
+	frames := []*image.Paletted{
+		image.NewPaletted(image.Rect(0, 0, 4, 4), palette.Plan9[:16]),
+		image.NewPaletted(image.Rect(0, 0, 4, 4), palette.Plan9[:16]),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, &gif.GIF{Image: frames, Delay: []int{10, 10}}))
+	data := buf.Bytes()
+
+	// 在首帧前(文件头和全局颜色表之后)插入注释扩展
+	comment := append([]byte{0x21, 0xFE, 6}, []byte("secret")...)
+	comment = append(comment, 0)
+	pos := 13
+	if data[10]&0x80 != 0 {
+		pos += 3 << (int(data[10]&0x07) + 1)
+	}
+	data = append(data[:pos:pos], append(comment, data[pos:]...)...)
+
+	result, err := Process(data, DefaultOptions())
+	require.NoError(t, err)
+
+	original := result.Variants[0]
+	assert.Equal(t, MimeGIF, original.MimeType)
+	assert.False(t, bytes.Contains(original.Data, []byte("secret")))
+
+	decoded, err := gif.DecodeAll(bytes.NewReader(original.Data))
+	require.NoError(t, err)
+	assert.Len(t, decoded.Image, 2)
+}
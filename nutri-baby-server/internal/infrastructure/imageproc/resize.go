@@ -0,0 +1,61 @@
+package imageproc
+
+import (
+	"image"
+)
+
+// fit 等比缩放到最长边不超过 maxSize,图片本身更小时不放大
+func fit(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxSize <= 0 || (w <= maxSize && h <= maxSize) {
+		return img
+	}
+
+	dstW, dstH := maxSize, maxSize
+	if w >= h {
+		dstH = max(1, h*maxSize/w)
+	} else {
+		dstW = max(1, w*maxSize/h)
+	}
+	return resizeBox(toNRGBA(img), dstW, dstH)
+}
+
+// resizeBox 区域平均(box filter)缩小,适合照片下采样且无需第三方库
+// 颜色按 alpha 预乘后求平均,避免透明像素边缘发黑
+func resizeBox(src *image.NRGBA, dstW, dstH int) *image.NRGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		y0 := y * srcH / dstH
+		y1 := max(y0+1, (y+1)*srcH/dstH)
+		for x := 0; x < dstW; x++ {
+			x0 := x * srcW / dstW
+			x1 := max(x0+1, (x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					pa := uint64(src.Pix[i+3])
+					r += uint64(src.Pix[i]) * pa
+					g += uint64(src.Pix[i+1]) * pa
+					b += uint64(src.Pix[i+2]) * pa
+					a += pa
+					n++
+					i += 4
+				}
+			}
+
+			di := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[di] = uint8(r / a)
+				dst.Pix[di+1] = uint8(g / a)
+				dst.Pix[di+2] = uint8(b / a)
+			}
+			dst.Pix[di+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,241 @@
+// Package pdf 报告用 PDF 生成器
+//
+// 基于 go-pdf/fpdf 封装多页、文本、直线、折线和矩形绘制,满足报告打印需要。
+// 中文字体由部署方提供 TrueType 字体文件(如 Noto Sans SC、思源黑体),程序不内置字体;
+// 输出时只嵌入实际用到的字形,不依赖阅读器或系统安装的字体。
+// 坐标单位为磅(1/72 英寸),原点在页面左上角,y 轴向下。
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// A4 页面尺寸(磅)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// 字体度量估算: 中文字符为全角,ASCII 按半角估算
+const (
+	fullWidth = 1.0
+	halfWidth = 0.5
+)
+
+const fontFamily = "cjk"
+
+// ErrNoFont 未设置字体
+var ErrNoFont = errors.New("pdf font not set")
+
+// LoadFont 读取 TrueType 字体文件
+// 仅支持 TrueType 轮廓(.ttf);CFF 轮廓的 .otf(如思源黑体 OTF 版)需使用对应的 TTF 版本
+func LoadFont(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 || !(bytes.Equal(data[:4], []byte{0, 1, 0, 0}) || bytes.Equal(data[:4], []byte("true"))) {
+		return nil, fmt.Errorf("%s is not a TrueType font", path)
+	}
+	return data, nil
+}
+
+// Color RGB 颜色
+type Color struct {
+	R, G, B uint8
+}
+
+// 常用颜色
+var (
+	Black     = Color{0, 0, 0}
+	White     = Color{255, 255, 255}
+	Gray      = Color{128, 128, 128}
+	LightGray = Color{230, 230, 230}
+)
+
+// Point 坐标点
+type Point struct {
+	X, Y float64
+}
+
+// Document PDF 文档
+type Document struct {
+	font    []byte
+	title   string
+	created time.Time
+	pages   []*Page
+}
+
+// New 创建文档
+func New() *Document {
+	return &Document{created: time.Now()}
+}
+
+// SetFont 设置文本使用的 TrueType 字体(LoadFont 的结果),输出前必须设置
+func (d *Document) SetFont(font []byte) {
+	d.font = font
+}
+
+// SetTitle 设置文档标题(显示在阅读器标题栏)
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// AddPage 添加 A4 页面
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// PageCount 页数
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Page 页面,记录绘图指令,输出时按顺序回放
+//
+// 报告排版完成后才会补绘页脚页码,因此页面先记录指令而不是直接写入 fpdf。
+type Page struct {
+	ops []func(f *fpdf.Fpdf)
+}
+
+func (p *Page) draw(op func(f *fpdf.Fpdf)) {
+	p.ops = append(p.ops, op)
+}
+
+// Text 在 (x, y) 处绘制文本,y 为文本基线位置
+func (p *Page) Text(x, y, size float64, color Color, text string) {
+	if text == "" {
+		return
+	}
+	p.draw(func(f *fpdf.Fpdf) {
+		f.SetFont(fontFamily, "", size)
+		f.SetTextColor(int(color.R), int(color.G), int(color.B))
+		f.Text(x, y, text)
+	})
+}
+
+// TextRight 文本右对齐到 x
+func (p *Page) TextRight(x, y, size float64, color Color, text string) {
+	p.Text(x-TextWidth(text, size), y, size, color, text)
+}
+
+// Line 绘制直线
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	p.draw(func(f *fpdf.Fpdf) {
+		setStroke(f, width, color)
+		f.Line(x1, y1, x2, y2)
+	})
+}
+
+// Polyline 绘制折线
+func (p *Page) Polyline(points []Point, width float64, color Color) {
+	if len(points) < 2 {
+		return
+	}
+	points = append([]Point(nil), points...)
+	p.draw(func(f *fpdf.Fpdf) {
+		setStroke(f, width, color)
+		f.SetLineJoinStyle("round")
+		f.MoveTo(points[0].X, points[0].Y)
+		for _, pt := range points[1:] {
+			f.LineTo(pt.X, pt.Y)
+		}
+		f.DrawPath("D")
+		f.SetLineJoinStyle("miter")
+	})
+}
+
+// Rect 绘制矩形,(x, y) 为左上角;fill 为 nil 时不填充,stroke 为 nil 时不描边
+func (p *Page) Rect(x, y, w, h float64, fill, stroke *Color) {
+	if fill == nil && stroke == nil {
+		return
+	}
+	style := ""
+	if fill != nil {
+		style += "F"
+	}
+	if stroke != nil {
+		style += "D"
+	}
+	var fillColor, strokeColor Color
+	if fill != nil {
+		fillColor = *fill
+	}
+	if stroke != nil {
+		strokeColor = *stroke
+	}
+	p.draw(func(f *fpdf.Fpdf) {
+		if fill != nil {
+			f.SetFillColor(int(fillColor.R), int(fillColor.G), int(fillColor.B))
+		}
+		if stroke != nil {
+			setStroke(f, 0.5, strokeColor)
+		}
+		f.Rect(x, y, w, h, style)
+	})
+}
+
+func setStroke(f *fpdf.Fpdf, width float64, color Color) {
+	f.SetLineWidth(width)
+	f.SetDrawColor(int(color.R), int(color.G), int(color.B))
+}
+
+// TextWidth 估算文本宽度
+func TextWidth(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r < 0x80 {
+			width += halfWidth
+		} else {
+			width += fullWidth
+		}
+	}
+	return width * size
+}
+
+// Truncate 截断文本使其宽度不超过 maxWidth,超出部分以省略号代替
+func Truncate(text string, size, maxWidth float64) string {
+	if TextWidth(text, size) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(string(runes)+"…", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// Write 输出 PDF 文件
+func (d *Document) Write(out io.Writer) error {
+	if len(d.font) == 0 {
+		return ErrNoFont
+	}
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	f := fpdf.New("P", "pt", "A4", "")
+	f.SetAutoPageBreak(false, 0)
+	f.SetMargins(0, 0, 0)
+	f.SetTitle(d.title, true)
+	f.SetProducer("nutri-baby", true)
+	f.SetCreationDate(d.created)
+	f.AddUTF8FontFromBytes(fontFamily, "", d.font)
+
+	for _, page := range d.pages {
+		f.AddPage()
+		for _, op := range page.ops {
+			op(f)
+		}
+	}
+	return f.Output(out)
+}
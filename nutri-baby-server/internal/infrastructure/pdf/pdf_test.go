@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/pdf/pdftest"
+)
+
+func TestDocumentWrite(t *testing.T) {
+	doc := New()
+	doc.SetFont(pdftest.Font(t))
+	doc.SetTitle("成长报告")
+	page := doc.AddPage()
+	page.Text(40, 60, 12, Black, "宝宝 A1")
+	page.Line(40, 70, 200, 70, 1, Gray)
+	page.Rect(40, 80, 100, 20, &LightGray, &Black)
+	page.Polyline([]Point{{40, 120}, {80, 110}, {120, 130}}, 1, Black)
+	doc.AddPage()
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	data := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.")))
+	assert.True(t, bytes.HasSuffix(bytes.TrimSpace(data), []byte("%%EOF")))
+	assert.Contains(t, string(data), "/Count 2")
+
+	// 字体以 TrueType 子集嵌入文档
+	assert.Contains(t, string(data), "/FontFile2")
+	assert.Contains(t, string(data), "/CIDFontType2")
+
+	// 第一页内容流可解压,坐标已换算为 PDF 的左下角原点
+	streams := regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`).FindAllSubmatch(data, -1)
+	require.NotEmpty(t, streams)
+	var content []byte
+	for _, s := range streams {
+		zr, err := zlib.NewReader(bytes.NewReader(s[1]))
+		if err != nil {
+			continue
+		}
+		b, err := io.ReadAll(zr)
+		if err == nil && bytes.Contains(b, []byte(" Tj")) {
+			content = b
+			break
+		}
+	}
+	require.NotNil(t, content)
+	assert.Contains(t, string(content), "BT 40.00 781.89 Td")
+	assert.Contains(t, string(content), "40.00 721.89 m")
+	assert.Contains(t, string(content), "120.00 711.89 l")
+}
+
+func TestDocumentWriteRequiresFont(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(40, 60, 12, Black, "宝宝")
+	assert.ErrorIs(t, doc.Write(io.Discard), ErrNoFont)
+}
+
+func TestLoadFont(t *testing.T) {
+	dir := t.TempDir()
+
+	ttf := filepath.Join(dir, "font.ttf")
+	require.NoError(t, os.WriteFile(ttf, pdftest.Font(t), 0o644))
+	data, err := LoadFont(ttf)
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+
+	// CFF 轮廓的 OpenType 字体不受支持
+	otf := filepath.Join(dir, "font.otf")
+	require.NoError(t, os.WriteFile(otf, []byte("OTTO\x00\x00"), 0o644))
+	_, err = LoadFont(otf)
+	assert.Error(t, err)
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 10.0, TextWidth("ab", 10))
+	assert.Equal(t, 20.0, TextWidth("宝宝", 10))
+	assert.Equal(t, "宝宝…", Truncate("宝宝宝宝宝宝", 10, 30))
+	assert.Equal(t, "abc", Truncate("abc", 10, 30))
+}
@@ -0,0 +1,26 @@
+// Package pdftest 提供 PDF 相关测试使用的字体
+package pdftest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Font 返回测试用的 TrueType 字体
+// 使用 go-pdf/fpdf 模块自带的 DejaVu Sans(不含中文字形,仅用于验证文档结构),找不到模块目录时跳过测试
+func Font(t testing.TB) []byte {
+	t.Helper()
+
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/go-pdf/fpdf").Output()
+	if err != nil {
+		t.Skipf("go-pdf/fpdf module not found: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(strings.TrimSpace(string(out)), "font", "DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Skipf("test font not found: %v", err)
+	}
+	return data
+}
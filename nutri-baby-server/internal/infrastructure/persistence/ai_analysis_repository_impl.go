@@ -280,4 +280,15 @@ func (r *dailyTipsRepositoryImpl) DeleteExpired(ctx context.Context, before time
 		return errors.Wrap(errors.DatabaseError, "删除过期每日建议失败", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// DeleteByBabyID 删除宝宝的全部每日建议
+func (r *dailyTipsRepositoryImpl) DeleteByBabyID(ctx context.Context, babyID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ?", babyID).
+		Delete(&entity.DailyTips{}).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "删除每日建议失败", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// attachmentRepositoryImpl 照片附件仓储实现
+type attachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository 创建照片附件仓储
+func NewAttachmentRepository(db *gorm.DB) repository.AttachmentRepository {
+	return &attachmentRepositoryImpl{db: db}
+}
+
+func (r *attachmentRepositoryImpl) Create(ctx context.Context, attachment *entity.Attachment) error {
+	if err := r.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create attachment", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepositoryImpl) FindByID(ctx context.Context, attachmentID int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := r.db.WithContext(ctx).
+		Where("id = ?", attachmentID).
+		First(&attachment).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find attachment", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepositoryImpl) FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.Attachment, error) {
+	var attachments []*entity.Attachment
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ?", recordType, recordID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find record attachments", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepositoryImpl) FindByBabyIDInRange(ctx context.Context, babyID int64, startTime, endTime int64) ([]*entity.Attachment, error) {
+	var attachments []*entity.Attachment
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND taken_at >= ? AND taken_at <= ?", babyID, startTime, endTime).
+		Order("taken_at DESC").
+		Find(&attachments).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find baby attachments", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepositoryImpl) FindLatestBefore(ctx context.Context, babyID int64, before int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND taken_at < ?", babyID, before).
+		Order("taken_at DESC").
+		First(&attachment).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find attachment", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepositoryImpl) FindEarliestAfter(ctx context.Context, babyID int64, after int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND taken_at > ?", babyID, after).
+		Order("taken_at ASC").
+		First(&attachment).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find attachment", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepositoryImpl) Delete(ctx context.Context, attachmentID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("id = ?", attachmentID).
+		Delete(&entity.Attachment{}).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to delete attachment", err)
+	}
+
+	return nil
+}
+
+func (r *attachmentRepositoryImpl) DeleteByRecord(ctx context.Context, recordType string, recordID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ?", recordType, recordID).
+		Delete(&entity.Attachment{}).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to delete record attachments", err)
+	}
+
+	return nil
+}
@@ -2,9 +2,9 @@ package persistence
 
 import (
 	"context"
-	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
 	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
@@ -106,6 +106,24 @@ func (r *babyCollaboratorRepositoryImpl) Update(ctx context.Context, collaborato
 	return nil
 }
 
+// UpdatePermissions 更新协作者的权限覆盖
+func (r *babyCollaboratorRepositoryImpl) UpdatePermissions(ctx context.Context, babyID, userID int64, overrides entity.PermissionOverrides) error {
+	var value any
+	if len(overrides) > 0 {
+		value = overrides
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyCollaborator{}).
+		Where("baby_id = ? AND user_id = ?", babyID, userID).
+		Update("permissions", value).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to update collaborator permissions", err)
+	}
+
+	return nil
+}
+
 // Delete 移除协作者(软删除)
 func (r *babyCollaboratorRepositoryImpl) Delete(ctx context.Context, babyID, userID int64) error {
 	err := r.db.WithContext(ctx).
@@ -120,6 +138,78 @@ func (r *babyCollaboratorRepositoryImpl) Delete(ctx context.Context, babyID, use
 	return nil
 }
 
+// UpdateKeepingAdmin 更新协作者信息,降级后宝宝将没有有效管理员时返回 false
+func (r *babyCollaboratorRepositoryImpl) UpdateKeepingAdmin(ctx context.Context, collaborator *entity.BabyCollaborator) (bool, error) {
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := adminRemainsAfter(tx, collaborator.BabyID, collaborator.UserID, collaborator.Role)
+		if err != nil || !ok {
+			return err
+		}
+
+		if err := tx.Model(&entity.BabyCollaborator{}).
+			Where("baby_id = ? AND user_id = ?", collaborator.BabyID, collaborator.UserID).
+			Updates(collaborator).Error; err != nil {
+			return err
+		}
+
+		updated = true
+		return nil
+	})
+
+	if err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to update collaborator", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteKeepingAdmin 移除协作者(软删除),移除后宝宝将没有有效管理员时返回 false
+func (r *babyCollaboratorRepositoryImpl) DeleteKeepingAdmin(ctx context.Context, babyID, userID int64) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := adminRemainsAfter(tx, babyID, userID, "")
+		if err != nil || !ok {
+			return err
+		}
+
+		if err := tx.Where("baby_id = ? AND user_id = ?", babyID, userID).
+			Delete(&entity.BabyCollaborator{}).Error; err != nil {
+			return err
+		}
+
+		deleted = true
+		return nil
+	})
+
+	if err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to delete collaborator", err)
+	}
+
+	return deleted, nil
+}
+
+// adminRemainsAfter 锁定宝宝的协作者行,检查 userID 改为 newRole(移除时为空)后是否仍有有效管理员
+// 锁一直持有到事务结束,并发的移除或降级会依次检查,不会同时移除最后两位管理员
+func adminRemainsAfter(tx *gorm.DB, babyID, userID int64, newRole string) (bool, error) {
+	var collaborators []*entity.BabyCollaborator
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("baby_id = ?", babyID).
+		Find(&collaborators).Error; err != nil {
+		return false, err
+	}
+
+	if newRole == entity.CollaboratorRoleAdmin {
+		return true, nil
+	}
+	for _, collab := range collaborators {
+		if collab.UserID == userID && collab.IsAdmin() {
+			return entity.HasOtherActiveAdmin(collaborators, userID), nil
+		}
+	}
+	return true, nil
+}
+
 // BatchCreate 批量创建协作者(用于复制协作者列表)
 func (r *babyCollaboratorRepositoryImpl) BatchCreate(ctx context.Context, collaborators []*entity.BabyCollaborator) error {
 	if len(collaborators) == 0 {
@@ -133,17 +223,76 @@ func (r *babyCollaboratorRepositoryImpl) BatchCreate(ctx context.Context, collab
 	return nil
 }
 
-// CleanExpired 清理过期的临时协作者
-func (r *babyCollaboratorRepositoryImpl) CleanExpired(ctx context.Context) error {
-	now := time.Now().UnixMilli()
+// FindExpiringBetween 查找在 (from, to] 内到期、尚未提醒过的临时协作者
+func (r *babyCollaboratorRepositoryImpl) FindExpiringBetween(ctx context.Context, from, to int64) ([]*entity.BabyCollaborator, error) {
+	var collaborators []*entity.BabyCollaborator
+	err := r.db.WithContext(ctx).
+		Where("access_type = ? AND expires_at > ? AND expires_at <= ? AND reminded_at IS NULL", "temporary", from, to).
+		Order("expires_at ASC").
+		Find(&collaborators).Error
 
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find expiring collaborators", err)
+	}
+
+	return collaborators, nil
+}
+
+// FindExpired 查找在 now 之前已到期的临时协作者
+func (r *babyCollaboratorRepositoryImpl) FindExpired(ctx context.Context, now int64) ([]*entity.BabyCollaborator, error) {
+	var collaborators []*entity.BabyCollaborator
 	err := r.db.WithContext(ctx).
-		Model(&entity.BabyCollaborator{}).
 		Where("access_type = ? AND expires_at IS NOT NULL AND expires_at < ?", "temporary", now).
-		Delete(&entity.BabyCollaborator{}).Error
+		Order("expires_at ASC").
+		Find(&collaborators).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find expired collaborators", err)
+	}
+
+	return collaborators, nil
+}
+
+// DeleteExpired 移除已到期的临时协作者,过期条件与删除在同一语句中判断,不会误删刚续期的成员
+func (r *babyCollaboratorRepositoryImpl) DeleteExpired(ctx context.Context, babyID, userID int64, now int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("baby_id = ? AND user_id = ?", babyID, userID).
+		Where("access_type = ? AND expires_at IS NOT NULL AND expires_at < ?", "temporary", now).
+		Delete(&entity.BabyCollaborator{})
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to delete expired collaborator", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// MarkReminded 记录到期前提醒已发送
+func (r *babyCollaboratorRepositoryImpl) MarkReminded(ctx context.Context, collaboratorID int64, remindedAt int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyCollaborator{}).
+		Where("id = ?", collaboratorID).
+		UpdateColumn("reminded_at", remindedAt).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to mark collaborator reminded", err)
+	}
+
+	return nil
+}
+
+// UpdateExpiry 延长临时协作者的过期时间,并清空到期提醒记录
+func (r *babyCollaboratorRepositoryImpl) UpdateExpiry(ctx context.Context, babyID, userID int64, expiresAt int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyCollaborator{}).
+		Where("baby_id = ? AND user_id = ?", babyID, userID).
+		Updates(map[string]any{
+			"expires_at":  expiresAt,
+			"reminded_at": nil,
+		}).Error
 
 	if err != nil {
-		return errors.Wrap(errors.DatabaseError, "failed to clean expired collaborators", err)
+		return errors.Wrap(errors.DatabaseError, "failed to update collaborator expiry", err)
 	}
 
 	return nil
@@ -29,6 +29,23 @@ func (r *babyInvitationRepositoryImpl) Create(ctx context.Context, invitation *e
 	return nil
 }
 
+// FindByID 根据ID查找邀请
+func (r *babyInvitationRepositoryImpl) FindByID(ctx context.Context, invitationID int64) (*entity.BabyInvitation, error) {
+	var invitation entity.BabyInvitation
+	err := r.db.WithContext(ctx).
+		Where("id = ?", invitationID).
+		First(&invitation).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(errors.NotFound, "邀请不存在")
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查找邀请记录失败", err)
+	}
+
+	return &invitation, nil
+}
+
 // FindByToken 根据token查找邀请
 func (r *babyInvitationRepositoryImpl) FindByToken(ctx context.Context, token string) (*entity.BabyInvitation, error) {
 	var invitation entity.BabyInvitation
@@ -79,17 +96,18 @@ func (r *babyInvitationRepositoryImpl) FindByBabyID(ctx context.Context, babyID
 	return invitations, nil
 }
 
-// FindByBabyAndInviter 根据宝宝ID和邀请人查找未使用的邀请记录
+// FindByBabyAndInviter 根据宝宝ID和邀请人查找最近一条未撤销的邀请记录
 func (r *babyInvitationRepositoryImpl) FindByBabyAndInviter(ctx context.Context, babyID, inviterID int64) (*entity.BabyInvitation, error) {
 	var invitation entity.BabyInvitation
 
 	// 查询条件:
 	// 1. 宝宝ID匹配
 	// 2. 邀请人ID匹配
-	// 3. 邀请未使用(used_by IS NULL)
+	// 3. 邀请未撤销(revoked_at IS NULL)
 	// 4. 未被删除(GORM soft_delete自动处理)
 	err := r.db.WithContext(ctx).
-		Where("baby_id = ? AND user_id = ?", babyID, inviterID).
+		Where("baby_id = ? AND user_id = ? AND revoked_at IS NULL", babyID, inviterID).
+		Order("created_at DESC").
 		First(&invitation).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -102,13 +120,42 @@ func (r *babyInvitationRepositoryImpl) FindByBabyAndInviter(ctx context.Context,
 	return &invitation, nil
 }
 
-// CleanExpired 清理过期的邀请
+// IncrementUsedCount 占用一次使用次数,已达上限时返回 false
+func (r *babyInvitationRepositoryImpl) IncrementUsedCount(ctx context.Context, invitationID int64) (bool, error) {
+	// 条件更新保证并发加入时不会超出上限
+	result := r.db.WithContext(ctx).
+		Model(&entity.BabyInvitation{}).
+		Where("id = ? AND (max_uses = 0 OR used_count < max_uses)", invitationID).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "更新邀请使用次数失败", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Revoke 撤销邀请
+func (r *babyInvitationRepositoryImpl) Revoke(ctx context.Context, invitationID int64, revokedAt int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyInvitation{}).
+		Where("id = ? AND revoked_at IS NULL", invitationID).
+		UpdateColumn("revoked_at", revokedAt).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "撤销邀请失败", err)
+	}
+
+	return nil
+}
+
+// CleanExpired 清理链接已过期的邀请
 func (r *babyInvitationRepositoryImpl) CleanExpired(ctx context.Context) error {
 	now := time.Now().UnixMilli()
 
 	err := r.db.WithContext(ctx).
 		Model(&entity.BabyInvitation{}).
-		Where("valid_until < ?", now).
+		Where("link_expires_at IS NOT NULL AND link_expires_at < ?", now).
 		Delete(&entity.BabyInvitation{}).Error
 
 	if err != nil {
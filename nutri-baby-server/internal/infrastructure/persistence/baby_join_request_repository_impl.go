@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// babyJoinRequestRepositoryImpl 加入申请仓储实现
+type babyJoinRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBabyJoinRequestRepository 创建加入申请仓储
+func NewBabyJoinRequestRepository(db *gorm.DB) repository.BabyJoinRequestRepository {
+	return &babyJoinRequestRepositoryImpl{db: db}
+}
+
+func (r *babyJoinRequestRepositoryImpl) Create(ctx context.Context, request *entity.BabyJoinRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "创建加入申请失败", err)
+	}
+	return nil
+}
+
+func (r *babyJoinRequestRepositoryImpl) FindByID(ctx context.Context, requestID int64) (*entity.BabyJoinRequest, error) {
+	var request entity.BabyJoinRequest
+	err := r.db.WithContext(ctx).
+		Where("id = ?", requestID).
+		First(&request).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(errors.NotFound, "加入申请不存在")
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查找加入申请失败", err)
+	}
+
+	return &request, nil
+}
+
+func (r *babyJoinRequestRepositoryImpl) FindPendingByBabyID(ctx context.Context, babyID int64) ([]*entity.BabyJoinRequest, error) {
+	var requests []*entity.BabyJoinRequest
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND status = ?", babyID, entity.JoinRequestStatusPending).
+		Order("created_at DESC").
+		Find(&requests).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询待审批的加入申请失败", err)
+	}
+
+	return requests, nil
+}
+
+func (r *babyJoinRequestRepositoryImpl) FindPendingByBabyAndUser(ctx context.Context, babyID, userID int64) (*entity.BabyJoinRequest, error) {
+	var request entity.BabyJoinRequest
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND user_id = ? AND status = ?", babyID, userID, entity.JoinRequestStatusPending).
+		First(&request).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询加入申请失败", err)
+	}
+
+	return &request, nil
+}
+
+func (r *babyJoinRequestRepositoryImpl) Review(ctx context.Context, requestID int64, status string, reviewerID int64, reviewedAt int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.BabyJoinRequest{}).
+		Where("id = ? AND status = ?", requestID, entity.JoinRequestStatusPending).
+		Updates(map[string]any{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": reviewedAt,
+		})
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "审批加入申请失败", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *babyJoinRequestRepositoryImpl) CancelPendingByInvitation(ctx context.Context, invitationID int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyJoinRequest{}).
+		Where("invitation_id = ? AND status = ?", invitationID, entity.JoinRequestStatusPending).
+		Update("status", entity.JoinRequestStatusCancelled).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "取消加入申请失败", err)
+	}
+
+	return nil
+}
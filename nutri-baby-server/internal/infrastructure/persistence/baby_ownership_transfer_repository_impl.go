@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// babyOwnershipTransferRepositoryImpl 所有权转让仓储实现
+type babyOwnershipTransferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBabyOwnershipTransferRepository 创建所有权转让仓储
+func NewBabyOwnershipTransferRepository(db *gorm.DB) repository.BabyOwnershipTransferRepository {
+	return &babyOwnershipTransferRepositoryImpl{db: db}
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) Create(ctx context.Context, transfer *entity.BabyOwnershipTransfer) error {
+	if err := r.db.WithContext(ctx).Create(transfer).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "创建所有权转让失败", err)
+	}
+	return nil
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) FindPendingByBabyID(ctx context.Context, babyID int64) (*entity.BabyOwnershipTransfer, error) {
+	var transfer entity.BabyOwnershipTransfer
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND status = ?", babyID, entity.OwnershipTransferStatusPending).
+		Order("created_at DESC").
+		First(&transfer).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询所有权转让失败", err)
+	}
+
+	return &transfer, nil
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) FindPendingByToUser(ctx context.Context, userID int64) ([]*entity.BabyOwnershipTransfer, error) {
+	var transfers []*entity.BabyOwnershipTransfer
+	err := r.db.WithContext(ctx).
+		Where("to_user_id = ? AND status = ?", userID, entity.OwnershipTransferStatusPending).
+		Order("created_at DESC").
+		Find(&transfers).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询待接受的所有权转让失败", err)
+	}
+
+	return transfers, nil
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) Respond(ctx context.Context, transferID int64, status string, respondedAt int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.BabyOwnershipTransfer{}).
+		Where("id = ? AND status = ?", transferID, entity.OwnershipTransferStatusPending).
+		Updates(map[string]any{
+			"status":       status,
+			"responded_at": respondedAt,
+		})
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "处理所有权转让失败", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) Accept(ctx context.Context, transfer *entity.BabyOwnershipTransfer, respondedAt int64) (bool, error) {
+	accepted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.BabyOwnershipTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, entity.OwnershipTransferStatusPending).
+			Updates(map[string]any{
+				"status":       entity.OwnershipTransferStatusAccepted,
+				"responded_at": respondedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		// 新所有者始终是管理员
+		result = tx.Model(&entity.BabyCollaborator{}).
+			Where("baby_id = ? AND user_id = ?", transfer.BabyID, transfer.ToUserID).
+			Update("role", entity.CollaboratorRoleAdmin)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTransferStale
+		}
+
+		result = tx.Model(&entity.Baby{}).
+			Where("id = ? AND user_id = ?", transfer.BabyID, transfer.FromUserID).
+			Update("user_id", transfer.ToUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTransferStale
+		}
+
+		accepted = true
+		return nil
+	})
+
+	if errors.Is(err, errTransferStale) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "接受所有权转让失败", err)
+	}
+
+	return accepted, nil
+}
+
+// errTransferStale 接受转让时被提名人或宝宝所有者已变更,用于回滚事务
+var errTransferStale = errors.New(errors.Conflict, "所有权转让已失效")
+
+func (r *babyOwnershipTransferRepositoryImpl) CancelPendingByBaby(ctx context.Context, babyID int64) error {
+	return r.cancelPending(ctx, "baby_id = ?", babyID)
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) CancelPendingByBabyAndUser(ctx context.Context, babyID, userID int64) error {
+	return r.cancelPending(ctx, "baby_id = ? AND (from_user_id = ? OR to_user_id = ?)", babyID, userID, userID)
+}
+
+func (r *babyOwnershipTransferRepositoryImpl) cancelPending(ctx context.Context, query string, args ...any) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyOwnershipTransfer{}).
+		Where(query, args...).
+		Where("status = ?", entity.OwnershipTransferStatusPending).
+		Updates(map[string]any{
+			"status":       entity.OwnershipTransferStatusCancelled,
+			"responded_at": time.Now().UnixMilli(),
+		}).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "取消所有权转让失败", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -49,10 +50,11 @@ func (r *babyRepositoryImpl) FindByID(ctx context.Context, babyID int64) (*entit
 func (r *babyRepositoryImpl) FindByUserID(ctx context.Context, userID int64) ([]*entity.Baby, error) {
 	var babies []*entity.Baby
 
-	// 通过 baby_collaborators 表关联查询
+	// 通过 baby_collaborators 表关联查询,排除已移除和临时权限已过期的协作关系
 	err := r.db.WithContext(ctx).
 		Joins("JOIN baby_collaborators ON baby_collaborators.baby_id = babies.id").
-		Where("baby_collaborators.user_id = ?", userID).
+		Where("baby_collaborators.user_id = ? AND baby_collaborators.deleted_at = 0", userID).
+		Where("baby_collaborators.access_type <> ? OR baby_collaborators.expires_at IS NULL OR baby_collaborators.expires_at >= ?", "temporary", time.Now().UnixMilli()).
 		Order("baby_collaborators.created_at DESC").
 		Find(&babies).Error
 
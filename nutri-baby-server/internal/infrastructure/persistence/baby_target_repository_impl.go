@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// babyTargetRepositoryImpl 宝宝每日目标仓储实现
+type babyTargetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBabyTargetRepository 创建宝宝每日目标仓储
+func NewBabyTargetRepository(db *gorm.DB) repository.BabyTargetRepository {
+	return &babyTargetRepositoryImpl{db: db}
+}
+
+// FindByBabyID 获取宝宝的目标覆盖配置
+func (r *babyTargetRepositoryImpl) FindByBabyID(ctx context.Context, babyID int64) (*entity.BabyTarget, error) {
+	var target entity.BabyTarget
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ?", babyID).
+		First(&target).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil // 未设置覆盖,返回 nil 而不是错误
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find baby target", err)
+	}
+
+	return &target, nil
+}
+
+// Save 保存目标覆盖配置
+func (r *babyTargetRepositoryImpl) Save(ctx context.Context, target *entity.BabyTarget) error {
+	if err := r.db.WithContext(ctx).Save(target).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to save baby target", err)
+	}
+	return nil
+}
+
+// DeleteByBabyID 删除宝宝的目标覆盖配置
+func (r *babyTargetRepositoryImpl) DeleteByBabyID(ctx context.Context, babyID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ?", babyID).
+		Delete(&entity.BabyTarget{}).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to delete baby target", err)
+	}
+
+	return nil
+}
@@ -13,16 +13,19 @@ import (
 
 type babyVaccineScheduleRepositoryImpl struct {
 	db                      *gorm.DB
+	vaccinePlanRepo         repository.VaccinePlanRepository
 	vaccinePlanTemplateRepo repository.VaccinePlanTemplateRepository
 }
 
 // NewBabyVaccineScheduleRepository 创建宝宝疫苗接种日程仓储实例
 func NewBabyVaccineScheduleRepository(
 	db *gorm.DB,
+	vaccinePlanRepo repository.VaccinePlanRepository,
 	vaccinePlanTemplateRepo repository.VaccinePlanTemplateRepository,
 ) repository.BabyVaccineScheduleRepository {
 	return &babyVaccineScheduleRepositoryImpl{
 		db:                      db,
+		vaccinePlanRepo:         vaccinePlanRepo,
 		vaccinePlanTemplateRepo: vaccinePlanTemplateRepo,
 	}
 }
@@ -152,8 +155,18 @@ func (r *babyVaccineScheduleRepositoryImpl) InitializeFromTemplates(ctx context.
 		return errors.Wrap(errors.ParamError, "解析出生日期失败", err)
 	}
 
-	// 3. 获取所有模板
-	templates, err := r.vaccinePlanTemplateRepo.FindAll(ctx)
+	// 3. 获取宝宝所选计划的模板,未选择时使用默认计划
+	planID := baby.VaccinePlanID
+	if planID == 0 {
+		plan, err := r.vaccinePlanRepo.FindDefault(ctx)
+		if err != nil && !errors.Is(err, errors.ErrNotFound) {
+			return err
+		}
+		if plan != nil {
+			planID = plan.ID
+		}
+	}
+	templates, err := r.vaccinePlanTemplateRepo.FindByPlanID(ctx, planID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.Wrap(errors.NotFound, "没有找到任何疫苗计划模板", err)
@@ -181,6 +194,8 @@ func (r *babyVaccineScheduleRepositoryImpl) InitializeFromTemplates(ctx context.
 			DoseNumber:        template.DoseNumber,
 			IsRequired:        template.IsRequired,
 			ReminderDays:      template.ReminderDays,
+			MinAgeDays:        template.MinAgeDays,
+			MinIntervalDays:   template.MinIntervalDays,
 			IsCustom:          false,
 			VaccinationStatus: entity.VaccinationStatusPending,
 			ScheduledDate:     scheduledDate, // 设置计划接种日期
@@ -190,8 +205,131 @@ func (r *babyVaccineScheduleRepositoryImpl) InitializeFromTemplates(ctx context.
 		schedules = append(schedules, schedule)
 	}
 
-	// 5. 批量插入
-	return r.BatchCreate(ctx, schedules)
+	// 5. 批量插入并记录宝宝使用的计划
+	if planID == baby.VaccinePlanID {
+		return r.BatchCreate(ctx, schedules)
+	}
+	return r.ApplyPlan(ctx, babyID, planID, schedules, nil, nil)
+}
+
+// planScheduleColumns 切换计划时随模板变化的日程字段
+var planScheduleColumns = []string{
+	"template_id", "vaccine_type", "vaccine_name", "description", "age_in_months", "dose_number",
+	"is_required", "reminder_days", "min_age_days", "min_interval_days",
+	"scheduled_date", "reminder_sent", "reminder_sent_at",
+}
+
+// ApplyPlan 在事务中应用计划调整
+func (r *babyVaccineScheduleRepositoryImpl) ApplyPlan(
+	ctx context.Context,
+	babyID, planID int64,
+	creates, updates []*entity.BabyVaccineSchedule,
+	deleteIDs []int64,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(deleteIDs) > 0 {
+			if err := tx.Where("baby_id = ? AND id IN ?", babyID, deleteIDs).
+				Delete(&entity.BabyVaccineSchedule{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, schedule := range updates {
+			if err := tx.Model(schedule).
+				Where("baby_id = ?", babyID).
+				Select(planScheduleColumns).
+				Updates(schedule).Error; err != nil {
+				return err
+			}
+		}
+		if len(creates) > 0 {
+			if err := tx.Create(&creates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entity.Baby{}).
+			Where("id = ?", babyID).
+			UpdateColumn("vaccine_plan_id", planID).Error
+	})
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "应用疫苗计划失败", err)
+	}
+	return nil
+}
+
+// completedScheduleColumns 记录接种时写入的日程字段
+var completedScheduleColumns = []string{
+	"vaccination_status", "vaccine_date", "hospital", "batch_number", "reaction", "note",
+	"completed_by", "completed_by_name", "completed_by_avatar", "completed_time",
+}
+
+// ImportCompleted 在事务中导入历史接种
+func (r *babyVaccineScheduleRepositoryImpl) ImportCompleted(
+	ctx context.Context,
+	completes, creates, rescheduled []*entity.BabyVaccineSchedule,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, schedule := range completes {
+			if err := tx.Model(schedule).
+				Select(completedScheduleColumns).
+				Updates(schedule).Error; err != nil {
+				return err
+			}
+		}
+		if len(creates) > 0 {
+			if err := tx.Create(&creates).Error; err != nil {
+				return err
+			}
+		}
+		for _, schedule := range rescheduled {
+			if err := tx.Model(schedule).
+				Select("scheduled_date", "reminder_sent", "reminder_sent_at").
+				Updates(schedule).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "导入疫苗接种记录失败", err)
+	}
+	return nil
+}
+
+// UpdateScheduledDates 在事务中批量更新计划接种日期及提醒状态
+func (r *babyVaccineScheduleRepositoryImpl) UpdateScheduledDates(ctx context.Context, schedules []*entity.BabyVaccineSchedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, schedule := range schedules {
+			if err := tx.Model(schedule).
+				Select("scheduled_date", "reminder_sent", "reminder_sent_at").
+				Updates(schedule).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "更新疫苗计划接种日期失败", err)
+	}
+	return nil
+}
+
+// UpdateReactionSummary 更新接种后反应汇总
+func (r *babyVaccineScheduleRepositoryImpl) UpdateReactionSummary(ctx context.Context, scheduleID int64, severity, summary string) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyVaccineSchedule{}).
+		Where("id = ?", scheduleID).
+		Updates(map[string]interface{}{
+			"reaction_severity": severity,
+			"reaction_summary":  summary,
+		}).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "更新接种反应汇总失败", err)
+	}
+	return nil
 }
 
 // CountByBabyID 统计宝宝的疫苗接种日程总数
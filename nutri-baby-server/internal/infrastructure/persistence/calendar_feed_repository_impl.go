@@ -0,0 +1,74 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// calendarFeedRepositoryImpl 日历订阅仓储实现
+type calendarFeedRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository 创建日历订阅仓储
+func NewCalendarFeedRepository(db *gorm.DB) repository.CalendarFeedRepository {
+	return &calendarFeedRepositoryImpl{db: db}
+}
+
+func (r *calendarFeedRepositoryImpl) FindByUserID(ctx context.Context, userID int64) (*entity.CalendarFeed, error) {
+	return r.findOne(ctx, "user_id = ?", userID)
+}
+
+func (r *calendarFeedRepositoryImpl) FindByToken(ctx context.Context, token string) (*entity.CalendarFeed, error) {
+	return r.findOne(ctx, "token = ?", token)
+}
+
+func (r *calendarFeedRepositoryImpl) findOne(ctx context.Context, query string, arg any) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+	err := r.db.WithContext(ctx).
+		Where(query, arg).
+		First(&feed).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find calendar feed", err)
+	}
+
+	return &feed, nil
+}
+
+func (r *calendarFeedRepositoryImpl) Create(ctx context.Context, feed *entity.CalendarFeed) error {
+	if err := r.db.WithContext(ctx).Create(feed).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create calendar feed", err)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepositoryImpl) UpdateToken(ctx context.Context, feedID int64, token string) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.CalendarFeed{}).
+		Where("id = ?", feedID).
+		Updates(map[string]any{"token": token, "last_accessed_at": nil}).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to update calendar feed token", err)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepositoryImpl) TouchAccess(ctx context.Context, feedID int64, accessedAt int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.CalendarFeed{}).
+		Where("id = ?", feedID).
+		UpdateColumn("last_accessed_at", accessedAt).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to update calendar feed access time", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// caregiverShiftRepositoryImpl 照护人值班仓储实现
+type caregiverShiftRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCaregiverShiftRepository 创建照护人值班仓储
+func NewCaregiverShiftRepository(db *gorm.DB) repository.CaregiverShiftRepository {
+	return &caregiverShiftRepositoryImpl{db: db}
+}
+
+func (r *caregiverShiftRepositoryImpl) Create(ctx context.Context, shift *entity.CaregiverShift) error {
+	if err := r.db.WithContext(ctx).Create(shift).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "创建值班失败", err)
+	}
+	return nil
+}
+
+func (r *caregiverShiftRepositoryImpl) FindOnDuty(ctx context.Context, babyID int64, startedAfter int64) (*entity.CaregiverShift, error) {
+	var shift entity.CaregiverShift
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND end_time IS NULL AND start_time >= ?", babyID, startedAfter).
+		Order("start_time DESC").
+		First(&shift).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询值班照护人失败", err)
+	}
+
+	return &shift, nil
+}
+
+func (r *caregiverShiftRepositoryImpl) FindLatestByOtherUser(ctx context.Context, babyID, userID int64) (*entity.CaregiverShift, error) {
+	var shift entity.CaregiverShift
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND user_id <> ?", babyID, userID).
+		Order("start_time DESC").
+		First(&shift).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询上一位照护人的值班失败", err)
+	}
+
+	return &shift, nil
+}
+
+func (r *caregiverShiftRepositoryImpl) FindRecentByBaby(ctx context.Context, babyID int64, limit int) ([]*entity.CaregiverShift, error) {
+	var shifts []*entity.CaregiverShift
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ?", babyID).
+		Order("start_time DESC").
+		Limit(limit).
+		Find(&shifts).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询值班记录失败", err)
+	}
+
+	return shifts, nil
+}
+
+func (r *caregiverShiftRepositoryImpl) End(ctx context.Context, shiftID int64, endTime int64, handoffNote string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.CaregiverShift{}).
+		Where("id = ? AND end_time IS NULL", shiftID).
+		Updates(map[string]any{
+			"end_time":     endTime,
+			"handoff_note": handoffNote,
+		})
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "结束值班失败", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *caregiverShiftRepositoryImpl) EndOpenByBaby(ctx context.Context, babyID int64, endTime int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.CaregiverShift{}).
+		Where("baby_id = ? AND end_time IS NULL", babyID).
+		Update("end_time", endTime).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "交接值班失败", err)
+	}
+
+	return nil
+}
@@ -58,16 +58,32 @@ func autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&entity.User{},
 		&entity.Baby{},
-		&entity.BabyCollaborator{}, // 去家庭化架构：宝宝协作者
-		&entity.BabyInvitation{},   // 去家庭化架构：宝宝邀请(微信分享/二维码)
+		&entity.BabyCollaborator{},      // 去家庭化架构：宝宝协作者
+		&entity.BabyInvitation{},        // 去家庭化架构：宝宝邀请(微信分享/二维码)
+		&entity.BabyJoinRequest{},       // 加入申请(需审批的邀请)
+		&entity.BabyOwnershipTransfer{}, // 宝宝所有权转让
 		&entity.FeedingRecord{},
 		&entity.SleepRecord{},
 		&entity.DiaperRecord{},
 		&entity.GrowthRecord{},
+		&entity.VaccinePlan{},
 		&entity.VaccinePlanTemplate{},
-		&entity.BabyVaccineSchedule{}, // 新表：合并计划、记录和提醒
-		&entity.SubscribeRecord{},     // 订阅消息：用户订阅记录
-		&entity.MessageSendLog{},      // 订阅消息：消息发送日志
-		&entity.MessageSendQueue{},    // 订阅消息：消息发送队列
+		&entity.BabyVaccineSchedule{},     // 新表：合并计划、记录和提醒
+		&entity.VaccineReactionFollowUp{}, // 接种后反应随访
+		&entity.SubscribeRecord{},         // 订阅消息：用户订阅记录
+		&entity.MessageSendLog{},          // 订阅消息：消息发送日志
+		&entity.MessageSendQueue{},        // 订阅消息：消息发送队列
+		&entity.BabyTarget{},              // 每日目标覆盖
+		&entity.MilestoneTemplate{},       // 发育里程碑模板
+		&entity.BabyMilestone{},           // 宝宝发育里程碑
+		&entity.Attachment{},              // 照片附件
+		&entity.ExportJob{},               // 数据导出任务
+		&entity.ShareLink{},               // 只读分享链接
+		&entity.ShareAccessLog{},          // 分享链接访问日志
+		&entity.CalendarFeed{},            // 日历订阅(ICS)
+		&entity.CaregiverShift{},          // 照护人值班
+		&entity.RecordComment{},           // 记录评论
+		&entity.RecordReaction{},          // 记录表情回应
+		&entity.RecordRevision{},          // 记录修订历史
 	)
 }
@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// exportJobRepositoryImpl 数据导出任务仓储实现
+type exportJobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewExportJobRepository 创建数据导出任务仓储
+func NewExportJobRepository(db *gorm.DB) repository.ExportJobRepository {
+	return &exportJobRepositoryImpl{db: db}
+}
+
+func (r *exportJobRepositoryImpl) Create(ctx context.Context, job *entity.ExportJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create export job", err)
+	}
+	return nil
+}
+
+func (r *exportJobRepositoryImpl) FindByID(ctx context.Context, jobID int64) (*entity.ExportJob, error) {
+	var job entity.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("id = ?", jobID).
+		First(&job).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find export job", err)
+	}
+
+	return &job, nil
+}
+
+func (r *exportJobRepositoryImpl) FindByBabyAndUser(ctx context.Context, babyID, userID int64, limit int) ([]*entity.ExportJob, error) {
+	var jobs []*entity.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND requested_by = ?", babyID, userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find export jobs", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *exportJobRepositoryImpl) FindExpired(ctx context.Context, now int64) ([]*entity.ExportJob, error) {
+	var jobs []*entity.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", entity.ExportStatusCompleted, now).
+		Find(&jobs).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find expired export jobs", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *exportJobRepositoryImpl) FindPendingBefore(ctx context.Context, before int64, limit int) ([]*entity.ExportJob, error) {
+	var jobs []*entity.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", entity.ExportStatusPending, before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find pending export jobs", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *exportJobRepositoryImpl) Claim(ctx context.Context, jobID int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ExportJob{}).
+		Where("id = ? AND status = ?", jobID, entity.ExportStatusPending).
+		Update("status", entity.ExportStatusProcessing)
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to claim export job", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *exportJobRepositoryImpl) FailStaleProcessing(ctx context.Context, before int64, message string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ExportJob{}).
+		Where("status = ? AND updated_at < ?", entity.ExportStatusProcessing, before).
+		Updates(map[string]any{
+			"status":        entity.ExportStatusFailed,
+			"error_message": message,
+		})
+
+	if result.Error != nil {
+		return 0, errors.Wrap(errors.DatabaseError, "failed to fail stale export jobs", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *exportJobRepositoryImpl) Update(ctx context.Context, job *entity.ExportJob) error {
+	if err := r.db.WithContext(ctx).Save(job).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to update export job", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+type milestoneTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMilestoneTemplateRepository 创建发育里程碑模板仓储实现
+func NewMilestoneTemplateRepository(db *gorm.DB) repository.MilestoneTemplateRepository {
+	return &milestoneTemplateRepositoryImpl{db: db}
+}
+
+func (r *milestoneTemplateRepositoryImpl) FindAll(ctx context.Context) ([]*entity.MilestoneTemplate, error) {
+	var templates []*entity.MilestoneTemplate
+	err := r.db.WithContext(ctx).
+		Order("age_in_months ASC, sort_order ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (r *milestoneTemplateRepositoryImpl) BatchCreate(ctx context.Context, templates []*entity.MilestoneTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(templates, 100).Error
+}
+
+type babyMilestoneRepositoryImpl struct {
+	db                    *gorm.DB
+	milestoneTemplateRepo repository.MilestoneTemplateRepository
+}
+
+// NewBabyMilestoneRepository 创建宝宝发育里程碑仓储实例
+func NewBabyMilestoneRepository(
+	db *gorm.DB,
+	milestoneTemplateRepo repository.MilestoneTemplateRepository,
+) repository.BabyMilestoneRepository {
+	return &babyMilestoneRepositoryImpl{
+		db:                    db,
+		milestoneTemplateRepo: milestoneTemplateRepo,
+	}
+}
+
+// Create 创建里程碑记录
+func (r *babyMilestoneRepositoryImpl) Create(ctx context.Context, milestone *entity.BabyMilestone) error {
+	if err := r.db.WithContext(ctx).Create(milestone).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "创建里程碑失败", err)
+	}
+	return nil
+}
+
+// FindByID 根据ID查找里程碑
+func (r *babyMilestoneRepositoryImpl) FindByID(ctx context.Context, milestoneID int64) (*entity.BabyMilestone, error) {
+	var milestone entity.BabyMilestone
+	err := r.db.WithContext(ctx).
+		Where("id = ?", milestoneID).
+		First(&milestone).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(errors.NotFound, "里程碑不存在")
+		}
+		return nil, errors.Wrap(errors.DatabaseError, "查询里程碑失败", err)
+	}
+	return &milestone, nil
+}
+
+// FindByBabyID 查找宝宝的里程碑
+func (r *babyMilestoneRepositoryImpl) FindByBabyID(ctx context.Context, babyID int64, status string) ([]*entity.BabyMilestone, error) {
+	var milestones []*entity.BabyMilestone
+	query := r.db.WithContext(ctx).Where("baby_id = ?", babyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("age_in_months ASC, id ASC").Find(&milestones).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询里程碑列表失败", err)
+	}
+	return milestones, nil
+}
+
+// FindAchievedByBabyID 查找时间范围内已达成的里程碑
+func (r *babyMilestoneRepositoryImpl) FindAchievedByBabyID(ctx context.Context, babyID int64, startTime, endTime int64) ([]*entity.BabyMilestone, error) {
+	var milestones []*entity.BabyMilestone
+	query := r.db.WithContext(ctx).
+		Where("baby_id = ? AND status = ? AND achieved_date IS NOT NULL", babyID, entity.MilestoneStatusAchieved)
+
+	if startTime > 0 {
+		query = query.Where("achieved_date >= ?", startTime)
+	}
+	if endTime > 0 {
+		query = query.Where("achieved_date <= ?", endTime)
+	}
+
+	err := query.Order("achieved_date DESC").Find(&milestones).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询已达成里程碑失败", err)
+	}
+	return milestones, nil
+}
+
+// Update 更新里程碑
+func (r *babyMilestoneRepositoryImpl) Update(ctx context.Context, milestone *entity.BabyMilestone) error {
+	if err := r.db.WithContext(ctx).Save(milestone).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "更新里程碑失败", err)
+	}
+	return nil
+}
+
+// Delete 删除里程碑(软删除)
+func (r *babyMilestoneRepositoryImpl) Delete(ctx context.Context, milestoneID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("id = ?", milestoneID).
+		Delete(&entity.BabyMilestone{}).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "删除里程碑失败", err)
+	}
+	return nil
+}
+
+// CountByBabyID 统计宝宝的里程碑数量
+func (r *babyMilestoneRepositoryImpl) CountByBabyID(ctx context.Context, babyID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.BabyMilestone{}).
+		Where("baby_id = ?", babyID).
+		Count(&count).Error
+	if err != nil {
+		return 0, errors.Wrap(errors.DatabaseError, "统计里程碑数量失败", err)
+	}
+	return count, nil
+}
+
+// InitializeFromTemplates 从模板为宝宝初始化里程碑
+func (r *babyMilestoneRepositoryImpl) InitializeFromTemplates(ctx context.Context, babyID, createdBy int64) error {
+	templates, err := r.milestoneTemplateRepo.FindAll(ctx)
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "获取里程碑模板失败", err)
+	}
+
+	if len(templates) == 0 {
+		return errors.New(errors.NotFound, "未找到里程碑模板")
+	}
+
+	milestones := make([]*entity.BabyMilestone, 0, len(templates))
+	for _, template := range templates {
+		milestones = append(milestones, &entity.BabyMilestone{
+			BabyID:         babyID,
+			TemplateID:     &template.ID,
+			Category:       template.Category,
+			Title:          template.Title,
+			Description:    template.Description,
+			AgeInMonths:    template.AgeInMonths,
+			MaxAgeInMonths: template.MaxAgeInMonths,
+			Status:         entity.MilestoneStatusUpcoming,
+			Photos:         entity.MilestonePhotos{},
+			CreatedBy:      createdBy,
+		})
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(milestones, 100).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "批量创建里程碑失败", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// recordCommentRepositoryImpl 记录评论与表情回应仓储实现
+type recordCommentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordCommentRepository 创建记录评论与表情回应仓储
+func NewRecordCommentRepository(db *gorm.DB) repository.RecordCommentRepository {
+	return &recordCommentRepositoryImpl{db: db}
+}
+
+func (r *recordCommentRepositoryImpl) Create(ctx context.Context, comment *entity.RecordComment) error {
+	if err := r.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create comment", err)
+	}
+	return nil
+}
+
+func (r *recordCommentRepositoryImpl) FindByID(ctx context.Context, commentID int64) (*entity.RecordComment, error) {
+	var comment entity.RecordComment
+	err := r.db.WithContext(ctx).
+		Where("id = ?", commentID).
+		First(&comment).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find comment", err)
+	}
+
+	return &comment, nil
+}
+
+func (r *recordCommentRepositoryImpl) FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.RecordComment, error) {
+	var comments []*entity.RecordComment
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ?", recordType, recordID).
+		Order("created_at ASC").
+		Find(&comments).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find record comments", err)
+	}
+
+	return comments, nil
+}
+
+func (r *recordCommentRepositoryImpl) FindByRecords(ctx context.Context, recordType string, recordIDs []int64) ([]*entity.RecordComment, error) {
+	var comments []*entity.RecordComment
+	if len(recordIDs) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id IN ?", recordType, recordIDs).
+		Order("created_at ASC").
+		Find(&comments).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find record comments", err)
+	}
+
+	return comments, nil
+}
+
+func (r *recordCommentRepositoryImpl) Delete(ctx context.Context, commentID int64) error {
+	err := r.db.WithContext(ctx).
+		Where("id = ? OR parent_id = ?", commentID, commentID).
+		Delete(&entity.RecordComment{}).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to delete comment", err)
+	}
+
+	return nil
+}
+
+func (r *recordCommentRepositoryImpl) AddReaction(ctx context.Context, reaction *entity.RecordReaction) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ? AND user_id = ? AND emoji = ?",
+			reaction.RecordType, reaction.RecordID, reaction.UserID, reaction.Emoji).
+		FirstOrCreate(reaction)
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to add reaction", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recordCommentRepositoryImpl) RemoveReaction(ctx context.Context, recordType string, recordID, userID int64, emoji string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ? AND user_id = ? AND emoji = ?", recordType, recordID, userID, emoji).
+		Delete(&entity.RecordReaction{})
+
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to remove reaction", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recordCommentRepositoryImpl) FindReactionsByRecords(ctx context.Context, recordType string, recordIDs []int64) ([]*entity.RecordReaction, error) {
+	var reactions []*entity.RecordReaction
+	if len(recordIDs) == 0 {
+		return reactions, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id IN ?", recordType, recordIDs).
+		Order("created_at ASC").
+		Find(&reactions).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find record reactions", err)
+	}
+
+	return reactions, nil
+}
+
+func (r *recordCommentRepositoryImpl) DeleteByRecord(ctx context.Context, recordType string, recordID int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("record_type = ? AND record_id = ?", recordType, recordID).
+			Delete(&entity.RecordComment{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("record_type = ? AND record_id = ?", recordType, recordID).
+			Delete(&entity.RecordReaction{}).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to delete record comments", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// 批量插入每批条数
+const importBatchSize = 200
+
+// recordImportRepositoryImpl 记录批量导入仓储实现
+type recordImportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordImportRepository 创建记录批量导入仓储
+func NewRecordImportRepository(db *gorm.DB) repository.RecordImportRepository {
+	return &recordImportRepositoryImpl{db: db}
+}
+
+// ImportRecords 在同一事务中写入全部记录
+func (r *recordImportRepositoryImpl) ImportRecords(ctx context.Context, batch *repository.RecordBatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(batch.Feedings) > 0 {
+			if err := tx.CreateInBatches(batch.Feedings, importBatchSize).Error; err != nil {
+				return errors.Wrap(errors.DatabaseError, "failed to import feeding records", err)
+			}
+		}
+		if len(batch.Sleeps) > 0 {
+			if err := tx.CreateInBatches(batch.Sleeps, importBatchSize).Error; err != nil {
+				return errors.Wrap(errors.DatabaseError, "failed to import sleep records", err)
+			}
+		}
+		if len(batch.Diapers) > 0 {
+			if err := tx.CreateInBatches(batch.Diapers, importBatchSize).Error; err != nil {
+				return errors.Wrap(errors.DatabaseError, "failed to import diaper records", err)
+			}
+		}
+		if len(batch.Growths) > 0 {
+			if err := tx.CreateInBatches(batch.Growths, importBatchSize).Error; err != nil {
+				return errors.Wrap(errors.DatabaseError, "failed to import growth records", err)
+			}
+		}
+		return nil
+	})
+}
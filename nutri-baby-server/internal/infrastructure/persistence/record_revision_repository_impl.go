@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// recordRevisionRepositoryImpl 记录修订历史仓储实现
+type recordRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordRevisionRepository 创建记录修订历史仓储
+func NewRecordRevisionRepository(db *gorm.DB) repository.RecordRevisionRepository {
+	return &recordRevisionRepositoryImpl{db: db}
+}
+
+func (r *recordRevisionRepositoryImpl) Create(ctx context.Context, revision *entity.RecordRevision) error {
+	if err := r.db.WithContext(ctx).Create(revision).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create revision", err)
+	}
+	return nil
+}
+
+func (r *recordRevisionRepositoryImpl) BatchCreate(ctx context.Context, revisions []*entity.RecordRevision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(revisions, importBatchSize).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create revisions", err)
+	}
+	return nil
+}
+
+func (r *recordRevisionRepositoryImpl) FindByID(ctx context.Context, revisionID int64) (*entity.RecordRevision, error) {
+	var revision entity.RecordRevision
+	err := r.db.WithContext(ctx).
+		Where("id = ?", revisionID).
+		First(&revision).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find revision", err)
+	}
+
+	return &revision, nil
+}
+
+func (r *recordRevisionRepositoryImpl) FindByRecord(ctx context.Context, recordType string, recordID int64) ([]*entity.RecordRevision, error) {
+	var revisions []*entity.RecordRevision
+	err := r.db.WithContext(ctx).
+		Where("record_type = ? AND record_id = ?", recordType, recordID).
+		Order("created_at DESC, id DESC").
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find record revisions", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *recordRevisionRepositoryImpl) FindByBabyAndAction(ctx context.Context, babyID int64, action string, since int64) ([]*entity.RecordRevision, error) {
+	var revisions []*entity.RecordRevision
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND action = ? AND created_at >= ?", babyID, action, since).
+		Order("created_at DESC, id DESC").
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find revisions by action", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *recordRevisionRepositoryImpl) RestoreRecord(ctx context.Context, record any, expectedUpdatedAt int64) (bool, error) {
+	query := r.db.WithContext(ctx).
+		Model(record).
+		Select("*").
+		Omit("id", "baby_id", "created_by", "created_at", "deleted_at")
+	if expectedUpdatedAt > 0 {
+		query = query.Where("updated_at = ?", expectedUpdatedAt)
+	}
+
+	result := query.Updates(record)
+	if result.Error != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to restore record", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := r.db.WithContext(ctx).First(record).Error; err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to reload restored record", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// recordTrashRepositoryImpl 回收站仓储实现
+type recordTrashRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordTrashRepository 创建回收站仓储
+func NewRecordTrashRepository(db *gorm.DB) repository.RecordTrashRepository {
+	return &recordTrashRepositoryImpl{db: db}
+}
+
+func (r *recordTrashRepositoryImpl) FindDeleted(ctx context.Context, records any, babyID, deletedSince int64) error {
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("baby_id = ? AND deleted_at > 0 AND deleted_at >= ?", babyID, deletedSince).
+		Order("deleted_at DESC").
+		Find(records).Error
+
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to find deleted records", err)
+	}
+	return nil
+}
+
+func (r *recordTrashRepositoryImpl) FindDeletedByID(ctx context.Context, record any, recordID int64) error {
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ? AND deleted_at > 0", recordID).
+		First(record).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to find deleted record", err)
+	}
+	return nil
+}
+
+func (r *recordTrashRepositoryImpl) Restore(ctx context.Context, record any, recordType string, recordID, deletedAt int64) (bool, error) {
+	restored := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Model(record).
+			Where("id = ? AND deleted_at = ?", recordID, deletedAt).
+			Update("deleted_at", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		restored = true
+
+		// 记录删除时级联软删除的附件、评论和表情回应,删除时间不早于记录本身
+		for _, model := range []any{&entity.Attachment{}, &entity.RecordComment{}, &entity.RecordReaction{}} {
+			if err := tx.Unscoped().
+				Model(model).
+				Where("record_type = ? AND record_id = ? AND deleted_at >= ?", recordType, recordID, deletedAt).
+				Update("deleted_at", 0).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to restore deleted record", err)
+	}
+	if !restored {
+		return false, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("id = ?", recordID).First(record).Error; err != nil {
+		return false, errors.Wrap(errors.DatabaseError, "failed to reload restored record", err)
+	}
+	return true, nil
+}
+
+func (r *recordTrashRepositoryImpl) FindPurgeable(ctx context.Context, model any, deletedBefore int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(model).
+		Where("deleted_at > 0 AND deleted_at < ?", deletedBefore).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find purgeable records", err)
+	}
+	return ids, nil
+}
+
+func (r *recordTrashRepositoryImpl) Purge(ctx context.Context, model any, recordType string, recordIDs []int64) ([]string, error) {
+	if len(recordIDs) == 0 {
+		return nil, nil
+	}
+
+	var paths []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Model(&entity.Attachment{}).
+			Where("record_type = ? AND record_id IN ?", recordType, recordIDs).
+			Pluck("path", &paths).Error; err != nil {
+			return err
+		}
+
+		for _, child := range []any{&entity.Attachment{}, &entity.RecordComment{}, &entity.RecordReaction{}} {
+			if err := tx.Unscoped().
+				Where("record_type = ? AND record_id IN ?", recordType, recordIDs).
+				Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		if recordType == entity.AttachmentRecordTypeVaccine {
+			if err := tx.Unscoped().
+				Where("schedule_id IN ?", recordIDs).
+				Delete(&entity.VaccineReactionFollowUp{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().
+			Where("id IN ? AND deleted_at > 0", recordIDs).
+			Delete(model).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to purge deleted records", err)
+	}
+	return paths, nil
+}
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// shareLinkRepositoryImpl 只读分享链接仓储实现
+type shareLinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewShareLinkRepository 创建只读分享链接仓储
+func NewShareLinkRepository(db *gorm.DB) repository.ShareLinkRepository {
+	return &shareLinkRepositoryImpl{db: db}
+}
+
+func (r *shareLinkRepositoryImpl) Create(ctx context.Context, link *entity.ShareLink) error {
+	if err := r.db.WithContext(ctx).Create(link).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create share link", err)
+	}
+	return nil
+}
+
+func (r *shareLinkRepositoryImpl) FindByID(ctx context.Context, linkID int64) (*entity.ShareLink, error) {
+	var link entity.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("id = ?", linkID).
+		First(&link).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find share link", err)
+	}
+
+	return &link, nil
+}
+
+func (r *shareLinkRepositoryImpl) FindByBabyID(ctx context.Context, babyID int64, limit int) ([]*entity.ShareLink, error) {
+	var links []*entity.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ?", babyID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&links).Error
+
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find share links", err)
+	}
+
+	return links, nil
+}
+
+func (r *shareLinkRepositoryImpl) Revoke(ctx context.Context, linkID int64, revokedAt int64) error {
+	// 只更新撤销时间,避免覆盖并发访问累加的访问次数
+	err := r.db.WithContext(ctx).
+		Model(&entity.ShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", linkID).
+		UpdateColumn("revoked_at", revokedAt).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to revoke share link", err)
+	}
+	return nil
+}
+
+func (r *shareLinkRepositoryImpl) RecordAccess(ctx context.Context, log *entity.ShareAccessLog) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+		if log.Result != entity.ShareAccessAllowed {
+			return nil
+		}
+		return tx.Model(&entity.ShareLink{}).
+			Where("id = ?", log.ShareLinkID).
+			UpdateColumns(map[string]any{
+				"access_count":     gorm.Expr("access_count + 1"),
+				"last_accessed_at": log.AccessedAt,
+			}).Error
+	})
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to record share access", err)
+	}
+	return nil
+}
+
+func (r *shareLinkRepositoryImpl) FindAccessLogs(ctx context.Context, linkID int64, page, pageSize int) ([]*entity.ShareAccessLog, int64, error) {
+	var logs []*entity.ShareAccessLog
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&entity.ShareAccessLog{}).
+		Where("share_link_id = ?", linkID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(errors.DatabaseError, "failed to count share access logs", err)
+	}
+
+	err := query.
+		Order("accessed_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, errors.Wrap(errors.DatabaseError, "failed to find share access logs", err)
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// vaccinePlanRepositoryImpl 疫苗计划仓储实现
+type vaccinePlanRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewVaccinePlanRepository 创建疫苗计划仓储
+func NewVaccinePlanRepository(db *gorm.DB) repository.VaccinePlanRepository {
+	return &vaccinePlanRepositoryImpl{db: db}
+}
+
+func (r *vaccinePlanRepositoryImpl) FindByID(ctx context.Context, planID int64) (*entity.VaccinePlan, error) {
+	return r.findOne(r.db.WithContext(ctx).Where("id = ?", planID))
+}
+
+func (r *vaccinePlanRepositoryImpl) FindDefault(ctx context.Context) (*entity.VaccinePlan, error) {
+	return r.findOne(r.db.WithContext(ctx).Where("is_default = ?", true).Order("version DESC"))
+}
+
+func (r *vaccinePlanRepositoryImpl) FindByCode(ctx context.Context, code string, version int) (*entity.VaccinePlan, error) {
+	query := r.db.WithContext(ctx).Where("code = ?", code)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+	return r.findOne(query.Order("version DESC"))
+}
+
+func (r *vaccinePlanRepositoryImpl) findOne(query *gorm.DB) (*entity.VaccinePlan, error) {
+	var plan entity.VaccinePlan
+	err := query.First(&plan).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find vaccine plan", err)
+	}
+
+	return &plan, nil
+}
+
+func (r *vaccinePlanRepositoryImpl) FindLatest(ctx context.Context, region string) ([]*entity.VaccinePlan, error) {
+	latest := r.db.Model(&entity.VaccinePlan{}).
+		Select("code, MAX(version) AS version").
+		Group("code")
+
+	query := r.db.WithContext(ctx).
+		Joins("JOIN (?) AS latest ON latest.code = vaccine_plans.code AND latest.version = vaccine_plans.version", latest)
+	if region != "" {
+		query = query.Where("vaccine_plans.region = ?", region)
+	}
+
+	var plans []*entity.VaccinePlan
+	err := query.
+		Order("vaccine_plans.is_default DESC, vaccine_plans.region ASC, vaccine_plans.code ASC").
+		Find(&plans).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "failed to find vaccine plans", err)
+	}
+
+	return plans, nil
+}
+
+func (r *vaccinePlanRepositoryImpl) MaxVersion(ctx context.Context, code string) (int, error) {
+	var version int
+	err := r.db.WithContext(ctx).
+		Model(&entity.VaccinePlan{}).
+		Unscoped(). // 已删除的版本号也不再复用
+		Where("code = ?", code).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	if err != nil {
+		return 0, errors.Wrap(errors.DatabaseError, "failed to query vaccine plan version", err)
+	}
+	return version, nil
+}
+
+func (r *vaccinePlanRepositoryImpl) CreateWithTemplates(ctx context.Context, plan *entity.VaccinePlan, templates []*entity.VaccinePlanTemplate) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if plan.IsDefault {
+			if err := tx.Model(&entity.VaccinePlan{}).
+				Where("is_default = ?", true).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(plan).Error; err != nil {
+			return err
+		}
+		for _, template := range templates {
+			template.PlanID = plan.ID
+		}
+		if len(templates) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(templates, 100).Error
+	})
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "failed to create vaccine plan", err)
+	}
+	return nil
+}
@@ -45,3 +45,12 @@ func (r *vaccinePlanTemplateRepositoryImpl) BatchCreate(ctx context.Context, tem
 	}
 	return r.db.WithContext(ctx).CreateInBatches(templates, 100).Error
 }
+
+func (r *vaccinePlanTemplateRepositoryImpl) FindByPlanID(ctx context.Context, planID int64) ([]*entity.VaccinePlanTemplate, error) {
+	var templates []*entity.VaccinePlanTemplate
+	err := r.db.WithContext(ctx).
+		Where("plan_id = ?", planID).
+		Order("sort_order ASC, age_in_months ASC, dose_number ASC").
+		Find(&templates).Error
+	return templates, err
+}
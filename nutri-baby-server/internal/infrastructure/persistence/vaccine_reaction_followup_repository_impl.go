@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
+	"github.com/wxlbd/nutri-baby-server/internal/domain/repository"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+)
+
+// vaccineReactionFollowUpRepositoryImpl 接种后反应随访仓储实现
+type vaccineReactionFollowUpRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewVaccineReactionFollowUpRepository 创建接种后反应随访仓储
+func NewVaccineReactionFollowUpRepository(db *gorm.DB) repository.VaccineReactionFollowUpRepository {
+	return &vaccineReactionFollowUpRepositoryImpl{db: db}
+}
+
+// BatchCreate 批量创建随访
+func (r *vaccineReactionFollowUpRepositoryImpl) BatchCreate(ctx context.Context, followUps []*entity.VaccineReactionFollowUp) error {
+	if len(followUps) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Create(&followUps).Error; err != nil {
+		return errors.Wrap(errors.DatabaseError, "创建接种反应随访失败", err)
+	}
+	return nil
+}
+
+// FindByID 根据ID查找随访
+func (r *vaccineReactionFollowUpRepositoryImpl) FindByID(ctx context.Context, followUpID int64) (*entity.VaccineReactionFollowUp, error) {
+	var followUp entity.VaccineReactionFollowUp
+	err := r.db.WithContext(ctx).
+		Where("id = ?", followUpID).
+		First(&followUp).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询接种反应随访失败", err)
+	}
+	return &followUp, nil
+}
+
+// FindByScheduleID 查找日程的全部随访
+func (r *vaccineReactionFollowUpRepositoryImpl) FindByScheduleID(ctx context.Context, scheduleID int64) ([]*entity.VaccineReactionFollowUp, error) {
+	var followUps []*entity.VaccineReactionFollowUp
+	err := r.db.WithContext(ctx).
+		Where("schedule_id = ?", scheduleID).
+		Order("checkpoint_hours ASC").
+		Find(&followUps).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询接种反应随访失败", err)
+	}
+	return followUps, nil
+}
+
+// FindDuePrompts 查找到期待提醒的随访
+func (r *vaccineReactionFollowUpRepositoryImpl) FindDuePrompts(ctx context.Context, from, to int64) ([]*entity.VaccineReactionFollowUp, error) {
+	var followUps []*entity.VaccineReactionFollowUp
+	err := r.db.WithContext(ctx).
+		Where("due_at BETWEEN ? AND ?", from, to).
+		Where("prompt_sent_at IS NULL AND recorded_at IS NULL").
+		Order("due_at ASC").
+		Find(&followUps).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询待提醒的接种反应随访失败", err)
+	}
+	return followUps, nil
+}
+
+// FindUnrecordedByBabyID 查找宝宝到期前尚未记录的随访
+func (r *vaccineReactionFollowUpRepositoryImpl) FindUnrecordedByBabyID(ctx context.Context, babyID, dueBefore int64) ([]*entity.VaccineReactionFollowUp, error) {
+	var followUps []*entity.VaccineReactionFollowUp
+	err := r.db.WithContext(ctx).
+		Where("baby_id = ? AND due_at <= ? AND recorded_at IS NULL", babyID, dueBefore).
+		Order("due_at ASC").
+		Find(&followUps).Error
+	if err != nil {
+		return nil, errors.Wrap(errors.DatabaseError, "查询未记录的接种反应随访失败", err)
+	}
+	return followUps, nil
+}
+
+// MarkPromptSent 标记已发送提醒
+func (r *vaccineReactionFollowUpRepositoryImpl) MarkPromptSent(ctx context.Context, followUpID, sentAt int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.VaccineReactionFollowUp{}).
+		Where("id = ?", followUpID).
+		Update("prompt_sent_at", sentAt).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "更新接种反应随访提醒状态失败", err)
+	}
+	return nil
+}
+
+// Update 保存随访记录
+func (r *vaccineReactionFollowUpRepositoryImpl) Update(ctx context.Context, followUp *entity.VaccineReactionFollowUp) error {
+	err := r.db.WithContext(ctx).
+		Model(followUp).
+		Select("temperature_c", "swelling", "fussiness", "note", "recorded_by", "recorded_at").
+		Updates(followUp).Error
+	if err != nil {
+		return errors.Wrap(errors.DatabaseError, "更新接种反应随访失败", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage 本地文件系统存储
+// 文件通过 {baseURL}/uploads/{key} 访问,私密文件需携带 expires 和 signature 参数
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewLocalStorage 创建本地文件系统存储
+func NewLocalStorage(root, baseURL, secret string) *LocalStorage {
+	if root == "" {
+		root = "uploads/"
+	}
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  []byte(secret),
+		now:     time.Now,
+	}
+}
+
+// Root 存储根目录
+func (s *LocalStorage) Root() string {
+	return s.root
+}
+
+// FilePath 返回 key 对应的本地文件路径
+func (s *LocalStorage) FilePath(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put 写入文件
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	filePath, err := s.FilePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// Get 读取文件
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	filePath, err := s.FilePath(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Delete 删除文件
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.FilePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// URL 公开访问URL
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/uploads/" + strings.TrimPrefix(key, "/")
+}
+
+// SignedURL 带 HMAC 签名的限时访问URL,由 /uploads 路由校验
+func (s *LocalStorage) SignedURL(key string, expires time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expiresAt := s.now().Add(expires).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", s.sign(key, expiresAt))
+	return s.URL(key) + "?" + query.Encode(), nil
+}
+
+// VerifySignature 校验签名URL参数,签名错误或已过期时返回 false
+func (s *LocalStorage) VerifySignature(key, expires, signature string) bool {
+	key, err := CleanKey(key)
+	if err != nil {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(s.sign(key, expiresAt)), []byte(signature))
+}
+
+// sign 计算签名: HMAC-SHA256(key + "\n" + expiresAt)
+func (s *LocalStorage) sign(key string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expiresAt, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
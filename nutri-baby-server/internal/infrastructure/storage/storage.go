@@ -0,0 +1,73 @@
+// Package storage 上传文件存储后端
+//
+// 默认使用本地文件系统(由 /uploads 路由提供访问),也可切换为 S3 兼容的对象存储(如 MinIO),
+// 以便多实例部署或运行在无持久磁盘的容器中。
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/config"
+)
+
+// 存储驱动
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage 文件存储接口
+// key 为相对路径,如 images/users/xxx.jpg,统一使用 / 分隔
+type Storage interface {
+	// Put 写入对象,已存在时覆盖
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get 读取对象,不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 删除对象,不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// URL 公开访问URL(头像、小程序码等非私密文件)
+	URL(key string) string
+	// SignedURL 带签名的限时访问URL(宝宝照片等私密文件)
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// NewStorage 根据配置创建存储后端
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.Upload.StoragePath, cfg.Server.BaseURL, SigningSecret(cfg)), nil
+	case DriverS3:
+		return NewS3Storage(cfg.Storage.S3)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Storage.Driver)
+	}
+}
+
+// SigningSecret 本地存储签名URL使用的密钥,未配置时复用 JWT 密钥
+func SigningSecret(cfg *config.Config) string {
+	if cfg.Storage.SigningSecret != "" {
+		return cfg.Storage.SigningSecret
+	}
+	return cfg.JWT.Secret
+}
+
+// CleanKey 校验并规范化对象 key,拒绝路径穿越
+func CleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/")
+	if key == "" {
+		return "", fmt.Errorf("storage: empty key")
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return key, nil
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/wxlbd/nutri-baby-server/internal/application/service"
 	"github.com/wxlbd/nutri-baby-server/internal/domain/entity"
-	"github.com/wxlbd/nutri-baby-server/pkg/errors"
 	"github.com/wxlbd/nutri-baby-server/pkg/response"
 )
 
@@ -50,13 +49,7 @@ func (h *AIAnalysisHandler) CreateAnalysis(c *gin.Context) {
 		return
 	}
 
-	// 验证权限
-	if err := h.checkPermission(c, req.BabyID); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	result, err := h.aiAnalysisService.CreateAnalysis(c.Request.Context(), &req)
+	result, err := h.aiAnalysisService.CreateAnalysis(c.Request.Context(), c.GetString("openid"), &req)
 	if err != nil {
 		h.logger.Error("使用工具调用创建AI分析任务失败",
 			zap.Error(err),
@@ -85,7 +78,11 @@ func (h *AIAnalysisHandler) CreateAnalysis(c *gin.Context) {
 // @Failure 500 {object} response.Response
 // @Router /api/ai/enhanced/daily-tips [post]
 func (h *AIAnalysisHandler) GenerateDailyTips(c *gin.Context) {
-	babyID, err := strconv.ParseInt(c.Query("baby_id"), 10, 64)
+	babyIDStr := c.Param("babyId")
+	if babyIDStr == "" {
+		babyIDStr = c.Query("baby_id")
+	}
+	babyID, err := strconv.ParseInt(babyIDStr, 10, 64)
 	if err != nil {
 		response.ErrorWithMessage(c, 1001, "无效的宝宝ID")
 		return
@@ -103,13 +100,7 @@ func (h *AIAnalysisHandler) GenerateDailyTips(c *gin.Context) {
 		date = time.Now()
 	}
 
-	// 验证权限
-	if err := h.checkPermission(c, babyID); err != nil {
-		response.Error(c, errors.ErrPermissionDenied)
-		return
-	}
-
-	result, err := h.aiAnalysisService.GenerateDailyTips(c.Request.Context(), strconv.FormatInt(babyID, 10), date)
+	result, err := h.aiAnalysisService.GetDailyTips(c.Request.Context(), c.GetString("openid"), strconv.FormatInt(babyID, 10), date)
 	if err != nil {
 		h.logger.Error("使用工具调用生成每日建议失败",
 			zap.Error(err),
@@ -163,12 +154,6 @@ func (h *AIAnalysisHandler) TestToolCalling(c *gin.Context) {
 		return
 	}
 
-	// 验证权限
-	if err := h.checkPermission(c, babyID); err != nil {
-		response.Error(c, err)
-		return
-	}
-
 	// 创建一个测试分析请求
 	req := &service.CreateAnalysisRequest{
 		BabyID:       babyID,
@@ -177,7 +162,7 @@ func (h *AIAnalysisHandler) TestToolCalling(c *gin.Context) {
 		EndDate:      service.CustomTime{Time: time.Now()},
 	}
 
-	result, err := h.aiAnalysisService.CreateAnalysis(c.Request.Context(), req)
+	result, err := h.aiAnalysisService.CreateAnalysis(c.Request.Context(), c.GetString("openid"), req)
 	if err != nil {
 		h.logger.Error("工具调用测试失败",
 			zap.Error(err),
@@ -196,7 +181,7 @@ func (h *AIAnalysisHandler) TestToolCalling(c *gin.Context) {
 // GetAnalysisResult 获取分析结果
 func (h *AIAnalysisHandler) GetAnalysisResult(c *gin.Context) {
 	analysisID := c.Param("id")
-	result, err := h.aiAnalysisService.GetAnalysisResult(c.Request.Context(), analysisID)
+	result, err := h.aiAnalysisService.GetAnalysisResult(c.Request.Context(), c.GetString("openid"), analysisID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -221,7 +206,7 @@ func (h *AIAnalysisHandler) GetAnalysisResult(c *gin.Context) {
 func (h *AIAnalysisHandler) GetAnalysisStatus(c *gin.Context) {
 	analysisID := c.Param("id")
 
-	status, err := h.aiAnalysisService.GetAnalysisStatus(c.Request.Context(), analysisID)
+	status, err := h.aiAnalysisService.GetAnalysisStatus(c.Request.Context(), c.GetString("openid"), analysisID)
 	if err != nil {
 		h.logger.Error("获取分析状态失败",
 			zap.String("analysis_id", analysisID),
@@ -239,18 +224,7 @@ func (h *AIAnalysisHandler) GetLatestAnalysis(c *gin.Context) {
 	babyID := c.Param("babyId")
 	analysisType := entity.AIAnalysisType(c.Query("type"))
 
-	id, err := strconv.ParseInt(babyID, 10, 64)
-	if err != nil {
-		response.ErrorWithMessage(c, 1001, "无效的宝宝ID")
-		return
-	}
-
-	if err := h.checkPermission(c, id); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	result, err := h.aiAnalysisService.GetLatestAnalysis(c.Request.Context(), babyID, analysisType)
+	result, err := h.aiAnalysisService.GetLatestAnalysis(c.Request.Context(), c.GetString("openid"), babyID, analysisType)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -263,18 +237,7 @@ func (h *AIAnalysisHandler) GetAnalysisStats(c *gin.Context) {
 	babyID := c.Param("babyId")
 	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
 
-	id, err := strconv.ParseInt(babyID, 10, 64)
-	if err != nil {
-		response.ErrorWithMessage(c, 1001, "无效的宝宝ID")
-		return
-	}
-
-	if err := h.checkPermission(c, id); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	result, err := h.aiAnalysisService.GetAnalysisStats(c.Request.Context(), babyID, days)
+	result, err := h.aiAnalysisService.GetAnalysisStats(c.Request.Context(), c.GetString("openid"), babyID, days)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -290,12 +253,7 @@ func (h *AIAnalysisHandler) BatchAnalyze(c *gin.Context) {
 		return
 	}
 
-	if err := h.checkPermission(c, req.BabyID); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	result, err := h.aiAnalysisService.BatchAnalyze(c.Request.Context(), &req)
+	result, err := h.aiAnalysisService.BatchAnalyze(c.Request.Context(), c.GetString("openid"), &req)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -314,18 +272,7 @@ func (h *AIAnalysisHandler) GetDailyTips(c *gin.Context) {
 		return
 	}
 
-	id, err := strconv.ParseInt(babyID, 10, 64)
-	if err != nil {
-		response.ErrorWithMessage(c, 1001, "无效的宝宝ID")
-		return
-	}
-
-	if err := h.checkPermission(c, id); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	result, err := h.aiAnalysisService.GetDailyTips(c.Request.Context(), babyID, date)
+	result, err := h.aiAnalysisService.GetDailyTips(c.Request.Context(), c.GetString("openid"), babyID, date)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -333,27 +280,6 @@ func (h *AIAnalysisHandler) GetDailyTips(c *gin.Context) {
 	response.Success(c, result)
 }
 
-// checkPermission 检查权限
-func (h *AIAnalysisHandler) checkPermission(c *gin.Context, babyID int64) error {
-	// 从上下文中获取当前用户的openid (由auth中间件设置)
-	openid, exists := c.Get("openid")
-	if !exists {
-		return errors.ErrUnauthorized
-	}
-
-	openidStr, ok := openid.(string)
-	if !ok || openidStr == "" {
-		return errors.ErrUnauthorized
-	}
-
-	// 检查用户是否有权限访问该宝宝的数据
-	// 这里需要调用权限检查服务，暂时直接返回nil
-	// TODO: 实现完整的权限检查逻辑
-	_ = openidStr
-	_ = babyID
-	return nil
-}
-
 // RegisterAIAnalysisRoutes 注册AI分析路由
 func RegisterAIAnalysisRoutes(router *gin.RouterGroup, handler *AIAnalysisHandler) {
 	aiGroup := router.Group("/ai")
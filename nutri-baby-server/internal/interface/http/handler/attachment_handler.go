@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// AttachmentHandler 照片附件处理器
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+// NewAttachmentHandler 创建照片附件处理器
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment 为记录上传照片
+// @Router /v1/attachments/:recordType/:recordId [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	var params dto.AttachmentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "Please select a file to upload")
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), openID, params.RecordType, params.RecordID, fileHeader)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, attachment)
+}
+
+// GetRecordAttachments 获取记录的照片列表
+// @Router /v1/attachments/:recordType/:recordId [get]
+func (h *AttachmentHandler) GetRecordAttachments(c *gin.Context) {
+	var params dto.AttachmentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	attachments, err := h.attachmentService.GetRecordAttachments(c.Request.Context(), openID, params.RecordType, params.RecordID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, attachments)
+}
+
+// DeleteAttachment 删除照片
+// @Router /v1/attachments/:attachmentId [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	attachmentID := c.Param("attachmentId")
+	openID := c.GetString("openid")
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), openID, attachmentID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetGallery 获取宝宝相册(按月分页)
+// @Router /v1/babies/:babyId/gallery [get]
+func (h *AttachmentHandler) GetGallery(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var query dto.GalleryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	gallery, err := h.attachmentService.GetGallery(c.Request.Context(), openID, babyID, &query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gallery)
+}
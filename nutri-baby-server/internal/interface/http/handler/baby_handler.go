@@ -12,13 +12,15 @@ import (
 type BabyHandler struct {
 	babyService   *service.BabyService
 	wechatService *service.WechatService
+	expiryService *service.AccessExpiryService
 }
 
 // NewBabyHandler 创建宝宝处理器
-func NewBabyHandler(babyService *service.BabyService, wechatService *service.WechatService) *BabyHandler {
+func NewBabyHandler(babyService *service.BabyService, wechatService *service.WechatService, expiryService *service.AccessExpiryService) *BabyHandler {
 	return &BabyHandler{
 		babyService:   babyService,
 		wechatService: wechatService,
+		expiryService: expiryService,
 	}
 }
 
@@ -72,6 +74,7 @@ func (h *BabyHandler) GetBabyDetail(c *gin.Context) {
 }
 
 // UpdateBaby 更新宝宝
+// 修改出生日期时返回疫苗日程的调整明细
 // @Router /v1/babies/:babyId [put]
 func (h *BabyHandler) UpdateBaby(c *gin.Context) {
 	babyID := c.Param("babyId")
@@ -83,12 +86,13 @@ func (h *BabyHandler) UpdateBaby(c *gin.Context) {
 		return
 	}
 
-	if err := h.babyService.UpdateBaby(c.Request.Context(), babyID, openID, &req); err != nil {
+	result, err := h.babyService.UpdateBaby(c.Request.Context(), babyID, openID, &req)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, nil)
+	response.Success(c, result)
 }
 
 // DeleteBaby 删除宝宝
@@ -152,15 +156,35 @@ func (h *BabyHandler) JoinBaby(c *gin.Context) {
 		return
 	}
 
-	baby, err := h.babyService.JoinBaby(c.Request.Context(), openID, &req)
+	baby, joinRequest, err := h.babyService.JoinBaby(c.Request.Context(), openID, &req)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	// 需要管理员审批时返回待审批的加入申请
+	if joinRequest != nil {
+		response.Success(c, joinRequest)
+		return
+	}
+
 	response.Success(c, baby)
 }
 
+// LeaveBaby 退出宝宝亲友团
+// @Router /v1/babies/:babyId/leave [post]
+func (h *BabyHandler) LeaveBaby(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	if err := h.babyService.LeaveBaby(c.Request.Context(), babyID, openID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
 // RemoveCollaborator 移除协作者
 // @Router /v1/babies/:babyId/collaborators/:openid [delete]
 func (h *BabyHandler) RemoveCollaborator(c *gin.Context) {
@@ -184,7 +208,7 @@ func (h *BabyHandler) UpdateCollaboratorRole(c *gin.Context) {
 	openID := c.GetString("openid")
 
 	var req struct {
-		Role string `json:"role" binding:"required,oneof=admin editor viewer"`
+		Role string `json:"role" binding:"required,oneof=admin editor caregiver viewer"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
@@ -220,6 +244,28 @@ func (h *BabyHandler) UpdateFamilyMember(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// ExtendCollaboratorAccess 延长临时成员的访问期限(仅管理员)
+// @Router /v1/babies/:babyId/collaborators/:openid/expiry [put]
+func (h *BabyHandler) ExtendCollaboratorAccess(c *gin.Context) {
+	babyID := c.Param("babyId")
+	targetOpenID := c.Param("openid")
+	openID := c.GetString("openid")
+
+	var req dto.ExtendCollaboratorAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	collaborator, err := h.expiryService.ExtendAccess(c.Request.Context(), babyID, openID, targetOpenID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, collaborator)
+}
+
 // GenerateInviteQRCode 生成邀请小程序码
 // @Router /v1/babies/:babyId/qrcode [get]
 func (h *BabyHandler) GenerateInviteQRCode(c *gin.Context) {
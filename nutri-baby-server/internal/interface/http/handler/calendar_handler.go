@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// CalendarHandler 日历订阅处理器
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+// NewCalendarHandler 创建日历订阅处理器
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetFeed 获取当前用户的日历订阅地址
+// @Router /v1/calendar/feed [get]
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	openID := c.GetString("openid")
+
+	result, err := h.calendarService.GetFeed(c.Request.Context(), openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RegenerateFeed 重新生成日历订阅地址,旧地址失效
+// @Router /v1/calendar/feed/regenerate [post]
+func (h *CalendarHandler) RegenerateFeed(c *gin.Context) {
+	openID := c.GetString("openid")
+
+	result, err := h.calendarService.RegenerateFeed(c.Request.Context(), openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetICS 日历应用拉取 ICS 内容(公开访问,凭订阅密钥)
+// @Router /v1/calendar/ics/:token [get]
+func (h *CalendarHandler) GetICS(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var req dto.CalendarFeedQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	content, err := h.calendarService.RenderFeed(c.Request.Context(), token, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", content)
+}
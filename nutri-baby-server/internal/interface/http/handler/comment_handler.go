@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// CommentHandler 记录评论与表情回应处理器
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+// NewCommentHandler 创建记录评论处理器
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// GetRecordComments 获取记录的评论和表情回应
+// @Router /v1/comments/:recordType/:recordId [get]
+func (h *CommentHandler) GetRecordComments(c *gin.Context) {
+	var params dto.CommentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	comments, err := h.commentService.GetRecordComments(c.Request.Context(), openID, params.RecordType, params.RecordID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, comments)
+}
+
+// CreateComment 发表评论或回复
+// @Router /v1/comments/:recordType/:recordId [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var params dto.CommentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	comment, err := h.commentService.CreateComment(c.Request.Context(), openID, params.RecordType, params.RecordID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+// @Router /v1/comments/:commentId [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID := c.Param("commentId")
+	openID := c.GetString("openid")
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), openID, commentID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AddReaction 添加表情回应
+// @Router /v1/reactions/:recordType/:recordId [post]
+func (h *CommentHandler) AddReaction(c *gin.Context) {
+	var params dto.CommentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	var req dto.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	reactions, err := h.commentService.AddReaction(c.Request.Context(), openID, params.RecordType, params.RecordID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reactions)
+}
+
+// RemoveReaction 取消表情回应
+// @Router /v1/reactions/:recordType/:recordId [delete]
+func (h *CommentHandler) RemoveReaction(c *gin.Context) {
+	var params dto.CommentRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	var req dto.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	reactions, err := h.commentService.RemoveReaction(c.Request.Context(), openID, params.RecordType, params.RecordID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reactions)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// ExportHandler 数据导出处理器
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler 创建数据导出处理器
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// CreateExport 创建宝宝数据导出任务
+// @Router /v1/babies/:babyId/exports [post]
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	job, err := h.exportService.CreateExport(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// ListExports 获取宝宝的导出任务列表
+// @Router /v1/babies/:babyId/exports [get]
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	jobs, err := h.exportService.ListExports(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs)
+}
+
+// GetExport 查询导出任务状态及下载链接
+// @Router /v1/exports/:exportId [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	exportID := c.Param("exportId")
+	openID := c.GetString("openid")
+
+	job, err := h.exportService.GetExport(c.Request.Context(), openID, exportID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
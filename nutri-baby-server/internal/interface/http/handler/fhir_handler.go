@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// maxFHIRImportSize FHIR 导入文件大小上限
+const maxFHIRImportSize = 5 << 20
+
+// FHIRHandler FHIR 导出导入处理器
+type FHIRHandler struct {
+	fhirService *service.FHIRService
+}
+
+// NewFHIRHandler 创建 FHIR 处理器
+func NewFHIRHandler(fhirService *service.FHIRService) *FHIRHandler {
+	return &FHIRHandler{
+		fhirService: fhirService,
+	}
+}
+
+// Export 导出宝宝的 FHIR R4 Bundle
+// @Router /v1/babies/:babyId/fhir [get]
+func (h *FHIRHandler) Export(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.FHIRRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	content, filename, err := h.fhirService.Export(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/fhir+json", content)
+}
+
+// ImportImmunizations 从 FHIR Immunization Bundle 导入接种记录
+// 请求体为 FHIR JSON,dryRun=true 时只返回匹配结果
+// @Router /v1/babies/:babyId/fhir/immunizations [post]
+func (h *FHIRHandler) ImportImmunizations(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.FHIRImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxFHIRImportSize+1))
+	if err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "读取请求体失败: "+err.Error())
+		return
+	}
+	if len(data) == 0 {
+		response.ErrorWithMessage(c, errors.ParamError, "请求体不能为空")
+		return
+	}
+	if len(data) > maxFHIRImportSize {
+		response.ErrorWithMessage(c, errors.ParamError, "文件过大,最大支持5MB")
+		return
+	}
+
+	result, err := h.fhirService.ImportImmunizations(c.Request.Context(), openID, babyID, data, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// ImportHandler 数据导入处理器
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+// NewImportHandler 创建数据导入处理器
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ListParsers 获取支持的导入格式
+// @Router /v1/imports/parsers [get]
+func (h *ImportHandler) ListParsers(c *gin.Context) {
+	response.Success(c, h.importService.ListParsers())
+}
+
+// Import 导入其他应用的记录(dryRun=true 时仅预览)
+// @Router /v1/babies/:babyId/imports [post]
+func (h *ImportHandler) Import(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "请选择要导入的文件")
+		return
+	}
+
+	result, err := h.importService.Import(c.Request.Context(), openID, babyID, fileHeader, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
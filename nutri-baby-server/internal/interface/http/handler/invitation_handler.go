@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// InvitationHandler 邀请管理处理器
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+}
+
+// NewInvitationHandler 创建邀请管理处理器
+func NewInvitationHandler(invitationService *service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// ListInvitations 获取宝宝当前可用的邀请(仅管理员)
+// @Router /v1/babies/:babyId/invitations [get]
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	invitations, err := h.invitationService.ListInvitations(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, invitations)
+}
+
+// RevokeInvitation 撤销邀请
+// @Router /v1/babies/:babyId/invitations/:invitationId [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	babyID := c.Param("babyId")
+	invitationID := c.Param("invitationId")
+	openID := c.GetString("openid")
+
+	if err := h.invitationService.RevokeInvitation(c.Request.Context(), openID, babyID, invitationID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListJoinRequests 获取待审批的加入申请(仅管理员)
+// @Router /v1/babies/:babyId/join-requests [get]
+func (h *InvitationHandler) ListJoinRequests(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	requests, err := h.invitationService.ListJoinRequests(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, requests)
+}
+
+// ApproveJoinRequest 通过加入申请(仅管理员)
+// @Router /v1/babies/:babyId/join-requests/:requestId/approve [post]
+func (h *InvitationHandler) ApproveJoinRequest(c *gin.Context) {
+	babyID := c.Param("babyId")
+	requestID := c.Param("requestId")
+	openID := c.GetString("openid")
+
+	request, err := h.invitationService.ApproveJoinRequest(c.Request.Context(), openID, babyID, requestID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// RejectJoinRequest 拒绝加入申请(仅管理员)
+// @Router /v1/babies/:babyId/join-requests/:requestId/reject [post]
+func (h *InvitationHandler) RejectJoinRequest(c *gin.Context) {
+	babyID := c.Param("babyId")
+	requestID := c.Param("requestId")
+	openID := c.GetString("openid")
+
+	request, err := h.invitationService.RejectJoinRequest(c.Request.Context(), openID, babyID, requestID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
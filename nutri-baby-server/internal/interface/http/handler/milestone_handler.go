@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// MilestoneHandler 发育里程碑处理器
+type MilestoneHandler struct {
+	milestoneService *service.MilestoneService
+}
+
+// NewMilestoneHandler 创建发育里程碑处理器
+func NewMilestoneHandler(milestoneService *service.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{
+		milestoneService: milestoneService,
+	}
+}
+
+// GetMilestones 获取宝宝的发育里程碑列表
+// @Router /v1/babies/:babyId/milestones [get]
+func (h *MilestoneHandler) GetMilestones(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var query dto.MilestoneListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	milestones, err := h.milestoneService.GetMilestones(c.Request.Context(), babyID, openID, &query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, milestones)
+}
+
+// CreateMilestone 创建自定义里程碑
+// @Router /v1/babies/:babyId/milestones [post]
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.CreateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	milestone, err := h.milestoneService.CreateMilestone(c.Request.Context(), babyID, openID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, milestone)
+}
+
+// UpdateMilestoneStatus 标记里程碑状态
+// @Router /v1/babies/:babyId/milestones/:milestoneId/status [put]
+func (h *MilestoneHandler) UpdateMilestoneStatus(c *gin.Context) {
+	babyID := c.Param("babyId")
+	milestoneID := c.Param("milestoneId")
+	openID := c.GetString("openid")
+
+	var req dto.UpdateMilestoneStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	milestone, err := h.milestoneService.UpdateMilestoneStatus(c.Request.Context(), babyID, milestoneID, openID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, milestone)
+}
+
+// DeleteMilestone 删除里程碑
+// @Router /v1/babies/:babyId/milestones/:milestoneId [delete]
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	babyID := c.Param("babyId")
+	milestoneID := c.Param("milestoneId")
+	openID := c.GetString("openid")
+
+	if err := h.milestoneService.DeleteMilestone(c.Request.Context(), babyID, milestoneID, openID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// OwnershipHandler 宝宝所有权转让处理器
+type OwnershipHandler struct {
+	ownershipService *service.OwnershipService
+}
+
+// NewOwnershipHandler 创建宝宝所有权转让处理器
+func NewOwnershipHandler(ownershipService *service.OwnershipService) *OwnershipHandler {
+	return &OwnershipHandler{
+		ownershipService: ownershipService,
+	}
+}
+
+// GetPendingTransfer 获取宝宝待处理的所有权转让
+// @Router /v1/babies/:babyId/ownership-transfer [get]
+func (h *OwnershipHandler) GetPendingTransfer(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	transfer, err := h.ownershipService.GetPendingTransfer(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, transfer)
+}
+
+// NominateOwner 提名新所有者(仅所有者)
+// @Router /v1/babies/:babyId/ownership-transfer [post]
+func (h *OwnershipHandler) NominateOwner(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.NominateOwnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	transfer, err := h.ownershipService.NominateOwner(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, transfer)
+}
+
+// CancelTransfer 取消所有权转让(仅所有者)
+// @Router /v1/babies/:babyId/ownership-transfer [delete]
+func (h *OwnershipHandler) CancelTransfer(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	if err := h.ownershipService.CancelTransfer(c.Request.Context(), openID, babyID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AcceptTransfer 接受所有权转让(仅被提名人)
+// @Router /v1/babies/:babyId/ownership-transfer/accept [post]
+func (h *OwnershipHandler) AcceptTransfer(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	transfer, err := h.ownershipService.AcceptTransfer(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, transfer)
+}
+
+// DeclineTransfer 拒绝所有权转让(仅被提名人)
+// @Router /v1/babies/:babyId/ownership-transfer/decline [post]
+func (h *OwnershipHandler) DeclineTransfer(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	transfer, err := h.ownershipService.DeclineTransfer(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, transfer)
+}
+
+// ListIncomingTransfers 获取提名给当前用户的所有权转让
+// @Router /v1/ownership-transfers [get]
+func (h *OwnershipHandler) ListIncomingTransfers(c *gin.Context) {
+	openID := c.GetString("openid")
+
+	transfers, err := h.ownershipService.ListIncomingTransfers(c.Request.Context(), openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, transfers)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// PermissionHandler 协作者权限处理器
+type PermissionHandler struct {
+	permissionService *service.PermissionService
+}
+
+// NewPermissionHandler 创建协作者权限处理器
+func NewPermissionHandler(permissionService *service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+	}
+}
+
+// ListRolePresets 获取角色预设权限
+// @Router /v1/permission-presets [get]
+func (h *PermissionHandler) ListRolePresets(c *gin.Context) {
+	response.Success(c, h.permissionService.ListRolePresets())
+}
+
+// GetMyPermissions 获取当前用户对宝宝的实际权限
+// @Router /v1/babies/:babyId/permissions [get]
+func (h *PermissionHandler) GetMyPermissions(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	permissions, err := h.permissionService.GetMyPermissions(c.Request.Context(), babyID, openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, permissions)
+}
+
+// GetCollaboratorPermissions 获取协作者的权限矩阵(仅管理员)
+// @Router /v1/babies/:babyId/collaborators/:openid/permissions [get]
+func (h *PermissionHandler) GetCollaboratorPermissions(c *gin.Context) {
+	babyID := c.Param("babyId")
+	targetOpenID := c.Param("openid")
+	openID := c.GetString("openid")
+
+	permissions, err := h.permissionService.GetCollaboratorPermissions(c.Request.Context(), babyID, openID, targetOpenID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, permissions)
+}
+
+// UpdateCollaboratorPermissions 更新协作者的权限覆盖(仅管理员)
+// @Router /v1/babies/:babyId/collaborators/:openid/permissions [put]
+func (h *PermissionHandler) UpdateCollaboratorPermissions(c *gin.Context) {
+	babyID := c.Param("babyId")
+	targetOpenID := c.Param("openid")
+	openID := c.GetString("openid")
+
+	var req dto.UpdateCollaboratorPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	permissions, err := h.permissionService.UpdateCollaboratorPermissions(c.Request.Context(), babyID, openID, targetOpenID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, permissions)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// RevisionHandler 记录修订历史处理器
+type RevisionHandler struct {
+	revisionService *service.RevisionService
+}
+
+// NewRevisionHandler 创建记录修订历史处理器
+func NewRevisionHandler(revisionService *service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+// GetRecordHistory 获取记录的修订历史
+// @Router /v1/revisions/:recordType/:recordId [get]
+func (h *RevisionHandler) GetRecordHistory(c *gin.Context) {
+	var params dto.RevisionRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	history, err := h.revisionService.GetHistory(c.Request.Context(), openID, params.RecordType, params.RecordID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, history)
+}
+
+// RestoreRevision 将记录恢复到指定的历史版本
+// @Router /v1/revisions/:recordType/:recordId/restore [post]
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	var params dto.RevisionRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	var req dto.RestoreRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	revision, err := h.revisionService.RestoreRevision(c.Request.Context(), openID, params.RecordType, params.RecordID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, revision)
+}
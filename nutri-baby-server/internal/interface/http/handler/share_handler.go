@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// ShareHandler 只读分享链接处理器
+type ShareHandler struct {
+	shareService *service.ShareService
+}
+
+// NewShareHandler 创建只读分享链接处理器
+func NewShareHandler(shareService *service.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// CreateShareLink 创建只读分享链接(仅管理员)
+// @Router /v1/babies/:babyId/share-links [post]
+func (h *ShareHandler) CreateShareLink(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	link, err := h.shareService.CreateShareLink(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, link)
+}
+
+// ListShareLinks 获取宝宝的分享链接列表(仅管理员)
+// @Router /v1/babies/:babyId/share-links [get]
+func (h *ShareHandler) ListShareLinks(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	links, err := h.shareService.ListShareLinks(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// RevokeShareLink 撤销分享链接(仅管理员)
+// @Router /v1/babies/:babyId/share-links/:shareId [delete]
+func (h *ShareHandler) RevokeShareLink(c *gin.Context) {
+	babyID := c.Param("babyId")
+	shareID := c.Param("shareId")
+	openID := c.GetString("openid")
+
+	if err := h.shareService.RevokeShareLink(c.Request.Context(), openID, babyID, shareID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetAccessLogs 获取分享链接的访问日志(仅管理员)
+// @Router /v1/babies/:babyId/share-links/:shareId/access-logs [get]
+func (h *ShareHandler) GetAccessLogs(c *gin.Context) {
+	babyID := c.Param("babyId")
+	shareID := c.Param("shareId")
+	openID := c.GetString("openid")
+
+	var req dto.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	logs, err := h.shareService.GetAccessLogs(c.Request.Context(), openID, babyID, shareID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, logs)
+}
+
+// GetSharedBaby 通过分享链接查看宝宝概况(无需登录)
+// @Router /v1/shared/:token [get]
+func (h *ShareHandler) GetSharedBaby(c *gin.Context) {
+	baby, err := h.shareService.GetSharedBaby(c.Request.Context(), c.Param("token"), shareAccessMeta(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, baby)
+}
+
+// GetSharedRecords 通过分享链接查看记录(无需登录)
+// @Router /v1/shared/:token/records [get]
+func (h *ShareHandler) GetSharedRecords(c *gin.Context) {
+	var query dto.SharedRecordsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	records, err := h.shareService.GetSharedRecords(c.Request.Context(), c.Param("token"), &query, shareAccessMeta(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, records)
+}
+
+func shareAccessMeta(c *gin.Context) *dto.ShareAccessMeta {
+	return &dto.ShareAccessMeta{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
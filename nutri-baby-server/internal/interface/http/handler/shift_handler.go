@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// ShiftHandler 照护人值班交接处理器
+type ShiftHandler struct {
+	shiftService *service.ShiftService
+}
+
+// NewShiftHandler 创建照护人值班交接处理器
+func NewShiftHandler(shiftService *service.ShiftService) *ShiftHandler {
+	return &ShiftHandler{
+		shiftService: shiftService,
+	}
+}
+
+// StartShift 开始值班(接班),返回上一位照护人值班以来的交接摘要
+// @Router /v1/babies/:babyId/shifts/start [post]
+func (h *ShiftHandler) StartShift(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.StartShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.shiftService.StartShift(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// EndShift 结束值班(交班),可指定接班照护人并推送交接摘要
+// @Router /v1/babies/:babyId/shifts/end [post]
+func (h *ShiftHandler) EndShift(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.EndShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.shiftService.EndShift(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetOnDuty 获取当前值班的照护人,无人值班时返回 null
+// @Router /v1/babies/:babyId/shifts/current [get]
+func (h *ShiftHandler) GetOnDuty(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	shift, err := h.shiftService.GetOnDuty(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, shift)
+}
+
+// ListShifts 获取最近的值班记录
+// @Router /v1/babies/:babyId/shifts [get]
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	shifts, err := h.shiftService.ListShifts(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, shifts)
+}
+
+// GetHandoffSummary 获取交接摘要(上一位照护人开始值班以来的记录和待办提醒)
+// @Router /v1/babies/:babyId/handoff-summary [get]
+func (h *ShiftHandler) GetHandoffSummary(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	summary, err := h.shiftService.GetHandoffSummary(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, summary)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
 	"github.com/wxlbd/nutri-baby-server/internal/application/service"
 	"github.com/wxlbd/nutri-baby-server/pkg/response"
 )
@@ -25,7 +26,34 @@ func (h *StatisticsHandler) GetBabyStatistics(c *gin.Context) {
 	babyID := c.Param("babyId")
 	openID := c.GetString("openid")
 
-	statistics, err := h.statisticsService.GetBabyStatistics(c.Request.Context(), babyID, openID)
+	var query dto.StatisticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	statistics, err := h.statisticsService.GetBabyStatistics(c.Request.Context(), babyID, openID, &query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, statistics)
+}
+
+// GetCaregiverStatistics 获取照护人分工统计(喂养、夜奶、换尿布、哄睡次数及时段分布)
+// @Router /v1/babies/:babyId/statistics/caregivers [get]
+func (h *StatisticsHandler) GetCaregiverStatistics(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var query dto.CaregiverStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	statistics, err := h.statisticsService.GetCaregiverStatistics(c.Request.Context(), babyID, openID, &query)
 	if err != nil {
 		response.Error(c, err)
 		return
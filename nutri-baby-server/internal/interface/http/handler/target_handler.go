@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// TargetHandler 每日目标处理器
+type TargetHandler struct {
+	targetService *service.TargetService
+}
+
+// NewTargetHandler 创建每日目标处理器
+func NewTargetHandler(targetService *service.TargetService) *TargetHandler {
+	return &TargetHandler{
+		targetService: targetService,
+	}
+}
+
+// GetTargets 获取宝宝每日目标
+// @Router /v1/babies/:babyId/targets [get]
+func (h *TargetHandler) GetTargets(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	targets, err := h.targetService.GetTargets(c.Request.Context(), babyID, openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, targets)
+}
+
+// UpdateTargets 自定义宝宝每日目标
+// @Router /v1/babies/:babyId/targets [put]
+func (h *TargetHandler) UpdateTargets(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.UpdateBabyTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, 1001, "参数错误: "+err.Error())
+		return
+	}
+
+	targets, err := h.targetService.UpdateTargets(c.Request.Context(), babyID, openID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, targets)
+}
+
+// ResetTargets 恢复系统推荐的每日目标
+// @Router /v1/babies/:babyId/targets [delete]
+func (h *TargetHandler) ResetTargets(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	targets, err := h.targetService.ResetTargets(c.Request.Context(), babyID, openID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, targets)
+}
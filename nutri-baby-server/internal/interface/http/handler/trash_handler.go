@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// TrashHandler 回收站处理器
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+// NewTrashHandler 创建回收站处理器
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetTrash 获取宝宝回收站中的已删除记录
+// @Router /v1/babies/:babyId/trash [get]
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var query dto.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	trash, err := h.trashService.GetTrash(c.Request.Context(), openID, babyID, &query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, trash)
+}
+
+// RestoreRecord 从回收站恢复记录
+// @Router /v1/babies/:babyId/trash/:recordType/:recordId/restore [post]
+func (h *TrashHandler) RestoreRecord(c *gin.Context) {
+	var params dto.TrashRecordParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	openID := c.GetString("openid")
+
+	record, err := h.trashService.RestoreRecord(c.Request.Context(), openID, &params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, record)
+}
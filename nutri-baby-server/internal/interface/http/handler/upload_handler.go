@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/wxlbd/nutri-baby-server/internal/application/service"
@@ -57,5 +59,31 @@ func (h *UploadHandler) Upload(c *gin.Context) {
 		"path":     result.Path,
 		"filename": result.Filename,
 		"size":     result.Size,
+		"width":    result.Width,
+		"height":   result.Height,
+		"variants": result.Variants,
 	})
 }
+
+// ServeFile 访问上传的文件
+// 本地存储直接返回文件,对象存储重定向到对应地址;宝宝照片需携带签名参数
+// @Router /uploads/*filepath [get]
+func (h *UploadHandler) ServeFile(c *gin.Context) {
+	localPath, redirectURL, err := h.uploadService.ResolveFile(
+		c.Request.Context(),
+		c.Param("filepath"),
+		c.Query("expires"),
+		c.Query("signature"),
+	)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if redirectURL != "" {
+		c.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
+	c.File(localPath)
+}
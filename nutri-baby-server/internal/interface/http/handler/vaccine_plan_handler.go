@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wxlbd/nutri-baby-server/internal/application/dto"
+	"github.com/wxlbd/nutri-baby-server/internal/application/service"
+	"github.com/wxlbd/nutri-baby-server/pkg/errors"
+	"github.com/wxlbd/nutri-baby-server/pkg/response"
+)
+
+// VaccinePlanHandler 疫苗计划处理器
+type VaccinePlanHandler struct {
+	planService *service.VaccinePlanService
+}
+
+// NewVaccinePlanHandler 创建疫苗计划处理器
+func NewVaccinePlanHandler(planService *service.VaccinePlanService) *VaccinePlanHandler {
+	return &VaccinePlanHandler{
+		planService: planService,
+	}
+}
+
+// ListPlans 获取可选的疫苗计划
+// @Router /v1/vaccine-plans [get]
+func (h *VaccinePlanHandler) ListPlans(c *gin.Context) {
+	var req dto.ListVaccinePlansRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.planService.ListPlans(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetPlan 获取疫苗计划详情
+// @Router /v1/vaccine-plans/:planId [get]
+func (h *VaccinePlanHandler) GetPlan(c *gin.Context) {
+	result, err := h.planService.GetPlan(c.Request.Context(), c.Param("planId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetBabyPlan 获取宝宝当前使用的疫苗计划
+// @Router /v1/babies/:babyId/vaccine-plan [get]
+func (h *VaccinePlanHandler) GetBabyPlan(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	result, err := h.planService.GetBabyPlan(c.Request.Context(), openID, babyID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// SwitchBabyPlan 切换宝宝的疫苗计划(仅管理员),dryRun=true 时只返回调整预览
+// @Router /v1/babies/:babyId/vaccine-plan [put]
+func (h *VaccinePlanHandler) SwitchBabyPlan(c *gin.Context) {
+	babyID := c.Param("babyId")
+	openID := c.GetString("openid")
+
+	var req dto.SwitchVaccinePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.planService.SwitchBabyPlan(c.Request.Context(), openID, babyID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -14,12 +14,17 @@ import (
 // VaccineScheduleHandler 疫苗接种日程处理器(新)
 type VaccineScheduleHandler struct {
 	scheduleService *service.VaccineScheduleService
+	reactionService *service.VaccineReactionService
 }
 
 // NewVaccineScheduleHandler 创建疫苗接种日程处理器实例
-func NewVaccineScheduleHandler(scheduleService *service.VaccineScheduleService) *VaccineScheduleHandler {
+func NewVaccineScheduleHandler(
+	scheduleService *service.VaccineScheduleService,
+	reactionService *service.VaccineReactionService,
+) *VaccineScheduleHandler {
 	return &VaccineScheduleHandler{
 		scheduleService: scheduleService,
+		reactionService: reactionService,
 	}
 }
 
@@ -65,7 +70,7 @@ func (h *VaccineScheduleHandler) GetVaccineSchedules(c *gin.Context) {
 // @Param babyId path string true "宝宝ID"
 // @Param scheduleId path string true "日程ID"
 // @Param request body dto.UpdateVaccineScheduleRequest true "接种记录"
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=dto.UpdateVaccineScheduleResultDTO}
 // @Router /babies/{babyId}/vaccine-schedules/{scheduleId} [put]
 func (h *VaccineScheduleHandler) UpdateVaccineSchedule(c *gin.Context) {
 	babyID := c.Param("babyId")
@@ -78,7 +83,7 @@ func (h *VaccineScheduleHandler) UpdateVaccineSchedule(c *gin.Context) {
 		return
 	}
 
-	err := h.scheduleService.UpdateVaccineSchedule(
+	result, err := h.scheduleService.UpdateVaccineSchedule(
 		c.Request.Context(),
 		babyID,
 		scheduleID,
@@ -90,7 +95,7 @@ func (h *VaccineScheduleHandler) UpdateVaccineSchedule(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, "记录疫苗接种成功")
+	response.Success(c, result)
 }
 
 // CreateCustomSchedule 创建自定义疫苗接种日程
@@ -228,3 +233,57 @@ func (h *VaccineScheduleHandler) GetReminders(c *gin.Context) {
 		"reminders": reminders,
 	})
 }
+
+// GetReactionFollowUps 获取接种后反应随访
+// @Summary 获取接种后反应随访及汇总
+// @Tags 疫苗管理
+// @Param babyId path string true "宝宝ID"
+// @Param scheduleId path string true "日程ID"
+// @Success 200 {object} response.Response{data=dto.VaccineReactionFollowUpListDTO}
+// @Router /babies/{babyId}/vaccine-schedules/{scheduleId}/reaction-followups [get]
+func (h *VaccineScheduleHandler) GetReactionFollowUps(c *gin.Context) {
+	result, err := h.reactionService.ListFollowUps(
+		c.Request.Context(),
+		c.Param("babyId"),
+		c.Param("scheduleId"),
+		c.GetString("openid"),
+	)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RecordReactionFollowUp 记录接种后反应随访
+// @Summary 记录某个随访时间点的体温、局部红肿和哭闹情况
+// @Tags 疫苗管理
+// @Param babyId path string true "宝宝ID"
+// @Param scheduleId path string true "日程ID"
+// @Param followUpId path string true "随访ID"
+// @Param request body dto.RecordVaccineReactionRequest true "随访记录"
+// @Success 200 {object} response.Response{data=dto.VaccineReactionFollowUpListDTO}
+// @Router /babies/{babyId}/vaccine-schedules/{scheduleId}/reaction-followups/{followUpId} [put]
+func (h *VaccineScheduleHandler) RecordReactionFollowUp(c *gin.Context) {
+	var req dto.RecordVaccineReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(c, errors.ParamError, fmt.Sprintf("参数错误：%s", err))
+		return
+	}
+
+	result, err := h.reactionService.RecordFollowUp(
+		c.Request.Context(),
+		c.Param("babyId"),
+		c.Param("scheduleId"),
+		c.Param("followUpId"),
+		c.GetString("openid"),
+		&req,
+	)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
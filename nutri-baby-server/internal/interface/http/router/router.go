@@ -23,6 +23,23 @@ func NewRouter(
 	syncHandler *handler.SyncHandler,
 	uploadHandler *handler.UploadHandler,
 	aiAnalysisHandler *handler.AIAnalysisHandler, // AI分析处理器
+	targetHandler *handler.TargetHandler, // 每日目标处理器
+	milestoneHandler *handler.MilestoneHandler, // 发育里程碑处理器
+	attachmentHandler *handler.AttachmentHandler, // 照片附件处理器
+	exportHandler *handler.ExportHandler, // 数据导出处理器
+	importHandler *handler.ImportHandler, // 数据导入处理器
+	reportHandler *handler.ReportHandler, // 就诊报告处理器
+	shareHandler *handler.ShareHandler, // 只读分享链接处理器
+	fhirHandler *handler.FHIRHandler, // FHIR 导出导入处理器
+	calendarHandler *handler.CalendarHandler, // 日历订阅处理器
+	vaccinePlanHandler *handler.VaccinePlanHandler, // 疫苗计划处理器
+	permissionHandler *handler.PermissionHandler, // 协作者权限处理器
+	invitationHandler *handler.InvitationHandler, // 邀请管理处理器
+	ownershipHandler *handler.OwnershipHandler, // 所有权转让处理器
+	shiftHandler *handler.ShiftHandler, // 照护人值班交接处理器
+	commentHandler *handler.CommentHandler, // 记录评论与表情回应处理器
+	revisionHandler *handler.RevisionHandler, // 记录修订历史处理器
+	trashHandler *handler.TrashHandler, // 回收站处理器
 	aiAnalysisService service.AIAnalysisService, // 添加AI分析服务依赖
 	logger *zap.Logger, // 添加logger依赖
 ) *gin.Engine {
@@ -36,8 +53,9 @@ func NewRouter(
 	r.Use(middleware.Logger())
 	r.Use(gin.Recovery())
 
-	// 静态文件服务 (用于小程序码、头像等上传文件)
-	r.Static("/uploads", "./uploads")
+	// 上传文件访问 (小程序码、头像等公开文件; 宝宝照片需签名URL)
+	r.GET("/uploads/*filepath", uploadHandler.ServeFile)
+	r.HEAD("/uploads/*filepath", uploadHandler.ServeFile)
 
 	// API v1 路由组
 	v1 := r.Group("/v1")
@@ -56,6 +74,16 @@ func NewRouter(
 			invitations.GET("/code/:shortCode", babyHandler.GetInvitationByShortCode)
 		}
 
+		// 只读分享链接（公开访问，凭签名令牌查看授权范围内的记录）
+		shared := v1.Group("/shared")
+		{
+			shared.GET("/:token", shareHandler.GetSharedBaby)
+			shared.GET("/:token/records", shareHandler.GetSharedRecords)
+		}
+
+		// 日历订阅（公开访问，日历应用凭订阅密钥拉取 ICS）
+		v1.GET("/calendar/ics/:token", calendarHandler.GetICS)
+
 		// 需要认证的路由
 		authRequired := v1.Group("")
 		authRequired.Use(middleware.Auth(cfg))
@@ -85,7 +113,35 @@ func NewRouter(
 				babies.POST("/join", babyHandler.JoinBaby) // 通过邀请码加入
 				babies.DELETE("/:babyId/collaborators/:openid", babyHandler.RemoveCollaborator)
 				babies.PUT("/:babyId/collaborators/:openid/role", babyHandler.UpdateCollaboratorRole)
-				babies.PUT("/:babyId/collaborators/:openid", babyHandler.UpdateFamilyMember) // 更新亲友团成员信息(角色+关系)
+				babies.PUT("/:babyId/collaborators/:openid", babyHandler.UpdateFamilyMember)              // 更新亲友团成员信息(角色+关系)
+				babies.PUT("/:babyId/collaborators/:openid/expiry", babyHandler.ExtendCollaboratorAccess) // 延长临时成员访问期限
+				babies.POST("/:babyId/leave", babyHandler.LeaveBaby)                                      // 退出亲友团
+
+				// 所有权转让(所有者提名,被提名人接受)
+				babies.GET("/:babyId/ownership-transfer", ownershipHandler.GetPendingTransfer)
+				babies.POST("/:babyId/ownership-transfer", ownershipHandler.NominateOwner)
+				babies.DELETE("/:babyId/ownership-transfer", ownershipHandler.CancelTransfer)
+				babies.POST("/:babyId/ownership-transfer/accept", ownershipHandler.AcceptTransfer)
+				babies.POST("/:babyId/ownership-transfer/decline", ownershipHandler.DeclineTransfer)
+
+				// 照护人值班交接
+				babies.GET("/:babyId/shifts", shiftHandler.ListShifts)
+				babies.GET("/:babyId/shifts/current", shiftHandler.GetOnDuty)
+				babies.POST("/:babyId/shifts/start", shiftHandler.StartShift)
+				babies.POST("/:babyId/shifts/end", shiftHandler.EndShift)
+				babies.GET("/:babyId/handoff-summary", shiftHandler.GetHandoffSummary)
+
+				// 协作者权限矩阵
+				babies.GET("/:babyId/permissions", permissionHandler.GetMyPermissions)
+				babies.GET("/:babyId/collaborators/:openid/permissions", permissionHandler.GetCollaboratorPermissions)
+				babies.PUT("/:babyId/collaborators/:openid/permissions", permissionHandler.UpdateCollaboratorPermissions)
+
+				// 邀请管理与加入审批
+				babies.GET("/:babyId/invitations", invitationHandler.ListInvitations)
+				babies.DELETE("/:babyId/invitations/:invitationId", invitationHandler.RevokeInvitation)
+				babies.GET("/:babyId/join-requests", invitationHandler.ListJoinRequests)
+				babies.POST("/:babyId/join-requests/:requestId/approve", invitationHandler.ApproveJoinRequest)
+				babies.POST("/:babyId/join-requests/:requestId/reject", invitationHandler.RejectJoinRequest)
 
 				// 小程序码生成
 				babies.GET("/:babyId/qrcode", babyHandler.GenerateInviteQRCode)
@@ -98,13 +154,78 @@ func NewRouter(
 				babies.DELETE("/:babyId/vaccine-schedules/:scheduleId", vaccineScheduleHandler.DeleteSchedule)
 				babies.GET("/:babyId/vaccine-schedule-statistics", vaccineScheduleHandler.GetStatistics)
 				babies.GET("/:babyId/vaccine-reminders", vaccineScheduleHandler.GetReminders)
+				babies.GET("/:babyId/vaccine-schedules/:scheduleId/reaction-followups", vaccineScheduleHandler.GetReactionFollowUps)               // 接种后反应随访
+				babies.PUT("/:babyId/vaccine-schedules/:scheduleId/reaction-followups/:followUpId", vaccineScheduleHandler.RecordReactionFollowUp) // 记录随访
+
+				// 疫苗计划选择(切换仅管理员)
+				babies.GET("/:babyId/vaccine-plan", vaccinePlanHandler.GetBabyPlan)
+				babies.PUT("/:babyId/vaccine-plan", vaccinePlanHandler.SwitchBabyPlan)
 
 				// 统计接口 (新增)
 				babies.GET("/:babyId/statistics", statisticsHandler.GetBabyStatistics)
+				babies.GET("/:babyId/statistics/caregivers", statisticsHandler.GetCaregiverStatistics) // 照护人分工统计
 				// 按日统计接口 (新增)
 				babies.GET("/:babyId/daily-stats", dailyStatsHandler.GetDailyStats)
+
+				// 每日目标(推荐值 + 自定义覆盖)
+				babies.GET("/:babyId/targets", targetHandler.GetTargets)
+				babies.PUT("/:babyId/targets", targetHandler.UpdateTargets)
+				babies.DELETE("/:babyId/targets", targetHandler.ResetTargets)
+
+				// 发育里程碑
+				babies.GET("/:babyId/milestones", milestoneHandler.GetMilestones)
+				babies.POST("/:babyId/milestones", milestoneHandler.CreateMilestone)
+				babies.PUT("/:babyId/milestones/:milestoneId/status", milestoneHandler.UpdateMilestoneStatus)
+				babies.DELETE("/:babyId/milestones/:milestoneId", milestoneHandler.DeleteMilestone)
+
+				// 宝宝相册(按月分页)
+				babies.GET("/:babyId/gallery", attachmentHandler.GetGallery)
+
+				// 数据导出(异步生成ZIP)
+				babies.POST("/:babyId/exports", exportHandler.CreateExport)
+				babies.GET("/:babyId/exports", exportHandler.ListExports)
+
+				// 从其他应用导入记录(dryRun 预览)
+				babies.POST("/:babyId/imports", importHandler.Import)
+
+				// 就诊报告(PDF)
+				babies.GET("/:babyId/report", reportHandler.GetReport)
+
+				// 只读分享链接(仅管理员)
+				babies.POST("/:babyId/share-links", shareHandler.CreateShareLink)
+				babies.GET("/:babyId/share-links", shareHandler.ListShareLinks)
+				babies.DELETE("/:babyId/share-links/:shareId", shareHandler.RevokeShareLink)
+				babies.GET("/:babyId/share-links/:shareId/access-logs", shareHandler.GetAccessLogs)
+
+				// FHIR R4 导出及疫苗接种记录导入
+				babies.GET("/:babyId/fhir", fhirHandler.Export)
+				babies.POST("/:babyId/fhir/immunizations", fhirHandler.ImportImmunizations)
+
+				// 回收站(保留期内可恢复已删除的记录)
+				babies.GET("/:babyId/trash", trashHandler.GetTrash)
+				babies.POST("/:babyId/trash/:recordType/:recordId/restore", trashHandler.RestoreRecord)
 			}
 
+			// 导出任务状态及下载链接
+			authRequired.GET("/exports/:exportId", exportHandler.GetExport)
+
+			// 提名给我的所有权转让
+			authRequired.GET("/ownership-transfers", ownershipHandler.ListIncomingTransfers)
+
+			// 协作者角色预设权限
+			authRequired.GET("/permission-presets", permissionHandler.ListRolePresets)
+
+			// 疫苗计划(导入导出通过 cmd/vaccine-plans 命令)
+			authRequired.GET("/vaccine-plans", vaccinePlanHandler.ListPlans)
+			authRequired.GET("/vaccine-plans/:planId", vaccinePlanHandler.GetPlan)
+
+			// 日历订阅地址
+			authRequired.GET("/calendar/feed", calendarHandler.GetFeed)
+			authRequired.POST("/calendar/feed/regenerate", calendarHandler.RegenerateFeed)
+
+			// 支持的导入格式
+			authRequired.GET("/imports/parsers", importHandler.ListParsers)
+
 			// 喂养记录
 			feedingRecords := authRequired.Group("/feeding-records")
 			{
@@ -145,6 +266,36 @@ func NewRouter(
 				growthRecords.DELETE("/:id", recordHandler.DeleteGrowthRecord)
 			}
 
+			// 照片附件(可关联任意记录)
+			attachments := authRequired.Group("/attachments")
+			{
+				attachments.POST("/:recordType/:recordId", attachmentHandler.UploadAttachment)
+				attachments.GET("/:recordType/:recordId", attachmentHandler.GetRecordAttachments)
+				attachments.DELETE("/:attachmentId", attachmentHandler.DeleteAttachment)
+			}
+
+			// 记录评论(可关联任意记录)
+			comments := authRequired.Group("/comments")
+			{
+				comments.GET("/:recordType/:recordId", commentHandler.GetRecordComments)
+				comments.POST("/:recordType/:recordId", commentHandler.CreateComment)
+				comments.DELETE("/:commentId", commentHandler.DeleteComment)
+			}
+
+			// 记录表情回应
+			reactions := authRequired.Group("/reactions")
+			{
+				reactions.POST("/:recordType/:recordId", commentHandler.AddReaction)
+				reactions.DELETE("/:recordType/:recordId", commentHandler.RemoveReaction)
+			}
+
+			// 记录修订历史
+			revisions := authRequired.Group("/revisions")
+			{
+				revisions.GET("/:recordType/:recordId", revisionHandler.GetRecordHistory)
+				revisions.POST("/:recordType/:recordId/restore", revisionHandler.RestoreRevision)
+			}
+
 			// 时间线聚合接口
 			authRequired.GET("record/timeline", recordHandler.GetTimeline)
 
@@ -0,0 +1,73 @@
+-- 010_milestone_templates.sql
+-- 发育里程碑模板与宝宝里程碑记录
+-- 功能：提供 0-36 月龄常见发育里程碑目录,新建宝宝时按模板初始化
+
+CREATE TABLE IF NOT EXISTS milestone_templates (
+    id BIGSERIAL PRIMARY KEY,
+    category VARCHAR(16),
+    title VARCHAR(64),
+    description TEXT,
+    age_in_months INTEGER,
+    max_age_in_months INTEGER,
+    sort_order INTEGER DEFAULT 0,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_milestone_templates_category ON milestone_templates(category);
+CREATE INDEX IF NOT EXISTS idx_milestone_templates_deleted_at ON milestone_templates(deleted_at);
+
+CREATE TABLE IF NOT EXISTS baby_milestones (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT NOT NULL,
+    template_id BIGINT,
+    category VARCHAR(16),
+    title VARCHAR(64) NOT NULL,
+    description TEXT,
+    age_in_months INTEGER,
+    max_age_in_months INTEGER,
+    status VARCHAR(32) DEFAULT 'upcoming',
+    achieved_date BIGINT,
+    photos JSONB,
+    note TEXT,
+    updated_by BIGINT,
+    created_by BIGINT NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_baby_milestones_baby_id ON baby_milestones(baby_id);
+CREATE INDEX IF NOT EXISTS idx_baby_milestones_status ON baby_milestones(status);
+CREATE INDEX IF NOT EXISTS idx_baby_milestones_achieved_date ON baby_milestones(achieved_date);
+CREATE INDEX IF NOT EXISTS idx_baby_milestones_deleted_at ON baby_milestones(deleted_at);
+
+-- 初始化里程碑目录
+INSERT INTO milestone_templates (category, title, description, age_in_months, max_age_in_months, sort_order, created_at, updated_at)
+SELECT v.category, v.title, v.description, v.age_in_months, v.max_age_in_months, v.sort_order,
+       (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT, (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT
+FROM (VALUES
+    ('social',    '社会性微笑',     '看到熟悉的人脸时会主动微笑',             2,  3,  1),
+    ('motor',     '俯卧抬头',       '俯卧时能抬头45度并维持片刻',             2,  4,  2),
+    ('language',  '发出咕咕声',     '会发出"啊""哦"等元音',                   2,  4,  3),
+    ('cognitive', '追视移动物体',   '眼睛能跟随移动的物体或人脸',             2,  3,  4),
+    ('motor',     '翻身',           '能从仰卧翻到俯卧或从俯卧翻到仰卧',       4,  6,  5),
+    ('social',    '大声笑',         '被逗弄时会发出笑声',                     4,  6,  6),
+    ('motor',     '伸手抓物',       '能主动伸手够取并抓握玩具',               4,  6,  7),
+    ('motor',     '独坐',           '不需要支撑能独自坐稳',                   6,  9,  8),
+    ('language',  '咿呀学语',       '会发出"ba""ma"等辅音音节',               6,  9,  9),
+    ('social',    '认生',           '能区分熟人与陌生人',                     6,  9,  10),
+    ('motor',     '爬行',           '能用手和膝盖向前爬',                     9,  12, 11),
+    ('motor',     '扶站',           '能扶着家具站立',                         9,  12, 12),
+    ('cognitive', '物体恒存',       '会寻找被藏起来的玩具',                   9,  12, 13),
+    ('language',  '有意识叫爸爸妈妈', '能有意识地称呼爸爸或妈妈',             12, 15, 14),
+    ('motor',     '独走',           '能独立行走几步',                         12, 18, 15),
+    ('social',    '用手指物',       '会用手指指向想要的东西',                 12, 15, 16),
+    ('language',  '说出10个词',     '能说出至少10个有意义的词',               18, 24, 17),
+    ('motor',     '自己用勺吃饭',   '能用勺子把食物送进嘴里',                 18, 24, 18),
+    ('language',  '说两个词的短句', '能说"要喝水""妈妈抱"等短句',             24, 30, 19),
+    ('motor',     '跑步',           '能比较平稳地跑动',                       24, 30, 20),
+    ('cognitive', '简单假想游戏',   '会玩喂娃娃、打电话等假想游戏',           24, 30, 21),
+    ('motor',     '双脚跳',         '能双脚同时离地跳起',                     30, 36, 22),
+    ('language',  '说出自己名字',   '能说出自己的名字和年龄',                 36, 42, 23)
+) AS v(category, title, description, age_in_months, max_age_in_months, sort_order)
+WHERE NOT EXISTS (SELECT 1 FROM milestone_templates);
@@ -0,0 +1,39 @@
+-- 011_vaccine_plans.sql
+-- 多套疫苗计划: 计划按编码+版本区分,模板归属于计划,宝宝记录所选计划
+-- 现有模板归入默认计划 cn-nip(国家免疫规划) 第1版
+
+CREATE TABLE IF NOT EXISTS vaccine_plans (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(64),
+    version INTEGER,
+    name VARCHAR(64) NOT NULL,
+    region VARCHAR(16),
+    description TEXT,
+    is_default BOOLEAN DEFAULT FALSE,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plan_code_version ON vaccine_plans(code, version);
+CREATE INDEX IF NOT EXISTS idx_vaccine_plans_region ON vaccine_plans(region);
+CREATE INDEX IF NOT EXISTS idx_vaccine_plans_deleted_at ON vaccine_plans(deleted_at);
+
+ALTER TABLE vaccine_plan_templates ADD COLUMN IF NOT EXISTS plan_id BIGINT DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_vaccine_plan_templates_plan_id ON vaccine_plan_templates(plan_id);
+
+ALTER TABLE babies ADD COLUMN IF NOT EXISTS vaccine_plan_id BIGINT DEFAULT 0;
+
+INSERT INTO vaccine_plans (code, version, name, region, description, is_default, created_at, updated_at)
+SELECT 'cn-nip', 1, '国家免疫规划', 'CN', '国家免疫规划疫苗儿童免疫程序', TRUE,
+       (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT, (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT
+WHERE NOT EXISTS (SELECT 1 FROM vaccine_plans WHERE code = 'cn-nip');
+
+UPDATE vaccine_plan_templates
+SET plan_id = (SELECT id FROM vaccine_plans WHERE code = 'cn-nip' AND version = 1)
+WHERE plan_id = 0;
+
+-- 已初始化日程的宝宝沿用国家免疫规划
+UPDATE babies
+SET vaccine_plan_id = (SELECT id FROM vaccine_plans WHERE code = 'cn-nip' AND version = 1)
+WHERE vaccine_plan_id = 0
+  AND EXISTS (SELECT 1 FROM baby_vaccine_schedules s WHERE s.baby_id = babies.id AND s.deleted_at = 0);
@@ -0,0 +1,38 @@
+-- 012_vaccine_min_intervals.sql
+-- 补种规则: 模板和宝宝日程增加最小接种日龄、与同系列上一剂的最小间隔(天)
+-- 迟种后按这两项顺延后续剂次,并在接种日期过早时给出提示
+
+ALTER TABLE vaccine_plan_templates ADD COLUMN IF NOT EXISTS min_age_days INTEGER DEFAULT 0;
+ALTER TABLE vaccine_plan_templates ADD COLUMN IF NOT EXISTS min_interval_days INTEGER DEFAULT 0;
+
+ALTER TABLE baby_vaccine_schedules ADD COLUMN IF NOT EXISTS min_age_days INTEGER DEFAULT 0;
+ALTER TABLE baby_vaccine_schedules ADD COLUMN IF NOT EXISTS min_interval_days INTEGER DEFAULT 0;
+
+-- 国家免疫规划常见剂次的最小日龄/最小间隔
+WITH rules (vaccine_type, dose_number, min_age_days, min_interval_days) AS (
+    VALUES
+        ('HepB', 2, 0, 28),
+        ('HepB', 3, 0, 60),
+        ('OPV', 2, 0, 28),
+        ('OPV', 3, 0, 28),
+        ('IPV', 1, 42, 0),
+        ('IPV', 2, 0, 28),
+        ('IPV', 3, 0, 28),
+        ('DTaP', 1, 42, 0),
+        ('DTaP', 2, 0, 28),
+        ('DTaP', 3, 0, 28),
+        ('MMR', 2, 0, 28),
+        ('HepA', 2, 0, 180),
+        ('MeningAC', 2, 0, 90)
+)
+UPDATE vaccine_plan_templates t
+SET min_age_days = r.min_age_days, min_interval_days = r.min_interval_days
+FROM rules r
+WHERE t.vaccine_type = r.vaccine_type AND t.dose_number = r.dose_number
+  AND t.min_age_days = 0 AND t.min_interval_days = 0;
+
+-- 已有的来自模板的日程同步规则
+UPDATE baby_vaccine_schedules s
+SET min_age_days = t.min_age_days, min_interval_days = t.min_interval_days
+FROM vaccine_plan_templates t
+WHERE s.template_id = t.id AND s.deleted_at = 0;
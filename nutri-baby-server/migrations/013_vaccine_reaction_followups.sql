@@ -0,0 +1,28 @@
+-- 013_vaccine_reaction_followups.sql
+-- 接种后反应随访: 接种后 6/24/48/72 小时提醒照护人记录体温、局部红肿和哭闹烦躁
+-- 随访结果汇总到接种日程,重度反应在同类疫苗下一剂接种前提示
+
+CREATE TABLE IF NOT EXISTS vaccine_reaction_followups (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id BIGINT NOT NULL,
+    baby_id BIGINT NOT NULL,
+    checkpoint_hours INTEGER,
+    due_at BIGINT,
+    prompt_sent_at BIGINT,
+    temperature_c NUMERIC(4, 1),
+    swelling VARCHAR(16),
+    fussiness VARCHAR(16),
+    note TEXT,
+    recorded_by BIGINT,
+    recorded_at BIGINT,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_vaccine_reaction_followups_schedule_id ON vaccine_reaction_followups(schedule_id);
+CREATE INDEX IF NOT EXISTS idx_vaccine_reaction_followups_baby_id ON vaccine_reaction_followups(baby_id);
+CREATE INDEX IF NOT EXISTS idx_vaccine_reaction_followups_due_at ON vaccine_reaction_followups(due_at);
+CREATE INDEX IF NOT EXISTS idx_vaccine_reaction_followups_deleted_at ON vaccine_reaction_followups(deleted_at);
+
+ALTER TABLE baby_vaccine_schedules ADD COLUMN IF NOT EXISTS reaction_severity VARCHAR(16);
+ALTER TABLE baby_vaccine_schedules ADD COLUMN IF NOT EXISTS reaction_summary TEXT;
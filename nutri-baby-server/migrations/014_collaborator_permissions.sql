@@ -0,0 +1,6 @@
+-- 014_collaborator_permissions.sql
+-- 协作者细粒度权限: 记录类型 × 操作(read/create/update_own/update_any/delete)
+-- 角色预设之外的单独调整以 JSON 存储: {"growth": {"read": true}, "feeding": {"delete": false}}
+-- 新增 caregiver(照护者) 角色: 仅可查看和记录喂养、睡眠、排泄
+
+ALTER TABLE baby_collaborators ADD COLUMN IF NOT EXISTS permissions JSONB;
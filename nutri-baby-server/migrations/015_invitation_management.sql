@@ -0,0 +1,31 @@
+-- 015_invitation_management.sql
+-- 邀请管理: 邀请可撤销、限制使用次数,链接过期时间与协作权限过期时间分开
+-- 开启审批的邀请在加入时先生成加入申请,管理员通过后才创建协作者
+
+ALTER TABLE baby_invitations ADD COLUMN IF NOT EXISTS link_expires_at BIGINT;
+ALTER TABLE baby_invitations ADD COLUMN IF NOT EXISTS max_uses INTEGER DEFAULT 0;
+ALTER TABLE baby_invitations ADD COLUMN IF NOT EXISTS used_count INTEGER DEFAULT 0;
+ALTER TABLE baby_invitations ADD COLUMN IF NOT EXISTS require_approval BOOLEAN DEFAULT FALSE;
+ALTER TABLE baby_invitations ADD COLUMN IF NOT EXISTS revoked_at BIGINT;
+
+CREATE TABLE IF NOT EXISTS baby_join_requests (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    invitation_id BIGINT,
+    user_id BIGINT,
+    role VARCHAR(16),
+    relationship VARCHAR(32),
+    access_type VARCHAR(16),
+    expires_at BIGINT,
+    status VARCHAR(16),
+    reviewed_by BIGINT,
+    reviewed_at BIGINT,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_baby_join_requests_baby_id ON baby_join_requests(baby_id);
+CREATE INDEX IF NOT EXISTS idx_baby_join_requests_invitation_id ON baby_join_requests(invitation_id);
+CREATE INDEX IF NOT EXISTS idx_baby_join_requests_user_id ON baby_join_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_baby_join_requests_status ON baby_join_requests(status);
+CREATE INDEX IF NOT EXISTS idx_baby_join_requests_deleted_at ON baby_join_requests(deleted_at);
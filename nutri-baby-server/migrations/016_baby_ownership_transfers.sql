@@ -0,0 +1,19 @@
+-- 016_baby_ownership_transfers.sql
+-- 宝宝所有权转让: 所有者提名一位长期成员,对方接受后 babies.user_id 变更为新所有者
+-- 同一宝宝同时只保留一条待处理(pending)的转让
+
+CREATE TABLE IF NOT EXISTS baby_ownership_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    from_user_id BIGINT,
+    to_user_id BIGINT,
+    status VARCHAR(16),
+    responded_at BIGINT,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_baby_ownership_transfers_baby_id ON baby_ownership_transfers(baby_id);
+CREATE INDEX IF NOT EXISTS idx_baby_ownership_transfers_to_user_id ON baby_ownership_transfers(to_user_id);
+CREATE INDEX IF NOT EXISTS idx_baby_ownership_transfers_status ON baby_ownership_transfers(status);
+CREATE INDEX IF NOT EXISTS idx_baby_ownership_transfers_deleted_at ON baby_ownership_transfers(deleted_at);
@@ -0,0 +1,9 @@
+-- 017_temporary_collaborator_expiry.sql
+-- 临时成员到期: 定时任务在到期前一天提醒管理员(reminded_at 防止重复提醒,续期后清空),到期后软删除协作关系
+-- 唯一索引带上 deleted_at,被撤销或移除的成员可以再次通过邀请加入
+
+ALTER TABLE baby_collaborators ADD COLUMN IF NOT EXISTS reminded_at BIGINT;
+
+DROP INDEX IF EXISTS idx_baby_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_baby_user ON baby_collaborators(baby_id, user_id, deleted_at);
+CREATE INDEX IF NOT EXISTS idx_baby_collaborators_expires_at ON baby_collaborators(expires_at) WHERE access_type = 'temporary';
@@ -0,0 +1,20 @@
+-- 018_caregiver_shifts.sql
+-- 照护人值班: 同一宝宝同时最多一位照护人值班,新照护人开始值班时结束其他未结束的班次(交接)
+-- route_reminders 开启后值班期间喂养、接种随访提醒只发给值班照护人
+
+CREATE TABLE IF NOT EXISTS caregiver_shifts (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    user_id BIGINT,
+    start_time BIGINT,
+    end_time BIGINT,
+    route_reminders BOOLEAN DEFAULT FALSE,
+    handoff_note TEXT,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_caregiver_shifts_baby_id ON caregiver_shifts(baby_id);
+CREATE INDEX IF NOT EXISTS idx_caregiver_shifts_user_id ON caregiver_shifts(user_id);
+CREATE INDEX IF NOT EXISTS idx_caregiver_shifts_start_time ON caregiver_shifts(start_time);
+CREATE INDEX IF NOT EXISTS idx_caregiver_shifts_deleted_at ON caregiver_shifts(deleted_at);
@@ -0,0 +1,35 @@
+-- 019_record_comments.sql
+-- 记录评论与表情回应: 可关联任意类型的记录(与附件相同),随记录一起软删除
+-- 评论支持一层回复(parent_id 指向顶层评论),mentioned_user_ids 为被@成员的用户ID,逗号分隔
+
+CREATE TABLE IF NOT EXISTS record_comments (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    record_type VARCHAR(16),
+    record_id BIGINT,
+    parent_id BIGINT DEFAULT 0,
+    reply_to_user_id BIGINT DEFAULT 0,
+    user_id BIGINT,
+    content TEXT,
+    mentioned_user_ids VARCHAR(512),
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_record_comments_baby_id ON record_comments(baby_id);
+CREATE INDEX IF NOT EXISTS idx_comment_record ON record_comments(record_type, record_id);
+CREATE INDEX IF NOT EXISTS idx_record_comments_deleted_at ON record_comments(deleted_at);
+
+CREATE TABLE IF NOT EXISTS record_reactions (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    record_type VARCHAR(16),
+    record_id BIGINT,
+    user_id BIGINT,
+    emoji VARCHAR(32),
+    created_at BIGINT,
+    deleted_at BIGINT DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_record_reactions_baby_id ON record_reactions(baby_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reaction_record_user_emoji ON record_reactions(record_type, record_id, user_id, emoji, deleted_at);
+CREATE INDEX IF NOT EXISTS idx_record_reactions_deleted_at ON record_reactions(deleted_at);
@@ -0,0 +1,20 @@
+-- 020_record_revisions.sql
+-- 记录修订历史: 各类记录和疫苗日程的创建、修改、删除、恢复,只追加不修改
+-- snapshot 为操作后的记录快照(删除时为删除前的快照),changes 为字段级变更 [{field, old, new}]
+
+CREATE TABLE IF NOT EXISTS record_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT,
+    record_type VARCHAR(16),
+    record_id BIGINT,
+    action VARCHAR(16),
+    actor_id BIGINT,
+    actor_name VARCHAR(64),
+    snapshot JSONB,
+    changes JSONB,
+    record_updated_at BIGINT,
+    restored_from BIGINT DEFAULT 0,
+    created_at BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_record_revisions_baby_id ON record_revisions(baby_id);
+CREATE INDEX IF NOT EXISTS idx_revision_record ON record_revisions(record_type, record_id, created_at);
@@ -0,0 +1,17 @@
+-- 021_baby_targets.sql
+-- 宝宝每日目标自定义覆盖: 每个宝宝至多一条,字段为空表示该项使用系统推荐值
+
+CREATE TABLE IF NOT EXISTS baby_targets (
+    id BIGSERIAL PRIMARY KEY,
+    baby_id BIGINT NOT NULL,
+    milk_min_ml BIGINT,
+    milk_max_ml BIGINT,
+    feed_count_min INTEGER,
+    feed_count_max INTEGER,
+    sleep_min_minutes INTEGER,
+    sleep_max_minutes INTEGER,
+    updated_by BIGINT,
+    created_at BIGINT,
+    updated_at BIGINT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_baby_target_baby ON baby_targets(baby_id);
@@ -12,6 +12,7 @@ import (
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/eino/tools"
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/logger"
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/persistence"
+	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/storage"
 	"github.com/wxlbd/nutri-baby-server/internal/infrastructure/wechat"
 	"github.com/wxlbd/nutri-baby-server/internal/interface/http/handler"
 	"github.com/wxlbd/nutri-baby-server/internal/interface/http/router"
@@ -25,6 +26,7 @@ func InitApp(cfg *config.Config) (*App, error) {
 		persistence.NewDatabase,
 		persistence.NewRedis, // Redis 客户端
 		wechat.NewClient,     // 微信 SDK 客户端
+		storage.NewStorage,   // 文件存储(本地/S3兼容)
 
 		// Eino AI框架（工具调用架构）
 		model.NewToolCallingChatModel, // 支持工具调用的AI模型客户端
@@ -36,18 +38,34 @@ func InitApp(cfg *config.Config) (*App, error) {
 		persistence.NewSubscriptionCacheRepository, // 订阅权限缓存管理器
 		persistence.NewUserRepository,
 		persistence.NewBabyRepository,
-		persistence.NewBabyCollaboratorRepository, // 去家庭化架构：宝宝协作者仓储
-		persistence.NewBabyInvitationRepository,   // 去家庭化架构：宝宝邀请仓储
+		persistence.NewBabyCollaboratorRepository,      // 去家庭化架构：宝宝协作者仓储
+		persistence.NewBabyInvitationRepository,        // 去家庭化架构：宝宝邀请仓储
+		persistence.NewBabyJoinRequestRepository,       // 加入申请仓储
+		persistence.NewBabyOwnershipTransferRepository, // 所有权转让仓储
 		persistence.NewFeedingRecordRepository,
 		persistence.NewSleepRecordRepository,
 		persistence.NewDiaperRecordRepository,
 		persistence.NewGrowthRecordRepository,
-		persistence.NewBabyVaccineScheduleRepository, // 新增：疫苗接种日程仓储
-		persistence.NewVaccinePlanTemplateRepository, // 疫苗计划模板仓储
-		persistence.NewSubscribeRepository,           // 订阅消息仓储
-		persistence.NewAIAnalysisRepository,          // AI分析结果仓储
-		persistence.NewDailyTipsRepository,           // 每日建议仓储
-		persistence.NewAppVersionRepository,          // 应用版本仓储
+		persistence.NewBabyVaccineScheduleRepository,     // 新增：疫苗接种日程仓储
+		persistence.NewVaccinePlanRepository,             // 疫苗计划仓储
+		persistence.NewVaccinePlanTemplateRepository,     // 疫苗计划模板仓储
+		persistence.NewVaccineReactionFollowUpRepository, // 接种后反应随访仓储
+		persistence.NewSubscribeRepository,               // 订阅消息仓储
+		persistence.NewAIAnalysisRepository,              // AI分析结果仓储
+		persistence.NewDailyTipsRepository,               // 每日建议仓储
+		persistence.NewAppVersionRepository,              // 应用版本仓储
+		persistence.NewBabyTargetRepository,              // 每日目标仓储
+		persistence.NewMilestoneTemplateRepository,       // 发育里程碑模板仓储
+		persistence.NewBabyMilestoneRepository,           // 宝宝发育里程碑仓储
+		persistence.NewAttachmentRepository,              // 照片附件仓储
+		persistence.NewExportJobRepository,               // 数据导出任务仓储
+		persistence.NewRecordImportRepository,            // 记录批量导入仓储
+		persistence.NewShareLinkRepository,               // 只读分享链接仓储
+		persistence.NewCalendarFeedRepository,            // 日历订阅仓储
+		persistence.NewCaregiverShiftRepository,          // 照护人值班仓储
+		persistence.NewRecordCommentRepository,           // 记录评论与表情回应仓储
+		persistence.NewRecordRevisionRepository,          // 记录修订历史仓储
+		persistence.NewRecordTrashRepository,             // 回收站仓储
 
 		// 应用服务层
 		service.NewWechatService,    // 微信服务
@@ -66,6 +84,25 @@ func InitApp(cfg *config.Config) (*App, error) {
 		service.NewUploadService,          // 文件上传服务
 		service.NewAIAnalysisService,      // AI分析服务（工具调用架构）
 		service.NewAppVersionService,      // 应用版本服务
+		service.NewTargetService,          // 每日目标服务
+		service.NewMilestoneService,       // 发育里程碑服务
+		service.NewAttachmentService,      // 照片附件服务
+		service.NewExportService,          // 数据导出服务
+		service.NewImportService,          // 数据导入服务
+		service.NewReportService,          // 就诊报告服务
+		service.NewShareService,           // 只读分享链接服务
+		service.NewFHIRService,            // FHIR 导出导入服务
+		service.NewCalendarService,        // 日历订阅服务
+		service.NewVaccinePlanService,     // 疫苗计划服务
+		service.NewVaccineReactionService, // 接种后反应随访服务
+		service.NewPermissionService,      // 协作者权限服务
+		service.NewInvitationService,      // 邀请管理服务
+		service.NewOwnershipService,       // 所有权转让服务
+		service.NewAccessExpiryService,    // 临时成员到期服务
+		service.NewShiftService,           // 照护人值班交接服务
+		service.NewCommentService,         // 记录评论与表情回应服务
+		service.NewRevisionService,        // 记录修订历史服务
+		service.NewTrashService,           // 回收站服务
 		// service.NewSyncService, // TODO: WebSocket同步未实现，暂时注释
 
 		// HTTP处理器
@@ -78,7 +115,24 @@ func InitApp(cfg *config.Config) (*App, error) {
 		handler.NewSubscribeHandler,       // 订阅消息处理器
 		handler.NewAIAnalysisHandler,      // AI分析处理器（工具调用架构）
 		handler.NewSyncHandler,
-		handler.NewUploadHandler, // 文件上传处理器
+		handler.NewUploadHandler,      // 文件上传处理器
+		handler.NewTargetHandler,      // 每日目标处理器
+		handler.NewMilestoneHandler,   // 发育里程碑处理器
+		handler.NewAttachmentHandler,  // 照片附件处理器
+		handler.NewExportHandler,      // 数据导出处理器
+		handler.NewImportHandler,      // 数据导入处理器
+		handler.NewReportHandler,      // 就诊报告处理器
+		handler.NewShareHandler,       // 只读分享链接处理器
+		handler.NewFHIRHandler,        // FHIR 导出导入处理器
+		handler.NewCalendarHandler,    // 日历订阅处理器
+		handler.NewVaccinePlanHandler, // 疫苗计划处理器
+		handler.NewPermissionHandler,  // 协作者权限处理器
+		handler.NewInvitationHandler,  // 邀请管理处理器
+		handler.NewOwnershipHandler,   // 所有权转让处理器
+		handler.NewShiftHandler,       // 照护人值班交接处理器
+		handler.NewCommentHandler,     // 记录评论与表情回应处理器
+		handler.NewRevisionHandler,    // 记录修订历史处理器
+		handler.NewTrashHandler,       // 回收站处理器
 
 		// 路由
 		router.NewRouter,